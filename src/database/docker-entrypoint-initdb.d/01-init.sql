@@ -1,5 +1,15 @@
-CREATE TYPE ALBUM_TYPE AS ENUM ('single', 'LP', 'EP');
+-- LP is only kept for albums created before the album type, new ones are saved as album
+CREATE TYPE ALBUM_TYPE AS ENUM ('single', 'LP', 'EP', 'album', 'compilation');
+CREATE TYPE ARTIST_ROLE AS ENUM ('primary', 'featured', 'remixer', 'producer');
+CREATE TYPE TRACK_CREDIT_ROLE AS ENUM ('featured', 'composer', 'producer');
 CREATE TYPE ROLE_TYPE AS ENUM ('user', 'musician', 'admin');
+CREATE TYPE PLAYLIST_KIND AS ENUM ('user', 'daily_mix', 'smart');
+CREATE TYPE PLAYLIST_VISIBILITY AS ENUM ('private', 'unlisted', 'public');
+CREATE TYPE CHART_KIND AS ENUM ('track', 'album', 'musician');
+CREATE TYPE CHART_PERIOD AS ENUM ('day', 'week', 'month');
+CREATE TYPE FEED_ITEM_KIND AS ENUM ('album', 'track', 'merch');
+CREATE TYPE ORDER_STATUS AS ENUM ('pending', 'paid', 'shipped', 'cancelled', 'refunded');
+CREATE TYPE REPORT_STATUS AS ENUM ('pending', 'confirmed', 'dismissed');
 
 CREATE TABLE IF NOT EXISTS musicians
 (
@@ -11,53 +21,233 @@ CREATE TABLE IF NOT EXISTS musicians
 
 CREATE TABLE IF NOT EXISTS albums
 (
-    id          INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
-    name        VARCHAR(100) NOT NULL,
-    cover_file  BYTEA        NOT NULL,
-    type        ALBUM_TYPE   NOT NULL,
-    musician_id INT          NOT NULL
+    id           INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name         VARCHAR(100) NOT NULL,
+    cover_file   BYTEA        NOT NULL,
+    type         ALBUM_TYPE   NOT NULL,
+    musician_id  INT          NOT NULL
         REFERENCES musicians (id)
             ON DELETE CASCADE,
+    release_date DATE,
+    label        VARCHAR(254),
+    -- UPC-A or EAN-13
+    upc          VARCHAR(13),
+    -- LUFS and dBTP of all tracks, NULL while any of them is not measured
+    loudness     DOUBLE PRECISION,
+    true_peak    DOUBLE PRECISION,
     CHECK ( name <> '' ),
-    CHECK ( length(cover_file) > 0 )
+    CHECK ( length(cover_file) > 0 ),
+    CHECK ( upc ~ '^[0-9]{12,13}$' )
 );
 
 
+-- parent_id is NULL for top level genres, genres with subgenres can't be deleted
 CREATE TABLE IF NOT EXISTS genres
 (
-    id   INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
-    name VARCHAR(100) NOT NULL UNIQUE,
-    CHECK ( name <> '' )
+    id        INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name      VARCHAR(100) NOT NULL,
+    parent_id INT REFERENCES genres (id),
+    CHECK ( name <> '' ),
+    CHECK ( parent_id <> id )
 );
 
+-- genre names are unique and matched ignoring case
+CREATE UNIQUE INDEX IF NOT EXISTS genres_lower_name_idx ON genres (lower(name));
+CREATE INDEX IF NOT EXISTS genres_parent_idx ON genres (parent_id);
+
+CREATE TABLE IF NOT EXISTS album_genres
+(
+    album_id INT NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+    genre_id INT NOT NULL REFERENCES genres (id) ON DELETE CASCADE,
+    PRIMARY KEY (album_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS album_genres_genre_idx ON album_genres (genre_id);
+
 
 CREATE TABLE IF NOT EXISTS tracks
 (
     id       INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
     source   VARCHAR(254) NOT NULL,
     name     VARCHAR(100) NOT NULL,
-    genre    INT REFERENCES genres (id),
+    -- primary genre, also present in track_genres
+    genre    INT REFERENCES genres (id) ON DELETE SET NULL,
     album_id INT          NOT NULL
         REFERENCES albums (id)
             ON DELETE CASCADE,
+    -- seconds, NULL until measured
+    duration INT,
+    -- without hyphens, e.g. USRC17607839
+    isrc     CHAR(12),
+    explicit BOOLEAN      NOT NULL DEFAULT FALSE,
+    -- LUFS and dBTP following EBU R128, NULL until measured
+    loudness  DOUBLE PRECISION,
+    true_peak DOUBLE PRECISION,
     CHECK ( source <> '' ),
+    CHECK ( name <> '' ),
+    CHECK ( duration > 0 ),
+    CHECK ( isrc ~ '^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$' )
+);
+
+CREATE INDEX IF NOT EXISTS tracks_lower_name_idx ON tracks (lower(btrim(name)));
+-- sources named after their SHA-256 are shared by tracks with identical
+-- payloads and deleted with the last of them. Sources of tracks uploaded
+-- before have no row until they are hashed. stored is set once the object is
+-- uploaded, removing_at while the object of the last reference is removed.
+CREATE TABLE IF NOT EXISTS track_sources
+(
+    source      VARCHAR(254) NOT NULL PRIMARY KEY,
+    refs        INT          NOT NULL,
+    stored      BOOLEAN      NOT NULL DEFAULT FALSE,
+    removing_at TIMESTAMPTZ,
+    CHECK ( refs >= 0 )
+);
+
+-- values of the chroma fingerprint, little endian uint32
+CREATE TABLE IF NOT EXISTS track_fingerprints
+(
+    track_id    INT   NOT NULL PRIMARY KEY REFERENCES tracks (id) ON DELETE CASCADE,
+    fingerprint BYTEA NOT NULL
+);
+
+-- keys look up fingerprints sharing frames with a new one
+CREATE TABLE IF NOT EXISTS track_fingerprint_keys
+(
+    key      INT NOT NULL,
+    track_id INT NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    PRIMARY KEY (key, track_id)
+);
+
+CREATE INDEX IF NOT EXISTS track_fingerprint_keys_track_idx ON track_fingerprint_keys (track_id);
+
+-- moderation queue of tracks sounding like an earlier track of another musician
+CREATE TABLE IF NOT EXISTS duplicate_reports
+(
+    id                INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    track_id          INT              NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    original_track_id INT              NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    similarity        DOUBLE PRECISION NOT NULL,
+    status            REPORT_STATUS    NOT NULL DEFAULT 'pending',
+    created_at        TIMESTAMPTZ      NOT NULL DEFAULT now(),
+    UNIQUE (track_id, original_track_id)
+);
+
+CREATE INDEX IF NOT EXISTS duplicate_reports_status_idx ON duplicate_reports (status, id);
+
+-- tracks also inherit the genres of their album
+CREATE TABLE IF NOT EXISTS track_genres
+(
+    track_id INT NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    genre_id INT NOT NULL REFERENCES genres (id) ON DELETE CASCADE,
+    PRIMARY KEY (track_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS track_genres_genre_idx ON track_genres (genre_id);
+
+-- moods are free-form lowercase tags
+CREATE TABLE IF NOT EXISTS track_moods
+(
+    track_id INT         NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    mood     VARCHAR(50) NOT NULL,
+    PRIMARY KEY (track_id, mood),
+    CHECK ( mood <> '' )
+);
+
+CREATE INDEX IF NOT EXISTS track_moods_mood_idx ON track_moods (mood);
+
+-- featured artists, composers and producers, position keeps the order within a role
+CREATE TABLE IF NOT EXISTS track_credits
+(
+    track_id INT               NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    role     TRACK_CREDIT_ROLE NOT NULL,
+    name     VARCHAR(254)      NOT NULL,
+    position INT               NOT NULL,
+    PRIMARY KEY (track_id, role, name),
     CHECK ( name <> '' )
 );
 
+CREATE TABLE IF NOT EXISTS track_lyrics
+(
+    track_id INT         NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    -- BCP 47 tag in lowercase, e.g. en or pt-br
+    language VARCHAR(35) NOT NULL,
+    synced   BOOLEAN     NOT NULL,
+    -- [{"offset_ms": 1500, "text": "..."}]
+    lines    JSONB       NOT NULL,
+    -- lines without offsets for the search
+    text     TEXT        NOT NULL,
+    search   TSVECTOR GENERATED ALWAYS AS ( to_tsvector('simple', text) ) STORED,
+    PRIMARY KEY (track_id, language)
+);
+
+CREATE INDEX IF NOT EXISTS track_lyrics_search_idx ON track_lyrics USING GIN (search);
+
+-- release_artists links musicians other than the album's own musician to an
+-- album, or to one of its tracks if track_id is set. A declined link is kept,
+-- hidden, so the album's musician can't add the musician again
+CREATE TABLE IF NOT EXISTS release_artists
+(
+    id          INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    album_id    INT         NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+    track_id    INT REFERENCES tracks (id) ON DELETE CASCADE,
+    musician_id INT         NOT NULL REFERENCES musicians (id) ON DELETE CASCADE,
+    role        ARTIST_ROLE NOT NULL,
+    -- set by the linked musician
+    approved    BOOLEAN     NOT NULL DEFAULT FALSE,
+    declined    BOOLEAN     NOT NULL DEFAULT FALSE,
+    position    INT         NOT NULL,
+    CHECK ( NOT (approved AND declined) )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS release_artists_unique_idx
+    ON release_artists (album_id, COALESCE(track_id, 0), musician_id);
+CREATE INDEX IF NOT EXISTS release_artists_musician_idx ON release_artists (musician_id);
+
 CREATE TABLE IF NOT EXISTS merch
 (
     id          INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
     name        VARCHAR(254) NOT NULL,
     description TEXT,
-    link        VARCHAR(254) NOT NULL,
+    -- NULL for merch sold through the store only
+    link        VARCHAR(254),
     musician_id INT          NOT NULL
         REFERENCES musicians (id)
             ON DELETE CASCADE,
     CHECK ( name <> '' ),
-    CHECK ( link <> '' ),
-    UNIQUE (name, link, musician_id)
+    CHECK ( link <> '' )
 );
 
+-- merch without a link is unique too
+CREATE UNIQUE INDEX IF NOT EXISTS merch_unique_idx
+    ON merch (name, COALESCE(link, ''), musician_id);
+
+CREATE TABLE IF NOT EXISTS merch_variants
+(
+    id                  INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    merch_id            INT         NOT NULL
+        REFERENCES merch (id)
+            ON DELETE CASCADE,
+    sku                 VARCHAR(64) NOT NULL,
+    size                VARCHAR(32) NOT NULL DEFAULT '',
+    colour              VARCHAR(32) NOT NULL DEFAULT '',
+    -- minor units of currency
+    price               BIGINT      NOT NULL,
+    currency            CHAR(3)     NOT NULL,
+    -- items on hand, reserved ones are in orders not shipped yet
+    stock               INT         NOT NULL DEFAULT 0,
+    reserved            INT         NOT NULL DEFAULT 0,
+    low_stock_threshold INT         NOT NULL DEFAULT 0,
+    CHECK ( sku <> '' ),
+    CHECK ( price >= 0 ),
+    CHECK ( stock >= 0 ),
+    CHECK ( reserved >= 0 ),
+    CHECK ( low_stock_threshold >= 0 ),
+    UNIQUE (merch_id, sku),
+    UNIQUE (merch_id, size, colour)
+);
+
+CREATE INDEX IF NOT EXISTS merch_variants_price_idx ON merch_variants (currency, price);
+
 
 CREATE TABLE IF NOT EXISTS merch_photos
 (
@@ -70,6 +260,35 @@ CREATE TABLE IF NOT EXISTS merch_photos
 );
 
 
+CREATE TABLE IF NOT EXISTS concerts
+(
+    id          INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    musician_id INT              NOT NULL
+        REFERENCES musicians (id)
+            ON DELETE CASCADE,
+    title       VARCHAR(254)     NOT NULL,
+    description TEXT,
+    venue       VARCHAR(254)     NOT NULL,
+    city        VARCHAR(100)     NOT NULL,
+    latitude    DOUBLE PRECISION NOT NULL,
+    longitude   DOUBLE PRECISION NOT NULL,
+    starts_at   TIMESTAMPTZ      NOT NULL,
+    -- IANA name, starts_at is shown in it
+    time_zone   VARCHAR(64)      NOT NULL,
+    ticket_url  VARCHAR(254),
+    sold_out    BOOLEAN          NOT NULL DEFAULT FALSE,
+    CHECK ( title <> '' ),
+    CHECK ( venue <> '' ),
+    CHECK ( city <> '' ),
+    CHECK ( latitude BETWEEN -90 AND 90 ),
+    CHECK ( longitude BETWEEN -180 AND 180 ),
+    CHECK ( ticket_url <> '' )
+);
+
+CREATE INDEX IF NOT EXISTS concerts_musician_idx ON concerts (musician_id, starts_at);
+CREATE INDEX IF NOT EXISTS concerts_location_idx ON concerts (latitude, longitude);
+
+
 CREATE TABLE IF NOT EXISTS musicians_photos
 (
     id          INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
@@ -94,14 +313,19 @@ CREATE TABLE IF NOT EXISTS users
 
 CREATE TABLE IF NOT EXISTS playlists
 (
-    id          INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
-    name        VARCHAR(254) NOT NULL,
-    cover_file  BYTEA        NOT NULL,
-    description TEXT,
-    user_id     INT          NOT NULL
+    id            INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name          VARCHAR(254)        NOT NULL,
+    cover_file    BYTEA               NOT NULL,
+    description   TEXT,
+    user_id       INT                 NOT NULL
         REFERENCES users (id)
             ON DELETE CASCADE,
+    kind          PLAYLIST_KIND       NOT NULL DEFAULT 'user',
+    visibility    PLAYLIST_VISIBILITY NOT NULL DEFAULT 'public',
+    collaborative BOOLEAN             NOT NULL DEFAULT FALSE,
+    rules         JSONB,
     CHECK ( name <> '' ),
+    CHECK ( (kind = 'smart') = (rules IS NOT NULL) ),
     CHECK ( length(cover_file) > 0 )
 );
 
@@ -114,14 +338,226 @@ CREATE TABLE IF NOT EXISTS users_musicians
 
 CREATE TABLE IF NOT EXISTS outbox
 (
-    id       SERIAL PRIMARY KEY,
-    event_id TEXT                  NOT NULL,
-    track_id INTEGER               NOT NULL,
-    source   VARCHAR(254),
-    name     VARCHAR(100),
-    genre    INT,
-    type     VARCHAR(100)          NOT NULL,
-    sent     BOOLEAN DEFAULT FALSE NOT NULL
+    id              SERIAL PRIMARY KEY,
+    event_id        TEXT                                   NOT NULL UNIQUE,
+    aggregate_type  VARCHAR(100)                           NOT NULL,
+    aggregate_id    INTEGER                                NOT NULL,
+    type            VARCHAR(100)                           NOT NULL,
+    payload         JSONB                                  NOT NULL,
+    occurred_at     TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+    sent            BOOLEAN                  DEFAULT FALSE NOT NULL,
+    sent_at         TIMESTAMP WITH TIME ZONE,
+    attempts        INT                      DEFAULT 0     NOT NULL,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+    dead_lettered   BOOLEAN                  DEFAULT FALSE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS outbox_waiting_idx ON outbox (next_attempt_at, id) WHERE sent = FALSE AND dead_lettered = FALSE;
+CREATE INDEX IF NOT EXISTS outbox_aggregate_idx ON outbox (aggregate_type, aggregate_id, id) WHERE sent = FALSE AND dead_lettered = FALSE;
+CREATE INDEX IF NOT EXISTS outbox_sent_at_idx ON outbox (sent_at) WHERE sent = TRUE;
+
+-- events handled by each kafka consumer group
+CREATE TABLE IF NOT EXISTS processed_events
+(
+    consumer     TEXT                     NOT NULL,
+    event_id     TEXT                     NOT NULL,
+    processed_at TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+    PRIMARY KEY (consumer, event_id)
+);
+
+CREATE TABLE IF NOT EXISTS similar_tracks
+(
+    track_id         INT              NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    similar_track_id INT              NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    position         INT              NOT NULL,
+    score            DOUBLE PRECISION NOT NULL,
+    computed_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (track_id, similar_track_id)
+);
+
+CREATE INDEX IF NOT EXISTS similar_tracks_position_idx ON similar_tracks (track_id, position);
+
+CREATE TABLE IF NOT EXISTS trending_tracks
+(
+    track_id    INT              NOT NULL PRIMARY KEY REFERENCES tracks (id) ON DELETE CASCADE,
+    position    INT              NOT NULL,
+    score       DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS track_neighbours
+(
+    track_id     INT              NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    neighbour_id INT              NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    position     INT              NOT NULL,
+    score        DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (track_id, neighbour_id)
+);
+
+CREATE INDEX IF NOT EXISTS track_neighbours_position_idx ON track_neighbours (track_id, position);
+
+
+-- append-only, rows are only inserted in batches and removed by cascade
+CREATE TABLE IF NOT EXISTS plays
+(
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    track_id    INT         NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    played_at   TIMESTAMPTZ NOT NULL,
+    duration_ms INT         NOT NULL CHECK (duration_ms >= 0),
+    completed   BOOLEAN     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS plays_user_idx ON plays (user_id, played_at DESC);
+CREATE INDEX IF NOT EXISTS plays_track_idx ON plays (track_id, played_at);
+CREATE INDEX IF NOT EXISTS plays_played_at_idx ON plays (played_at);
+
+CREATE TABLE IF NOT EXISTS merch_clicks
+(
+    id         BIGSERIAL PRIMARY KEY,
+    merch_id   INT         NOT NULL REFERENCES merch (id) ON DELETE CASCADE,
+    clicked_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS merch_clicks_clicked_at_idx ON merch_clicks (clicked_at);
+
+-- daily rollups for musician stats, days are in UTC
+CREATE TABLE IF NOT EXISTS track_daily_stats
+(
+    track_id      INT  NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    day           DATE NOT NULL,
+    plays         INT  NOT NULL,
+    skips         INT  NOT NULL,
+    likes         INT  NOT NULL,
+    playlist_adds INT  NOT NULL,
+    listeners     INT  NOT NULL,
+    PRIMARY KEY (track_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS track_daily_stats_day_idx ON track_daily_stats (day);
+
+CREATE TABLE IF NOT EXISTS album_daily_stats
+(
+    album_id      INT  NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+    day           DATE NOT NULL,
+    plays         INT  NOT NULL,
+    skips         INT  NOT NULL,
+    likes         INT  NOT NULL,
+    playlist_adds INT  NOT NULL,
+    listeners     INT  NOT NULL,
+    PRIMARY KEY (album_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS album_daily_stats_day_idx ON album_daily_stats (day);
+
+CREATE TABLE IF NOT EXISTS merch_daily_stats
+(
+    merch_id INT  NOT NULL REFERENCES merch (id) ON DELETE CASCADE,
+    day      DATE NOT NULL,
+    clicks   INT  NOT NULL,
+    PRIMARY KEY (merch_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS merch_daily_stats_day_idx ON merch_daily_stats (day);
+
+-- the single row of stats_rollups keeps where the rollup stopped, days before
+-- rolled_up_to are rolled up
+CREATE TABLE IF NOT EXISTS stats_rollups
+(
+    id           BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK ( id ),
+    rolled_up_to DATE NOT NULL
+);
+
+-- genre_id is NULL for global charts
+CREATE TABLE IF NOT EXISTS chart_snapshots
+(
+    id          BIGSERIAL PRIMARY KEY,
+    kind        CHART_KIND   NOT NULL,
+    period      CHART_PERIOD NOT NULL,
+    genre_id    INT REFERENCES genres (id) ON DELETE CASCADE,
+    computed_at TIMESTAMPTZ  NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS chart_snapshots_lookup_idx ON chart_snapshots (kind, period, genre_id, computed_at DESC);
+
+-- item_id is a track, album or musician id depending on the snapshot kind
+CREATE TABLE IF NOT EXISTS chart_entries
+(
+    snapshot_id       BIGINT           NOT NULL REFERENCES chart_snapshots (id) ON DELETE CASCADE,
+    position          INT              NOT NULL,
+    item_id           INT              NOT NULL,
+    score             DOUBLE PRECISION NOT NULL,
+    previous_position INT,
+    PRIMARY KEY (snapshot_id, position)
+);
+
+
+-- projection of album, track and merch events, names are joined on read
+CREATE TABLE IF NOT EXISTS feed_items
+(
+    id           BIGSERIAL PRIMARY KEY,
+    kind         FEED_ITEM_KIND NOT NULL,
+    item_id      INT            NOT NULL,
+    musician_id  INT            NOT NULL REFERENCES musicians (id) ON DELETE CASCADE,
+    album_id     INT,
+    published_at TIMESTAMPTZ    NOT NULL,
+    UNIQUE (kind, item_id)
+);
+
+CREATE INDEX IF NOT EXISTS feed_items_musician_idx ON feed_items (musician_id, published_at DESC);
+
+-- one order per musician and currency, items are reserved while pending
+CREATE TABLE IF NOT EXISTS orders
+(
+    id          INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id     INT          REFERENCES users (id) ON DELETE SET NULL,
+    musician_id INT          NOT NULL REFERENCES musicians (id) ON DELETE CASCADE,
+    status      ORDER_STATUS NOT NULL DEFAULT 'pending',
+    -- minor units of currency
+    total       BIGINT       NOT NULL,
+    currency    CHAR(3)      NOT NULL,
+    payment_id  VARCHAR(128) UNIQUE,
+    payment_url TEXT,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CHECK ( total >= 0 )
+);
+
+CREATE INDEX IF NOT EXISTS orders_user_idx ON orders (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS orders_musician_idx ON orders (musician_id, status, created_at DESC);
+-- pending orders are cancelled when they expire
+CREATE INDEX IF NOT EXISTS orders_pending_idx ON orders (created_at) WHERE status = 'pending';
+
+-- a copy of the variant as it was sold
+CREATE TABLE IF NOT EXISTS order_items
+(
+    id         INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    order_id   INT          NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    variant_id INT          REFERENCES merch_variants (id) ON DELETE SET NULL,
+    merch_id   INT          REFERENCES merch (id) ON DELETE SET NULL,
+    name       VARCHAR(254) NOT NULL,
+    sku        VARCHAR(64)  NOT NULL,
+    size       VARCHAR(32)  NOT NULL DEFAULT '',
+    colour     VARCHAR(32)  NOT NULL DEFAULT '',
+    unit_price BIGINT       NOT NULL,
+    quantity   INT          NOT NULL,
+    CHECK ( unit_price >= 0 ),
+    CHECK ( quantity > 0 )
+);
+
+CREATE INDEX IF NOT EXISTS order_items_order_idx ON order_items (order_id);
+
+-- payment provider notifications already applied, makes webhooks idempotent
+-- refund_pending is set while the payment of a cancelled order is being
+-- refunded, the provider is called after the order is unlocked
+CREATE TABLE IF NOT EXISTS payment_events
+(
+    event_id       TEXT PRIMARY KEY,
+    payment_id     VARCHAR(128) NOT NULL,
+    status         VARCHAR(32)  NOT NULL,
+    refund_pending BOOLEAN      NOT NULL DEFAULT FALSE,
+    received_at    TIMESTAMPTZ  NOT NULL DEFAULT now()
 );
 
 
@@ -129,14 +565,76 @@ CREATE TABLE IF NOT EXISTS outbox
 
 CREATE TABLE IF NOT EXISTS track_playlist
 (
-    track_id    INT NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
-    playlist_id INT NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    track_id    INT         NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    playlist_id INT         NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    added_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    added_by    INT REFERENCES users (id) ON DELETE SET NULL,
     PRIMARY KEY (track_id, playlist_id)
 );
 
+CREATE INDEX IF NOT EXISTS track_playlist_added_at_idx ON track_playlist (added_at);
+
 CREATE TABLE IF NOT EXISTS user_track
 (
-    track_id INT NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
-    user_id  INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    track_id INT         NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+    user_id  INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    liked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
     PRIMARY KEY (track_id, user_id)
 );
+
+CREATE INDEX IF NOT EXISTS user_track_liked_at_idx ON user_track (liked_at);
+
+CREATE TABLE IF NOT EXISTS musician_followers
+(
+    user_id     INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    musician_id INT         NOT NULL REFERENCES musicians (id) ON DELETE CASCADE,
+    followed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, musician_id)
+);
+
+CREATE INDEX IF NOT EXISTS musician_followers_musician_idx ON musician_followers (musician_id);
+
+CREATE TABLE IF NOT EXISTS user_album
+(
+    user_id  INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    album_id INT         NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, album_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_playlist
+(
+    user_id     INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    playlist_id INT         NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    added_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, playlist_id)
+);
+
+CREATE TABLE IF NOT EXISTS playlist_collaborators
+(
+    playlist_id INT         NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    user_id     INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    joined_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (playlist_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS playlist_invites
+(
+    token       TEXT PRIMARY KEY,
+    playlist_id INT         NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    created_by  INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS playlist_invites_playlist_idx ON playlist_invites (playlist_id);
+
+CREATE TABLE IF NOT EXISTS cart_items
+(
+    user_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    variant_id INT         NOT NULL REFERENCES merch_variants (id) ON DELETE CASCADE,
+    quantity   INT         NOT NULL,
+    added_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, variant_id),
+    CHECK ( quantity > 0 )
+);
@@ -13,8 +13,21 @@ import (
 	"os"
 	"os/signal"
 	"src/internal/config"
+	postgres12 "src/internal/cron/charts_builder/repository/postgres"
+	usecase14 "src/internal/cron/charts_builder/usecase"
+	usecase12 "src/internal/cron/daily_mix/usecase"
+	usecase18 "src/internal/cron/feed_consumer/usecase"
+	usecase24 "src/internal/cron/media_processor/usecase"
+	usecase26 "src/internal/cron/order_expiry/usecase"
+	delivery9 "src/internal/cron/outbox_producer/delivery"
 	postgres6 "src/internal/cron/outbox_producer/repository/postgres"
 	usecase5 "src/internal/cron/outbox_producer/usecase"
+	postgres10 "src/internal/cron/recsys_builder/repository/postgres"
+	usecase11 "src/internal/cron/recsys_builder/usecase"
+	usecase10 "src/internal/cron/recsys_consumer/usecase"
+	usecase27 "src/internal/cron/source_hashing/usecase"
+	postgres14 "src/internal/cron/stats_rollup/repository/postgres"
+	usecase16 "src/internal/cron/stats_rollup/usecase"
 	delivery2 "src/internal/domain/album/delivery"
 	middleware2 "src/internal/domain/album/middleware"
 	postgres3 "src/internal/domain/album/repository/postgres"
@@ -22,20 +35,50 @@ import (
 	"src/internal/domain/auth/delivery"
 	"src/internal/domain/auth/middleware"
 	"src/internal/domain/auth/usecase"
+	delivery11 "src/internal/domain/charts/delivery"
+	postgres13 "src/internal/domain/charts/repository/postgres"
+	usecase15 "src/internal/domain/charts/usecase"
+	delivery16 "src/internal/domain/concert/delivery"
+	middleware8 "src/internal/domain/concert/middleware"
+	postgres19 "src/internal/domain/concert/repository/postgres"
+	usecase22 "src/internal/domain/concert/usecase"
+	delivery13 "src/internal/domain/feed/delivery"
+	postgres16 "src/internal/domain/feed/repository/postgres"
+	usecase19 "src/internal/domain/feed/usecase"
+	delivery17 "src/internal/domain/genre/delivery"
+	postgres20 "src/internal/domain/genre/repository/postgres"
+	usecase23 "src/internal/domain/genre/usecase"
+	delivery10 "src/internal/domain/history/delivery"
+	postgres11 "src/internal/domain/history/repository/postgres"
+	usecase13 "src/internal/domain/history/usecase"
+	delivery14 "src/internal/domain/library/delivery"
+	postgres17 "src/internal/domain/library/repository/postgres"
+	usecase20 "src/internal/domain/library/usecase"
 	delivery3 "src/internal/domain/merch/delivery"
 	middleware3 "src/internal/domain/merch/middleware"
 	postgres5 "src/internal/domain/merch/repository/postgres"
 	usecase4 "src/internal/domain/merch/usecase"
+	delivery18 "src/internal/domain/moderation/delivery"
+	postgres21 "src/internal/domain/moderation/repository/postgres"
+	usecase25 "src/internal/domain/moderation/usecase"
 	delivery5 "src/internal/domain/musician/delivery"
 	postgres4 "src/internal/domain/musician/repository/postgres"
 	usecase3 "src/internal/domain/musician/usecase"
+	delivery15 "src/internal/domain/order/delivery"
+	"src/internal/domain/order/payment_provider"
+	postgres18 "src/internal/domain/order/repository/postgres"
+	usecase21 "src/internal/domain/order/usecase"
 	delivery6 "src/internal/domain/playlist/delivery"
 	middleware5 "src/internal/domain/playlist/middleware"
 	postgres7 "src/internal/domain/playlist/repository/postgres"
 	usecase6 "src/internal/domain/playlist/usecase"
 	delivery4 "src/internal/domain/recsys/delivery"
 	"src/internal/domain/recsys/recsys_client"
+	postgres9 "src/internal/domain/recsys/repository/postgres"
 	usecase9 "src/internal/domain/recsys/usecase"
+	delivery12 "src/internal/domain/stats/delivery"
+	postgres15 "src/internal/domain/stats/repository/postgres"
+	usecase17 "src/internal/domain/stats/usecase"
 	delivery7 "src/internal/domain/track/delivery"
 	middleware7 "src/internal/domain/track/middleware"
 	"src/internal/domain/track/repository/minio"
@@ -48,8 +91,10 @@ import (
 	jwt2 "src/internal/lib/jwt"
 	"src/internal/lib/kafka"
 	"src/internal/lib/logger/handlers/slogpretty"
+	"sync"
 	"syscall"
 	"time"
+	_ "time/tzdata" // concert time zones are resolved without the system database
 
 	_ "src/docs" // docs is generated by Swag CLI, you have to import it.
 )
@@ -68,8 +113,8 @@ func App() {
 	logger := setupLogger(cfg.Env)
 	logger.Info("Logger init")
 
-	done := make(chan os.Signal, 1)
-	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	dsn := "host=localhost user=postgres password=123 dbname=postgres port=5432"
 	db, err := gorm.Open(postgres2.Open(dsn), &gorm.Config{})
@@ -86,8 +131,6 @@ func App() {
 		logger.Error(err.Error())
 	}
 
-	ctx := context.Background()
-
 	err = client.MakeBucket(ctx, minio.TrackBucket, minio2.MakeBucketOptions{})
 	if err != nil {
 		logger.Error(err.Error())
@@ -100,6 +143,22 @@ func App() {
 		logger.Error(err.Error())
 	}
 
+	// consumers are not started without the broker, the rest keeps working
+	consumerGroup, err := kafka.NewConsumerGroup("localhost:29092", kafka.RecSysGroup)
+	if err != nil {
+		logger.Error("recsys consumer is not started", slog.String("error", err.Error()))
+	}
+
+	feedConsumerGroup, err := kafka.NewConsumerGroup("localhost:29092", kafka.FeedGroup)
+	if err != nil {
+		logger.Error("feed consumer is not started", slog.String("error", err.Error()))
+	}
+
+	mediaConsumerGroup, err := kafka.NewConsumerGroup("localhost:29092", kafka.MediaGroup)
+	if err != nil {
+		logger.Error("media processor is not started", slog.String("error", err.Error()))
+	}
+
 	userRep := postgres.NewUserRepository(db)
 	albumRep := postgres3.NewAlbumRepository(db)
 	trackStorage := minio.NewTrackStorage(client)
@@ -107,7 +166,29 @@ func App() {
 	merchRep := postgres5.NewMerchRepository(db)
 	playlistRep := postgres7.NewPlaylistRepository(db)
 	trackRep := postgres8.NewTrackRepository(db)
-	recSysClient := recsys_client.NewRecSysClient("http://127.0.0.1:12121/rec")
+	recSysRep := postgres9.NewRecSysRepository(db)
+	recSysBuilderRep := postgres10.NewRecSysBuilderRepo(db)
+	historyRep := postgres11.NewHistoryRepository(db)
+	chartsBuilderRep := postgres12.NewChartsBuilderRepo(db)
+	chartsRep := postgres13.NewChartsRepository(db)
+	statsRollupRep := postgres14.NewStatsRollupRepo(db)
+	statsRep := postgres15.NewStatsRepository(db)
+	feedRep := postgres16.NewFeedRepository(db)
+	libraryRep := postgres17.NewLibraryRepository(db)
+	orderRep := postgres18.NewOrderRepository(db)
+	concertRep := postgres19.NewConcertRepository(db)
+	genreRep := postgres20.NewGenreRepository(db)
+	moderationRep := postgres21.NewModerationRepository(db)
+
+	var recSysClient recsys_client.RecSysProvider
+	if cfg.RecSys.Mode == config.RecSysModeLocal {
+		recSysClient = recsys_client.NewLocalRecSys(recSysRep)
+	} else {
+		recSysClient = recsys_client.NewRecSysClient(cfg.RecSys)
+	}
+
+	// payments mode is validated on load, fake is the only one for now
+	fakePayments := payment_provider.NewFakeProvider(cfg.Payments)
 
 	outboxRep := postgres6.NewOutboxRepo(db)
 
@@ -119,20 +200,102 @@ func App() {
 	playlistUseCase := usecase6.NewPlaylistUseCase(playlistRep, trackRep)
 	userUseCase := usecase7.NewUserUseCase(userRep, trackRep, encryptor)
 	trackUseCase := usecase8.NewTrackUseCase(trackRep, trackStorage)
-	outbox := usecase5.NewOutboxUseCase(producer, outboxRep)
-	recSysUseCase := usecase9.NewRecSysUseCase(recSysClient, trackRep)
+	outbox := usecase5.NewOutboxUseCase(producer, outboxRep, cfg.Outbox)
+	recSysUseCase := usecase9.NewRecSysUseCase(recSysClient, recSysRep, trackRep, playlistRep)
+	recSysConsumer := usecase10.NewRecSysConsumer(consumerGroup, recSysRep, logger)
+	recSysBuilder := usecase11.NewRecSysBuilder(recSysBuilderRep, cfg.RecSys)
+	dailyMixGenerator := usecase12.NewDailyMixGenerator(recSysRep, recSysUseCase, cfg.RecSys)
+	historyUseCase := usecase13.NewHistoryUseCase(historyRep, trackRep, cfg.History)
+	chartsBuilder := usecase14.NewChartsBuilder(chartsBuilderRep, cfg.Charts)
+	chartsUseCase := usecase15.NewChartsUseCase(chartsRep)
+	statsRollup := usecase16.NewStatsRollup(statsRollupRep, cfg.Stats)
+	statsUseCase := usecase17.NewStatsUseCase(statsRep)
+	feedConsumer := usecase18.NewFeedConsumer(feedConsumerGroup, feedRep, logger)
+	feedUseCase := usecase19.NewFeedUseCase(feedRep)
+	libraryUseCase := usecase20.NewLibraryUseCase(libraryRep)
+	orderUseCase := usecase21.NewOrderUseCase(orderRep, fakePayments)
+	concertUseCase := usecase22.NewConcertUseCase(concertRep)
+	genreUseCase := usecase23.NewGenreUseCase(genreRep)
+	mediaProcessor := usecase24.NewMediaProcessor(mediaConsumerGroup, trackRep, trackStorage, moderationRep, cfg.Media, logger)
+	moderationUseCase := usecase25.NewModerationUseCase(moderationRep)
+	orderExpiry := usecase26.NewOrderExpiry(orderRep, cfg.Payments)
+	sourceHashing := usecase27.NewSourceHashing(trackRep, trackStorage, cfg.Media)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		outbox.Run(ctx, logger)
+	}()
+
+	if consumerGroup != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recSysConsumer.Run(ctx)
+		}()
+	}
+
+	if feedConsumerGroup != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			feedConsumer.Run(ctx)
+		}()
+	}
+
+	if mediaConsumerGroup != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mediaProcessor.Run(ctx)
+		}()
+	}
+
+	if cfg.RecSys.Mode == config.RecSysModeLocal {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recSysBuilder.Run(ctx, logger)
+		}()
+	}
 
+	wg.Add(1)
 	go func() {
-		for {
-			select {
-			case <-done:
-				logger.Info("stopping cron")
-				return
-			default:
-				outbox.ProduceMessages()
-				time.Sleep(10 * time.Second)
-			}
-		}
+		defer wg.Done()
+		dailyMixGenerator.Run(ctx, logger)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		historyUseCase.Run(ctx, logger)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		chartsBuilder.Run(ctx, logger)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		statsRollup.Run(ctx, logger)
+	}()
+
+	if cfg.Payments.Enabled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			orderExpiry.Run(ctx, logger)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sourceHashing.Run(ctx, logger)
 	}()
 
 	musicianMiddleware := (func(h http.Handler) http.Handler {
@@ -163,10 +326,22 @@ func App() {
 		return middleware5.CheckPlaylistOwnership(h, playlistUseCase)
 	})
 
+	checkCanReadPlaylist := (func(h http.Handler) http.Handler {
+		return middleware5.CheckPlaylistReadAccess(h, playlistUseCase)
+	})
+
+	checkCanEditPlaylistTracks := (func(h http.Handler) http.Handler {
+		return middleware5.CheckPlaylistTracksEditAccess(h, playlistUseCase)
+	})
+
 	checkIsMerchRelated := (func(h http.Handler) http.Handler {
 		return middleware3.CheckMerchOwnership(h, merchUseCase, musicianUseCase)
 	})
 
+	checkIsConcertRelated := (func(h http.Handler) http.Handler {
+		return middleware8.CheckConcertOwnership(h, concertUseCase, musicianUseCase)
+	})
+
 	checkIsTrackRelated := (func(h http.Handler) http.Handler {
 		return middleware7.CheckTrackOwnership(h, albumUseCase, musicianUseCase)
 	})
@@ -188,6 +363,13 @@ func App() {
 		r.Use(musicianMiddleware)
 		r.With(checkForMusicianId).Post("/api/musician/{musician_id}/album", delivery2.AddAlbumWithTracks(albumUseCase))
 
+		r.Group(func(r chi.Router) {
+			r.Use(checkForMusicianId)
+			r.Get("/api/musician/{musician_id}/credits", delivery2.GetArtistCredits(albumUseCase))
+			r.Post("/api/musician/{musician_id}/credits/{id}/approve", delivery2.ApproveArtistCredit(albumUseCase))
+			r.Delete("/api/musician/{musician_id}/credits/{id}", delivery2.DeclineArtistCredit(albumUseCase))
+		})
+
 		r.Group(func(r chi.Router) {
 			r.Use(checkIsAlbumRelated)
 			r.Post("/api/album/{id}/tracks", delivery2.CreateTrack(albumUseCase))
@@ -208,11 +390,54 @@ func App() {
 		})
 	})
 
+	// concerts
+	router.Group(func(r chi.Router) {
+		r.Use(musicianMiddleware)
+		r.With(checkForMusicianId).Post("/api/musician/{musician_id}/concerts", delivery16.ConcertCreate(concertUseCase))
+
+		r.Group(func(r chi.Router) {
+			r.Use(checkIsConcertRelated)
+			r.Delete("/api/concert/{id}", delivery16.DeleteConcert(concertUseCase))
+			r.Put("/api/concert/{id}", delivery16.UpdateConcert(concertUseCase))
+		})
+	})
+
+	if cfg.Payments.Enabled {
+		// orders
+		router.Group(func(r chi.Router) {
+			r.Use(userMiddleware)
+			r.Use(checkForUserId)
+			r.Get("/api/user/{user_id}/cart", delivery15.GetCart(orderUseCase))
+			r.Put("/api/user/{user_id}/cart/{variant_id}", delivery15.SetCartItem(orderUseCase))
+			r.Delete("/api/user/{user_id}/cart/{variant_id}", delivery15.RemoveCartItem(orderUseCase))
+			r.Post("/api/user/{user_id}/checkout", delivery15.Checkout(orderUseCase))
+			r.Get("/api/user/{user_id}/orders", delivery15.GetUserOrders(orderUseCase))
+			r.Get("/api/user/{user_id}/orders/{order_id}", delivery15.GetUserOrder(orderUseCase))
+			r.Post("/api/user/{user_id}/orders/{order_id}/cancel", delivery15.CancelOrder(orderUseCase))
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(musicianMiddleware)
+			r.Use(checkForMusicianId)
+			r.Get("/api/musician/{musician_id}/orders", delivery15.GetMusicianOrders(orderUseCase))
+			r.Get("/api/musician/{musician_id}/orders/{order_id}", delivery15.GetMusicianOrder(orderUseCase))
+			r.Put("/api/musician/{musician_id}/orders/{order_id}/status", delivery15.UpdateOrderStatus(orderUseCase))
+		})
+
+		// payment provider callbacks, authenticated by signature
+		router.Post("/api/payments/webhook", delivery15.PaymentWebhook(orderUseCase))
+		if cfg.Payments.FakePayEndpoint {
+			// anyone can pay their orders here, development only
+			router.Post("/api/payments/fake/{payment_id}", delivery15.FakePay(fakePayments, orderUseCase))
+		}
+	}
+
 	// musician
 	router.Group(func(r chi.Router) {
 		r.Use(musicianMiddleware)
 		r.With(checkForMusicianId).Put("/api/musician/{musician_id}", delivery5.UpdateMusician(musicianUseCase))
 		r.With(checkForMusicianId).Delete("/api/musician/{musician_id}", delivery5.DeleteMusician(musicianUseCase))
+		r.With(checkForMusicianId).Get("/api/musician/{musician_id}/stats", delivery12.GetMusicianStats(statsUseCase))
 	})
 
 	// playlist
@@ -222,10 +447,18 @@ func App() {
 
 		r.With(checkForUserId).Post("/api/user/{user_id}/playlist", delivery6.PlaylistCreate(playlistUseCase))
 		r.With(checkForUserId).Get("/api/user/{user_id}/playlist", delivery6.GetAllPlaylists(playlistUseCase))
+		r.With(checkForUserId).Post("/api/user/{user_id}/playlist/import", delivery6.ImportPlaylist(playlistUseCase))
+		r.Post("/api/playlist/invites/{token}", delivery6.AcceptInvite(playlistUseCase))
 		r.Group(func(r chi.Router) {
 			r.Use(checkIsPlaylistRelated)
 			r.Put("/api/playlist/{id}", delivery6.UpdatePlaylist(playlistUseCase))
 			r.Delete("/api/playlist/{id}", delivery6.DeletePlaylist(playlistUseCase))
+			r.Put("/api/playlist/{id}/sharing", delivery6.UpdateSharing(playlistUseCase))
+			r.Post("/api/playlist/{id}/invites", delivery6.CreateInvite(playlistUseCase))
+			r.Delete("/api/playlist/{id}/collaborators/{user_id}", delivery6.RemoveCollaborator(playlistUseCase))
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(checkCanEditPlaylistTracks)
 			r.Post("/api/playlist/{id}/track", delivery6.AddTrack(playlistUseCase))
 			r.Delete("/api/playlist/{id}/track/{track_id}", delivery6.DeleteTrack(playlistUseCase))
 		})
@@ -237,6 +470,8 @@ func App() {
 		r.Use(checkIsTrackRelated)
 		r.Put("/api/track/{id}", delivery7.UpdateTrack(trackUseCase))
 		r.Delete("/api/track/{id}", delivery2.DeleteTrack(albumUseCase))
+		r.Put("/api/track/{id}/lyrics", delivery7.SetLyrics(trackUseCase))
+		r.Delete("/api/track/{id}/lyrics/{language}", delivery7.DeleteLyrics(trackUseCase))
 	})
 
 	// User
@@ -253,6 +488,13 @@ func App() {
 	router.Group(func(r chi.Router) {
 		r.Use(adminMiddleware)
 		r.Post("/api/musician", delivery5.CreateMusician(musicianUseCase))
+		r.Get("/api/admin/outbox/stats", delivery9.GetOutboxStats(outbox))
+		r.Post("/api/admin/outbox/replay", delivery9.ReplayOutbox(outbox))
+		r.Post("/api/genres", delivery17.AddGenre(genreUseCase))
+		r.Put("/api/genres/{id}", delivery17.UpdateGenre(genreUseCase))
+		r.Delete("/api/genres/{id}", delivery17.DeleteGenre(genreUseCase))
+		r.Get("/api/admin/moderation/duplicates", delivery18.GetDuplicateReports(moderationUseCase))
+		r.Put("/api/admin/moderation/duplicates/{id}", delivery18.ResolveDuplicateReport(moderationUseCase))
 	})
 
 	// Likes
@@ -263,27 +505,67 @@ func App() {
 		r.Delete("/api/user/{user_id}/favorite", delivery8.Dislike(userUseCase))
 		r.Get("/api/user/{user_id}/favorite", delivery8.GetAllLiked(userUseCase))
 		r.Get("/api/user/{user_id}/favorite/{track_id}", delivery8.IsLiked(userUseCase))
+		r.Get("/api/user/{user_id}/feed", delivery13.GetFeed(feedUseCase))
+		r.Post("/api/user/{user_id}/library/{kind}", delivery14.AddToLibrary(libraryUseCase))
+		r.Get("/api/user/{user_id}/library/{kind}", delivery14.GetLibrary(libraryUseCase))
+		r.Get("/api/user/{user_id}/library/{kind}/contains", delivery14.Contains(libraryUseCase))
+		r.Delete("/api/user/{user_id}/library/{kind}/{item_id}", delivery14.RemoveFromLibrary(libraryUseCase))
+	})
+
+	// Recommendations
+	router.Group(func(r chi.Router) {
+		r.Use(userMiddleware)
+		r.Use(checkForUserId)
+		r.Get("/api/user/{user_id}/recommendations", delivery4.GetUserRecommendations(recSysUseCase))
+	})
+
+	// Listening history
+	router.Group(func(r chi.Router) {
+		r.Use(userMiddleware)
+		r.Post("/api/track/{id}/plays", delivery10.RecordPlay(historyUseCase))
+		r.Post("/api/musician/{musician_id}/follow", delivery8.Follow(userUseCase))
+		r.Delete("/api/musician/{musician_id}/follow", delivery8.Unfollow(userUseCase))
+		r.With(checkForUserId).Get("/api/user/{user_id}/history", delivery10.GetHistory(historyUseCase))
 	})
 
 	// Other opened requests
 	router.Group(func(r chi.Router) {
 		r.Use(basicAuthMiddleware)
 		r.Get("/api/track/genres", delivery7.GetGenres(trackUseCase))
+		r.Get("/api/genres", delivery17.GetGenresTree(genreUseCase))
+		r.Get("/api/genres/{id}", delivery17.GetGenre(genreUseCase))
 		r.Get("/api/track", delivery7.FindTracks(trackUseCase))
 		r.Get("/api/merch", delivery3.FindMerch(merchUseCase))
 		r.Get("/api/track/recs", delivery4.GetRecommendedTracks(recSysUseCase))
+		r.Get("/api/track/trending", delivery4.GetTrendingTracks(recSysUseCase))
+		r.Get("/api/charts/{period}", delivery11.GetChart(chartsUseCase))
 		r.Get("/api/track/{id}", delivery7.GetTrack(trackUseCase))
-		r.Get("/api/playlist/{playlist_id}/track", delivery6.GetAllTracksForPlaylist(playlistUseCase))
-		r.Get("/api/playlist/{id}", delivery6.GetPlaylist(playlistUseCase))
+		r.Get("/api/track/{id}/lyrics", delivery7.GetLyrics(trackUseCase))
+		r.With(checkCanReadPlaylist).Get("/api/playlist/{playlist_id}/track", delivery6.GetAllTracksForPlaylist(playlistUseCase))
+		r.With(checkCanReadPlaylist).Get("/api/playlist/{id}", delivery6.GetPlaylist(playlistUseCase))
+		r.With(checkCanReadPlaylist).Get("/api/playlist/{id}/collaborators", delivery6.GetCollaborators(playlistUseCase))
+		r.With(checkCanReadPlaylist).Get("/api/playlist/{id}/export", delivery6.ExportPlaylist(playlistUseCase))
 		r.Get("/api/musician/{musician_id}", delivery5.GetMusician(musicianUseCase))
 		r.Get("/api/album/{id}/tracks", delivery2.GetAllTracks(albumUseCase))
 		r.Get("/api/musician/{musician_id}/merch", delivery3.GetAllMerchForMusician(merchUseCase))
 		r.Get("/api/album/{id}", delivery2.GetAlbum(albumUseCase))
 		r.Get("/api/merch/{id}", delivery3.GetMerch(merchUseCase))
+		r.Get("/api/merch/{id}/order", delivery3.ClickMerch(merchUseCase))
+		r.Get("/api/concerts/nearby", delivery16.FindConcertsNearby(concertUseCase))
+		r.Get("/api/concert/{id}", delivery16.GetConcert(concertUseCase))
+		r.Get("/api/concert/{id}/ics", delivery16.ExportConcert(concertUseCase))
+		r.Get("/api/musician/{musician_id}/concerts", delivery16.GetUpcomingConcerts(concertUseCase))
+		r.Get("/api/musician/{musician_id}/concerts/ics", delivery16.ExportUpcomingConcerts(concertUseCase))
 		r.Get("/api/get-me", delivery8.GetMe(musicianUseCase))
 		r.Get("/api/musician/{musician_id}/album", delivery2.GetAllAlbumForMusician(albumUseCase))
 	})
 
+	// Previews are opened without authorization, they never expose full tracks
+	router.Group(func(r chi.Router) {
+		r.Get("/api/track/{id}/waveform", delivery7.GetWaveform(trackUseCase))
+		r.Get("/api/track/{id}/preview", delivery7.GetPreview(trackUseCase))
+	})
+
 	// Swagger
 	router.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/swagger/doc.json"), //The url pointing to API definition
@@ -299,16 +581,40 @@ func App() {
 		}
 	}()
 
-	<-done
+	<-ctx.Done()
 
 	logger.Info("stopping server")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
+	if err := srv.Shutdown(shutdownCtx); err != nil {
 		logger.Error("failed to stop server")
-		return
+	}
+
+	if consumerGroup != nil {
+		if err := consumerGroup.Close(); err != nil {
+			logger.Error("failed to close consumer group")
+		}
+	}
+
+	if feedConsumerGroup != nil {
+		if err := feedConsumerGroup.Close(); err != nil {
+			logger.Error("failed to close feed consumer group")
+		}
+	}
+
+	if mediaConsumerGroup != nil {
+		if err := mediaConsumerGroup.Close(); err != nil {
+			logger.Error("failed to close media consumer group")
+		}
+	}
+
+	wg.Wait()
+
+	// plays recorded while in-flight requests were drained
+	if err := historyUseCase.Flush(); err != nil {
+		logger.Error("failed to flush history", slog.String("error", err.Error()))
 	}
 
 	logger.Info("server stopped")
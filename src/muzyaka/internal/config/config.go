@@ -11,6 +11,13 @@ type Config struct {
 	Env         string `yaml:"env" env-default:"local"`
 	StoragePath string `yaml:"storage_path" env-required:"true"`
 	HTTPServer  `yaml:"http_server"`
+	Outbox      `yaml:"outbox"`
+	RecSys      `yaml:"recsys"`
+	History     `yaml:"history"`
+	Charts      `yaml:"charts"`
+	Stats       `yaml:"stats"`
+	Payments    `yaml:"payments"`
+	Media       `yaml:"media"`
 }
 
 type HTTPServer struct {
@@ -19,6 +26,103 @@ type HTTPServer struct {
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 }
 
+type Outbox struct {
+	Interval        time.Duration `yaml:"interval" env-default:"10s"`
+	BatchSize       int           `yaml:"batch_size" env-default:"100"`
+	MaxAttempts     int           `yaml:"max_attempts" env-default:"10"`
+	BaseBackoff     time.Duration `yaml:"base_backoff" env-default:"1s"`
+	MaxBackoff      time.Duration `yaml:"max_backoff" env-default:"10m"`
+	Retention       time.Duration `yaml:"retention" env-default:"168h"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env-default:"1h"`
+}
+
+const (
+	RecSysModeLocal  = "local"
+	RecSysModeRemote = "remote"
+)
+
+type RecSys struct {
+	Mode             string        `yaml:"mode" env-default:"remote"`
+	Address          string        `yaml:"address" env-default:"http://127.0.0.1:12121/rec"`
+	Timeout          time.Duration `yaml:"timeout" env-default:"2s"`
+	Retries          int           `yaml:"retries" env-default:"2"`
+	RetryBaseDelay   time.Duration `yaml:"retry_base_delay" env-default:"100ms"`
+	BreakerThreshold int           `yaml:"breaker_threshold" env-default:"5"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown" env-default:"30s"`
+	// used in local mode only
+	TopK            int           `yaml:"top_k" env-default:"50"`
+	RebuildInterval time.Duration `yaml:"rebuild_interval" env-default:"1h"`
+
+	DailyMixInterval time.Duration `yaml:"daily_mix_interval" env-default:"24h"`
+}
+
+type History struct {
+	BatchSize     int           `yaml:"batch_size" env-default:"500"`
+	FlushInterval time.Duration `yaml:"flush_interval" env-default:"1s"`
+	MaxBuffered   int           `yaml:"max_buffered" env-default:"50000"`
+	// MaxAttempts of writing a batch, then its plays are written one by one
+	// and the ones the database rejects are dropped
+	MaxAttempts int `yaml:"max_attempts" env-default:"5"`
+}
+
+type Charts struct {
+	Interval time.Duration `yaml:"interval" env-default:"24h"`
+	Size     int           `yaml:"size" env-default:"100"`
+}
+
+type Stats struct {
+	RollupInterval time.Duration `yaml:"rollup_interval" env-default:"1h"`
+	// RollupDays is how many last days are recomputed, older days are final
+	RollupDays int `yaml:"rollup_days" env-default:"2"`
+}
+
+const (
+	PaymentsModeFake = "fake"
+
+	// exampleWebhookSecret is the secret of the docs, it is never accepted
+	exampleWebhookSecret = "secret"
+)
+
+type Payments struct {
+	// Enabled mounts cart, order and payment routes and runs the order expiry
+	Enabled bool `yaml:"enabled" env:"PAYMENTS_ENABLED" env-default:"false"`
+	// Mode is fake only for now, nothing is charged
+	Mode string `yaml:"mode" env-default:"fake"`
+	// WebhookSecret signs payment notifications with HMAC-SHA256, required
+	// when payments are enabled
+	WebhookSecret string `yaml:"webhook_secret" env:"PAYMENTS_WEBHOOK_SECRET"`
+	// PaymentUrl is where users are sent to pay, the payment id is appended
+	PaymentUrl string `yaml:"payment_url" env-default:"/api/payments/fake/"`
+	// FakePayEndpoint mounts the endpoint completing fake payments without
+	// authentication, for development and tests only
+	FakePayEndpoint bool `yaml:"fake_pay_endpoint" env-default:"false"`
+	// PendingTtl is how long an order is reserved for before it is cancelled
+	PendingTtl     time.Duration `yaml:"pending_ttl" env-default:"1h"`
+	ExpiryInterval time.Duration `yaml:"expiry_interval" env-default:"5m"`
+}
+
+var defaultWaveformPoints = []int{100, 500, 2000}
+
+type Media struct {
+	// the preview is taken from the first half of tracks shorter than twice its length
+	PreviewOffset time.Duration `yaml:"preview_offset" env-default:"30s"`
+	PreviewLength time.Duration `yaml:"preview_length" env-default:"30s"`
+	// WaveformPoints are resolutions of generated waveforms, the default ones if empty
+	WaveformPoints []int `yaml:"waveform_points" env-default:"100,500,2000"`
+	// MaxDuration is the longest source that is decoded, longer ones are
+	// skipped as invalid
+	MaxDuration time.Duration `yaml:"max_duration" env-default:"1h"`
+	// MaxAttempts of processing an event, it is skipped after that, so one
+	// broken track doesn't block its partition
+	MaxAttempts int `yaml:"max_attempts" env-default:"10"`
+	// tracks of different musicians with fingerprints at least this similar
+	// are reported, unrelated tracks are about 0.5 similar
+	DuplicateSimilarity float64 `yaml:"duplicate_similarity" env-default:"0.8"`
+	// HashInterval is how often sources of tracks uploaded before sources were
+	// hashed are looked for
+	HashInterval time.Duration `yaml:"hash_interval" env-default:"1h"`
+}
+
 func MustLoad() *Config {
 	configPath := fetchConfigPath()
 	if configPath == "" {
@@ -40,6 +144,52 @@ func MustLoadPath(configPath string) *Config {
 		panic("cannot read config: " + err.Error())
 	}
 
+	if cfg.RecSys.Mode != RecSysModeLocal && cfg.RecSys.Mode != RecSysModeRemote {
+		panic("invalid recsys mode: " + cfg.RecSys.Mode)
+	}
+
+	if cfg.Payments.Mode != PaymentsModeFake {
+		panic("invalid payments mode: " + cfg.Payments.Mode)
+	}
+
+	if cfg.Payments.Enabled && (cfg.Payments.WebhookSecret == "" || cfg.Payments.WebhookSecret == exampleWebhookSecret) {
+		panic("webhook secret is empty or the example one")
+	}
+
+	if cfg.Payments.PendingTtl <= 0 || cfg.Payments.ExpiryInterval <= 0 {
+		panic("invalid pending order expiry")
+	}
+
+	if cfg.Payments.FakePayEndpoint && cfg.Env == "prod" {
+		panic("fake pay endpoint is not allowed in prod")
+	}
+
+	if len(cfg.Media.WaveformPoints) == 0 {
+		cfg.Media.WaveformPoints = defaultWaveformPoints
+	}
+
+	for _, v := range cfg.Media.WaveformPoints {
+		if v <= 0 {
+			panic("invalid waveform points")
+		}
+	}
+
+	if cfg.Media.MaxDuration <= 0 {
+		panic("invalid media max duration")
+	}
+
+	if cfg.Media.MaxAttempts <= 0 {
+		panic("invalid media max attempts")
+	}
+
+	if cfg.Media.DuplicateSimilarity <= 0.5 || cfg.Media.DuplicateSimilarity > 1 {
+		panic("invalid duplicate similarity")
+	}
+
+	if cfg.Media.HashInterval <= 0 {
+		panic("invalid media hash interval")
+	}
+
 	return &cfg
 }
 
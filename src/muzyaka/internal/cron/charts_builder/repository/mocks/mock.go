@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChartsBuilderRepository is a mock of ChartsBuilderRepository interface.
+type MockChartsBuilderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockChartsBuilderRepositoryMockRecorder
+}
+
+// MockChartsBuilderRepositoryMockRecorder is the mock recorder for MockChartsBuilderRepository.
+type MockChartsBuilderRepositoryMockRecorder struct {
+	mock *MockChartsBuilderRepository
+}
+
+// NewMockChartsBuilderRepository creates a new mock instance.
+func NewMockChartsBuilderRepository(ctrl *gomock.Controller) *MockChartsBuilderRepository {
+	mock := &MockChartsBuilderRepository{ctrl: ctrl}
+	mock.recorder = &MockChartsBuilderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChartsBuilderRepository) EXPECT() *MockChartsBuilderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetGenreIds mocks base method.
+func (m *MockChartsBuilderRepository) GetGenreIds() ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenreIds")
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenreIds indicates an expected call of GetGenreIds.
+func (mr *MockChartsBuilderRepositoryMockRecorder) GetGenreIds() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreIds", reflect.TypeOf((*MockChartsBuilderRepository)(nil).GetGenreIds))
+}
+
+// GetLastComputedAt mocks base method.
+func (m *MockChartsBuilderRepository) GetLastComputedAt() (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastComputedAt")
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastComputedAt indicates an expected call of GetLastComputedAt.
+func (mr *MockChartsBuilderRepositoryMockRecorder) GetLastComputedAt() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastComputedAt", reflect.TypeOf((*MockChartsBuilderRepository)(nil).GetLastComputedAt))
+}
+
+// GetTrackSignals mocks base method.
+func (m *MockChartsBuilderRepository) GetTrackSignals(since time.Time) ([]*models.TrackSignals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrackSignals", since)
+	ret0, _ := ret[0].([]*models.TrackSignals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrackSignals indicates an expected call of GetTrackSignals.
+func (mr *MockChartsBuilderRepositoryMockRecorder) GetTrackSignals(since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrackSignals", reflect.TypeOf((*MockChartsBuilderRepository)(nil).GetTrackSignals), since)
+}
+
+// SaveCharts mocks base method.
+func (m *MockChartsBuilderRepository) SaveCharts(charts []*models.Chart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCharts", charts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCharts indicates an expected call of SaveCharts.
+func (mr *MockChartsBuilderRepositoryMockRecorder) SaveCharts(charts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCharts", reflect.TypeOf((*MockChartsBuilderRepository)(nil).SaveCharts), charts)
+}
@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/cron/charts_builder/repository"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"time"
+)
+
+const insertBatchSize = 500
+
+type chartsBuilderRepo struct {
+	db *gorm.DB
+}
+
+func NewChartsBuilderRepo(db *gorm.DB) repository.ChartsBuilderRepository {
+	return &chartsBuilderRepo{db: db}
+}
+
+// signalsQuery sums signals of every track since the given time
+const signalsQuery = `
+		signals AS (SELECT track_id,
+		                   count(*) FILTER (WHERE completed)     AS plays,
+		                   count(*) FILTER (WHERE NOT completed) AS skips,
+		                   0                                     AS likes,
+		                   0                                     AS playlist_adds
+		            FROM plays
+		            WHERE played_at >= @since
+		            GROUP BY track_id
+		            UNION ALL
+		            SELECT track_id, 0, 0, count(*), 0
+		            FROM user_track
+		            WHERE liked_at >= @since
+		            GROUP BY track_id
+		            UNION ALL
+		            SELECT tp.track_id, 0, 0, 0, count(*)
+		            FROM track_playlist tp
+		            JOIN playlists p ON p.id = tp.playlist_id
+		            WHERE tp.added_at >= @since AND p.kind = @kind
+		            GROUP BY tp.track_id)`
+
+type signalsRow struct {
+	TrackId      uint64
+	AlbumId      uint64
+	MusicianId   uint64
+	Plays        int64
+	Skips        int64
+	Likes        int64
+	PlaylistAdds int64
+}
+
+type trackGenreRow struct {
+	TrackId uint64
+	GenreId uint64
+}
+
+func (c chartsBuilderRepo) GetTrackSignals(since time.Time) ([]*models.TrackSignals, error) {
+	var rows []*signalsRow
+
+	args := []interface{}{sql.Named("since", since), sql.Named("kind", models.PlaylistKindUser)}
+
+	tx := c.db.Raw(`
+		WITH`+signalsQuery+`
+		SELECT t.id                    AS track_id,
+		       t.album_id              AS album_id,
+		       a.musician_id           AS musician_id,
+		       sum(s.plays)            AS plays,
+		       sum(s.skips)            AS skips,
+		       sum(s.likes)            AS likes,
+		       sum(s.playlist_adds)    AS playlist_adds
+		FROM signals s
+		JOIN tracks t ON t.id = s.track_id
+		JOIN albums a ON a.id = t.album_id
+		GROUP BY t.id, t.album_id, a.musician_id`, args...).Scan(&rows)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table plays)")
+	}
+
+	// a track counts in charts of its primary genre, its other genres, genres
+	// of its album and all their parent genres, the same subtrees track search
+	// matches
+	var genres []*trackGenreRow
+	tx = c.db.Raw(`
+		WITH RECURSIVE`+signalsQuery+`,
+		ancestors AS (SELECT id AS genre_id, id AS ancestor_id
+		              FROM genres
+		              UNION
+		              SELECT a.genre_id, g.parent_id
+		              FROM ancestors a
+		              JOIN genres g ON g.id = a.ancestor_id
+		              WHERE g.parent_id IS NOT NULL),
+		track_genre AS (SELECT t.id AS track_id, t.genre AS genre_id
+		                FROM tracks t
+		                WHERE t.id IN (SELECT track_id FROM signals) AND t.genre IS NOT NULL
+		                UNION
+		                SELECT tg.track_id, tg.genre_id
+		                FROM track_genres tg
+		                WHERE tg.track_id IN (SELECT track_id FROM signals)
+		                UNION
+		                SELECT t.id, ag.genre_id
+		                FROM tracks t
+		                JOIN album_genres ag ON ag.album_id = t.album_id
+		                WHERE t.id IN (SELECT track_id FROM signals))
+		SELECT DISTINCT tg.track_id, a.ancestor_id AS genre_id
+		FROM track_genre tg
+		JOIN ancestors a ON a.genre_id = tg.genre_id
+		ORDER BY tg.track_id, genre_id`, args...).Scan(&genres)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table genres)")
+	}
+
+	byTrack := make(map[uint64][]uint64, len(rows))
+	for _, v := range genres {
+		byTrack[v.TrackId] = append(byTrack[v.TrackId], v.GenreId)
+	}
+
+	signals := make([]*models.TrackSignals, 0, len(rows))
+	for _, v := range rows {
+		signals = append(signals, &models.TrackSignals{
+			TrackId:      v.TrackId,
+			GenreIds:     byTrack[v.TrackId],
+			AlbumId:      v.AlbumId,
+			MusicianId:   v.MusicianId,
+			Plays:        v.Plays,
+			Skips:        v.Skips,
+			Likes:        v.Likes,
+			PlaylistAdds: v.PlaylistAdds,
+		})
+	}
+
+	return signals, nil
+}
+
+func (c chartsBuilderRepo) GetGenreIds() ([]uint64, error) {
+	var ids []uint64
+	tx := c.db.Model(&dao.Genre{}).Order("id").Pluck("id", &ids)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table genres)")
+	}
+
+	return ids, nil
+}
+
+func (c chartsBuilderRepo) GetLastComputedAt() (*time.Time, error) {
+	var last []time.Time
+	tx := c.db.Model(&dao.ChartSnapshot{}).Order("computed_at DESC").Limit(1).Pluck("computed_at", &last)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table chart_snapshots)")
+	}
+
+	if len(last) == 0 {
+		return nil, nil
+	}
+
+	return &last[0], nil
+}
+
+func (c chartsBuilderRepo) SaveCharts(charts []*models.Chart) error {
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		for _, chart := range charts {
+			previous, err := previousPositions(tx, chart)
+			if err != nil {
+				return err
+			}
+
+			snapshot := dao.ToPostgresChartSnapshot(chart)
+			if err := tx.Create(snapshot).Error; err != nil {
+				return err
+			}
+			chart.Id = snapshot.ID
+
+			if len(chart.Entries) == 0 {
+				continue
+			}
+
+			entries := make([]*dao.ChartEntry, 0, len(chart.Entries))
+			for _, v := range chart.Entries {
+				if position, ok := previous[v.ItemId]; ok {
+					v.PreviousPosition = &position
+				}
+
+				entries = append(entries, &dao.ChartEntry{
+					SnapshotId:       snapshot.ID,
+					Position:         v.Position,
+					ItemId:           v.ItemId,
+					Score:            v.Score,
+					PreviousPosition: v.PreviousPosition,
+				})
+			}
+
+			if err := tx.CreateInBatches(entries, insertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table chart_snapshots)")
+	}
+
+	return nil
+}
+
+// previousPositions returns positions of items in the latest snapshot of the same chart
+func previousPositions(tx *gorm.DB, chart *models.Chart) (map[uint64]int, error) {
+	query := tx.Where("kind = ? AND period = ?", chart.Kind, chart.Period)
+	if chart.GenreId == nil {
+		query = query.Where("genre_id IS NULL")
+	} else {
+		query = query.Where("genre_id = ?", *chart.GenreId)
+	}
+
+	var snapshots []*dao.ChartSnapshot
+	if err := query.Order("computed_at DESC").Limit(1).Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	positions := make(map[uint64]int)
+	if len(snapshots) == 0 {
+		return positions, nil
+	}
+
+	var entries []*dao.ChartEntry
+	if err := tx.Where("snapshot_id = ?", snapshots[0].ID).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	for _, v := range entries {
+		positions[v.ItemId] = v.Position
+	}
+
+	return positions, nil
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+type ChartsBuilderRepository interface {
+	GetTrackSignals(since time.Time) ([]*models.TrackSignals, error)
+	GetGenreIds() ([]uint64, error)
+	// GetLastComputedAt returns when the latest snapshot was taken, it is nil
+	// if there are no snapshots
+	GetLastComputedAt() (*time.Time, error)
+	// SaveCharts stores new snapshots and fills previous positions of their
+	// entries from the latest snapshot of the same chart
+	SaveCharts(charts []*models.Chart) error
+}
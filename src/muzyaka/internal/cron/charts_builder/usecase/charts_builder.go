@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log/slog"
+	"sort"
+	"src/internal/config"
+	"src/internal/cron/charts_builder/repository"
+	"src/internal/models"
+	"time"
+)
+
+// Weights of signals in chart scores, plays are completed plays only and
+// skips lower the score
+const (
+	playWeight        = 1.0
+	skipWeight        = -0.5
+	likeWeight        = 5.0
+	playlistAddWeight = 3.0
+)
+
+// ChartsBuilder takes chart snapshots for every period, kind and genre
+type ChartsBuilder struct {
+	repository repository.ChartsBuilderRepository
+	cfg        config.Charts
+	now        func() time.Time
+}
+
+func NewChartsBuilder(chartsRepository repository.ChartsBuilderRepository, cfg config.Charts) *ChartsBuilder {
+	return &ChartsBuilder{
+		repository: chartsRepository,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+// Run takes snapshots every cfg.Interval since the latest one until ctx is
+// cancelled, so restarts don't take extra snapshots
+func (b *ChartsBuilder) Run(ctx context.Context, logger *slog.Logger) {
+	delay, err := b.firstDelay()
+	if err != nil {
+		logger.Error("charts builder schedule failed", slog.String("error", err.Error()))
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("charts builder stopped")
+			return
+		case <-timer.C:
+			start := time.Now()
+			if err := b.Rebuild(); err != nil {
+				logger.Error("charts rebuild failed", slog.String("error", err.Error()))
+			} else {
+				logger.Debug("charts rebuild done", slog.Duration("took", time.Since(start)))
+			}
+			timer.Reset(b.cfg.Interval)
+		}
+	}
+}
+
+// firstDelay is the rest of cfg.Interval since the latest snapshot, it is 0
+// if there are no snapshots yet or the interval has passed
+func (b *ChartsBuilder) firstDelay() (time.Duration, error) {
+	last, err := b.repository.GetLastComputedAt()
+	if err != nil {
+		return 0, errors.Wrap(err, "charts_builder.firstDelay error while GetLastComputedAt call")
+	}
+
+	if last == nil {
+		return 0, nil
+	}
+
+	return max(last.Add(b.cfg.Interval).Sub(b.now()), 0), nil
+}
+
+func (b *ChartsBuilder) Rebuild() error {
+	now := b.now().UTC()
+
+	genres, err := b.repository.GetGenreIds()
+	if err != nil {
+		return errors.Wrap(err, "charts_builder.Rebuild error while GetGenreIds call")
+	}
+
+	var charts []*models.Chart
+	for _, period := range []string{models.ChartPeriodDay, models.ChartPeriodWeek, models.ChartPeriodMonth} {
+		signals, err := b.repository.GetTrackSignals(now.Add(-models.ChartPeriods[period]))
+		if err != nil {
+			return errors.Wrap(err, "charts_builder.Rebuild error while GetTrackSignals call")
+		}
+
+		charts = append(charts, buildCharts(signals, genres, period, now, b.cfg.Size)...)
+	}
+
+	err = b.repository.SaveCharts(charts)
+	if err != nil {
+		return errors.Wrap(err, "charts_builder.Rebuild error while SaveCharts call")
+	}
+
+	return nil
+}
+
+func score(s *models.TrackSignals) float64 {
+	return float64(s.Plays)*playWeight +
+		float64(s.Skips)*skipWeight +
+		float64(s.Likes)*likeWeight +
+		float64(s.PlaylistAdds)*playlistAddWeight
+}
+
+// buildCharts makes a global chart and a chart per genre for every kind.
+// Albums and musicians get the sum of scores of their tracks, in a genre chart
+// only tracks of the genre or its subgenres count. Genres without signals get empty charts,
+// so they don't show an outdated snapshot.
+func buildCharts(signals []*models.TrackSignals, genres []uint64,
+	period string, computedAt time.Time, size int) []*models.Chart {
+	var charts []*models.Chart
+
+	for _, kind := range models.ChartKinds {
+		global := make(map[uint64]float64)
+		byGenre := make(map[uint64]map[uint64]float64)
+
+		for _, s := range signals {
+			item := itemId(s, kind)
+			global[item] += score(s)
+
+			for _, genre := range s.GenreIds {
+				if byGenre[genre] == nil {
+					byGenre[genre] = make(map[uint64]float64)
+				}
+				byGenre[genre][item] += score(s)
+			}
+		}
+
+		charts = append(charts, &models.Chart{
+			Kind:       kind,
+			Period:     period,
+			ComputedAt: computedAt,
+			Entries:    rank(global, size),
+		})
+
+		for _, v := range genres {
+			genre := v
+			charts = append(charts, &models.Chart{
+				Kind:       kind,
+				Period:     period,
+				GenreId:    &genre,
+				ComputedAt: computedAt,
+				Entries:    rank(byGenre[genre], size),
+			})
+		}
+	}
+
+	return charts
+}
+
+func itemId(s *models.TrackSignals, kind string) uint64 {
+	switch kind {
+	case models.ChartKindAlbum:
+		return s.AlbumId
+	case models.ChartKindMusician:
+		return s.MusicianId
+	default:
+		return s.TrackId
+	}
+}
+
+// rank returns top size items by score, ties are broken by id. Items without
+// a positive score are not charted.
+func rank(scores map[uint64]float64, size int) []*models.ChartEntry {
+	entries := make([]*models.ChartEntry, 0, len(scores))
+	for id, v := range scores {
+		if v <= 0 {
+			continue
+		}
+		entries = append(entries, &models.ChartEntry{ItemId: id, Score: v})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].ItemId < entries[j].ItemId
+	})
+
+	if len(entries) > size {
+		entries = entries[:size]
+	}
+
+	for i, v := range entries {
+		v.Position = i + 1
+	}
+
+	return entries
+}
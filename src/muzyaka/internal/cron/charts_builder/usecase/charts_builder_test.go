@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"src/internal/config"
+	mock_repository "src/internal/cron/charts_builder/repository/mocks"
+	"src/internal/models"
+	"testing"
+	"time"
+)
+
+func TestBuildCharts(t *testing.T) {
+	computedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	rock := uint64(1)
+	jazz := uint64(2)
+
+	signals := []*models.TrackSignals{
+		{TrackId: 1, GenreIds: []uint64{1}, AlbumId: 10, MusicianId: 100, Plays: 10},
+		{TrackId: 2, GenreIds: []uint64{1, 2}, AlbumId: 10, MusicianId: 100, Likes: 1, Skips: 5},
+		{TrackId: 3, AlbumId: 11, MusicianId: 101, PlaylistAdds: 4},
+		{TrackId: 4, GenreIds: []uint64{1}, AlbumId: 12, MusicianId: 102, Plays: 1, Skips: 3},
+	}
+
+	charts := buildCharts(signals, []uint64{rock, jazz}, models.ChartPeriodWeek, computedAt, 2)
+
+	expected := []*models.Chart{
+		{
+			Kind: models.ChartKindTrack, Period: models.ChartPeriodWeek, ComputedAt: computedAt,
+			Entries: []*models.ChartEntry{
+				{Position: 1, ItemId: 3, Score: 12},
+				{Position: 2, ItemId: 1, Score: 10},
+			},
+		},
+		{
+			Kind: models.ChartKindTrack, Period: models.ChartPeriodWeek, GenreId: &rock, ComputedAt: computedAt,
+			Entries: []*models.ChartEntry{
+				{Position: 1, ItemId: 1, Score: 10},
+				{Position: 2, ItemId: 2, Score: 2.5},
+			},
+		},
+		{
+			Kind: models.ChartKindTrack, Period: models.ChartPeriodWeek, GenreId: &jazz, ComputedAt: computedAt,
+			Entries: []*models.ChartEntry{
+				{Position: 1, ItemId: 2, Score: 2.5},
+			},
+		},
+		{
+			Kind: models.ChartKindAlbum, Period: models.ChartPeriodWeek, ComputedAt: computedAt,
+			Entries: []*models.ChartEntry{
+				{Position: 1, ItemId: 10, Score: 12.5},
+				{Position: 2, ItemId: 11, Score: 12},
+			},
+		},
+		{
+			Kind: models.ChartKindAlbum, Period: models.ChartPeriodWeek, GenreId: &rock, ComputedAt: computedAt,
+			Entries: []*models.ChartEntry{
+				{Position: 1, ItemId: 10, Score: 12.5},
+			},
+		},
+		{
+			Kind: models.ChartKindAlbum, Period: models.ChartPeriodWeek, GenreId: &jazz, ComputedAt: computedAt,
+			Entries: []*models.ChartEntry{
+				{Position: 1, ItemId: 10, Score: 2.5},
+			},
+		},
+		{
+			Kind: models.ChartKindMusician, Period: models.ChartPeriodWeek, ComputedAt: computedAt,
+			Entries: []*models.ChartEntry{
+				{Position: 1, ItemId: 100, Score: 12.5},
+				{Position: 2, ItemId: 101, Score: 12},
+			},
+		},
+		{
+			Kind: models.ChartKindMusician, Period: models.ChartPeriodWeek, GenreId: &rock, ComputedAt: computedAt,
+			Entries: []*models.ChartEntry{
+				{Position: 1, ItemId: 100, Score: 12.5},
+			},
+		},
+		{
+			Kind: models.ChartKindMusician, Period: models.ChartPeriodWeek, GenreId: &jazz, ComputedAt: computedAt,
+			Entries: []*models.ChartEntry{
+				{Position: 1, ItemId: 100, Score: 2.5},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, charts)
+}
+
+func TestChartsBuilder_Rebuild(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	type mock func(r *mock_repository.MockChartsBuilderRepository)
+
+	testTable := []struct {
+		name        string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockChartsBuilderRepository) {
+				r.EXPECT().GetGenreIds().Return(nil, nil)
+				r.EXPECT().GetTrackSignals(now.Add(-24*time.Hour)).Return(nil, nil)
+				r.EXPECT().GetTrackSignals(now.Add(-7*24*time.Hour)).Return(nil, nil)
+				r.EXPECT().GetTrackSignals(now.Add(-30*24*time.Hour)).Return(nil, nil)
+				r.EXPECT().SaveCharts(gomock.Len(9)).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Signals fail test",
+			mock: func(r *mock_repository.MockChartsBuilderRepository) {
+				r.EXPECT().GetGenreIds().Return(nil, nil)
+				r.EXPECT().GetTrackSignals(now.Add(-24*time.Hour)).Return(nil, errors.New("error"))
+			},
+			expectedErr: errors.Wrap(errors.New("error"),
+				"charts_builder.Rebuild error while GetTrackSignals call"),
+		},
+		{
+			name: "Save fail test",
+			mock: func(r *mock_repository.MockChartsBuilderRepository) {
+				r.EXPECT().GetGenreIds().Return(nil, nil)
+				r.EXPECT().GetTrackSignals(gomock.Any()).Return(nil, nil).Times(3)
+				r.EXPECT().SaveCharts(gomock.Any()).Return(errors.New("error"))
+			},
+			expectedErr: errors.Wrap(errors.New("error"),
+				"charts_builder.Rebuild error while SaveCharts call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockChartsBuilderRepository(ctrl)
+			tc.mock(repo)
+
+			b := NewChartsBuilder(repo, config.Charts{Size: 10})
+			b.now = func() time.Time { return now }
+
+			err := b.Rebuild()
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChartsBuilder_firstDelay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(t time.Time) *time.Time { return &t }
+
+	type mock func(r *mock_repository.MockChartsBuilderRepository)
+
+	testTable := []struct {
+		name          string
+		mock          mock
+		expectedDelay time.Duration
+		expectedErr   error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockChartsBuilderRepository) {
+				r.EXPECT().GetLastComputedAt().Return(at(now.Add(-6*time.Hour)), nil)
+			},
+			expectedDelay: 18 * time.Hour,
+			expectedErr:   nil,
+		},
+		{
+			name: "Overdue test",
+			mock: func(r *mock_repository.MockChartsBuilderRepository) {
+				r.EXPECT().GetLastComputedAt().Return(at(now.Add(-48*time.Hour)), nil)
+			},
+			expectedDelay: 0,
+			expectedErr:   nil,
+		},
+		{
+			name: "No snapshots test",
+			mock: func(r *mock_repository.MockChartsBuilderRepository) {
+				r.EXPECT().GetLastComputedAt().Return(nil, nil)
+			},
+			expectedDelay: 0,
+			expectedErr:   nil,
+		},
+		{
+			name: "Repo fail test",
+			mock: func(r *mock_repository.MockChartsBuilderRepository) {
+				r.EXPECT().GetLastComputedAt().Return(nil, errors.New("error"))
+			},
+			expectedDelay: 0,
+			expectedErr: errors.Wrap(errors.New("error"),
+				"charts_builder.firstDelay error while GetLastComputedAt call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockChartsBuilderRepository(ctrl)
+			tc.mock(repo)
+
+			b := NewChartsBuilder(repo, config.Charts{Interval: 24 * time.Hour})
+			b.now = func() time.Time { return now }
+
+			delay, err := b.firstDelay()
+
+			assert.Equal(t, tc.expectedDelay, delay)
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
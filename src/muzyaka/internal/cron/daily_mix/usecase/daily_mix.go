@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log/slog"
+	"src/internal/config"
+	"src/internal/domain/recsys/repository"
+	"src/internal/domain/recsys/usecase"
+	"time"
+)
+
+const usersBatchSize = 100
+
+// DailyMixGenerator regenerates daily mixes of every active user
+type DailyMixGenerator struct {
+	repository repository.RecSysRepository
+	recSys     usecase.RecSysUseCase
+	cfg        config.RecSys
+}
+
+func NewDailyMixGenerator(recsRepository repository.RecSysRepository,
+	recSys usecase.RecSysUseCase,
+	cfg config.RecSys) *DailyMixGenerator {
+	return &DailyMixGenerator{
+		repository: recsRepository,
+		recSys:     recSys,
+		cfg:        cfg,
+	}
+}
+
+// Run generates mixes on start and then every cfg.DailyMixInterval until ctx is cancelled
+func (g *DailyMixGenerator) Run(ctx context.Context, logger *slog.Logger) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("daily mix generator stopped")
+			return
+		case <-timer.C:
+			start := time.Now()
+			if err := g.Generate(ctx, logger); err != nil {
+				logger.Error("daily mix generation failed", slog.String("error", err.Error()))
+			} else {
+				logger.Debug("daily mix generation done", slog.Duration("took", time.Since(start)))
+			}
+			timer.Reset(g.cfg.DailyMixInterval)
+		}
+	}
+}
+
+// Generate goes through all active users. A failure for one user is logged
+// and doesn't stop the others.
+func (g *DailyMixGenerator) Generate(ctx context.Context, logger *slog.Logger) error {
+	var afterId uint64
+
+	for ctx.Err() == nil {
+		users, err := g.repository.GetActiveUsers(afterId, usersBatchSize)
+		if err != nil {
+			return errors.Wrap(err, "daily_mix.Generate error while GetActiveUsers call")
+		}
+
+		for _, v := range users {
+			if err := g.recSys.GenerateDailyMixes(v); err != nil {
+				logger.Error("daily mix generation failed",
+					slog.Uint64("user_id", v),
+					slog.String("error", err.Error()))
+			}
+		}
+
+		if len(users) < usersBatchSize {
+			return nil
+		}
+
+		afterId = users[len(users)-1]
+	}
+
+	return nil
+}
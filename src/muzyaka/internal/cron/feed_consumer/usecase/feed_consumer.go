@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"github.com/pkg/errors"
+	"log/slog"
+	"src/internal/domain/feed/repository"
+	"src/internal/lib/kafka"
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
+)
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+var Topics = []string{kafka.DefaultTopic, kafka.AddTopic, kafka.DeleteTopic}
+
+// FeedConsumer projects album, track and merch events published from the
+// outbox into the feed. Other events on the same topics are ignored.
+type FeedConsumer struct {
+	group      sarama.ConsumerGroup
+	repository repository.FeedRepository
+	logger     *slog.Logger
+}
+
+func NewFeedConsumer(group sarama.ConsumerGroup,
+	feedRepository repository.FeedRepository,
+	logger *slog.Logger) *FeedConsumer {
+	return &FeedConsumer{
+		group:      group,
+		repository: feedRepository,
+		logger:     logger,
+	}
+}
+
+// Run consumes until ctx is cancelled, see RecSysConsumer.Run
+func (c *FeedConsumer) Run(ctx context.Context) {
+	errs := c.group.Errors()
+	go func() {
+		for err := range errs {
+			c.logger.Error("feed consumer error", slog.String("error", err.Error()))
+		}
+	}()
+
+	for {
+		err := c.group.Consume(ctx, Topics, c)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return
+		} else if err != nil {
+			c.logger.Error("feed consume failed", slog.String("error", err.Error()))
+		}
+
+		if ctx.Err() != nil {
+			c.logger.Info("feed consumer stopped")
+			return
+		}
+	}
+}
+
+func (c *FeedConsumer) Setup(session sarama.ConsumerGroupSession) error {
+	c.logger.Info("feed consumer partitions assigned",
+		slog.Any("claims", session.Claims()),
+		slog.Int("generation", int(session.GenerationID())))
+	return nil
+}
+
+func (c *FeedConsumer) Cleanup(session sarama.ConsumerGroupSession) error {
+	c.logger.Info("feed consumer partitions revoked",
+		slog.Int("generation", int(session.GenerationID())))
+	return nil
+}
+
+func (c *FeedConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			if !c.handleWithRetry(session.Context(), msg) {
+				return nil
+			}
+			session.MarkMessage(msg, "")
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (c *FeedConsumer) handleWithRetry(ctx context.Context, msg *sarama.ConsumerMessage) bool {
+	delay := retryBaseDelay
+
+	for {
+		err := c.HandleMessage(msg)
+		if err == nil {
+			return true
+		}
+
+		if errors.Is(err, models.ErrInvalidPayload) {
+			c.logger.Error("feed event skipped",
+				slog.String("topic", msg.Topic),
+				slog.Int64("offset", msg.Offset),
+				slog.String("error", err.Error()))
+			return true
+		}
+
+		c.logger.Error("feed event handling failed",
+			slog.String("topic", msg.Topic),
+			slog.Int64("offset", msg.Offset),
+			slog.String("error", err.Error()),
+			slog.Duration("retry_in", delay))
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > retryMaxDelay {
+			delay = retryMaxDelay
+		}
+	}
+}
+
+func (c *FeedConsumer) HandleMessage(msg *sarama.ConsumerMessage) error {
+	var event events.Event
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return errors.Wrap(models.ErrInvalidPayload, err.Error())
+	}
+
+	if event.EventId == "" {
+		return errors.Wrap(models.ErrInvalidPayload, "empty event id")
+	}
+
+	var err error
+	switch event.Type {
+	case events.AlbumCreated, events.AlbumDeleted:
+		var payload events.AlbumPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return errors.Wrap(models.ErrInvalidPayload, err.Error())
+		}
+
+		if event.Type == events.AlbumDeleted {
+			err = c.repository.DeleteFeedItem(event.EventId, models.FeedItemAlbum, payload.AlbumId)
+			break
+		}
+		err = c.repository.AddFeedItem(event.EventId, &models.FeedItem{
+			Kind:        models.FeedItemAlbum,
+			ItemId:      payload.AlbumId,
+			MusicianId:  payload.MusicianId,
+			PublishedAt: event.OccurredAt,
+		})
+	case events.TrackAdded, events.TrackDeleted:
+		var payload events.TrackPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return errors.Wrap(models.ErrInvalidPayload, err.Error())
+		}
+
+		if event.Type == events.TrackDeleted {
+			err = c.repository.DeleteFeedItem(event.EventId, models.FeedItemTrack, payload.TrackId)
+			break
+		}
+		err = c.repository.AddFeedItem(event.EventId, &models.FeedItem{
+			Kind:        models.FeedItemTrack,
+			ItemId:      payload.TrackId,
+			AlbumId:     payload.AlbumId,
+			PublishedAt: event.OccurredAt,
+		})
+	case events.MerchCreated, events.MerchDeleted:
+		var payload events.MerchPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return errors.Wrap(models.ErrInvalidPayload, err.Error())
+		}
+
+		if event.Type == events.MerchDeleted {
+			err = c.repository.DeleteFeedItem(event.EventId, models.FeedItemMerch, payload.MerchId)
+			break
+		}
+		err = c.repository.AddFeedItem(event.EventId, &models.FeedItem{
+			Kind:        models.FeedItemMerch,
+			ItemId:      payload.MerchId,
+			MusicianId:  payload.MusicianId,
+			PublishedAt: event.OccurredAt,
+		})
+	default:
+		return nil
+	}
+
+	if errors.Is(err, models.ErrAlreadyProcessed) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "feed_consumer.HandleMessage error from repository")
+	}
+
+	return nil
+}
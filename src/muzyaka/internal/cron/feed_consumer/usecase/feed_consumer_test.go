@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	mock_repository "src/internal/domain/feed/repository/mocks"
+	"src/internal/lib/kafka"
+	mock_sarama "src/internal/lib/kafka/mocks"
+	"src/internal/models"
+	"src/internal/models/events"
+	"testing"
+	"time"
+)
+
+var (
+	testLogger     = slog.New(slog.NewTextHandler(io.Discard, nil))
+	testOccurredAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func newMessage(t *testing.T, eventId string, eventType string, payload interface{}) *sarama.ConsumerMessage {
+	rawPayload, err := json.Marshal(payload)
+	assert.NoError(t, err)
+
+	value, err := json.Marshal(events.Event{
+		Version:    events.Version,
+		EventId:    eventId,
+		Type:       eventType,
+		Payload:    rawPayload,
+		OccurredAt: testOccurredAt,
+	})
+	assert.NoError(t, err)
+
+	return &sarama.ConsumerMessage{Topic: kafka.DefaultTopic, Value: value}
+}
+
+func TestFeedConsumer_HandleMessage(t *testing.T) {
+	type mock func(r *mock_repository.MockFeedRepository)
+
+	testTable := []struct {
+		name        string
+		msg         func(t *testing.T) *sarama.ConsumerMessage
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Album created test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e1", events.AlbumCreated, events.AlbumPayload{AlbumId: 1, MusicianId: 2})
+			},
+			mock: func(r *mock_repository.MockFeedRepository) {
+				r.EXPECT().AddFeedItem("e1", &models.FeedItem{
+					Kind:        models.FeedItemAlbum,
+					ItemId:      1,
+					MusicianId:  2,
+					PublishedAt: testOccurredAt,
+				}).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Track added test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e2", events.TrackAdded, events.TrackPayload{TrackId: 3, AlbumId: 1})
+			},
+			mock: func(r *mock_repository.MockFeedRepository) {
+				r.EXPECT().AddFeedItem("e2", &models.FeedItem{
+					Kind:        models.FeedItemTrack,
+					ItemId:      3,
+					AlbumId:     1,
+					PublishedAt: testOccurredAt,
+				}).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Merch deleted test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e3", events.MerchDeleted, events.MerchPayload{MerchId: 4, MusicianId: 2})
+			},
+			mock: func(r *mock_repository.MockFeedRepository) {
+				r.EXPECT().DeleteFeedItem("e3", models.FeedItemMerch, uint64(4)).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Duplicate event test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e1", events.AlbumDeleted, events.AlbumPayload{AlbumId: 1})
+			},
+			mock: func(r *mock_repository.MockFeedRepository) {
+				r.EXPECT().DeleteFeedItem("e1", models.FeedItemAlbum, uint64(1)).Return(models.ErrAlreadyProcessed)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Other event test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e4", events.UserCreated, events.UserPayload{UserId: 1})
+			},
+			mock:        func(r *mock_repository.MockFeedRepository) {},
+			expectedErr: nil,
+		},
+		{
+			name: "Invalid message test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return &sarama.ConsumerMessage{Value: []byte("not json")}
+			},
+			mock:        func(r *mock_repository.MockFeedRepository) {},
+			expectedErr: models.ErrInvalidPayload,
+		},
+		{
+			name: "Repo fail test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e5", events.MerchCreated, events.MerchPayload{MerchId: 4, MusicianId: 2})
+			},
+			mock: func(r *mock_repository.MockFeedRepository) {
+				r.EXPECT().AddFeedItem("e5", gomock.Any()).Return(errors.New("error"))
+			},
+			expectedErr: errors.Wrap(errors.New("error"), "feed_consumer.HandleMessage error from repository"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockFeedRepository(ctrl)
+			tc.mock(repo)
+
+			c := NewFeedConsumer(mock_sarama.NewMockConsumerGroup(ctrl), repo, testLogger)
+			err := c.HandleMessage(tc.msg(t))
+
+			switch {
+			case tc.expectedErr == nil:
+				assert.NoError(t, err)
+			case errors.Is(tc.expectedErr, models.ErrInvalidPayload):
+				assert.ErrorIs(t, err, models.ErrInvalidPayload)
+			default:
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			}
+		})
+	}
+}
+
+func TestFeedConsumer_ConsumeClaim(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	msg1 := newMessage(t, "e1", events.MerchCreated, events.MerchPayload{MerchId: 1, MusicianId: 2})
+	msg2 := &sarama.ConsumerMessage{Value: []byte("not json")}
+
+	messages := make(chan *sarama.ConsumerMessage, 2)
+	messages <- msg1
+	messages <- msg2
+	close(messages)
+
+	repo := mock_repository.NewMockFeedRepository(ctrl)
+	repo.EXPECT().AddFeedItem("e1", gomock.Any()).Return(nil)
+
+	session := mock_sarama.NewMockConsumerGroupSession(ctrl)
+	session.EXPECT().Context().Return(context.Background()).AnyTimes()
+	gomock.InOrder(
+		session.EXPECT().MarkMessage(msg1, ""),
+		session.EXPECT().MarkMessage(msg2, ""),
+	)
+
+	claim := mock_sarama.NewMockConsumerGroupClaim(ctrl)
+	claim.EXPECT().Messages().Return(messages).AnyTimes()
+
+	c := NewFeedConsumer(mock_sarama.NewMockConsumerGroup(ctrl), repo, testLogger)
+	assert.NoError(t, c.ConsumeClaim(session, claim))
+}
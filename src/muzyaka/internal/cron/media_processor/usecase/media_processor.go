@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"github.com/pkg/errors"
+	"log/slog"
+	"src/internal/config"
+	repository2 "src/internal/domain/moderation/repository"
+	"src/internal/domain/track/repository"
+	"src/internal/lib/audio"
+	"src/internal/lib/fingerprint"
+	"src/internal/lib/kafka"
+	"src/internal/lib/loudness"
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
+)
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+
+	// fingerprintCandidates are compared with the fingerprint of a track
+	fingerprintCandidates = 10
+)
+
+var Topics = []string{kafka.AddTopic, kafka.UpdateTopic}
+
+// MediaProcessor makes the preview and the waveform of a track, measures its
+// loudness and reports it if it sounds like a track of another musician when
+// it is added or its source is replaced. Sources that are already processed
+// are skipped by their tag unless the loudness is reset.
+type MediaProcessor struct {
+	group         sarama.ConsumerGroup
+	trackRep      repository.TrackRepository
+	storage       repository.TrackStorage
+	moderationRep repository2.ModerationRepository
+	cfg           config.Media
+	logger        *slog.Logger
+}
+
+func NewMediaProcessor(group sarama.ConsumerGroup,
+	trackRep repository.TrackRepository,
+	storage repository.TrackStorage,
+	moderationRep repository2.ModerationRepository,
+	cfg config.Media,
+	logger *slog.Logger) *MediaProcessor {
+	return &MediaProcessor{
+		group:         group,
+		trackRep:      trackRep,
+		storage:       storage,
+		moderationRep: moderationRep,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// Run consumes until ctx is cancelled, see RecSysConsumer.Run
+func (c *MediaProcessor) Run(ctx context.Context) {
+	errs := c.group.Errors()
+	go func() {
+		for err := range errs {
+			c.logger.Error("media processor error", slog.String("error", err.Error()))
+		}
+	}()
+
+	for {
+		err := c.group.Consume(ctx, Topics, c)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return
+		} else if err != nil {
+			c.logger.Error("media consume failed", slog.String("error", err.Error()))
+		}
+
+		if ctx.Err() != nil {
+			c.logger.Info("media processor stopped")
+			return
+		}
+	}
+}
+
+func (c *MediaProcessor) Setup(session sarama.ConsumerGroupSession) error {
+	c.logger.Info("media processor partitions assigned",
+		slog.Any("claims", session.Claims()),
+		slog.Int("generation", int(session.GenerationID())))
+	return nil
+}
+
+func (c *MediaProcessor) Cleanup(session sarama.ConsumerGroupSession) error {
+	c.logger.Info("media processor partitions revoked",
+		slog.Int("generation", int(session.GenerationID())))
+	return nil
+}
+
+func (c *MediaProcessor) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			if !c.handleWithRetry(session.Context(), msg) {
+				return nil
+			}
+			session.MarkMessage(msg, "")
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+// handleWithRetry returns false if ctx is cancelled before the message is
+// handled or skipped
+func (c *MediaProcessor) handleWithRetry(ctx context.Context, msg *sarama.ConsumerMessage) bool {
+	delay := retryBaseDelay
+
+	for attempt := 1; ; attempt++ {
+		err := c.HandleMessage(msg)
+		if err == nil {
+			return true
+		}
+
+		if errors.Is(err, models.ErrInvalidPayload) || attempt >= c.cfg.MaxAttempts {
+			c.logger.Error("media event skipped",
+				slog.String("topic", msg.Topic),
+				slog.Int64("offset", msg.Offset),
+				slog.Int("attempts", attempt),
+				slog.String("error", err.Error()))
+			return true
+		}
+
+		c.logger.Error("media event handling failed",
+			slog.String("topic", msg.Topic),
+			slog.Int64("offset", msg.Offset),
+			slog.String("error", err.Error()),
+			slog.Duration("retry_in", delay))
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > retryMaxDelay {
+			delay = retryMaxDelay
+		}
+	}
+}
+
+func (c *MediaProcessor) HandleMessage(msg *sarama.ConsumerMessage) error {
+	var event events.Event
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return errors.Wrap(models.ErrInvalidPayload, err.Error())
+	}
+
+	if event.Type != events.TrackAdded && event.Type != events.TrackUpdated {
+		return nil
+	}
+
+	var payload events.TrackPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return errors.Wrap(models.ErrInvalidPayload, err.Error())
+	}
+
+	// updates of lyrics, credits and other metadata keep the media
+	if event.Type == events.TrackUpdated && !payload.SourceChanged {
+		return nil
+	}
+
+	err := c.process(payload.TrackId)
+	if errors.Is(err, models.ErrNotFound) {
+		// the track or its source is deleted since
+		return nil
+	} else if errors.Is(err, models.ErrInvalidFileFormat) {
+		return errors.Wrap(models.ErrInvalidPayload, err.Error())
+	} else if err != nil {
+		return errors.Wrap(err, "media_processor.HandleMessage error while process")
+	}
+
+	return nil
+}
+
+func (c *MediaProcessor) process(trackId uint64) error {
+	track, err := c.trackRep.GetTrack(trackId)
+	if err != nil {
+		return err
+	}
+
+	// the tag is taken before the source is loaded, so a source replaced
+	// meanwhile is processed again on its own event
+	tag, err := c.storage.SourceTag(track)
+	if err != nil {
+		return err
+	}
+
+	waveform, err := c.storage.LoadWaveform(track)
+	if err == nil && waveform.SourceTag == tag && track.Loudness != nil {
+		return nil
+	} else if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return err
+	}
+
+	obj, err := c.storage.LoadObject(track)
+	if err != nil {
+		return err
+	}
+
+	pcm, err := audio.Decode(obj.Payload, c.cfg.MaxDuration)
+	if err != nil {
+		return err
+	}
+
+	offset, length := previewRange(pcm.Duration(), c.cfg.PreviewOffset, c.cfg.PreviewLength)
+	if err := c.storage.SavePreview(track, audio.EncodeWav(pcm.Clip(offset, length))); err != nil {
+		return err
+	}
+
+	// the waveform is saved last as its tag marks the source as processed
+	waveform = &models.Waveform{SourceTag: tag, Duration: pcm.Duration()}
+	for _, points := range c.cfg.WaveformPoints {
+		waveform.Peaks = append(waveform.Peaks, audio.Peaks(pcm, points))
+	}
+
+	if seconds := int(pcm.Duration().Round(time.Second) / time.Second); seconds > 0 {
+		if err := c.trackRep.UpdateDuration(trackId, seconds); err != nil {
+			return err
+		}
+	}
+
+	// saved after the duration as album loudness is weighted by it
+	if err := c.trackRep.SaveLoudness(trackId, loudness.Measure(pcm)); err != nil {
+		return err
+	}
+
+	if err := c.reportDuplicates(trackId, pcm); err != nil {
+		return err
+	}
+
+	return c.storage.SaveWaveform(track, waveform)
+}
+
+// reportDuplicates saves the fingerprint of the track and compares it with
+// the ones sharing most keys. Of two similar tracks the one uploaded later
+// is reported.
+func (c *MediaProcessor) reportDuplicates(trackId uint64, pcm *audio.PCM) error {
+	values := fingerprint.Compute(pcm)
+	keys := fingerprint.Keys(values)
+
+	err := c.trackRep.SaveFingerprint(&models.Fingerprint{TrackId: trackId, Values: values}, keys)
+	if err != nil {
+		return err
+	}
+
+	candidates, err := c.trackRep.FindFingerprints(trackId, keys, fingerprintCandidates)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range candidates {
+		similarity := fingerprint.Similarity(values, v.Values)
+		if similarity < c.cfg.DuplicateSimilarity {
+			continue
+		}
+
+		report := &models.DuplicateReport{
+			TrackId:         max(trackId, v.TrackId),
+			OriginalTrackId: min(trackId, v.TrackId),
+			Similarity:      similarity,
+			Status:          models.ReportPending,
+		}
+		if err := c.moderationRep.AddDuplicateReport(report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// previewRange keeps the preview within the track. Tracks shorter than twice
+// the length get a preview of half of them, so a full track is never exposed.
+func previewRange(duration time.Duration, offset time.Duration, length time.Duration) (time.Duration, time.Duration) {
+	length = min(length, duration/2)
+	offset = max(0, min(offset, duration-length))
+
+	return offset, length
+}
@@ -0,0 +1,322 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"math"
+	"src/internal/config"
+	mock_repository2 "src/internal/domain/moderation/repository/mocks"
+	mock_repository "src/internal/domain/track/repository/mocks"
+	"src/internal/lib/audio"
+	"src/internal/lib/fingerprint"
+	"src/internal/lib/kafka"
+	"src/internal/lib/loudness"
+	"src/internal/models"
+	"src/internal/models/events"
+	"testing"
+	"time"
+)
+
+var (
+	testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	testConfig = config.Media{
+		PreviewOffset:       time.Second,
+		PreviewLength:       time.Second,
+		WaveformPoints:      []int{2, 4},
+		MaxDuration:         time.Minute,
+		MaxAttempts:         1,
+		DuplicateSimilarity: 0.8,
+	}
+)
+
+func newMessage(t *testing.T, eventType string, payload interface{}) *sarama.ConsumerMessage {
+	rawPayload, err := json.Marshal(payload)
+	assert.NoError(t, err)
+
+	value, err := json.Marshal(events.Event{
+		Version: events.Version,
+		EventId: "e1",
+		Type:    eventType,
+		Payload: rawPayload,
+	})
+	assert.NoError(t, err)
+
+	return &sarama.ConsumerMessage{Topic: kafka.AddTopic, Value: value}
+}
+
+// testSource is 4 seconds of 440 Hz getting louder every second
+func testSource() *audio.PCM {
+	const rate = 8000
+	samples := make([]float32, 4*rate)
+	for i := range samples {
+		samples[i] = float32(i/rate+1) / 4 * float32(math.Sin(2*math.Pi*440*float64(i)/rate))
+	}
+	return &audio.PCM{SampleRate: rate, Channels: [][]float32{samples}}
+}
+
+func TestMediaProcessor_HandleMessage(t *testing.T) {
+	type mock func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository)
+
+	track := &models.TrackMeta{Id: 1, Source: "source", Name: "name"}
+	measured := &models.TrackMeta{Id: 1, Source: "source", Name: "name", Loudness: &models.Loudness{Integrated: -20}}
+	source := testSource()
+	payload := audio.EncodeWav(source)
+	decoded, _ := audio.Decode(payload, time.Minute)
+	values := fingerprint.Compute(decoded)
+	keys := fingerprint.Keys(values)
+	inverted := make([]uint32, len(values))
+	for i, v := range values {
+		inverted[i] = ^v
+	}
+	waveform := &models.Waveform{
+		SourceTag: "tag",
+		Duration:  4 * time.Second,
+		Peaks:     [][]float32{audio.Peaks(decoded, 2), audio.Peaks(decoded, 4)},
+	}
+
+	testTable := []struct {
+		name        string
+		msg         func(t *testing.T) *sarama.ConsumerMessage
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Track added test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackAdded, events.TrackPayload{TrackId: 1})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().SourceTag(track).Return("tag", nil)
+				s.EXPECT().LoadWaveform(track).Return(nil, models.ErrNotFound)
+				s.EXPECT().LoadObject(track).Return(&models.TrackObject{TrackMeta: *track, Payload: payload}, nil)
+				s.EXPECT().SavePreview(track, audio.EncodeWav(decoded.Clip(time.Second, time.Second))).Return(nil)
+				r.EXPECT().UpdateDuration(uint64(1), 4).Return(nil)
+				r.EXPECT().SaveLoudness(uint64(1), loudness.Measure(decoded)).Return(nil)
+				r.EXPECT().SaveFingerprint(&models.Fingerprint{TrackId: 1, Values: values}, keys).Return(nil)
+				r.EXPECT().FindFingerprints(uint64(1), keys, fingerprintCandidates).Return(nil, nil)
+				s.EXPECT().SaveWaveform(track, waveform).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Duplicate test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackAdded, events.TrackPayload{TrackId: 1})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().SourceTag(track).Return("tag", nil)
+				s.EXPECT().LoadWaveform(track).Return(nil, models.ErrNotFound)
+				s.EXPECT().LoadObject(track).Return(&models.TrackObject{TrackMeta: *track, Payload: payload}, nil)
+				s.EXPECT().SavePreview(track, gomock.Any()).Return(nil)
+				r.EXPECT().UpdateDuration(uint64(1), 4).Return(nil)
+				r.EXPECT().SaveLoudness(uint64(1), gomock.Any()).Return(nil)
+				r.EXPECT().SaveFingerprint(gomock.Any(), keys).Return(nil)
+				r.EXPECT().FindFingerprints(uint64(1), keys, fingerprintCandidates).Return([]*models.Fingerprint{
+					{TrackId: 2, Values: values},
+					{TrackId: 3, Values: inverted},
+				}, nil)
+				// the track processed later is uploaded earlier
+				m.EXPECT().AddDuplicateReport(&models.DuplicateReport{
+					TrackId:         2,
+					OriginalTrackId: 1,
+					Similarity:      1,
+					Status:          models.ReportPending,
+				}).Return(nil)
+				s.EXPECT().SaveWaveform(track, waveform).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Source replaced test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackUpdated, events.TrackPayload{TrackId: 1, SourceChanged: true})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().SourceTag(track).Return("tag", nil)
+				s.EXPECT().LoadWaveform(track).Return(&models.Waveform{SourceTag: "old"}, nil)
+				s.EXPECT().LoadObject(track).Return(&models.TrackObject{TrackMeta: *track, Payload: payload}, nil)
+				s.EXPECT().SavePreview(track, gomock.Any()).Return(nil)
+				r.EXPECT().UpdateDuration(uint64(1), 4).Return(nil)
+				r.EXPECT().SaveLoudness(uint64(1), loudness.Measure(decoded)).Return(nil)
+				r.EXPECT().SaveFingerprint(gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().FindFingerprints(uint64(1), gomock.Any(), gomock.Any()).Return(nil, nil)
+				s.EXPECT().SaveWaveform(track, waveform).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Already processed test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackUpdated, events.TrackPayload{TrackId: 1, SourceChanged: true})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+				r.EXPECT().GetTrack(uint64(1)).Return(measured, nil)
+				s.EXPECT().SourceTag(measured).Return("tag", nil)
+				s.EXPECT().LoadWaveform(measured).Return(waveform, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Loudness reset test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackUpdated, events.TrackPayload{TrackId: 1, SourceChanged: true})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().SourceTag(track).Return("tag", nil)
+				s.EXPECT().LoadWaveform(track).Return(waveform, nil)
+				s.EXPECT().LoadObject(track).Return(&models.TrackObject{TrackMeta: *track, Payload: payload}, nil)
+				s.EXPECT().SavePreview(track, gomock.Any()).Return(nil)
+				r.EXPECT().UpdateDuration(uint64(1), 4).Return(nil)
+				r.EXPECT().SaveLoudness(uint64(1), gomock.Any()).Return(nil)
+				r.EXPECT().SaveFingerprint(gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().FindFingerprints(uint64(1), gomock.Any(), gomock.Any()).Return(nil, nil)
+				s.EXPECT().SaveWaveform(track, waveform).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Metadata update test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackUpdated, events.TrackPayload{TrackId: 1})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Unsupported format test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackAdded, events.TrackPayload{TrackId: 1})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().SourceTag(track).Return("tag", nil)
+				s.EXPECT().LoadWaveform(track).Return(nil, models.ErrNotFound)
+				s.EXPECT().LoadObject(track).Return(&models.TrackObject{TrackMeta: *track, Payload: []byte("text")}, nil)
+			},
+			expectedErr: models.ErrInvalidPayload,
+		},
+		{
+			name: "Too long source test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackAdded, events.TrackPayload{TrackId: 1})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+				long := audio.EncodeWav(&audio.PCM{SampleRate: 8000, Channels: [][]float32{make([]float32, 61*8000)}})
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().SourceTag(track).Return("tag", nil)
+				s.EXPECT().LoadWaveform(track).Return(nil, models.ErrNotFound)
+				s.EXPECT().LoadObject(track).Return(&models.TrackObject{TrackMeta: *track, Payload: long}, nil)
+			},
+			expectedErr: models.ErrInvalidPayload,
+		},
+		{
+			name: "Deleted track test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackAdded, events.TrackPayload{TrackId: 1})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+				r.EXPECT().GetTrack(uint64(1)).Return(nil, models.ErrNotFound)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Storage error test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackAdded, events.TrackPayload{TrackId: 1})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().SourceTag(track).Return("", errors.New("error"))
+			},
+			expectedErr: errors.New("error"),
+		},
+		{
+			name: "Other event test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, events.TrackDeleted, events.TrackPayload{TrackId: 1})
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Invalid payload test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return &sarama.ConsumerMessage{Topic: kafka.AddTopic, Value: []byte("{")}
+			},
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage, m *mock_repository2.MockModerationRepository) {
+			},
+			expectedErr: models.ErrInvalidPayload,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := mock_repository.NewMockTrackRepository(ctrl)
+			s := mock_repository.NewMockTrackStorage(ctrl)
+			m := mock_repository2.NewMockModerationRepository(ctrl)
+			tc.mock(r, s, m)
+
+			c := NewMediaProcessor(nil, r, s, m, testConfig, testLogger)
+			err := c.HandleMessage(tc.msg(t))
+
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else if errors.Is(tc.expectedErr, models.ErrInvalidPayload) {
+				assert.True(t, errors.Is(err, models.ErrInvalidPayload))
+			} else {
+				assert.Equal(t, tc.expectedErr.Error(), errors.Cause(err).Error())
+			}
+		})
+	}
+}
+
+func TestPreviewRange(t *testing.T) {
+	testTable := []struct {
+		name           string
+		duration       time.Duration
+		expectedOffset time.Duration
+		expectedLength time.Duration
+	}{
+		{name: "Long track", duration: 3 * time.Minute, expectedOffset: 30 * time.Second, expectedLength: 30 * time.Second},
+		{name: "Offset moved back", duration: 50 * time.Second, expectedOffset: 25 * time.Second, expectedLength: 25 * time.Second},
+		{name: "Short track", duration: 10 * time.Second, expectedOffset: 5 * time.Second, expectedLength: 5 * time.Second},
+		{name: "Exactly twice", duration: time.Minute, expectedOffset: 30 * time.Second, expectedLength: 30 * time.Second},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			offset, length := previewRange(tc.duration, 30*time.Second, 30*time.Second)
+			assert.Equal(t, tc.expectedOffset, offset)
+			assert.Equal(t, tc.expectedLength, length)
+		})
+	}
+}
+
+func TestMediaProcessor_handleWithRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	r := mock_repository.NewMockTrackRepository(ctrl)
+	r.EXPECT().GetTrack(uint64(1)).Return(nil, errors.New("error"))
+
+	c := NewMediaProcessor(nil, r, mock_repository.NewMockTrackStorage(ctrl),
+		mock_repository2.NewMockModerationRepository(ctrl), testConfig, testLogger)
+
+	// the event is skipped after the last attempt
+	assert.True(t, c.handleWithRetry(context.Background(), newMessage(t, events.TrackAdded, events.TrackPayload{TrackId: 1})))
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log/slog"
+	"src/internal/config"
+	"src/internal/domain/order/repository"
+	"time"
+)
+
+const batchSize = 100
+
+// OrderExpiry cancels orders not paid within cfg.PendingTtl, so their items go
+// back to stock. Payments completed later are refunded by the webhook.
+type OrderExpiry struct {
+	orderRep repository.OrderRepository
+	cfg      config.Payments
+	now      func() time.Time
+}
+
+func NewOrderExpiry(orderRep repository.OrderRepository, cfg config.Payments) *OrderExpiry {
+	return &OrderExpiry{
+		orderRep: orderRep,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+// Run expires orders on start and then every cfg.ExpiryInterval until ctx is cancelled
+func (o *OrderExpiry) Run(ctx context.Context, logger *slog.Logger) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("order expiry stopped")
+			return
+		case <-timer.C:
+			count, err := o.Expire()
+			if err != nil {
+				logger.Error("order expiry failed", slog.String("error", err.Error()))
+			} else if count > 0 {
+				logger.Info("orders expired", slog.Int("count", count))
+			}
+			timer.Reset(o.cfg.ExpiryInterval)
+		}
+	}
+}
+
+// Expire cancels all expired orders in batches and returns how many were cancelled
+func (o *OrderExpiry) Expire() (int, error) {
+	createdBefore := o.now().Add(-o.cfg.PendingTtl)
+
+	var total int
+	for {
+		count, err := o.orderRep.ExpireOrders(createdBefore, batchSize)
+		if err != nil {
+			return total, errors.Wrap(err, "order_expiry.Expire error while ExpireOrders call")
+		}
+
+		total += count
+		if count < batchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"src/internal/config"
+	mock_repository "src/internal/domain/order/repository/mocks"
+	"testing"
+	"time"
+)
+
+func TestOrderExpiry_Expire(t *testing.T) {
+	now := time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC)
+	createdBefore := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+
+	type mock func(r *mock_repository.MockOrderRepository)
+
+	testTable := []struct {
+		name          string
+		mock          mock
+		expectedCount int
+		expectedErr   error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockOrderRepository) {
+				r.EXPECT().ExpireOrders(createdBefore, batchSize).Return(3, nil)
+			},
+			expectedCount: 3,
+			expectedErr:   nil,
+		},
+		{
+			name: "Several batches test",
+			mock: func(r *mock_repository.MockOrderRepository) {
+				r.EXPECT().ExpireOrders(createdBefore, batchSize).Return(batchSize, nil).Times(2)
+				r.EXPECT().ExpireOrders(createdBefore, batchSize).Return(0, nil)
+			},
+			expectedCount: 2 * batchSize,
+			expectedErr:   nil,
+		},
+		{
+			name: "Repo fail test",
+			mock: func(r *mock_repository.MockOrderRepository) {
+				r.EXPECT().ExpireOrders(createdBefore, batchSize).Return(batchSize, nil)
+				r.EXPECT().ExpireOrders(createdBefore, batchSize).Return(0, errors.New("error"))
+			},
+			expectedCount: batchSize,
+			expectedErr:   errors.Wrap(errors.New("error"), "order_expiry.Expire error while ExpireOrders call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockOrderRepository(ctrl)
+			tc.mock(repo)
+
+			o := NewOrderExpiry(repo, config.Payments{PendingTtl: time.Hour})
+			o.now = func() time.Time { return now }
+
+			count, err := o.Expire()
+
+			assert.Equal(t, tc.expectedCount, count)
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, tc.expectedErr.Error(), err.Error())
+			}
+		})
+	}
+}
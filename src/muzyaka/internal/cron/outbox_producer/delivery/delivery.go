@@ -0,0 +1,73 @@
+package delivery
+
+import (
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"net/http"
+	"src/internal/cron/outbox_producer/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/models"
+	"src/internal/models/dto"
+)
+
+// @Summary GetOutboxStats
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description get outbox relay stats
+// @ID get-outbox-stats
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} dto.OutboxStats
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/admin/outbox/stats [get]
+func GetOutboxStats(useCase usecase.OutboxUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		metrics, err := useCase.GetMetrics()
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoOutboxStats(metrics))
+	}
+}
+
+// @Summary ReplayOutbox
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description send processed or dead lettered outbox events again
+// @ID replay-outbox
+// @Accept  json
+// @Produce  json
+// @Param input body dto.OutboxReplayRequest true "events to replay"
+// @Success 200 {object} dto.OutboxReplayResponse
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/admin/outbox/replay [post]
+func ReplayOutbox(useCase usecase.OutboxUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.OutboxReplayRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		replayed, err := useCase.Replay(dto.ToModelOutboxReplayFilter(&req))
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.OutboxReplayResponse{Replayed: replayed})
+	}
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+	dao "src/internal/models/dao"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteSentBefore mocks base method.
+func (m *MockOutboxRepository) DeleteSentBefore(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSentBefore", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSentBefore indicates an expected call of DeleteSentBefore.
+func (mr *MockOutboxRepositoryMockRecorder) DeleteSentBefore(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSentBefore", reflect.TypeOf((*MockOutboxRepository)(nil).DeleteSentBefore), before)
+}
+
+// GetStats mocks base method.
+func (m *MockOutboxRepository) GetStats() (*models.OutboxStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats")
+	ret0, _ := ret[0].(*models.OutboxStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockOutboxRepositoryMockRecorder) GetStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockOutboxRepository)(nil).GetStats))
+}
+
+// ProcessWaitingEvents mocks base method.
+func (m *MockOutboxRepository) ProcessWaitingEvents(limit int, process func([]*dao.Outbox) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessWaitingEvents", limit, process)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessWaitingEvents indicates an expected call of ProcessWaitingEvents.
+func (mr *MockOutboxRepositoryMockRecorder) ProcessWaitingEvents(limit, process interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessWaitingEvents", reflect.TypeOf((*MockOutboxRepository)(nil).ProcessWaitingEvents), limit, process)
+}
+
+// ReplayEvents mocks base method.
+func (m *MockOutboxRepository) ReplayEvents(filter *models.OutboxReplayFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayEvents", filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayEvents indicates an expected call of ReplayEvents.
+func (mr *MockOutboxRepositoryMockRecorder) ReplayEvents(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayEvents", reflect.TypeOf((*MockOutboxRepository)(nil).ReplayEvents), filter)
+}
@@ -3,8 +3,12 @@ package postgres
 import (
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
 	"src/internal/cron/outbox_producer/repository"
+	"src/internal/models"
 	"src/internal/models/dao"
+	"time"
 )
 
 type outboxRepo struct {
@@ -15,24 +19,166 @@ func NewOutboxRepo(db *gorm.DB) repository.OutboxRepository {
 	return &outboxRepo{db: db}
 }
 
-func (o outboxRepo) GetWaitingEvents() ([]*dao.Outbox, error) {
-	var events []*dao.Outbox
+// pendingEvent matches events that are not sent or dead lettered yet
+const pendingEvent = "sent = false AND dead_lettered = false"
 
-	tx := o.db.Limit(dao.MaxLimit).Find(&events, "sent = false").Order("id")
+func (o outboxRepo) ProcessWaitingEvents(limit int, process func(events []*dao.Outbox) error) error {
+	var processErr error
 
-	if tx.Error != nil {
-		return nil, errors.Wrap(tx.Error, "GetWaitingEvents database error (table outbox)")
+	err := o.db.Transaction(func(tx *gorm.DB) error {
+		events, err := claimWaitingEvents(tx, limit)
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		if processErr = process(events); processErr != nil {
+			return processErr
+		}
+
+		for _, v := range events {
+			if err := tx.Model(v).
+				Select("sent", "sent_at", "attempts", "last_error", "next_attempt_at", "dead_lettered").
+				Updates(v).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if processErr != nil {
+		return processErr
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "ProcessWaitingEvents database error (table outbox)")
 	}
 
+	return nil
+}
+
+// claimWaitingEvents locks the earliest pending event of every aggregate it
+// takes, relays skip locked ones, so an aggregate is sent by one relay at a
+// time. Later due events of the same aggregates are claimed with them, up to
+// the first one that is not due.
+func claimWaitingEvents(tx *gorm.DB, limit int) ([]*dao.Outbox, error) {
+	var heads []*dao.Outbox
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where(pendingEvent + " AND next_attempt_at <= now()").
+		Where(`NOT EXISTS (SELECT 1
+		                   FROM outbox e
+		                   WHERE e.aggregate_type = outbox.aggregate_type
+		                     AND e.aggregate_id = outbox.aggregate_id
+		                     AND e.id < outbox.id
+		                     AND e.sent = false
+		                     AND e.dead_lettered = false)`).
+		Order("id").
+		Limit(limit).
+		Find(&heads).Error; err != nil {
+		return nil, err
+	}
+
+	if len(heads) == 0 || len(heads) == limit {
+		return heads, nil
+	}
+
+	ids := make([]uint64, 0, len(heads))
+	aggregates := make([][]interface{}, 0, len(heads))
+	for _, v := range heads {
+		ids = append(ids, v.ID)
+		aggregates = append(aggregates, []interface{}{v.AggregateType, v.AggregateId})
+	}
+
+	var later []*dao.Outbox
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(pendingEvent+" AND next_attempt_at <= now()").
+		Where("(aggregate_type, aggregate_id) IN ? AND id NOT IN ?", aggregates, ids).
+		Where(`NOT EXISTS (SELECT 1
+		                   FROM outbox e
+		                   WHERE e.aggregate_type = outbox.aggregate_type
+		                     AND e.aggregate_id = outbox.aggregate_id
+		                     AND e.id < outbox.id
+		                     AND e.sent = false
+		                     AND e.dead_lettered = false
+		                     AND e.next_attempt_at > now())`).
+		Order("id").
+		Limit(limit - len(heads)).
+		Find(&later).Error; err != nil {
+		return nil, err
+	}
+
+	events := append(heads, later...)
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+
 	return events, nil
 }
 
-func (o outboxRepo) MarkEventsSent(events []*dao.Outbox) error {
-	if len(events) > 0 {
-		tx := o.db.Model(&events).Update("sent", "true")
+func (o outboxRepo) GetStats() (*models.OutboxStats, error) {
+	var stats models.OutboxStats
+
+	tx := o.db.Model(&dao.Outbox{}).Where("sent = false AND dead_lettered = false").Count(&stats.Pending)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "GetStats database error (table outbox)")
+	}
+
+	tx = o.db.Model(&dao.Outbox{}).Where("dead_lettered = true").Count(&stats.DeadLettered)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "GetStats database error (table outbox)")
+	}
+
+	if stats.Pending > 0 {
+		var oldest dao.Outbox
+		tx = o.db.Where("sent = false AND dead_lettered = false").Order("id").Take(&oldest)
 		if tx.Error != nil {
-			return errors.Wrap(tx.Error, "MarkEventsSent database error (table outbox)")
+			return nil, errors.Wrap(tx.Error, "GetStats database error (table outbox)")
 		}
+		stats.OldestPending = &oldest.OccurredAt
 	}
-	return nil
+
+	return &stats, nil
+}
+
+func (o outboxRepo) DeleteSentBefore(before time.Time) (int64, error) {
+	tx := o.db.Where("sent = true AND sent_at < ?", before).Delete(&dao.Outbox{})
+	if tx.Error != nil {
+		return 0, errors.Wrap(tx.Error, "DeleteSentBefore database error (table outbox)")
+	}
+
+	return tx.RowsAffected, nil
+}
+
+func (o outboxRepo) ReplayEvents(filter *models.OutboxReplayFilter) (int64, error) {
+	query := o.db.Model(&dao.Outbox{}).Where("sent = true OR dead_lettered = true")
+
+	if len(filter.EventIds) > 0 {
+		query = query.Where("event_id IN ?", filter.EventIds)
+	}
+	if filter.DeadOnly {
+		query = query.Where("dead_lettered = true")
+	}
+	if filter.Since != nil {
+		query = query.Where("occurred_at >= ?", *filter.Since)
+	}
+
+	tx := query.Updates(map[string]interface{}{
+		"sent":            false,
+		"sent_at":         nil,
+		"attempts":        0,
+		"last_error":      nil,
+		"next_attempt_at": gorm.Expr("now()"),
+		"dead_lettered":   false,
+	})
+	if tx.Error != nil {
+		return 0, errors.Wrap(tx.Error, "ReplayEvents database error (table outbox)")
+	}
+
+	return tx.RowsAffected, nil
 }
@@ -1,8 +1,23 @@
 package repository
 
-import "src/internal/models/dao"
+import (
+	"src/internal/models"
+	"src/internal/models/dao"
+	"time"
+)
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
 
 type OutboxRepository interface {
-	GetWaitingEvents() ([]*dao.Outbox, error)
-	MarkEventsSent(events []*dao.Outbox) error
+	// ProcessWaitingEvents claims up to limit events that are due for sending and
+	// passes them to process, which updates their delivery state in place.
+	// Events are passed in order. The earliest pending event of an aggregate is
+	// claimed with its later due events, so events of an aggregate are never
+	// claimed before an earlier one or by two relays at once.
+	// The state is saved if process succeeds. Claimed rows stay locked until then,
+	// so concurrent relays never get the same events.
+	ProcessWaitingEvents(limit int, process func(events []*dao.Outbox) error) error
+	GetStats() (*models.OutboxStats, error)
+	DeleteSentBefore(before time.Time) (int64, error)
+	ReplayEvents(filter *models.OutboxReplayFilter) (int64, error)
 }
@@ -1,61 +1,301 @@
 package usecase
 
 import (
-	"fmt"
+	"context"
+	"encoding/json"
 	"github.com/IBM/sarama"
 	"github.com/pkg/errors"
+	"log/slog"
+	"src/internal/config"
 	"src/internal/cron/outbox_producer/repository"
 	"src/internal/lib/kafka"
+	"src/internal/models"
 	"src/internal/models/dao"
+	"src/internal/models/events"
+	"strconv"
+	"sync/atomic"
+	"time"
 )
 
+type OutboxUseCase interface {
+	GetMetrics() (*models.OutboxMetrics, error)
+	Replay(filter *models.OutboxReplayFilter) (int64, error)
+}
+
 type OutboxProducer struct {
 	producer   sarama.SyncProducer
 	repository repository.OutboxRepository
+	cfg        config.Outbox
+	now        func() time.Time
+
+	sentTotal         atomic.Uint64
+	failedTotal       atomic.Uint64
+	deadLetteredTotal atomic.Uint64
+	purgedTotal       atomic.Uint64
 }
 
-func NewOutboxUseCase(producer sarama.SyncProducer, outboxRepository repository.OutboxRepository) *OutboxProducer {
+func NewOutboxUseCase(producer sarama.SyncProducer,
+	outboxRepository repository.OutboxRepository,
+	cfg config.Outbox) *OutboxProducer {
 	return &OutboxProducer{
 		producer:   producer,
 		repository: outboxRepository,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+// Run relays outbox events until ctx is cancelled. A full batch is followed by
+// the next one at once, failed runs are retried with exponential backoff, sent
+// events are purged every cfg.CleanupInterval.
+func (op *OutboxProducer) Run(ctx context.Context, logger *slog.Logger) {
+	relay := time.NewTimer(0)
+	defer relay.Stop()
+
+	cleanup := time.NewTicker(op.cfg.CleanupInterval)
+	defer cleanup.Stop()
+
+	failures := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("outbox relay stopped")
+			return
+		case <-cleanup.C:
+			purged, err := op.PurgeSent()
+			if err != nil {
+				logger.Error("outbox cleanup failed", slog.String("error", err.Error()))
+				continue
+			}
+			logger.Debug("outbox cleanup done", slog.Int64("purged", purged))
+		case <-relay.C:
+			full, err := op.ProduceMessages()
+			if err != nil {
+				failures++
+				delay := backoff(failures, op.cfg.BaseBackoff, op.cfg.MaxBackoff)
+				logger.Error("outbox relay failed",
+					slog.String("error", err.Error()),
+					slog.Duration("retry_in", delay))
+				relay.Reset(delay)
+				continue
+			}
+			failures = 0
+			if full {
+				relay.Reset(0)
+				continue
+			}
+			relay.Reset(op.cfg.Interval)
+		}
+	}
+}
+
+type aggregateKey struct {
+	aggregateType string
+	aggregateId   uint64
+}
+
+// ProduceMessages sends a batch of events. Events of an aggregate are sent in
+// order, one per round, and the rest of them wait once one of them fails.
+// Reports whether the batch was full and returns an error if the producer
+// failed to send any of them.
+func (op *OutboxProducer) ProduceMessages() (bool, error) {
+	var sendErr error
+	var full bool
+
+	err := op.repository.ProcessWaitingEvents(op.cfg.BatchSize, func(outbox []*dao.Outbox) error {
+		now := op.now()
+		full = len(outbox) >= op.cfg.BatchSize
+
+		var keys []aggregateKey
+		queues := make(map[aggregateKey][]*dao.Outbox)
+		for _, v := range outbox {
+			key := aggregateKey{aggregateType: v.AggregateType, aggregateId: v.AggregateId}
+			if _, ok := queues[key]; !ok {
+				keys = append(keys, key)
+			}
+			queues[key] = append(queues[key], v)
+		}
+
+		for len(keys) > 0 {
+			saramaMsgs := make([]*sarama.ProducerMessage, 0, len(keys))
+			for _, key := range keys {
+				v := queues[key][0]
+				msg, err := toProducerMessage(v)
+				if err != nil {
+					op.markFailed(v, errors.Wrap(err, "marshal error"), now)
+					continue
+				}
+				msg.Metadata = v
+				saramaMsgs = append(saramaMsgs, msg)
+			}
+
+			if len(saramaMsgs) == 0 {
+				return nil
+			}
+
+			failed := make(map[*dao.Outbox]error)
+			if err := op.producer.SendMessages(saramaMsgs); err != nil {
+				sendErr = err
+
+				var producerErrs sarama.ProducerErrors
+				if errors.As(err, &producerErrs) {
+					for _, v := range producerErrs {
+						failed[v.Msg.Metadata.(*dao.Outbox)] = v.Err
+					}
+				} else {
+					for _, v := range saramaMsgs {
+						failed[v.Metadata.(*dao.Outbox)] = err
+					}
+				}
+			}
+
+			keys = keys[:0]
+			for _, v := range saramaMsgs {
+				event := v.Metadata.(*dao.Outbox)
+				if failErr, ok := failed[event]; ok {
+					op.markFailed(event, failErr, now)
+					continue
+				}
+				op.markSent(event, now)
+
+				key := aggregateKey{aggregateType: event.AggregateType, aggregateId: event.AggregateId}
+				if queue := queues[key]; len(queue) > 1 {
+					queues[key] = queue[1:]
+					keys = append(keys, key)
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, errors.Wrap(err, "outbox_producer.ProduceMessages error from repository")
+	}
+
+	if sendErr != nil {
+		return full, errors.Wrap(sendErr, "outbox_producer.ProduceMessages error while send")
+	}
+
+	return full, nil
+}
+
+func (op *OutboxProducer) PurgeSent() (int64, error) {
+	purged, err := op.repository.DeleteSentBefore(op.now().Add(-op.cfg.Retention))
+	if err != nil {
+		return 0, errors.Wrap(err, "outbox_producer.PurgeSent error from repository")
 	}
+
+	op.purgedTotal.Add(uint64(purged))
+	return purged, nil
 }
 
-func (op *OutboxProducer) ProduceMessages() error {
-	saramaMsgs := make([]*sarama.ProducerMessage, 0, dao.MaxLimit)
+func (op *OutboxProducer) Replay(filter *models.OutboxReplayFilter) (int64, error) {
+	if len(filter.EventIds) == 0 && !filter.DeadOnly && filter.Since == nil {
+		return 0, models.ErrInvalidParameter
+	}
 
-	events, err := op.repository.GetWaitingEvents()
+	replayed, err := op.repository.ReplayEvents(filter)
 	if err != nil {
-		return errors.Wrap(err, "outbox_producer.ProduceMessages error from repository")
+		return 0, errors.Wrap(err, "outbox_producer.Replay error from repository")
 	}
 
-	for _, v := range events {
-		saramaMsgs = append(saramaMsgs, &sarama.ProducerMessage{
-			Topic: kafka.DefaultTopic,
-			Value: sarama.StringEncoder(
-				fmt.Sprintf(
-					"{\"event_id\": \"%s\","+
-						" \"track_id\": %d,"+
-						" \"operation\": \"%s\","+
-						" \"source\": \"%s\","+
-						" \"name\": \"%s\","+
-						" \"genre_id\": %d}",
-					v.EventId,
-					v.TrackId,
-					v.Type,
-					v.Source,
-					v.Name,
-					v.GenreRefer)),
-		})
+	return replayed, nil
+}
+
+func (op *OutboxProducer) GetMetrics() (*models.OutboxMetrics, error) {
+	stats, err := op.repository.GetStats()
+	if err != nil {
+		return nil, errors.Wrap(err, "outbox_producer.GetMetrics error from repository")
 	}
 
-	if err := op.producer.SendMessages(saramaMsgs); err != nil {
-		return errors.Wrap(err, "outbox_producer.ProduceMessages error from producer")
+	var lag time.Duration
+	if stats.OldestPending != nil {
+		lag = op.now().Sub(*stats.OldestPending)
+	}
+
+	return &models.OutboxMetrics{
+		OutboxStats:       *stats,
+		Lag:               lag,
+		SentTotal:         op.sentTotal.Load(),
+		FailedTotal:       op.failedTotal.Load(),
+		DeadLetteredTotal: op.deadLetteredTotal.Load(),
+		PurgedTotal:       op.purgedTotal.Load(),
+	}, nil
+}
+
+func (op *OutboxProducer) markSent(v *dao.Outbox, now time.Time) {
+	v.Sent = true
+	v.SentAt = &now
+	v.LastError = ""
+	op.sentTotal.Add(1)
+}
+
+func (op *OutboxProducer) markFailed(v *dao.Outbox, err error, now time.Time) {
+	v.Attempts++
+	v.LastError = err.Error()
+	op.failedTotal.Add(1)
+
+	if v.Attempts >= op.cfg.MaxAttempts {
+		v.DeadLettered = true
+		op.deadLetteredTotal.Add(1)
+		return
 	}
 
-	if err := op.repository.MarkEventsSent(events); err != nil {
-		return errors.Wrap(err, "outbox_producer.ProduceMessages error from repository")
+	v.NextAttemptAt = now.Add(backoff(v.Attempts, op.cfg.BaseBackoff, op.cfg.MaxBackoff))
+}
+
+// backoff doubles base for every attempt after the first one, up to max
+func backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	if attempt <= 0 {
+		return base
+	}
+
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max || delay <= 0 {
+			return max
+		}
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
+
+// topicForType keeps track events on the topics the recommendation service
+// listens to, every other event goes to the default topic
+func topicForType(eventType string) string {
+	switch eventType {
+	case events.TrackAdded:
+		return kafka.AddTopic
+	case events.TrackDeleted:
+		return kafka.DeleteTopic
+	case events.TrackUpdated:
+		return kafka.UpdateTopic
+	default:
+		return kafka.DefaultTopic
+	}
+}
+
+func toProducerMessage(v *dao.Outbox) (*sarama.ProducerMessage, error) {
+	value, err := json.Marshal(dao.ToEvent(v))
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return &sarama.ProducerMessage{
+		Topic: topicForType(v.Type),
+		Key:   sarama.StringEncoder(strconv.FormatUint(v.AggregateId, 10)),
+		Value: sarama.ByteEncoder(value),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(events.HeaderEventId), Value: []byte(v.EventId)},
+			{Key: []byte(events.HeaderVersion), Value: []byte(strconv.Itoa(events.Version))},
+			{Key: []byte(events.HeaderType), Value: []byte(v.Type)},
+		},
+	}, nil
 }
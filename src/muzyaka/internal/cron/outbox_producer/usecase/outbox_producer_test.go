@@ -0,0 +1,403 @@
+package usecase
+
+import (
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"src/internal/config"
+	mock_repository "src/internal/cron/outbox_producer/repository/mocks"
+	"src/internal/lib/kafka"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"src/internal/models/events"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var (
+	testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	testCfg = config.Outbox{
+		BatchSize:   100,
+		MaxAttempts: 3,
+		BaseBackoff: time.Second,
+		MaxBackoff:  time.Minute,
+		Retention:   time.Hour,
+	}
+)
+
+// partialFailProducer fails every message with the given ids the way a real
+// sync producer does, with sarama.ProducerErrors
+type partialFailProducer struct {
+	*mocks.SyncProducer
+	fail map[uint64]bool
+}
+
+func (p *partialFailProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	var errs sarama.ProducerErrors
+	for _, v := range msgs {
+		if p.fail[v.Metadata.(*dao.Outbox).ID] {
+			errs = append(errs, &sarama.ProducerError{Msg: v, Err: sarama.ErrOutOfBrokers})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func TestOutboxProducer_ProduceMessages(t *testing.T) {
+	type producerMock func(p *mocks.SyncProducer, outbox []*dao.Outbox) sarama.SyncProducer
+	type repoMock func(r *mock_repository.MockOutboxRepository, outbox []*dao.Outbox)
+
+	sentAt := testNow
+
+	defaultRepoMock := func(r *mock_repository.MockOutboxRepository, outbox []*dao.Outbox) {
+		r.EXPECT().ProcessWaitingEvents(testCfg.BatchSize, gomock.Any()).
+			DoAndReturn(func(limit int, process func([]*dao.Outbox) error) error {
+				return process(outbox)
+			})
+	}
+
+	testTable := []struct {
+		name         string
+		batchSize    int
+		outbox       []*dao.Outbox
+		producerMock producerMock
+		repoMock     repoMock
+		expected     []*dao.Outbox
+		expectedFull bool
+		expectedErr  error
+	}{
+		{
+			name: "Usual test",
+			outbox: []*dao.Outbox{
+				{ID: 1, EventId: "e1", AggregateId: 10, Type: events.TrackAdded, Payload: `{"track_id":10,"name":"Say \"hi\""}`},
+				{ID: 2, EventId: "e2", AggregateId: 11, Type: events.TrackDeleted, Payload: `{"track_id":11}`},
+				{ID: 3, EventId: "e3", AggregateId: 1, Type: events.TrackLiked, Payload: `{"user_id":1,"track_id":10}`},
+			},
+			producerMock: func(p *mocks.SyncProducer, outbox []*dao.Outbox) sarama.SyncProducer {
+				for range outbox {
+					p.ExpectSendMessageAndSucceed()
+				}
+				return p
+			},
+			repoMock: defaultRepoMock,
+			expected: []*dao.Outbox{
+				{ID: 1, Sent: true, SentAt: &sentAt},
+				{ID: 2, Sent: true, SentAt: &sentAt},
+				{ID: 3, Sent: true, SentAt: &sentAt},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Producer fail test",
+			outbox: []*dao.Outbox{
+				{ID: 1, EventId: "e1", AggregateId: 10, Type: events.TrackAdded, Payload: `{"track_id":10}`, Attempts: 1},
+			},
+			producerMock: func(p *mocks.SyncProducer, outbox []*dao.Outbox) sarama.SyncProducer {
+				p.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+				return p
+			},
+			repoMock: defaultRepoMock,
+			expected: []*dao.Outbox{
+				{ID: 1, Attempts: 2, LastError: sarama.ErrOutOfBrokers.Error(), NextAttemptAt: testNow.Add(2 * time.Second)},
+			},
+			expectedErr: errors.Wrap(sarama.ErrOutOfBrokers, "outbox_producer.ProduceMessages error while send"),
+		},
+		{
+			name: "Dead letter test",
+			outbox: []*dao.Outbox{
+				{ID: 1, EventId: "e1", AggregateId: 10, Type: events.TrackAdded, Payload: `{"track_id":10}`, Attempts: 2},
+			},
+			producerMock: func(p *mocks.SyncProducer, outbox []*dao.Outbox) sarama.SyncProducer {
+				p.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+				return p
+			},
+			repoMock: defaultRepoMock,
+			expected: []*dao.Outbox{
+				{ID: 1, Attempts: 3, LastError: sarama.ErrOutOfBrokers.Error(), DeadLettered: true},
+			},
+			expectedErr: errors.Wrap(sarama.ErrOutOfBrokers, "outbox_producer.ProduceMessages error while send"),
+		},
+		{
+			name: "Partial fail test",
+			outbox: []*dao.Outbox{
+				{ID: 1, EventId: "e1", AggregateId: 10, Type: events.TrackAdded, Payload: `{"track_id":10}`},
+				{ID: 2, EventId: "e2", AggregateId: 11, Type: events.TrackAdded, Payload: `{"track_id":11}`},
+			},
+			producerMock: func(p *mocks.SyncProducer, outbox []*dao.Outbox) sarama.SyncProducer {
+				return &partialFailProducer{SyncProducer: p, fail: map[uint64]bool{2: true}}
+			},
+			repoMock: defaultRepoMock,
+			expected: []*dao.Outbox{
+				{ID: 1, Sent: true, SentAt: &sentAt},
+				{ID: 2, Attempts: 1, LastError: sarama.ErrOutOfBrokers.Error(), NextAttemptAt: testNow.Add(time.Second)},
+			},
+			expectedErr: errors.Wrap(sarama.ProducerErrors{{Err: sarama.ErrOutOfBrokers}},
+				"outbox_producer.ProduceMessages error while send"),
+		},
+		{
+			name: "Aggregate order test",
+			outbox: []*dao.Outbox{
+				{ID: 1, EventId: "e1", AggregateId: 10, Type: events.TrackAdded, Payload: `{"track_id":10}`},
+				{ID: 2, EventId: "e2", AggregateId: 11, Type: events.TrackAdded, Payload: `{"track_id":11}`},
+				{ID: 3, EventId: "e3", AggregateId: 10, Type: events.TrackUpdated, Payload: `{"track_id":10}`},
+				{ID: 4, EventId: "e4", AggregateId: 11, Type: events.TrackUpdated, Payload: `{"track_id":11}`},
+			},
+			producerMock: func(p *mocks.SyncProducer, outbox []*dao.Outbox) sarama.SyncProducer {
+				return &partialFailProducer{SyncProducer: p, fail: map[uint64]bool{1: true}}
+			},
+			repoMock: defaultRepoMock,
+			expected: []*dao.Outbox{
+				{ID: 1, Attempts: 1, LastError: sarama.ErrOutOfBrokers.Error(), NextAttemptAt: testNow.Add(time.Second)},
+				{ID: 2, Sent: true, SentAt: &sentAt},
+				{ID: 3},
+				{ID: 4, Sent: true, SentAt: &sentAt},
+			},
+			expectedErr: errors.Wrap(sarama.ProducerErrors{{Err: sarama.ErrOutOfBrokers}},
+				"outbox_producer.ProduceMessages error while send"),
+		},
+		{
+			name:      "Full batch test",
+			batchSize: 2,
+			outbox: []*dao.Outbox{
+				{ID: 1, EventId: "e1", AggregateId: 10, Type: events.TrackAdded, Payload: `{"track_id":10}`},
+				{ID: 2, EventId: "e2", AggregateId: 10, Type: events.TrackUpdated, Payload: `{"track_id":10}`},
+			},
+			producerMock: func(p *mocks.SyncProducer, outbox []*dao.Outbox) sarama.SyncProducer {
+				for range outbox {
+					p.ExpectSendMessageAndSucceed()
+				}
+				return p
+			},
+			repoMock: func(r *mock_repository.MockOutboxRepository, outbox []*dao.Outbox) {
+				r.EXPECT().ProcessWaitingEvents(2, gomock.Any()).
+					DoAndReturn(func(limit int, process func([]*dao.Outbox) error) error {
+						return process(outbox)
+					})
+			},
+			expected: []*dao.Outbox{
+				{ID: 1, Sent: true, SentAt: &sentAt},
+				{ID: 2, Sent: true, SentAt: &sentAt},
+			},
+			expectedFull: true,
+			expectedErr:  nil,
+		},
+		{
+			name:   "Repository fail test",
+			outbox: nil,
+			producerMock: func(p *mocks.SyncProducer, outbox []*dao.Outbox) sarama.SyncProducer {
+				return p
+			},
+			repoMock: func(r *mock_repository.MockOutboxRepository, outbox []*dao.Outbox) {
+				r.EXPECT().ProcessWaitingEvents(testCfg.BatchSize, gomock.Any()).Return(errors.New("error"))
+			},
+			expectedErr: errors.Wrap(errors.New("error"), "outbox_producer.ProduceMessages error from repository"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			producer := mocks.NewSyncProducer(t, nil)
+			defer producer.Close()
+
+			repo := mock_repository.NewMockOutboxRepository(ctrl)
+			tc.repoMock(repo, tc.outbox)
+
+			cfg := testCfg
+			if tc.batchSize > 0 {
+				cfg.BatchSize = tc.batchSize
+			}
+
+			u := NewOutboxUseCase(tc.producerMock(producer, tc.outbox), repo, cfg)
+			u.now = func() time.Time { return testNow }
+			full, err := u.ProduceMessages()
+
+			assert.Equal(t, tc.expectedFull, full)
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			}
+
+			for i, v := range tc.expected {
+				got := tc.outbox[i]
+				assert.Equal(t, v.ID, got.ID)
+				assert.Equal(t, v.Sent, got.Sent)
+				assert.Equal(t, v.SentAt, got.SentAt)
+				assert.Equal(t, v.Attempts, got.Attempts)
+				assert.Equal(t, v.LastError, got.LastError)
+				assert.Equal(t, v.NextAttemptAt, got.NextAttemptAt)
+				assert.Equal(t, v.DeadLettered, got.DeadLettered)
+			}
+		})
+	}
+}
+
+func TestOutboxProducer_Replay(t *testing.T) {
+	type mock func(r *mock_repository.MockOutboxRepository, filter *models.OutboxReplayFilter)
+
+	testTable := []struct {
+		name        string
+		filter      *models.OutboxReplayFilter
+		mock        mock
+		expected    int64
+		expectedErr error
+	}{
+		{
+			name:   "Usual test",
+			filter: &models.OutboxReplayFilter{DeadOnly: true},
+			mock: func(r *mock_repository.MockOutboxRepository, filter *models.OutboxReplayFilter) {
+				r.EXPECT().ReplayEvents(filter).Return(int64(5), nil)
+			},
+			expected:    5,
+			expectedErr: nil,
+		},
+		{
+			name:        "Empty filter test",
+			filter:      &models.OutboxReplayFilter{},
+			mock:        func(r *mock_repository.MockOutboxRepository, filter *models.OutboxReplayFilter) {},
+			expected:    0,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:   "Repo fail test",
+			filter: &models.OutboxReplayFilter{EventIds: []string{"e1"}},
+			mock: func(r *mock_repository.MockOutboxRepository, filter *models.OutboxReplayFilter) {
+				r.EXPECT().ReplayEvents(filter).Return(int64(0), errors.New("error"))
+			},
+			expected:    0,
+			expectedErr: errors.Wrap(errors.New("error"), "outbox_producer.Replay error from repository"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockOutboxRepository(ctrl)
+			tc.mock(repo, tc.filter)
+
+			u := NewOutboxUseCase(mocks.NewSyncProducer(t, nil), repo, testCfg)
+			replayed, err := u.Replay(tc.filter)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			}
+			assert.Equal(t, tc.expected, replayed)
+		})
+	}
+}
+
+func TestOutboxProducer_PurgeAndMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oldest := testNow.Add(-time.Minute)
+
+	repo := mock_repository.NewMockOutboxRepository(ctrl)
+	repo.EXPECT().DeleteSentBefore(testNow.Add(-testCfg.Retention)).Return(int64(7), nil)
+	repo.EXPECT().GetStats().Return(&models.OutboxStats{Pending: 2, DeadLettered: 1, OldestPending: &oldest}, nil)
+
+	u := NewOutboxUseCase(mocks.NewSyncProducer(t, nil), repo, testCfg)
+	u.now = func() time.Time { return testNow }
+
+	purged, err := u.PurgeSent()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), purged)
+
+	metrics, err := u.GetMetrics()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), metrics.Pending)
+	assert.Equal(t, int64(1), metrics.DeadLettered)
+	assert.Equal(t, time.Minute, metrics.Lag)
+	assert.Equal(t, uint64(7), metrics.PurgedTotal)
+}
+
+func TestBackoff(t *testing.T) {
+	testTable := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 1, expected: time.Second},
+		{attempt: 2, expected: 2 * time.Second},
+		{attempt: 4, expected: 8 * time.Second},
+		{attempt: 7, expected: time.Minute},
+		{attempt: 100, expected: time.Minute},
+	}
+
+	for _, tc := range testTable {
+		assert.Equal(t, tc.expected, backoff(tc.attempt, time.Second, time.Minute))
+	}
+}
+
+func TestToProducerMessage(t *testing.T) {
+	testTable := []struct {
+		name          string
+		outbox        *dao.Outbox
+		expectedTopic string
+	}{
+		{
+			name: "Track added event",
+			outbox: &dao.Outbox{EventId: "e1", AggregateType: events.AggregateTrack, AggregateId: 10,
+				Type: events.TrackAdded, Payload: `{"track_id":10,"name":"\"quoted\" name"}`},
+			expectedTopic: kafka.AddTopic,
+		},
+		{
+			name: "Track deleted event",
+			outbox: &dao.Outbox{EventId: "e2", AggregateType: events.AggregateTrack, AggregateId: 11,
+				Type: events.TrackDeleted, Payload: `{"track_id":11}`},
+			expectedTopic: kafka.DeleteTopic,
+		},
+		{
+			name: "Track updated event",
+			outbox: &dao.Outbox{EventId: "e3", AggregateType: events.AggregateTrack, AggregateId: 12,
+				Type: events.TrackUpdated, Payload: `{"track_id":12}`},
+			expectedTopic: kafka.UpdateTopic,
+		},
+		{
+			name: "Other event",
+			outbox: &dao.Outbox{EventId: "e4", AggregateType: events.AggregateAlbum, AggregateId: 13,
+				Type: events.AlbumUpdated, Payload: `{"album_id":13}`},
+			expectedTopic: kafka.DefaultTopic,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			msg, err := toProducerMessage(tc.outbox)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedTopic, msg.Topic)
+
+			key, err := msg.Key.Encode()
+			assert.NoError(t, err)
+			assert.Equal(t, []byte(strconv.FormatUint(tc.outbox.AggregateId, 10)), key)
+
+			assert.Equal(t, []byte(events.HeaderEventId), msg.Headers[0].Key)
+			assert.Equal(t, []byte(tc.outbox.EventId), msg.Headers[0].Value)
+
+			value, err := msg.Value.Encode()
+			assert.NoError(t, err)
+
+			var event events.Event
+			assert.NoError(t, json.Unmarshal(value, &event))
+			assert.Equal(t, events.Version, event.Version)
+			assert.Equal(t, tc.outbox.EventId, event.EventId)
+			assert.Equal(t, tc.outbox.AggregateType, event.AggregateType)
+			assert.Equal(t, tc.outbox.AggregateId, event.AggregateId)
+			assert.Equal(t, tc.outbox.Type, event.Type)
+			assert.JSONEq(t, tc.outbox.Payload, string(event.Payload))
+		})
+	}
+}
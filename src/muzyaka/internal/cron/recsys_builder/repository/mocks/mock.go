@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRecSysBuilderRepository is a mock of RecSysBuilderRepository interface.
+type MockRecSysBuilderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecSysBuilderRepositoryMockRecorder
+}
+
+// MockRecSysBuilderRepositoryMockRecorder is the mock recorder for MockRecSysBuilderRepository.
+type MockRecSysBuilderRepositoryMockRecorder struct {
+	mock *MockRecSysBuilderRepository
+}
+
+// NewMockRecSysBuilderRepository creates a new mock instance.
+func NewMockRecSysBuilderRepository(ctrl *gomock.Controller) *MockRecSysBuilderRepository {
+	mock := &MockRecSysBuilderRepository{ctrl: ctrl}
+	mock.recorder = &MockRecSysBuilderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecSysBuilderRepository) EXPECT() *MockRecSysBuilderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetBaskets mocks base method.
+func (m *MockRecSysBuilderRepository) GetBaskets() ([][]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBaskets")
+	ret0, _ := ret[0].([][]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBaskets indicates an expected call of GetBaskets.
+func (mr *MockRecSysBuilderRepositoryMockRecorder) GetBaskets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBaskets", reflect.TypeOf((*MockRecSysBuilderRepository)(nil).GetBaskets))
+}
+
+// ReplaceNeighbours mocks base method.
+func (m *MockRecSysBuilderRepository) ReplaceNeighbours(neighbours map[uint64][]*models.ScoredTrack) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceNeighbours", neighbours)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceNeighbours indicates an expected call of ReplaceNeighbours.
+func (mr *MockRecSysBuilderRepositoryMockRecorder) ReplaceNeighbours(neighbours interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceNeighbours", reflect.TypeOf((*MockRecSysBuilderRepository)(nil).ReplaceNeighbours), neighbours)
+}
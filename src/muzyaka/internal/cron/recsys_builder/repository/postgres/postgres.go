@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/cron/recsys_builder/repository"
+	"src/internal/models"
+	"src/internal/models/dao"
+)
+
+const insertBatchSize = 1000
+
+type recSysBuilderRepo struct {
+	db *gorm.DB
+}
+
+func NewRecSysBuilderRepo(db *gorm.DB) repository.RecSysBuilderRepository {
+	return &recSysBuilderRepo{db: db}
+}
+
+type basketRow struct {
+	Basket  uint64
+	TrackId uint64
+}
+
+func (r recSysBuilderRepo) GetBaskets() ([][]uint64, error) {
+	var likes []*basketRow
+	tx := r.db.Model(&dao.UserTrack{}).
+		Select("user_id AS basket, track_id").
+		Order("user_id").
+		Scan(&likes)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "GetBaskets database error (table user_track)")
+	}
+
+	// generated playlists are made of recommendations, so only the users'
+	// own playlists count
+	var playlists []*basketRow
+	tx = r.db.Model(&dao.PlaylistTrack{}).
+		Select("track_playlist.playlist_id AS basket, track_playlist.track_id").
+		Joins("JOIN playlists p ON p.id = track_playlist.playlist_id").
+		Where("p.kind = ?", models.PlaylistKindUser).
+		Order("track_playlist.playlist_id").
+		Scan(&playlists)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "GetBaskets database error (table track_playlist)")
+	}
+
+	return append(groupBaskets(likes), groupBaskets(playlists)...), nil
+}
+
+func (r recSysBuilderRepo) ReplaceNeighbours(neighbours map[uint64][]*models.ScoredTrack) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&dao.TrackNeighbour{}).Error; err != nil {
+			return err
+		}
+
+		// tracks deleted while the neighbours were computed are skipped
+		var ids []uint64
+		if err := tx.Model(&dao.TrackMeta{}).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		existing := make(map[uint64]bool, len(ids))
+		for _, v := range ids {
+			existing[v] = true
+		}
+
+		var rows []*dao.TrackNeighbour
+		for trackId, v := range neighbours {
+			if !existing[trackId] {
+				continue
+			}
+
+			position := 1
+			for _, neighbour := range v {
+				if !existing[neighbour.TrackId] {
+					continue
+				}
+				rows = append(rows, &dao.TrackNeighbour{
+					TrackId:     trackId,
+					NeighbourId: neighbour.TrackId,
+					Position:    position,
+					Score:       neighbour.Score,
+				})
+				position++
+			}
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+
+		return tx.CreateInBatches(rows, insertBatchSize).Error
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "ReplaceNeighbours database error (table track_neighbours)")
+	}
+
+	return nil
+}
+
+// groupBaskets expects rows ordered by basket
+func groupBaskets(rows []*basketRow) [][]uint64 {
+	var baskets [][]uint64
+	for i, v := range rows {
+		if i == 0 || rows[i-1].Basket != v.Basket {
+			baskets = append(baskets, nil)
+		}
+		baskets[len(baskets)-1] = append(baskets[len(baskets)-1], v.TrackId)
+	}
+
+	return baskets
+}
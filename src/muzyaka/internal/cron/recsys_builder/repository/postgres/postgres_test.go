@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	postgres2 "src/internal/domain/album/repository/postgres"
+	"src/internal/lib/testhelpers"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"testing"
+)
+
+func TestRepo_GetBasketsAndReplaceNeighbours(t *testing.T) {
+	ctx := context.Background()
+	pgContainer, err := testhelpers.CreatePostgresContainer(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer func() {
+		if err := pgContainer.Terminate(ctx); err != nil {
+			log.Fatalf("error terminating postgres container: %s", err)
+		}
+	}()
+
+	db, err := gorm.Open(postgres.Open(pgContainer.ConnectionString), &gorm.Config{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = db.Exec("insert into genres (name) values ('test')").Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = db.Exec("insert into musicians (name, description) values ('test', 'test')").Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = db.Exec("insert into users (name, email, password) values ('Sasha', 'test3@gmail.test', 'aaaaaa')").Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = postgres2.NewAlbumRepository(db).AddAlbumWithTracksOutbox(&models.Album{
+		Name: "TestName", CoverFile: []byte("TestCover"), Type: "LP",
+	}, []*models.TrackMeta{
+		{Source: "TestSrc1", Name: "TestName1", Genre: "test"},
+		{Source: "TestSrc2", Name: "TestName2", Genre: "test"},
+		{Source: "TestSrc3", Name: "TestName3", Genre: "test"},
+	}, 1)
+	require.NoError(t, err)
+
+	err = db.Exec("insert into user_track (user_id, track_id) values (1, 1), (1, 2)").Error
+	require.NoError(t, err)
+	err = db.Exec("insert into playlists (name, cover_file, user_id) values ('test', 'cover', 1)").Error
+	require.NoError(t, err)
+	err = db.Exec("insert into track_playlist (playlist_id, track_id) values (1, 2), (1, 3)").Error
+	require.NoError(t, err)
+
+	repository := NewRecSysBuilderRepo(db)
+
+	baskets, err := repository.GetBaskets()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, [][]uint64{{1, 2}, {2, 3}}, baskets)
+
+	err = repository.ReplaceNeighbours(map[uint64][]*models.ScoredTrack{
+		1: {{TrackId: 100, Score: 1}, {TrackId: 2, Score: 0.5}}, // unknown track is skipped
+		2: {{TrackId: 1, Score: 0.5}},
+	})
+	assert.NoError(t, err)
+
+	var neighbours []*dao.TrackNeighbour
+	assert.NoError(t, db.Order("track_id, position").Find(&neighbours).Error)
+	assert.Equal(t, []*dao.TrackNeighbour{
+		{TrackId: 1, NeighbourId: 2, Position: 1, Score: 0.5},
+		{TrackId: 2, NeighbourId: 1, Position: 1, Score: 0.5},
+	}, neighbours)
+
+	err = repository.ReplaceNeighbours(map[uint64][]*models.ScoredTrack{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Find(&neighbours).Error)
+	assert.Empty(t, neighbours)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "src/internal/models"
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+type RecSysBuilderRepository interface {
+	// GetBaskets returns sets of tracks that were picked together:
+	// liked tracks of every user and tracks of every playlist made by a user
+	GetBaskets() ([][]uint64, error)
+	// ReplaceNeighbours swaps all stored neighbours in one transaction
+	ReplaceNeighbours(neighbours map[uint64][]*models.ScoredTrack) error
+}
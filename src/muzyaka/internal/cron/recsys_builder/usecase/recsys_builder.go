@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log/slog"
+	"math"
+	"sort"
+	"src/internal/config"
+	"src/internal/cron/recsys_builder/repository"
+	"src/internal/models"
+	"time"
+)
+
+// Baskets larger than this say little about similarity of their tracks
+// and would make the job quadratic in their size, so they are skipped.
+const maxBasketSize = 500
+
+// RecSysBuilder computes item-item neighbours for the built-in recommender
+type RecSysBuilder struct {
+	repository repository.RecSysBuilderRepository
+	cfg        config.RecSys
+}
+
+func NewRecSysBuilder(builderRepository repository.RecSysBuilderRepository, cfg config.RecSys) *RecSysBuilder {
+	return &RecSysBuilder{
+		repository: builderRepository,
+		cfg:        cfg,
+	}
+}
+
+// Run rebuilds neighbours on start and then every cfg.RebuildInterval until ctx is cancelled
+func (b *RecSysBuilder) Run(ctx context.Context, logger *slog.Logger) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("recsys builder stopped")
+			return
+		case <-timer.C:
+			start := time.Now()
+			if err := b.Rebuild(); err != nil {
+				logger.Error("recsys rebuild failed", slog.String("error", err.Error()))
+			} else {
+				logger.Debug("recsys rebuild done", slog.Duration("took", time.Since(start)))
+			}
+			timer.Reset(b.cfg.RebuildInterval)
+		}
+	}
+}
+
+func (b *RecSysBuilder) Rebuild() error {
+	baskets, err := b.repository.GetBaskets()
+	if err != nil {
+		return errors.Wrap(err, "recsys_builder.Rebuild error while GetBaskets call")
+	}
+
+	err = b.repository.ReplaceNeighbours(computeNeighbours(baskets, b.cfg.TopK))
+	if err != nil {
+		return errors.Wrap(err, "recsys_builder.Rebuild error while ReplaceNeighbours call")
+	}
+
+	return nil
+}
+
+// computeNeighbours returns top k tracks for every track by cosine similarity
+// of their basket vectors: co(a, b) / sqrt(n(a) * n(b)), where co is the number of
+// baskets with both tracks and n is the number of baskets with the track.
+func computeNeighbours(baskets [][]uint64, k int) map[uint64][]*models.ScoredTrack {
+	counts := make(map[uint64]int)
+	cooccurrences := make(map[uint64]map[uint64]int)
+
+	for _, basket := range baskets {
+		tracks := unique(basket)
+		if len(tracks) > maxBasketSize {
+			continue
+		}
+
+		for i, a := range tracks {
+			counts[a]++
+			for _, b := range tracks[i+1:] {
+				increment(cooccurrences, a, b)
+				increment(cooccurrences, b, a)
+			}
+		}
+	}
+
+	neighbours := make(map[uint64][]*models.ScoredTrack, len(cooccurrences))
+	for a, row := range cooccurrences {
+		scored := make([]*models.ScoredTrack, 0, len(row))
+		for b, co := range row {
+			scored = append(scored, &models.ScoredTrack{
+				TrackId: b,
+				Score:   float64(co) / math.Sqrt(float64(counts[a])*float64(counts[b])),
+			})
+		}
+
+		sort.Slice(scored, func(i, j int) bool {
+			if scored[i].Score != scored[j].Score {
+				return scored[i].Score > scored[j].Score
+			}
+			return scored[i].TrackId < scored[j].TrackId
+		})
+
+		if len(scored) > k {
+			scored = scored[:k]
+		}
+		neighbours[a] = scored
+	}
+
+	return neighbours
+}
+
+func increment(m map[uint64]map[uint64]int, a uint64, b uint64) {
+	row, ok := m[a]
+	if !ok {
+		row = make(map[uint64]int)
+		m[a] = row
+	}
+	row[b]++
+}
+
+func unique(tracks []uint64) []uint64 {
+	seen := make(map[uint64]bool, len(tracks))
+	res := make([]uint64, 0, len(tracks))
+	for _, v := range tracks {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
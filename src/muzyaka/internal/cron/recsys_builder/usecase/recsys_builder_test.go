@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"src/internal/config"
+	mock_repository "src/internal/cron/recsys_builder/repository/mocks"
+	"src/internal/models"
+	"testing"
+)
+
+func TestComputeNeighbours(t *testing.T) {
+	testTable := []struct {
+		name     string
+		baskets  [][]uint64
+		k        int
+		expected map[uint64][]*models.ScoredTrack
+	}{
+		{
+			name: "Usual test",
+			baskets: [][]uint64{
+				{1, 2, 3},
+				{1, 2},
+				{2, 3},
+				{4},
+			},
+			k: 10,
+			expected: map[uint64][]*models.ScoredTrack{
+				1: {{TrackId: 2, Score: 2 / math.Sqrt(6)}, {TrackId: 3, Score: 1 / math.Sqrt(4)}},
+				2: {{TrackId: 1, Score: 2 / math.Sqrt(6)}, {TrackId: 3, Score: 2 / math.Sqrt(6)}},
+				3: {{TrackId: 2, Score: 2 / math.Sqrt(6)}, {TrackId: 1, Score: 1 / math.Sqrt(4)}},
+			},
+		},
+		{
+			name: "Top k test",
+			baskets: [][]uint64{
+				{1, 2, 3},
+				{1, 3},
+			},
+			k: 1,
+			expected: map[uint64][]*models.ScoredTrack{
+				1: {{TrackId: 3, Score: 1}},
+				2: {{TrackId: 1, Score: 1 / math.Sqrt(2)}},
+				3: {{TrackId: 1, Score: 1}},
+			},
+		},
+		{
+			name: "Duplicates in basket test",
+			baskets: [][]uint64{
+				{1, 1, 2},
+			},
+			k: 10,
+			expected: map[uint64][]*models.ScoredTrack{
+				1: {{TrackId: 2, Score: 1}},
+				2: {{TrackId: 1, Score: 1}},
+			},
+		},
+		{
+			name:     "Empty test",
+			baskets:  nil,
+			k:        10,
+			expected: map[uint64][]*models.ScoredTrack{},
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			neighbours := computeNeighbours(tc.baskets, tc.k)
+
+			assert.Equal(t, len(tc.expected), len(neighbours))
+			for trackId, expected := range tc.expected {
+				got := neighbours[trackId]
+				assert.Equal(t, len(expected), len(got))
+				for i := range expected {
+					assert.Equal(t, expected[i].TrackId, got[i].TrackId)
+					assert.InDelta(t, expected[i].Score, got[i].Score, 1e-9)
+				}
+			}
+		})
+	}
+}
+
+func TestRecSysBuilder_Rebuild(t *testing.T) {
+	type mock func(r *mock_repository.MockRecSysBuilderRepository)
+
+	testTable := []struct {
+		name        string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockRecSysBuilderRepository) {
+				r.EXPECT().GetBaskets().Return([][]uint64{{1, 2}}, nil)
+				r.EXPECT().ReplaceNeighbours(map[uint64][]*models.ScoredTrack{
+					1: {{TrackId: 2, Score: 1}},
+					2: {{TrackId: 1, Score: 1}},
+				}).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "GetBaskets fail test",
+			mock: func(r *mock_repository.MockRecSysBuilderRepository) {
+				r.EXPECT().GetBaskets().Return(nil, errors.New("error"))
+			},
+			expectedErr: errors.Wrap(errors.New("error"), "recsys_builder.Rebuild error while GetBaskets call"),
+		},
+		{
+			name: "ReplaceNeighbours fail test",
+			mock: func(r *mock_repository.MockRecSysBuilderRepository) {
+				r.EXPECT().GetBaskets().Return(nil, nil)
+				r.EXPECT().ReplaceNeighbours(gomock.Any()).Return(errors.New("error"))
+			},
+			expectedErr: errors.Wrap(errors.New("error"), "recsys_builder.Rebuild error while ReplaceNeighbours call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockRecSysBuilderRepository(ctrl)
+			tc.mock(repo)
+
+			b := NewRecSysBuilder(repo, config.RecSys{TopK: 10})
+			err := b.Rebuild()
+
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			}
+		})
+	}
+}
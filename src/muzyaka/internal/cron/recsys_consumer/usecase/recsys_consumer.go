@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"github.com/pkg/errors"
+	"log/slog"
+	"src/internal/domain/recsys/repository"
+	"src/internal/lib/kafka"
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
+)
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+var Topics = []string{kafka.RecSysSimilarTopic, kafka.RecSysTrendingTopic}
+
+// RecSysConsumer applies results pushed by the recommendation service.
+// Offsets are marked only after an event is stored, so events may be
+// delivered again after a rebalance; the repository skips duplicates by event id.
+type RecSysConsumer struct {
+	group      sarama.ConsumerGroup
+	repository repository.RecSysRepository
+	logger     *slog.Logger
+}
+
+func NewRecSysConsumer(group sarama.ConsumerGroup,
+	recSysRepository repository.RecSysRepository,
+	logger *slog.Logger) *RecSysConsumer {
+	return &RecSysConsumer{
+		group:      group,
+		repository: recSysRepository,
+		logger:     logger,
+	}
+}
+
+// Run consumes until ctx is cancelled. Consume returns on every rebalance,
+// so it is called in a loop to join the new generation.
+func (c *RecSysConsumer) Run(ctx context.Context) {
+	errs := c.group.Errors()
+	go func() {
+		for err := range errs {
+			c.logger.Error("recsys consumer error", slog.String("error", err.Error()))
+		}
+	}()
+
+	for {
+		err := c.group.Consume(ctx, Topics, c)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return
+		} else if err != nil {
+			c.logger.Error("recsys consume failed", slog.String("error", err.Error()))
+		}
+
+		if ctx.Err() != nil {
+			c.logger.Info("recsys consumer stopped")
+			return
+		}
+	}
+}
+
+func (c *RecSysConsumer) Setup(session sarama.ConsumerGroupSession) error {
+	c.logger.Info("recsys consumer partitions assigned",
+		slog.Any("claims", session.Claims()),
+		slog.Int("generation", int(session.GenerationID())))
+	return nil
+}
+
+func (c *RecSysConsumer) Cleanup(session sarama.ConsumerGroupSession) error {
+	c.logger.Info("recsys consumer partitions revoked",
+		slog.Int("generation", int(session.GenerationID())))
+	return nil
+}
+
+func (c *RecSysConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			if !c.handleWithRetry(session.Context(), msg) {
+				// the session ended before the event was stored, the next
+				// owner of the partition gets it again
+				return nil
+			}
+			session.MarkMessage(msg, "")
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+// handleWithRetry retries storage errors until ctx is done. Malformed events
+// can never succeed, they are logged and skipped.
+func (c *RecSysConsumer) handleWithRetry(ctx context.Context, msg *sarama.ConsumerMessage) bool {
+	delay := retryBaseDelay
+
+	for {
+		err := c.HandleMessage(msg)
+		if err == nil {
+			return true
+		}
+
+		if errors.Is(err, models.ErrInvalidPayload) || errors.Is(err, models.ErrUnknownEvent) {
+			c.logger.Error("recsys event skipped",
+				slog.String("topic", msg.Topic),
+				slog.Int64("offset", msg.Offset),
+				slog.String("error", err.Error()))
+			return true
+		}
+
+		c.logger.Error("recsys event handling failed",
+			slog.String("topic", msg.Topic),
+			slog.Int64("offset", msg.Offset),
+			slog.String("error", err.Error()),
+			slog.Duration("retry_in", delay))
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > retryMaxDelay {
+			delay = retryMaxDelay
+		}
+	}
+}
+
+func (c *RecSysConsumer) HandleMessage(msg *sarama.ConsumerMessage) error {
+	var event events.Event
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return errors.Wrap(models.ErrInvalidPayload, err.Error())
+	}
+
+	if event.EventId == "" {
+		return errors.Wrap(models.ErrInvalidPayload, "empty event id")
+	}
+
+	var err error
+	switch event.Type {
+	case events.RecSysSimilarTracks:
+		var payload events.SimilarTracksPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return errors.Wrap(models.ErrInvalidPayload, err.Error())
+		}
+		err = c.repository.SaveSimilarTracks(event.EventId, toModelSimilarTracks(&payload))
+	case events.RecSysTrending:
+		var payload events.TrendingPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return errors.Wrap(models.ErrInvalidPayload, err.Error())
+		}
+		err = c.repository.SaveTrendingTracks(event.EventId, toModelTrendingTracks(&payload))
+	default:
+		return errors.Wrap(models.ErrUnknownEvent, event.Type)
+	}
+
+	if errors.Is(err, models.ErrAlreadyProcessed) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "recsys_consumer.HandleMessage error from repository")
+	}
+
+	return nil
+}
+
+func toModelSimilarTracks(p *events.SimilarTracksPayload) *models.SimilarTracks {
+	return &models.SimilarTracks{
+		TrackId:    p.TrackId,
+		Tracks:     toModelScoredTracks(p.Similar),
+		ComputedAt: p.ComputedAt,
+	}
+}
+
+func toModelTrendingTracks(p *events.TrendingPayload) *models.TrendingTracks {
+	return &models.TrendingTracks{
+		Tracks:     toModelScoredTracks(p.Tracks),
+		ComputedAt: p.ComputedAt,
+	}
+}
+
+func toModelScoredTracks(tracks []events.ScoredTrack) []*models.ScoredTrack {
+	res := make([]*models.ScoredTrack, 0, len(tracks))
+	for _, v := range tracks {
+		res = append(res, &models.ScoredTrack{TrackId: v.TrackId, Score: v.Score})
+	}
+
+	return res
+}
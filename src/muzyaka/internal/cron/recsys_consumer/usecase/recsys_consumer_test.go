@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	mock_repository "src/internal/domain/recsys/repository/mocks"
+	"src/internal/lib/kafka"
+	mock_sarama "src/internal/lib/kafka/mocks"
+	"src/internal/models"
+	"src/internal/models/events"
+	"testing"
+	"time"
+)
+
+var (
+	testLogger     = slog.New(slog.NewTextHandler(io.Discard, nil))
+	testComputedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func newMessage(t *testing.T, eventId string, eventType string, payload interface{}) *sarama.ConsumerMessage {
+	rawPayload, err := json.Marshal(payload)
+	assert.NoError(t, err)
+
+	value, err := json.Marshal(events.Event{
+		Version:    events.Version,
+		EventId:    eventId,
+		Type:       eventType,
+		Payload:    rawPayload,
+		OccurredAt: testComputedAt,
+	})
+	assert.NoError(t, err)
+
+	return &sarama.ConsumerMessage{Topic: kafka.RecSysSimilarTopic, Value: value}
+}
+
+func TestRecSysConsumer_HandleMessage(t *testing.T) {
+	type mock func(r *mock_repository.MockRecSysRepository)
+
+	testTable := []struct {
+		name        string
+		msg         func(t *testing.T) *sarama.ConsumerMessage
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Similar tracks test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e1", events.RecSysSimilarTracks, events.SimilarTracksPayload{
+					TrackId:    1,
+					Similar:    []events.ScoredTrack{{TrackId: 2, Score: 0.9}, {TrackId: 3, Score: 0.5}},
+					ComputedAt: testComputedAt,
+				})
+			},
+			mock: func(r *mock_repository.MockRecSysRepository) {
+				r.EXPECT().SaveSimilarTracks("e1", &models.SimilarTracks{
+					TrackId:    1,
+					Tracks:     []*models.ScoredTrack{{TrackId: 2, Score: 0.9}, {TrackId: 3, Score: 0.5}},
+					ComputedAt: testComputedAt,
+				}).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Trending test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e2", events.RecSysTrending, events.TrendingPayload{
+					Tracks:     []events.ScoredTrack{{TrackId: 5, Score: 10}},
+					ComputedAt: testComputedAt,
+				})
+			},
+			mock: func(r *mock_repository.MockRecSysRepository) {
+				r.EXPECT().SaveTrendingTracks("e2", &models.TrendingTracks{
+					Tracks:     []*models.ScoredTrack{{TrackId: 5, Score: 10}},
+					ComputedAt: testComputedAt,
+				}).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Duplicate event test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e1", events.RecSysTrending, events.TrendingPayload{})
+			},
+			mock: func(r *mock_repository.MockRecSysRepository) {
+				r.EXPECT().SaveTrendingTracks("e1", gomock.Any()).Return(models.ErrAlreadyProcessed)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Unknown event test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e3", events.TrackAdded, events.TrackPayload{TrackId: 1})
+			},
+			mock:        func(r *mock_repository.MockRecSysRepository) {},
+			expectedErr: errors.Wrap(models.ErrUnknownEvent, events.TrackAdded),
+		},
+		{
+			name: "Invalid message test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return &sarama.ConsumerMessage{Value: []byte("not json")}
+			},
+			mock:        func(r *mock_repository.MockRecSysRepository) {},
+			expectedErr: models.ErrInvalidPayload,
+		},
+		{
+			name: "Repo fail test",
+			msg: func(t *testing.T) *sarama.ConsumerMessage {
+				return newMessage(t, "e4", events.RecSysTrending, events.TrendingPayload{})
+			},
+			mock: func(r *mock_repository.MockRecSysRepository) {
+				r.EXPECT().SaveTrendingTracks("e4", gomock.Any()).Return(errors.New("error"))
+			},
+			expectedErr: errors.Wrap(errors.New("error"), "recsys_consumer.HandleMessage error from repository"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockRecSysRepository(ctrl)
+			tc.mock(repo)
+
+			c := NewRecSysConsumer(mock_sarama.NewMockConsumerGroup(ctrl), repo, testLogger)
+			err := c.HandleMessage(tc.msg(t))
+
+			switch {
+			case tc.expectedErr == nil:
+				assert.NoError(t, err)
+			case errors.Is(tc.expectedErr, models.ErrInvalidPayload):
+				assert.ErrorIs(t, err, models.ErrInvalidPayload)
+			default:
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			}
+		})
+	}
+}
+
+func TestRecSysConsumer_ConsumeClaim(t *testing.T) {
+	t.Run("Messages are marked after they are stored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		msg1 := newMessage(t, "e1", events.RecSysTrending, events.TrendingPayload{})
+		msg2 := &sarama.ConsumerMessage{Value: []byte("not json")}
+
+		messages := make(chan *sarama.ConsumerMessage, 2)
+		messages <- msg1
+		messages <- msg2
+		close(messages)
+
+		repo := mock_repository.NewMockRecSysRepository(ctrl)
+		repo.EXPECT().SaveTrendingTracks("e1", gomock.Any()).Return(nil)
+
+		session := mock_sarama.NewMockConsumerGroupSession(ctrl)
+		session.EXPECT().Context().Return(context.Background()).AnyTimes()
+		gomock.InOrder(
+			session.EXPECT().MarkMessage(msg1, ""),
+			session.EXPECT().MarkMessage(msg2, ""),
+		)
+
+		claim := mock_sarama.NewMockConsumerGroupClaim(ctrl)
+		claim.EXPECT().Messages().Return(messages).AnyTimes()
+
+		c := NewRecSysConsumer(mock_sarama.NewMockConsumerGroup(ctrl), repo, testLogger)
+		assert.NoError(t, c.ConsumeClaim(session, claim))
+	})
+
+	t.Run("Message is not marked when the session ends", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		msg := newMessage(t, "e1", events.RecSysTrending, events.TrendingPayload{})
+		messages := make(chan *sarama.ConsumerMessage, 1)
+		messages <- msg
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		repo := mock_repository.NewMockRecSysRepository(ctrl)
+		repo.EXPECT().SaveTrendingTracks("e1", gomock.Any()).DoAndReturn(
+			func(string, *models.TrendingTracks) error {
+				// rebalance starts while the database is unavailable
+				cancel()
+				return errors.New("error")
+			})
+
+		session := mock_sarama.NewMockConsumerGroupSession(ctrl)
+		session.EXPECT().Context().Return(ctx).AnyTimes()
+		session.EXPECT().MarkMessage(gomock.Any(), gomock.Any()).Times(0)
+
+		claim := mock_sarama.NewMockConsumerGroupClaim(ctrl)
+		claim.EXPECT().Messages().Return(messages).AnyTimes()
+
+		c := NewRecSysConsumer(mock_sarama.NewMockConsumerGroup(ctrl), repo, testLogger)
+		assert.NoError(t, c.ConsumeClaim(session, claim))
+	})
+}
+
+func TestRecSysConsumer_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	msg := newMessage(t, "e1", events.RecSysTrending, events.TrendingPayload{})
+	messages := make(chan *sarama.ConsumerMessage, 1)
+	messages <- msg
+	close(messages)
+
+	repo := mock_repository.NewMockRecSysRepository(ctrl)
+	repo.EXPECT().SaveTrendingTracks("e1", gomock.Any()).Return(nil)
+
+	session := mock_sarama.NewMockConsumerGroupSession(ctrl)
+	session.EXPECT().Context().Return(context.Background()).AnyTimes()
+	session.EXPECT().Claims().Return(map[string][]int32{kafka.RecSysTrendingTopic: {0}}).AnyTimes()
+	session.EXPECT().GenerationID().Return(int32(1)).AnyTimes()
+	session.EXPECT().MarkMessage(msg, "")
+
+	claim := mock_sarama.NewMockConsumerGroupClaim(ctrl)
+	claim.EXPECT().Messages().Return(messages).AnyTimes()
+
+	errs := make(chan error)
+	close(errs)
+
+	group := mock_sarama.NewMockConsumerGroup(ctrl)
+	group.EXPECT().Errors().Return((<-chan error)(errs))
+	gomock.InOrder(
+		// the first generation ends with a rebalance
+		group.EXPECT().Consume(gomock.Any(), Topics, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+				assert.NoError(t, handler.Setup(session))
+				assert.NoError(t, handler.ConsumeClaim(session, claim))
+				return handler.Cleanup(session)
+			}),
+		group.EXPECT().Consume(gomock.Any(), Topics, gomock.Any()).Return(sarama.ErrClosedConsumerGroup),
+	)
+
+	c := NewRecSysConsumer(group, repo, testLogger)
+	c.Run(context.Background())
+}
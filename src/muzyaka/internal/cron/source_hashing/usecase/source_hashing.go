@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log/slog"
+	"src/internal/config"
+	"src/internal/domain/track/repository"
+	usecase2 "src/internal/domain/track/usecase"
+	"src/internal/models"
+	"time"
+)
+
+const batchSize = 100
+
+// SourceHashing moves sources of tracks uploaded before sources were hashed
+// under their content hash, so identical ones are stored once
+type SourceHashing struct {
+	trackRep repository.TrackRepository
+	storage  repository.TrackStorage
+	cfg      config.Media
+}
+
+func NewSourceHashing(trackRep repository.TrackRepository,
+	storage repository.TrackStorage,
+	cfg config.Media) *SourceHashing {
+	return &SourceHashing{
+		trackRep: trackRep,
+		storage:  storage,
+		cfg:      cfg,
+	}
+}
+
+// Run hashes sources on start and then every cfg.HashInterval until ctx is cancelled
+func (s *SourceHashing) Run(ctx context.Context, logger *slog.Logger) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("source hashing stopped")
+			return
+		case <-timer.C:
+			count, err := s.HashSources()
+			if err != nil {
+				logger.Error("source hashing failed", slog.String("error", err.Error()))
+			}
+			if count > 0 {
+				logger.Info("sources hashed", slog.Int("count", count))
+			}
+			timer.Reset(s.cfg.HashInterval)
+		}
+	}
+}
+
+// HashSources hashes sources of all tracks in batches and returns how many
+// were hashed. Tracks failed to hash are skipped until the next call, the
+// last error is returned.
+func (s *SourceHashing) HashSources() (int, error) {
+	var total int
+	var res error
+	var afterId uint64
+
+	for {
+		tracks, err := s.trackRep.GetUnhashedTracks(afterId, batchSize)
+		if err != nil {
+			return total, errors.Wrap(err, "source_hashing.HashSources error while trackRep call")
+		}
+
+		for _, v := range tracks {
+			if err := s.hashSource(v); err != nil {
+				res = errors.Wrapf(err, "source_hashing.HashSources error while hashing track %d", v.Id)
+				continue
+			}
+			total++
+		}
+
+		if len(tracks) < batchSize {
+			return total, res
+		}
+		afterId = tracks[len(tracks)-1].Id
+	}
+}
+
+func (s *SourceHashing) hashSource(track *models.TrackMeta) error {
+	obj, err := s.storage.LoadObject(track)
+	if err != nil {
+		return err
+	}
+
+	if err := usecase2.StoreSource(s.trackRep, s.storage, obj); err != nil {
+		return err
+	}
+
+	// the source is named after its hash already and is referenced now
+	if obj.Source == track.Source {
+		return nil
+	}
+
+	if err := s.trackRep.ReplaceSource(track.Id, track.Source, obj.Source); err != nil {
+		// the track was deleted or its source changed meanwhile
+		_ = usecase2.ReleaseSource(s.trackRep, s.storage, obj.ExtractMeta())
+		return err
+	}
+
+	return usecase2.ReleaseSource(s.trackRep, s.storage, track)
+}
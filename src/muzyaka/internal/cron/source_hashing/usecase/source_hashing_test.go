@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"src/internal/config"
+	mock_repository "src/internal/domain/track/repository/mocks"
+	"src/internal/models"
+	"testing"
+)
+
+// sha256 of the payload of test tracks
+const hashed = "039058c6f2c0cb492c533b0a4d14ef77cc0f78abccced5287d84a1a2011cfb81"
+
+var payload = []byte{1, 2, 3}
+
+// unshared calls back as AcquireSource and ReleaseSource do for a source of one track
+func unshared(source string, f func() error) error {
+	return f()
+}
+
+func TestSourceHashing_HashSources(t *testing.T) {
+	type mock func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage)
+
+	legacy := &models.TrackMeta{Id: 1, Source: "3f2b9c1e-uuid"}
+	moved := &models.TrackMeta{Id: 1, Source: hashed}
+
+	testTable := []struct {
+		name          string
+		mock          mock
+		expectedCount int
+		expectedErr   error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage) {
+				r.EXPECT().GetUnhashedTracks(uint64(0), batchSize).Return([]*models.TrackMeta{legacy}, nil)
+				s.EXPECT().LoadObject(legacy).Return(&models.TrackObject{TrackMeta: *legacy, Payload: payload}, nil)
+				r.EXPECT().AcquireSource(hashed, gomock.Any()).DoAndReturn(unshared)
+				s.EXPECT().UploadObject(&models.TrackObject{TrackMeta: *moved, Payload: payload}).Return(nil)
+				r.EXPECT().ReplaceSource(uint64(1), legacy.Source, hashed).Return(nil)
+				r.EXPECT().ReleaseSource(legacy.Source, gomock.Any()).DoAndReturn(unshared)
+				s.EXPECT().DeleteObject(legacy).Return(nil)
+			},
+			expectedCount: 1,
+			expectedErr:   nil,
+		},
+		{
+			name: "Named after hash test",
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage) {
+				r.EXPECT().GetUnhashedTracks(uint64(0), batchSize).Return([]*models.TrackMeta{moved}, nil)
+				s.EXPECT().LoadObject(moved).Return(&models.TrackObject{TrackMeta: *moved, Payload: payload}, nil)
+				r.EXPECT().AcquireSource(hashed, gomock.Any()).Return(nil)
+			},
+			expectedCount: 1,
+			expectedErr:   nil,
+		},
+		{
+			name: "Changed meanwhile test",
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage) {
+				r.EXPECT().GetUnhashedTracks(uint64(0), batchSize).Return([]*models.TrackMeta{legacy}, nil)
+				s.EXPECT().LoadObject(legacy).Return(&models.TrackObject{TrackMeta: *legacy, Payload: payload}, nil)
+				r.EXPECT().AcquireSource(hashed, gomock.Any()).DoAndReturn(unshared)
+				s.EXPECT().UploadObject(gomock.Any()).Return(nil)
+				r.EXPECT().ReplaceSource(uint64(1), legacy.Source, hashed).Return(models.ErrNotFound)
+				r.EXPECT().ReleaseSource(hashed, gomock.Any()).DoAndReturn(unshared)
+				s.EXPECT().DeleteObject(moved).Return(nil)
+			},
+			expectedCount: 0,
+			expectedErr:   errors.Wrap(models.ErrNotFound, "source_hashing.HashSources error while hashing track 1"),
+		},
+		{
+			name: "Missing source test",
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage) {
+				missing := &models.TrackMeta{Id: 2, Source: "7a1d0e4f-uuid"}
+				tracks := make([]*models.TrackMeta, 0, batchSize)
+				tracks = append(tracks, missing)
+				for i := 1; i < batchSize; i++ {
+					tracks = append(tracks, moved)
+				}
+
+				r.EXPECT().GetUnhashedTracks(uint64(0), batchSize).Return(tracks, nil)
+				s.EXPECT().LoadObject(missing).Return(nil, models.ErrNotFound)
+				s.EXPECT().LoadObject(moved).Return(&models.TrackObject{TrackMeta: *moved, Payload: payload}, nil).
+					Times(batchSize - 1)
+				r.EXPECT().AcquireSource(hashed, gomock.Any()).Return(nil).Times(batchSize - 1)
+				r.EXPECT().GetUnhashedTracks(uint64(1), batchSize).Return(nil, nil)
+			},
+			expectedCount: batchSize - 1,
+			expectedErr:   errors.Wrap(models.ErrNotFound, "source_hashing.HashSources error while hashing track 2"),
+		},
+		{
+			name: "Repo fail test",
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage) {
+				r.EXPECT().GetUnhashedTracks(uint64(0), batchSize).Return(nil, errors.New("error"))
+			},
+			expectedCount: 0,
+			expectedErr:   errors.Wrap(errors.New("error"), "source_hashing.HashSources error while trackRep call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trackRep := mock_repository.NewMockTrackRepository(ctrl)
+			storage := mock_repository.NewMockTrackStorage(ctrl)
+			tc.mock(trackRep, storage)
+
+			u := NewSourceHashing(trackRep, storage, config.Media{})
+			count, err := u.HashSources()
+
+			assert.Equal(t, tc.expectedCount, count)
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			}
+		})
+	}
+}
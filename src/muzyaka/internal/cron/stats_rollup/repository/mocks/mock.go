@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStatsRollupRepository is a mock of StatsRollupRepository interface.
+type MockStatsRollupRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsRollupRepositoryMockRecorder
+}
+
+// MockStatsRollupRepositoryMockRecorder is the mock recorder for MockStatsRollupRepository.
+type MockStatsRollupRepositoryMockRecorder struct {
+	mock *MockStatsRollupRepository
+}
+
+// NewMockStatsRollupRepository creates a new mock instance.
+func NewMockStatsRollupRepository(ctrl *gomock.Controller) *MockStatsRollupRepository {
+	mock := &MockStatsRollupRepository{ctrl: ctrl}
+	mock.recorder = &MockStatsRollupRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsRollupRepository) EXPECT() *MockStatsRollupRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetRolledUpTo mocks base method.
+func (m *MockStatsRollupRepository) GetRolledUpTo() (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolledUpTo")
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRolledUpTo indicates an expected call of GetRolledUpTo.
+func (mr *MockStatsRollupRepositoryMockRecorder) GetRolledUpTo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolledUpTo", reflect.TypeOf((*MockStatsRollupRepository)(nil).GetRolledUpTo))
+}
+
+// Rollup mocks base method.
+func (m *MockStatsRollupRepository) Rollup(from, to time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollup", from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollup indicates an expected call of Rollup.
+func (mr *MockStatsRollupRepositoryMockRecorder) Rollup(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollup", reflect.TypeOf((*MockStatsRollupRepository)(nil).Rollup), from, to)
+}
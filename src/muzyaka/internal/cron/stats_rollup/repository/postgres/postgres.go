@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/cron/stats_rollup/repository"
+	"src/internal/models"
+	"time"
+)
+
+// signalsQuery counts plays, likes and playlist adds per day grouped by %[1]s,
+// which is a column of tracks t. Listeners are unique users who played.
+const signalsQuery = `
+	SELECT item_id, day, sum(plays) AS plays, sum(skips) AS skips, sum(likes) AS likes,
+	       sum(playlist_adds) AS playlist_adds, sum(listeners) AS listeners
+	FROM (SELECT %[1]s AS item_id, (p.played_at AT TIME ZONE 'UTC')::date AS day,
+	             count(*) AS plays, count(*) FILTER (WHERE NOT p.completed) AS skips,
+	             0 AS likes, 0 AS playlist_adds, count(DISTINCT p.user_id) AS listeners
+	      FROM plays p
+	      JOIN tracks t ON t.id = p.track_id
+	      WHERE p.played_at >= @from AND p.played_at < @to
+	      GROUP BY 1, 2
+	      UNION ALL
+	      SELECT %[1]s, (ut.liked_at AT TIME ZONE 'UTC')::date, 0, 0, count(*), 0, 0
+	      FROM user_track ut
+	      JOIN tracks t ON t.id = ut.track_id
+	      WHERE ut.liked_at >= @from AND ut.liked_at < @to
+	      GROUP BY 1, 2
+	      UNION ALL
+	      SELECT %[1]s, (tp.added_at AT TIME ZONE 'UTC')::date, 0, 0, 0, count(*), 0
+	      FROM track_playlist tp
+	      JOIN tracks t ON t.id = tp.track_id
+	      JOIN playlists pl ON pl.id = tp.playlist_id
+	      WHERE tp.added_at >= @from AND tp.added_at < @to AND pl.kind = @kind
+	      GROUP BY 1, 2) s
+	GROUP BY 1, 2`
+
+type statsRollupRepo struct {
+	db *gorm.DB
+}
+
+func NewStatsRollupRepo(db *gorm.DB) repository.StatsRollupRepository {
+	return &statsRollupRepo{db: db}
+}
+
+func (s statsRollupRepo) GetRolledUpTo() (*time.Time, error) {
+	var days []time.Time
+	tx := s.db.Table("stats_rollups").Pluck("rolled_up_to", &days)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table stats_rollups)")
+	}
+
+	if len(days) == 0 {
+		return nil, nil
+	}
+
+	return &days[0], nil
+}
+
+func (s statsRollupRepo) Rollup(from time.Time, to time.Time) error {
+	args := map[string]interface{}{
+		"from": from,
+		"to":   to,
+		"kind": models.PlaylistKindUser,
+		// days are passed as text not to depend on the session time zone
+		"from_day": from.UTC().Format(time.DateOnly),
+		"to_day":   to.UTC().Format(time.DateOnly),
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"track_daily_stats", "album_daily_stats", "merch_daily_stats"} {
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE day >= @from_day::date AND day < @to_day::date", table),
+				args).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO track_daily_stats (track_id, day, plays, skips, likes, playlist_adds, listeners)
+			`+fmt.Sprintf(signalsQuery, "t.id"), args).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO album_daily_stats (album_id, day, plays, skips, likes, playlist_adds, listeners)
+			`+fmt.Sprintf(signalsQuery, "t.album_id"), args).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO merch_daily_stats (merch_id, day, clicks)
+			SELECT merch_id, (clicked_at AT TIME ZONE 'UTC')::date, count(*)
+			FROM merch_clicks
+			WHERE clicked_at >= @from AND clicked_at < @to
+			GROUP BY 1, 2`, args).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			INSERT INTO stats_rollups (rolled_up_to)
+			VALUES (@to_day::date)
+			ON CONFLICT (id) DO UPDATE SET rolled_up_to = GREATEST(stats_rollups.rolled_up_to, excluded.rolled_up_to)`,
+			args).Error
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table track_daily_stats)")
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import "time"
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+type StatsRollupRepository interface {
+	// GetRolledUpTo returns the day the last rollup ended before, it is nil
+	// before the first rollup
+	GetRolledUpTo() (*time.Time, error)
+	// Rollup recomputes daily stats of days in [from, to) and moves the end of
+	// rolled up days to to, if it is later
+	Rollup(from time.Time, to time.Time) error
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log/slog"
+	"src/internal/config"
+	"src/internal/cron/stats_rollup/repository"
+	"time"
+)
+
+const (
+	day = 24 * time.Hour
+
+	// maxRollupDays bounds the days recomputed in one transaction
+	maxRollupDays = 31
+)
+
+// StatsRollup keeps daily stats tables up to date. The last cfg.RollupDays days
+// are recomputed on every run, so late plays and removed likes are taken into account.
+// Days missed while the service was down are caught up from where the last
+// rollup ended.
+type StatsRollup struct {
+	repository repository.StatsRollupRepository
+	cfg        config.Stats
+	now        func() time.Time
+}
+
+func NewStatsRollup(rollupRepository repository.StatsRollupRepository, cfg config.Stats) *StatsRollup {
+	return &StatsRollup{
+		repository: rollupRepository,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+// Run rolls up stats on start and then every cfg.RollupInterval until ctx is cancelled
+func (s *StatsRollup) Run(ctx context.Context, logger *slog.Logger) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("stats rollup stopped")
+			return
+		case <-timer.C:
+			start := time.Now()
+			if err := s.Rollup(); err != nil {
+				logger.Error("stats rollup failed", slog.String("error", err.Error()))
+			} else {
+				logger.Debug("stats rollup done", slog.Duration("took", time.Since(start)))
+			}
+			timer.Reset(s.cfg.RollupInterval)
+		}
+	}
+}
+
+func (s *StatsRollup) Rollup() error {
+	today := s.now().UTC().Truncate(day)
+	from := today.Add(-time.Duration(s.cfg.RollupDays-1) * day)
+	to := today.Add(day)
+
+	rolledUpTo, err := s.repository.GetRolledUpTo()
+	if err != nil {
+		return errors.Wrap(err, "stats_rollup.Rollup error while GetRolledUpTo call")
+	}
+
+	if rolledUpTo != nil && rolledUpTo.Before(from) {
+		from = rolledUpTo.UTC().Truncate(day)
+	}
+
+	// every part moves the end of rolled up days, so a failed catch up
+	// continues from the failed part
+	for from.Before(to) {
+		end := from.Add(maxRollupDays * day)
+		if end.After(to) {
+			end = to
+		}
+
+		if err := s.repository.Rollup(from, end); err != nil {
+			return errors.Wrap(err, "stats_rollup.Rollup error while Rollup call")
+		}
+
+		from = end
+	}
+
+	return nil
+}
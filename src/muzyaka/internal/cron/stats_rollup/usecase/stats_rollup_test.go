@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"src/internal/config"
+	mock_repository "src/internal/cron/stats_rollup/repository/mocks"
+	"testing"
+	"time"
+)
+
+func TestStatsRollup_Rollup(t *testing.T) {
+	now := time.Date(2024, 5, 10, 15, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	rolledUpTo := func(t time.Time) *time.Time { return &t }
+
+	type mock func(r *mock_repository.MockStatsRollupRepository)
+
+	testTable := []struct {
+		name        string
+		days        int
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			days: 2,
+			mock: func(r *mock_repository.MockStatsRollupRepository) {
+				r.EXPECT().GetRolledUpTo().Return(rolledUpTo(time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC)), nil)
+				r.EXPECT().Rollup(
+					time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC),
+				).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Today only test",
+			days: 1,
+			mock: func(r *mock_repository.MockStatsRollupRepository) {
+				r.EXPECT().GetRolledUpTo().Return(rolledUpTo(time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)), nil)
+				r.EXPECT().Rollup(
+					time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC),
+				).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "First rollup test",
+			days: 2,
+			mock: func(r *mock_repository.MockStatsRollupRepository) {
+				r.EXPECT().GetRolledUpTo().Return(nil, nil)
+				r.EXPECT().Rollup(
+					time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC),
+				).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Missed days test",
+			days: 2,
+			mock: func(r *mock_repository.MockStatsRollupRepository) {
+				r.EXPECT().GetRolledUpTo().Return(rolledUpTo(time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)), nil)
+				r.EXPECT().Rollup(
+					time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC),
+				).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Long catch up test",
+			days: 2,
+			mock: func(r *mock_repository.MockStatsRollupRepository) {
+				r.EXPECT().GetRolledUpTo().Return(rolledUpTo(time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)), nil)
+				gomock.InOrder(
+					r.EXPECT().Rollup(
+						time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC),
+						time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC),
+					).Return(nil),
+					r.EXPECT().Rollup(
+						time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC),
+						time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC),
+					).Return(nil),
+				)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Watermark fail test",
+			days: 2,
+			mock: func(r *mock_repository.MockStatsRollupRepository) {
+				r.EXPECT().GetRolledUpTo().Return(nil, errors.New("error"))
+			},
+			expectedErr: errors.Wrap(errors.New("error"), "stats_rollup.Rollup error while GetRolledUpTo call"),
+		},
+		{
+			name: "Repo fail test",
+			days: 2,
+			mock: func(r *mock_repository.MockStatsRollupRepository) {
+				r.EXPECT().GetRolledUpTo().Return(nil, nil)
+				r.EXPECT().Rollup(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			},
+			expectedErr: errors.Wrap(errors.New("error"), "stats_rollup.Rollup error while Rollup call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockStatsRollupRepository(ctrl)
+			tc.mock(repo)
+
+			s := NewStatsRollup(repo, config.Stats{RollupDays: tc.days})
+			s.now = func() time.Time { return now }
+
+			err := s.Rollup()
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
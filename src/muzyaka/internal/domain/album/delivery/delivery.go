@@ -3,6 +3,7 @@ package delivery
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/pkg/errors"
 	"net/http"
 	"src/internal/domain/album/usecase"
 	"src/internal/lib/api/response"
@@ -78,7 +79,12 @@ func UpdateAlbum(useCase usecase.AlbumUseCase) http.HandlerFunc {
 		}
 
 		err = useCase.UpdateAlbum(dto.ToModelAlbumWithId(albumIDUint, &req))
-		if err != nil {
+		if errors.Is(err, models.ErrInvalidGenre) || errors.Is(err, models.ErrInvalidMusician) ||
+			errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
@@ -160,7 +166,12 @@ func AddAlbumWithTracks(useCase usecase.AlbumUseCase) http.HandlerFunc {
 		}
 
 		albumID, err := useCase.AddAlbumWithTracks(dto.ToModelAlbumWithId(0, &req.AlbumWithoutId), modelTracks, musicianIDUint)
-		if err != nil {
+		if errors.Is(err, models.ErrInvalidGenre) || errors.Is(err, models.ErrInvalidMusician) ||
+			errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
@@ -180,6 +191,7 @@ func AddAlbumWithTracks(useCase usecase.AlbumUseCase) http.HandlerFunc {
 // @Param id path int true "album ID"
 // @Param input body dto.TrackObjectWithoutId true "track info"
 // @Success 200 {object} dto.CreateTrackResponse
+// @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Failure default {object} response.Response
 // @Router /api/album/{id}/tracks [post]
@@ -202,7 +214,12 @@ func CreateTrack(useCase usecase.AlbumUseCase) http.HandlerFunc {
 		}
 
 		trackID, err := useCase.AddTrack(albumIDUint, dto.ToModelTrackObjectWithoutId(&req, 0, ""))
-		if err != nil {
+		if errors.Is(err, models.ErrInvalidGenre) || errors.Is(err, models.ErrInvalidMusician) ||
+			errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
@@ -324,3 +341,127 @@ func DeleteTrack(useCase usecase.AlbumUseCase) http.HandlerFunc {
 		render.JSON(w, r, response.OK())
 	}
 }
+
+// @Summary GetArtistCredits
+// @Security ApiKeyAuth
+// @Tags musician
+// @Description get credits of the musician on releases of other musicians, pending ones first
+// @ID get-artist-credits
+// @Accept  json
+// @Produce  json
+// @Param musician_id path int true "musician ID"
+// @Success 200 {object} dto.ArtistCreditsCollection
+// @Failure 400,405 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/credits [get]
+func GetArtistCredits(useCase usecase.AlbumUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianID := chi.URLParam(r, "musician_id")
+		musicianIDUint, err := strconv.ParseUint(musicianID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		credits, err := useCase.GetArtistCredits(musicianIDUint)
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoArtistCredits(credits))
+	}
+}
+
+// @Summary ApproveArtistCredit
+// @Security ApiKeyAuth
+// @Tags musician
+// @Description approve a credit, the release is then shown on the musician page
+// @ID approve-artist-credit
+// @Accept  json
+// @Produce  json
+// @Param musician_id path int true "musician ID"
+// @Param id path int true "credit ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404,405 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/credits/{id}/approve [post]
+func ApproveArtistCredit(useCase usecase.AlbumUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianIDUint, creditIDUint, err := parseCreditParams(r)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.ApproveArtistCredit(musicianIDUint, creditIDUint)
+		if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary DeclineArtistCredit
+// @Security ApiKeyAuth
+// @Tags musician
+// @Description remove the musician from a release of another musician
+// @ID decline-artist-credit
+// @Accept  json
+// @Produce  json
+// @Param musician_id path int true "musician ID"
+// @Param id path int true "credit ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404,405 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/credits/{id} [delete]
+func DeclineArtistCredit(useCase usecase.AlbumUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianIDUint, creditIDUint, err := parseCreditParams(r)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.DeclineArtistCredit(musicianIDUint, creditIDUint)
+		if errors.Is(err, models.ErrNothingToDelete) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+func parseCreditParams(r *http.Request) (uint64, uint64, error) {
+	musicianID, err := strconv.ParseUint(chi.URLParam(r, "musician_id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	creditID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return musicianID, creditID, nil
+}
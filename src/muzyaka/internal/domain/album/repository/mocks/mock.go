@@ -64,6 +64,34 @@ func (mr *MockAlbumRepositoryMockRecorder) AddTrackToAlbumOutbox(albumId, track
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTrackToAlbumOutbox", reflect.TypeOf((*MockAlbumRepository)(nil).AddTrackToAlbumOutbox), albumId, track)
 }
 
+// ApproveArtistCredit mocks base method.
+func (m *MockAlbumRepository) ApproveArtistCredit(musicianId, creditId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveArtistCredit", musicianId, creditId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApproveArtistCredit indicates an expected call of ApproveArtistCredit.
+func (mr *MockAlbumRepositoryMockRecorder) ApproveArtistCredit(musicianId, creditId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveArtistCredit", reflect.TypeOf((*MockAlbumRepository)(nil).ApproveArtistCredit), musicianId, creditId)
+}
+
+// DeclineArtistCredit mocks base method.
+func (m *MockAlbumRepository) DeclineArtistCredit(musicianId, creditId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineArtistCredit", musicianId, creditId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeclineArtistCredit indicates an expected call of DeclineArtistCredit.
+func (mr *MockAlbumRepositoryMockRecorder) DeclineArtistCredit(musicianId, creditId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineArtistCredit", reflect.TypeOf((*MockAlbumRepository)(nil).DeclineArtistCredit), musicianId, creditId)
+}
+
 // DeleteAlbumOutbox mocks base method.
 func (m *MockAlbumRepository) DeleteAlbumOutbox(id uint64) error {
 	m.ctrl.T.Helper()
@@ -81,15 +109,15 @@ func (mr *MockAlbumRepositoryMockRecorder) DeleteAlbumOutbox(id interface{}) *go
 // DeleteTrackFromAlbumOutbox mocks base method.
 func (m *MockAlbumRepository) DeleteTrackFromAlbumOutbox(trackId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTrackFromAlbumOutbox", trackId, track)
+	ret := m.ctrl.Call(m, "DeleteTrackFromAlbumOutbox", trackId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteTrackFromAlbumOutbox indicates an expected call of DeleteTrackFromAlbumOutbox.
-func (mr *MockAlbumRepositoryMockRecorder) DeleteTrackFromAlbumOutbox(albumId, track interface{}) *gomock.Call {
+func (mr *MockAlbumRepositoryMockRecorder) DeleteTrackFromAlbumOutbox(trackId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrackFromAlbumOutbox", reflect.TypeOf((*MockAlbumRepository)(nil).DeleteTrackFromAlbumOutbox), albumId, track)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrackFromAlbumOutbox", reflect.TypeOf((*MockAlbumRepository)(nil).DeleteTrackFromAlbumOutbox), trackId)
 }
 
 // GetAlbum mocks base method.
@@ -152,6 +180,21 @@ func (mr *MockAlbumRepositoryMockRecorder) GetAllTracksForAlbum(albumId interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTracksForAlbum", reflect.TypeOf((*MockAlbumRepository)(nil).GetAllTracksForAlbum), albumId)
 }
 
+// GetArtistCredits mocks base method.
+func (m *MockAlbumRepository) GetArtistCredits(musicianId uint64) ([]*models.ArtistCredit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtistCredits", musicianId)
+	ret0, _ := ret[0].([]*models.ArtistCredit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtistCredits indicates an expected call of GetArtistCredits.
+func (mr *MockAlbumRepositoryMockRecorder) GetArtistCredits(musicianId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtistCredits", reflect.TypeOf((*MockAlbumRepository)(nil).GetArtistCredits), musicianId)
+}
+
 // IsAlbumOwned mocks base method.
 func (m *MockAlbumRepository) IsAlbumOwned(albumId, musicianId uint64) (bool, error) {
 	m.ctrl.T.Helper()
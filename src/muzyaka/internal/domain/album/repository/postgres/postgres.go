@@ -1,12 +1,13 @@
 package postgres
 
 import (
-	"github.com/hashicorp/go-uuid"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 	"src/internal/domain/album/repository"
+	postgres2 "src/internal/domain/track/repository/postgres"
 	"src/internal/models"
 	"src/internal/models/dao"
+	"src/internal/models/events"
 )
 
 type albumRepository struct {
@@ -19,14 +20,34 @@ func NewAlbumRepository(db *gorm.DB) repository.AlbumRepository {
 
 func (ar *albumRepository) GetAllAlbumsForMusician(musicianId uint64) ([]*models.Album, error) {
 	var pgAlbums []*dao.Album
-	tx := ar.db.Limit(dao.MaxLimit).Find(&pgAlbums, "musician_id = ?", musicianId)
+	// albums of other musicians are listed once the musician approves the credit
+	linked := ar.db.Model(&dao.ReleaseArtist{}).Select("album_id").Where("musician_id = ? AND approved", musicianId)
+	tx := ar.db.Where("musician_id = ?", musicianId).Or("id IN (?)", linked).Limit(dao.MaxLimit).Find(&pgAlbums)
 	if tx.Error != nil {
 		return nil, errors.Wrap(tx.Error, "database error (table albums)")
 	}
 
+	ids := make([]uint64, 0, len(pgAlbums))
+	for _, v := range pgAlbums {
+		ids = append(ids, v.ID)
+	}
+
+	genres, err := postgres2.LoadAlbumGenres(ar.db, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table albums)")
+	}
+
+	artists, err := postgres2.LoadAlbumArtists(ar.db, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table albums)")
+	}
+
 	var albums []*models.Album
 	for _, v := range pgAlbums {
-		albums = append(albums, dao.ToModelAlbum(v))
+		album := dao.ToModelAlbum(v)
+		album.Genres = genres[v.ID]
+		album.Artists = artists[v.ID]
+		albums = append(albums, album)
 	}
 
 	return albums, nil
@@ -61,15 +82,69 @@ func (ar *albumRepository) GetAlbum(id uint64) (*models.Album, error) {
 	if tx.Error != nil {
 		return nil, errors.Wrap(tx.Error, "database error (table album)")
 	}
-	return dao.ToModelAlbum(&album), nil
+
+	genres, err := postgres2.LoadAlbumGenres(ar.db, []uint64{album.ID})
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table album)")
+	}
+
+	artists, err := postgres2.LoadAlbumArtists(ar.db, []uint64{album.ID})
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table album)")
+	}
+
+	res := dao.ToModelAlbum(&album)
+	res.Genres = genres[album.ID]
+	res.Artists = artists[album.ID]
+	return res, nil
 }
 
 func (ar *albumRepository) UpdateAlbum(album *models.Album) error {
 	pgAlbum := dao.ToPostgresAlbum(album, 0)
-	tx := ar.db.Omit("id").Updates(pgAlbum)
 
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table album)")
+	genres, err := postgres2.ResolveGenres(ar.db, album.Genres)
+	if err != nil {
+		return errors.Wrap(err, "database error (table album)")
+	}
+
+	err = ar.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("id", "musician_id", "release_date", "label", "upc").Updates(pgAlbum).Error; err != nil {
+			return err
+		}
+
+		// Updates skips nil fields, so the optional ones are set separately
+		err := tx.Model(&dao.Album{}).Where("id = ?", pgAlbum.ID).Updates(map[string]interface{}{
+			"release_date": pgAlbum.ReleaseDate,
+			"label":        pgAlbum.Label,
+			"upc":          pgAlbum.Upc,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := saveAlbumGenres(tx, pgAlbum.ID, genres); err != nil {
+			return err
+		}
+
+		if err := postgres2.SaveAlbumArtists(tx, pgAlbum.ID, album.Artists); err != nil {
+			return err
+		}
+
+		var updated dao.Album
+		if err := tx.Where("id = ?", pgAlbum.ID).Take(&updated).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.AlbumUpdated, updated.ID, dao.ToAlbumPayload(&updated))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table album)")
 	}
 
 	return nil
@@ -84,42 +159,64 @@ func (ar *albumRepository) AddAlbumWithTracksOutbox(album *models.Album, tracks
 			return err
 		}
 
+		albumGenres, err := postgres2.ResolveGenres(tx, album.Genres)
+		if err != nil {
+			return err
+		}
+
+		if err := saveAlbumGenres(tx, pgAlbum.ID, albumGenres); err != nil {
+			return err
+		}
+
+		if err := postgres2.SaveAlbumArtists(tx, pgAlbum.ID, album.Artists); err != nil {
+			return err
+		}
+
+		tracksGenres := make([][]*dao.Genre, 0, len(tracks))
 		for _, v := range tracks {
-			var pgGenre dao.Genre
-			txInner := tx.Where("name = ?", v.Genre).Take(&pgGenre)
-			if txInner.Error != nil {
-				return tx.Error
+			genres, err := postgres2.ResolveGenres(tx, v.AllGenres())
+			if err != nil {
+				return err
 			}
 
-			pgTracks = append(pgTracks, dao.ToPostgresTrack(v, pgGenre.ID, pgAlbum.ID))
+			tracksGenres = append(tracksGenres, genres)
+			pgTracks = append(pgTracks, dao.ToPostgresTrack(v, postgres2.PrimaryGenre(genres), pgAlbum.ID))
 		}
 
 		if err := tx.Create(&pgTracks).Error; err != nil {
 			return err
 		}
 
-		for _, v := range pgTracks {
-			eventID, err := uuid.GenerateUUID()
-			if err != nil {
+		for i, v := range pgTracks {
+			if err := postgres2.SaveTrackTags(tx, v.ID, tracksGenres[i], tracks[i].Moods); err != nil {
 				return err
 			}
 
-			var genre uint64
-			genre = 0
-			if v.GenreRefer != nil {
-				genre = *v.GenreRefer
+			if err := postgres2.SaveTrackCredits(tx, v.ID, tracks[i].FeaturedArtists, tracks[i].Credits); err != nil {
+				return err
 			}
 
-			if err := tx.Create(&dao.Outbox{
-				ID:         0,
-				EventId:    eventID,
-				TrackId:    v.ID,
-				Source:     v.Source,
-				Name:       v.Name,
-				GenreRefer: genre,
-				Type:       dao.TypeAdd,
-				Sent:       false,
-			}).Error; err != nil {
+			if err := postgres2.SaveTrackArtists(tx, v.ID, tracks[i].Artists); err != nil {
+				return err
+			}
+		}
+
+		outbox, err := dao.NewOutbox(events.AlbumCreated, pgAlbum.ID, dao.ToAlbumPayload(pgAlbum))
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(outbox).Error; err != nil {
+			return err
+		}
+
+		for _, v := range pgTracks {
+			outbox, err := dao.NewOutbox(events.TrackAdded, v.ID, dao.ToTrackPayload(v))
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Create(outbox).Error; err != nil {
 				return err
 			}
 		}
@@ -146,21 +243,12 @@ func (ar *albumRepository) DeleteAlbumOutbox(id uint64) error {
 				return err
 			}
 
-			eventID, err := uuid.GenerateUUID()
+			outbox, err := dao.NewOutbox(events.TrackDeleted, v.ID, events.TrackPayload{TrackId: v.ID})
 			if err != nil {
 				return err
 			}
 
-			if err := tx.Create(&dao.Outbox{
-				ID:         0,
-				EventId:    eventID,
-				TrackId:    v.ID,
-				Source:     "",
-				Name:       "",
-				GenreRefer: 0,
-				Type:       dao.TypeDelete,
-				Sent:       false,
-			}).Error; err != nil {
+			if err := tx.Create(outbox).Error; err != nil {
 				return err
 			}
 		}
@@ -169,7 +257,12 @@ func (ar *albumRepository) DeleteAlbumOutbox(id uint64) error {
 			return err
 		}
 
-		return nil
+		outbox, err := dao.NewOutbox(events.AlbumDeleted, id, events.AlbumPayload{AlbumId: id})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
 	})
 
 	if err != nil {
@@ -180,47 +273,43 @@ func (ar *albumRepository) DeleteAlbumOutbox(id uint64) error {
 }
 
 func (ar *albumRepository) AddTrackToAlbumOutbox(albumId uint64, track *models.TrackMeta) (uint64, error) {
-	var pgGenre dao.Genre
-	if track.Genre != "" {
-		tx := ar.db.Where("name = ?", track.Genre).First(&pgGenre)
-		if tx.Error != nil {
-			return 0, errors.Wrap(tx.Error, "database error (table album)")
-		}
+	genres, err := postgres2.ResolveGenres(ar.db, track.AllGenres())
+	if err != nil {
+		return 0, errors.Wrap(err, "database error (table album)")
 	}
 
-	pgTrack := dao.ToPostgresTrack(track, pgGenre.ID, albumId)
+	pgTrack := dao.ToPostgresTrack(track, postgres2.PrimaryGenre(genres), albumId)
 
-	err := ar.db.Transaction(func(tx *gorm.DB) error {
+	err = ar.db.Transaction(func(tx *gorm.DB) error {
 		// Add track to tracks table
 		if err := tx.Create(&pgTrack).Error; err != nil {
 			return err
 		}
-		// Add event to outbox
-		eventID, err := uuid.GenerateUUID()
-		if err != nil {
+
+		if err := postgres2.SaveTrackTags(tx, pgTrack.ID, genres, track.Moods); err != nil {
 			return err
 		}
 
-		var genre uint64
-		genre = 0
-		if pgTrack.GenreRefer != nil {
-			genre = *pgTrack.GenreRefer
+		if err := postgres2.SaveTrackCredits(tx, pgTrack.ID, track.FeaturedArtists, track.Credits); err != nil {
+			return err
 		}
 
-		if err := tx.Create(&dao.Outbox{
-			ID:         0,
-			EventId:    eventID,
-			TrackId:    pgTrack.ID,
-			Source:     pgTrack.Source,
-			Name:       pgTrack.Name,
-			GenreRefer: genre,
-			Type:       dao.TypeAdd,
-			Sent:       false,
-		}).Error; err != nil {
+		if err := postgres2.SaveTrackArtists(tx, pgTrack.ID, track.Artists); err != nil {
 			return err
 		}
 
-		return nil
+		// album loudness is cleared until the new track is measured
+		if err := postgres2.UpdateAlbumLoudness(tx, albumId); err != nil {
+			return err
+		}
+
+		// Add event to outbox
+		outbox, err := dao.NewOutbox(events.TrackAdded, pgTrack.ID, dao.ToTrackPayload(pgTrack))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
 	})
 
 	if err != nil {
@@ -245,18 +334,12 @@ func (ar *albumRepository) DeleteTrackFromAlbumOutbox(trackId uint64) error {
 			return err
 		}
 
-		eventID, err := uuid.GenerateUUID()
+		outbox, err := dao.NewOutbox(events.TrackDeleted, trackId, events.TrackPayload{TrackId: trackId})
 		if err != nil {
 			return err
 		}
 
-		if err := tx.Create(&dao.Outbox{
-			ID:      0,
-			EventId: eventID,
-			TrackId: trackId,
-			Type:    dao.TypeDelete,
-			Sent:    false,
-		}).Error; err != nil {
+		if err := tx.Create(outbox).Error; err != nil {
 			return err
 		}
 
@@ -270,9 +353,20 @@ func (ar *albumRepository) DeleteTrackFromAlbumOutbox(trackId uint64) error {
 			if err := tx.Delete(&dao.Album{}, pgTrack.AlbumID).Error; err != nil {
 				return err
 			}
+
+			outbox, err := dao.NewOutbox(events.AlbumDeleted, pgTrack.AlbumID, events.AlbumPayload{AlbumId: pgTrack.AlbumID})
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Create(outbox).Error; err != nil {
+				return err
+			}
+
+			return nil
 		}
 
-		return nil
+		return postgres2.UpdateAlbumLoudness(tx, pgTrack.AlbumID)
 	})
 
 	if err != nil {
@@ -291,15 +385,136 @@ func (ar *albumRepository) GetAllTracksForAlbum(albumId uint64) ([]*models.Track
 		return nil, errors.Wrap(tx.Error, "database error (table album)")
 	}
 
-	var tracks []*models.TrackMeta
-	for _, v := range tempTracks {
-		var pgGenre dao.Genre
-		tx := ar.db.Where("id = ?", v.GenreRefer).Limit(1).Find(&pgGenre)
-		if tx.Error != nil {
-			return nil, errors.Wrap(tx.Error, "database error (table album)")
+	tracks, err := postgres2.LoadTracks(ar.db, tempTracks)
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table album)")
+	}
+	return tracks, nil
+}
+
+func (ar *albumRepository) GetArtistCredits(musicianId uint64) ([]*models.ArtistCredit, error) {
+	var credits []*dao.ArtistCredit
+	tx := ar.db.Raw(`
+		SELECT ra.id, ra.album_id, a.name AS album_name, ra.track_id, t.name AS track_name, ra.role, ra.approved
+		FROM release_artists ra
+		         JOIN albums a ON a.id = ra.album_id
+		         LEFT JOIN tracks t ON t.id = ra.track_id
+		WHERE ra.musician_id = ?
+		  AND NOT ra.declined
+		ORDER BY ra.approved, ra.id DESC
+		LIMIT ?`, musicianId, dao.MaxLimit).Scan(&credits)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table release_artists)")
+	}
+
+	res := make([]*models.ArtistCredit, 0, len(credits))
+	for _, v := range credits {
+		res = append(res, dao.ToModelArtistCredit(v))
+	}
+
+	return res, nil
+}
+
+func (ar *albumRepository) ApproveArtistCredit(musicianId uint64, creditId uint64) error {
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		var credit dao.ReleaseArtist
+		err := tx.Where("id = ? AND musician_id = ? AND NOT declined", creditId, musicianId).Take(&credit).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if credit.Approved {
+			return nil
+		}
+
+		if err := tx.Model(&credit).Update("approved", true).Error; err != nil {
+			return err
+		}
+
+		return createCreditOutbox(tx, &credit)
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table release_artists)")
+	}
+
+	return nil
+}
+
+func (ar *albumRepository) DeclineArtistCredit(musicianId uint64, creditId uint64) error {
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		var credit dao.ReleaseArtist
+		err := tx.Where("id = ? AND musician_id = ? AND NOT declined", creditId, musicianId).Take(&credit).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNothingToDelete
+		} else if err != nil {
+			return err
+		}
+
+		// the credit is kept declined, so the album's musician can't add it again
+		err = tx.Model(&credit).Updates(map[string]interface{}{"approved": false, "declined": true}).Error
+		if err != nil {
+			return err
+		}
+
+		// pending credits are not shown, so nothing changes for the release
+		if !credit.Approved {
+			return nil
 		}
 
-		tracks = append(tracks, dao.ToModelTrack(v, &pgGenre))
+		return createCreditOutbox(tx, &credit)
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table release_artists)")
 	}
-	return tracks, nil
+
+	return nil
+}
+
+// createCreditOutbox writes an update of the album or the track the credit is on
+func createCreditOutbox(tx *gorm.DB, credit *dao.ReleaseArtist) error {
+	var outbox *dao.Outbox
+	var err error
+	if credit.TrackID == nil {
+		var album dao.Album
+		if err := tx.Where("id = ?", credit.AlbumID).Take(&album).Error; err != nil {
+			return err
+		}
+
+		outbox, err = dao.NewOutbox(events.AlbumUpdated, album.ID, dao.ToAlbumPayload(&album))
+	} else {
+		var track dao.TrackMeta
+		if err := tx.Where("id = ?", *credit.TrackID).Take(&track).Error; err != nil {
+			return err
+		}
+
+		outbox, err = dao.NewOutbox(events.TrackUpdated, track.ID, dao.ToTrackPayload(&track))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(outbox).Error
+}
+
+// saveAlbumGenres replaces genres of the album
+func saveAlbumGenres(tx *gorm.DB, albumId uint64, genres []*dao.Genre) error {
+	if err := tx.Where("album_id = ?", albumId).Delete(&dao.AlbumGenre{}).Error; err != nil {
+		return err
+	}
+
+	if len(genres) == 0 {
+		return nil
+	}
+
+	rows := make([]*dao.AlbumGenre, 0, len(genres))
+	for _, v := range genres {
+		rows = append(rows, &dao.AlbumGenre{AlbumID: albumId, GenreID: *v.ID})
+	}
+
+	return tx.Create(&rows).Error
 }
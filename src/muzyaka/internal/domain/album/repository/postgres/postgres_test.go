@@ -8,6 +8,8 @@ import (
 	"log"
 	"src/internal/lib/testhelpers"
 	"src/internal/models"
+	"src/internal/models/dao"
+	"src/internal/models/events"
 	"testing"
 )
 
@@ -76,6 +78,14 @@ func TestRepo_AddAlbumWithTracks(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, getAl)
 
+	// the owner is listed as the primary artist
+	if assert.Len(t, getAl.Artists, 1) {
+		assert.Equal(t, uint64(1), getAl.Artists[0].MusicianId)
+		assert.Equal(t, models.ArtistPrimary, getAl.Artists[0].Role)
+		assert.True(t, getAl.Artists[0].Approved)
+	}
+	album.Artists = getAl.Artists
+
 	assert.Equal(t, getAl, album)
 
 	tracksFromPg, err := repository.GetAllTracksForAlbum(id)
@@ -88,4 +98,20 @@ func TestRepo_AddAlbumWithTracks(t *testing.T) {
 	tracksFromPg, err = repository.GetAllTracksForAlbum(id)
 	assert.Equal(t, len(tracksFromPg), 0)
 	assert.NoError(t, err)
+
+	var outbox []*dao.Outbox
+	err = db.Order("id").Find(&outbox).Error
+	assert.NoError(t, err)
+
+	var eventTypes []string
+	for _, v := range outbox {
+		eventTypes = append(eventTypes, v.Type)
+	}
+
+	assert.Equal(t, []string{
+		events.AlbumCreated,
+		events.TrackAdded, events.TrackAdded, events.TrackAdded,
+		events.TrackDeleted, events.TrackDeleted, events.TrackDeleted,
+		events.AlbumDeleted,
+	}, eventTypes)
 }
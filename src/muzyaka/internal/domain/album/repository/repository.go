@@ -16,4 +16,8 @@ type AlbumRepository interface {
 	IsAlbumOwned(albumId uint64, musicianId uint64) (bool, error)
 	GetAlbumId(trackId uint64) (uint64, error)
 	GetAllAlbumsForMusician(musicianId uint64) ([]*models.Album, error)
+
+	GetArtistCredits(musicianId uint64) ([]*models.ArtistCredit, error)
+	ApproveArtistCredit(musicianId uint64, creditId uint64) error
+	DeclineArtistCredit(musicianId uint64, creditId uint64) error
 }
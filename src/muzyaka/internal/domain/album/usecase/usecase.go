@@ -1,10 +1,10 @@
 package usecase
 
 import (
-	"github.com/hashicorp/go-uuid"
 	"github.com/pkg/errors"
 	"src/internal/domain/album/repository"
 	repository2 "src/internal/domain/track/repository"
+	usecase2 "src/internal/domain/track/usecase"
 	"src/internal/models"
 )
 
@@ -20,6 +20,14 @@ type AlbumUseCase interface {
 	IsAlbumOwned(albumId uint64, musicianId uint64) (bool, error)
 	GetAlbumIdForTrack(trackId uint64) (uint64, error)
 	GetAllAlbumsForMusician(musicianId uint64) ([]*models.Album, error)
+
+	// GetArtistCredits lists credits of the musician on releases of other
+	// musicians, pending ones first
+	GetArtistCredits(musicianId uint64) ([]*models.ArtistCredit, error)
+	ApproveArtistCredit(musicianId uint64, creditId uint64) error
+	// DeclineArtistCredit removes the musician from the release, the album's
+	// musician can't add them to it again
+	DeclineArtistCredit(musicianId uint64, creditId uint64) error
 }
 
 type usecase struct {
@@ -73,6 +81,10 @@ func (u *usecase) GetAlbum(id uint64) (*models.Album, error) {
 }
 
 func (u *usecase) UpdateAlbum(album *models.Album) error {
+	if err := album.Normalize(); err != nil {
+		return errors.Wrap(err, "album.usecase.UpdateAlbum invalid album")
+	}
+
 	err := u.albumRep.UpdateAlbum(album)
 
 	if err != nil {
@@ -83,21 +95,27 @@ func (u *usecase) UpdateAlbum(album *models.Album) error {
 }
 
 func (u *usecase) AddAlbumWithTracks(album *models.Album, tracks []*models.TrackObject, musicianId uint64) (uint64, error) {
-	var tracksMeta []*models.TrackMeta
+	if err := album.Normalize(); err != nil {
+		return 0, errors.Wrap(err, "album.usecase.AddAlbum invalid album")
+	}
+
 	for _, v := range tracks {
-		if len(v.Payload) == 0 {
-			return 0, models.ErrInvalidPayload
+		if err := v.Normalize(); err != nil {
+			return 0, errors.Wrap(err, "album.usecase.AddAlbum invalid track")
 		}
+	}
 
-		newSource, err := uuid.GenerateUUID()
-		if err != nil {
-			return 0, errors.Wrap(err, "album.usecase.AddAlbum error in UUID gen")
+	for _, v := range tracks {
+		if len(v.Payload) == 0 {
+			return 0, models.ErrInvalidPayload
 		}
+	}
 
-		v.Source = newSource
-
-		err = u.storageRep.UploadObject(v)
+	var tracksMeta []*models.TrackMeta
+	for _, v := range tracks {
+		err := usecase2.StoreSource(u.trackRep, u.storageRep, v)
 		if err != nil {
+			u.releaseSources(tracksMeta)
 			return 0, errors.Wrap(err, "album.usecase.AddAlbum error while add")
 		}
 
@@ -106,12 +124,21 @@ func (u *usecase) AddAlbumWithTracks(album *models.Album, tracks []*models.Track
 	id, err := u.albumRep.AddAlbumWithTracksOutbox(album, tracksMeta, musicianId)
 
 	if err != nil {
+		u.releaseSources(tracksMeta)
 		return 0, errors.Wrap(err, "album.usecase.AddAlbum error while add")
 	}
 
 	return id, nil
 }
 
+// releaseSources drops sources stored for tracks that were not saved, a source
+// failed to release is kept
+func (u *usecase) releaseSources(tracks []*models.TrackMeta) {
+	for _, v := range tracks {
+		_ = usecase2.ReleaseSource(u.trackRep, u.storageRep, v)
+	}
+}
+
 func (u *usecase) DeleteAlbum(id uint64) error {
 	tracks, err := u.albumRep.GetAllTracksForAlbum(id)
 	if err != nil {
@@ -124,7 +151,7 @@ func (u *usecase) DeleteAlbum(id uint64) error {
 	}
 
 	for _, v := range tracks {
-		err = u.storageRep.DeleteObject(v)
+		err = usecase2.ReleaseSource(u.trackRep, u.storageRep, v)
 		if err != nil {
 			return errors.Wrap(err, "album.usecase.AddAlbum error while add")
 		}
@@ -138,19 +165,18 @@ func (u *usecase) AddTrack(albumId uint64, track *models.TrackObject) (uint64, e
 		return 0, models.ErrInvalidPayload
 	}
 
-	newSource, err := uuid.GenerateUUID()
-	if err != nil {
-		return 0, errors.Wrap(err, "album.usecase.AddTrack error in UUID gen")
+	if err := track.Normalize(); err != nil {
+		return 0, errors.Wrap(err, "album.usecase.AddTrack invalid track")
 	}
-	track.Source = newSource
 
-	err = u.storageRep.UploadObject(track)
+	err := usecase2.StoreSource(u.trackRep, u.storageRep, track)
 	if err != nil {
 		return 0, errors.Wrap(err, "album.usecase.AddTrack error while add")
 	}
 
 	id, err := u.albumRep.AddTrackToAlbumOutbox(albumId, track.ExtractMeta())
 	if err != nil {
+		u.releaseSources([]*models.TrackMeta{track.ExtractMeta()})
 		return 0, errors.Wrap(err, "album.usecase.AddTrack error while add")
 	}
 
@@ -168,7 +194,7 @@ func (u *usecase) DeleteTrack(trackId uint64) error {
 		return errors.Wrap(err, "album.usecase.DeleteTrack error while delete")
 	}
 
-	err = u.storageRep.DeleteObject(trackMeta)
+	err = usecase2.ReleaseSource(u.trackRep, u.storageRep, trackMeta)
 	if err != nil {
 		return errors.Wrap(err, "album.usecase.DeleteTrack error while add")
 	}
@@ -185,3 +211,28 @@ func (u *usecase) GetAllTracks(albumId uint64) ([]*models.TrackMeta, error) {
 
 	return tracks, err
 }
+
+func (u *usecase) GetArtistCredits(musicianId uint64) ([]*models.ArtistCredit, error) {
+	credits, err := u.albumRep.GetArtistCredits(musicianId)
+	if err != nil {
+		return nil, errors.Wrap(err, "album.usecase.GetArtistCredits error while get")
+	}
+
+	return credits, nil
+}
+
+func (u *usecase) ApproveArtistCredit(musicianId uint64, creditId uint64) error {
+	if err := u.albumRep.ApproveArtistCredit(musicianId, creditId); err != nil {
+		return errors.Wrap(err, "album.usecase.ApproveArtistCredit error while approve")
+	}
+
+	return nil
+}
+
+func (u *usecase) DeclineArtistCredit(musicianId uint64, creditId uint64) error {
+	if err := u.albumRep.DeclineArtistCredit(musicianId, creditId); err != nil {
+		return errors.Wrap(err, "album.usecase.DeclineArtistCredit error while delete")
+	}
+
+	return nil
+}
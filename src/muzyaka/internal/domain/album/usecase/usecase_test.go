@@ -10,6 +10,16 @@ import (
 	"testing"
 )
 
+// newReference calls back as a source taken or dropped by one track only,
+// sharedReference as one shared with other tracks
+func newReference(source string, f func() error) error {
+	return f()
+}
+
+func sharedReference(source string, f func() error) error {
+	return nil
+}
+
 func TestUsecase_GetAlbum(t *testing.T) {
 	type mock func(r *mock_repository.MockAlbumRepository, id uint64)
 
@@ -60,8 +70,9 @@ func TestUsecase_GetAlbum(t *testing.T) {
 			tc.mock(repo, tc.input)
 
 			storage := mock_repository2.NewMockTrackStorage(c)
+			trackRep := mock_repository2.NewMockTrackRepository(c)
 
-			s := NewAlbumUseCase(repo, storage)
+			s := NewAlbumUseCase(repo, storage, trackRep)
 			res, err := s.GetAlbum(tc.input)
 
 			assert.Equal(t, tc.expectedValue, res)
@@ -89,7 +100,7 @@ func TestUsecase_UpdateAlbum(t *testing.T) {
 				Id:        1,
 				Name:      "test_name",
 				CoverFile: []byte("test_cover"),
-				Type:      "test_type",
+				Type:      "single",
 			},
 			mock: func(r *mock_repository.MockAlbumRepository, album models.Album) {
 				r.EXPECT().UpdateAlbum(&album).Return(nil)
@@ -102,13 +113,90 @@ func TestUsecase_UpdateAlbum(t *testing.T) {
 				Id:        1,
 				Name:      "test_name",
 				CoverFile: []byte("test_cover"),
-				Type:      "test_type",
+				Type:      "single",
 			},
 			mock: func(r *mock_repository.MockAlbumRepository, album models.Album) {
 				r.EXPECT().UpdateAlbum(&album).Return(errors.New("error in repo"))
 			},
 			expectedErr: errors.Wrap(errors.New("error in repo"), "album.usecase.UpdateAlbum error while update"),
 		},
+		{
+			name: "Type and UPC are normalized",
+			input: models.Album{
+				Id:    1,
+				Name:  "test_name",
+				Type:  "LP",
+				Label: " test_label ",
+				Upc:   "036000291452",
+			},
+			mock: func(r *mock_repository.MockAlbumRepository, album models.Album) {
+				album.Type = models.AlbumAlbum
+				album.Label = "test_label"
+				r.EXPECT().UpdateAlbum(&album).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Duplicated artists are dropped",
+			input: models.Album{
+				Id:   1,
+				Name: "test_name",
+				Type: "single",
+				Artists: []*models.Artist{
+					{MusicianId: 2, Role: models.ArtistFeatured},
+					{MusicianId: 2, Role: models.ArtistFeatured, Approved: true},
+					{MusicianId: 2, Role: models.ArtistRemixer},
+				},
+			},
+			mock: func(r *mock_repository.MockAlbumRepository, album models.Album) {
+				album.Artists = []*models.Artist{{MusicianId: 2, Role: models.ArtistFeatured}}
+				r.EXPECT().UpdateAlbum(&album).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Primary artist role",
+			input: models.Album{
+				Id:      1,
+				Name:    "test_name",
+				Type:    "single",
+				Artists: []*models.Artist{{MusicianId: 2, Role: models.ArtistPrimary}},
+			},
+			mock:        func(r *mock_repository.MockAlbumRepository, album models.Album) {},
+			expectedErr: errors.Wrap(models.ErrInvalidParameter, "album.usecase.UpdateAlbum invalid album"),
+		},
+		{
+			name: "Unknown artist role",
+			input: models.Album{
+				Id:      1,
+				Name:    "test_name",
+				Type:    "single",
+				Artists: []*models.Artist{{MusicianId: 2, Role: "drummer"}},
+			},
+			mock:        func(r *mock_repository.MockAlbumRepository, album models.Album) {},
+			expectedErr: errors.Wrap(models.ErrInvalidParameter, "album.usecase.UpdateAlbum invalid album"),
+		},
+		{
+			name: "Unknown type",
+			input: models.Album{
+				Id:   1,
+				Name: "test_name",
+				Type: "test_type",
+			},
+			mock:        func(r *mock_repository.MockAlbumRepository, album models.Album) {},
+			expectedErr: errors.Wrap(models.ErrInvalidParameter, "album.usecase.UpdateAlbum invalid album"),
+		},
+		{
+			name: "Invalid UPC check digit",
+			input: models.Album{
+				Id:   1,
+				Name: "test_name",
+				Type: "single",
+				Upc:  "036000291453",
+			},
+			mock:        func(r *mock_repository.MockAlbumRepository, album models.Album) {},
+			expectedErr: errors.Wrap(models.ErrInvalidParameter, "album.usecase.UpdateAlbum invalid album"),
+		},
 	}
 
 	for _, tc := range testTable {
@@ -121,8 +209,9 @@ func TestUsecase_UpdateAlbum(t *testing.T) {
 			tc.mock(repo, tc.input)
 
 			storage := mock_repository2.NewMockTrackStorage(c)
+			trackRep := mock_repository2.NewMockTrackRepository(c)
 
-			s := NewAlbumUseCase(repo, storage)
+			s := NewAlbumUseCase(repo, storage, trackRep)
 			err := s.UpdateAlbum(&tc.input)
 
 			if tc.expectedErr == nil {
@@ -137,6 +226,10 @@ func TestUsecase_UpdateAlbum(t *testing.T) {
 func TestUseCase_AddAlbumWithTracks(t *testing.T) {
 	type mock func(r *mock_repository.MockAlbumRepository, album *models.Album, tracks []*models.TrackObject)
 	type storageMock func(r *mock_repository2.MockTrackStorage, tracks []*models.TrackObject)
+	type trackMock func(r *mock_repository2.MockTrackRepository)
+
+	// sha256 of the payload of test tracks
+	const source = "039058c6f2c0cb492c533b0a4d14ef77cc0f78abccced5287d84a1a2011cfb81"
 
 	testTable := []struct {
 		name        string
@@ -144,6 +237,7 @@ func TestUseCase_AddAlbumWithTracks(t *testing.T) {
 		inputTracks []*models.TrackObject
 		mock        mock
 		storageMock storageMock
+		trackMock   trackMock
 		expectedID  uint64
 		expectedErr error
 	}{
@@ -180,6 +274,9 @@ func TestUseCase_AddAlbumWithTracks(t *testing.T) {
 				}
 
 			},
+			trackMock: func(r *mock_repository2.MockTrackRepository) {
+				r.EXPECT().AcquireSource(source, gomock.Any()).DoAndReturn(newReference).Times(2)
+			},
 			expectedID:  1,
 			expectedErr: nil,
 		},
@@ -213,11 +310,44 @@ func TestUseCase_AddAlbumWithTracks(t *testing.T) {
 			storageMock: func(r *mock_repository2.MockTrackStorage, tracks []*models.TrackObject) {
 				for _, v := range tracks {
 					r.EXPECT().UploadObject(v).Return(nil)
+					r.EXPECT().DeleteObject(v.ExtractMeta()).Return(nil)
 				}
 			},
+			trackMock: func(r *mock_repository2.MockTrackRepository) {
+				r.EXPECT().AcquireSource(source, gomock.Any()).DoAndReturn(newReference).Times(2)
+				r.EXPECT().ReleaseSource(source, gomock.Any()).DoAndReturn(newReference).Times(2)
+			},
 			expectedID:  0,
 			expectedErr: errors.Wrap(errors.New("error in repo"), "album.usecase.AddAlbum error while add"),
 		},
+		{
+			name: "Stored source test",
+			inputAlbum: &models.Album{
+				Id:        1,
+				Name:      "Test Album",
+				CoverFile: []byte{1, 2, 3},
+				Type:      "single",
+			},
+			inputTracks: []*models.TrackObject{
+				{
+					TrackMeta: models.TrackMeta{Id: 1, Name: "TrackMeta 1"},
+					Payload:   []byte{1, 2, 3},
+				},
+			},
+			mock: func(r *mock_repository.MockAlbumRepository, album *models.Album, tracks []*models.TrackObject) {
+				r.EXPECT().AddAlbumWithTracksOutbox(album, []*models.TrackMeta{{
+					Id:     1,
+					Name:   "TrackMeta 1",
+					Source: source,
+				}}, uint64(1)).Return(uint64(1), nil)
+			},
+			storageMock: func(r *mock_repository2.MockTrackStorage, tracks []*models.TrackObject) {},
+			trackMock: func(r *mock_repository2.MockTrackRepository) {
+				r.EXPECT().AcquireSource(source, gomock.Any()).DoAndReturn(sharedReference)
+			},
+			expectedID:  1,
+			expectedErr: nil,
+		},
 	}
 
 	for _, tc := range testTable {
@@ -229,9 +359,11 @@ func TestUseCase_AddAlbumWithTracks(t *testing.T) {
 			tc.mock(repo, tc.inputAlbum, tc.inputTracks)
 
 			storage := mock_repository2.NewMockTrackStorage(ctrl)
+			trackRep := mock_repository2.NewMockTrackRepository(ctrl)
 			tc.storageMock(storage, tc.inputTracks)
+			tc.trackMock(trackRep)
 
-			u := NewAlbumUseCase(repo, storage)
+			u := NewAlbumUseCase(repo, storage, trackRep)
 			id, err := u.AddAlbumWithTracks(tc.inputAlbum, tc.inputTracks, 1)
 
 			assert.Equal(t, tc.expectedID, id)
@@ -249,6 +381,7 @@ func TestUseCase_AddAlbumWithTracks(t *testing.T) {
 func TestUsecase_DeleteAlbum(t *testing.T) {
 	type mock func(r *mock_repository.MockAlbumRepository, id uint64, tracks []*models.TrackMeta)
 	type storageMock func(r *mock_repository2.MockTrackStorage, tracks []*models.TrackMeta)
+	type trackMock func(r *mock_repository2.MockTrackRepository)
 
 	testTable := []struct {
 		name        string
@@ -256,6 +389,7 @@ func TestUsecase_DeleteAlbum(t *testing.T) {
 		tracks      []*models.TrackMeta
 		mock        mock
 		storageMock storageMock
+		trackMock   trackMock
 		expectedErr error
 	}{
 		{
@@ -284,6 +418,38 @@ func TestUsecase_DeleteAlbum(t *testing.T) {
 					r.EXPECT().DeleteObject(v).Return(nil)
 				}
 			},
+			trackMock: func(r *mock_repository2.MockTrackRepository) {
+				r.EXPECT().ReleaseSource("track_src_1", gomock.Any()).DoAndReturn(newReference)
+				r.EXPECT().ReleaseSource("track_src_2", gomock.Any()).DoAndReturn(newReference)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Shared source test",
+			input: uint64(1),
+			tracks: []*models.TrackMeta{
+				{
+					Id:     1,
+					Source: "track_src_1",
+					Name:   "track_name_1",
+				},
+				{
+					Id:     2,
+					Source: "track_src_2",
+					Name:   "track_name_2",
+				},
+			},
+			mock: func(r *mock_repository.MockAlbumRepository, id uint64, tracks []*models.TrackMeta) {
+				r.EXPECT().GetAllTracksForAlbum(id).Return(tracks, nil)
+				r.EXPECT().DeleteAlbumOutbox(id).Return(nil)
+			},
+			storageMock: func(r *mock_repository2.MockTrackStorage, tracks []*models.TrackMeta) {
+				r.EXPECT().DeleteObject(tracks[1]).Return(nil)
+			},
+			trackMock: func(r *mock_repository2.MockTrackRepository) {
+				r.EXPECT().ReleaseSource("track_src_1", gomock.Any()).DoAndReturn(sharedReference)
+				r.EXPECT().ReleaseSource("track_src_2", gomock.Any()).DoAndReturn(newReference)
+			},
 			expectedErr: nil,
 		},
 		{
@@ -310,6 +476,7 @@ func TestUsecase_DeleteAlbum(t *testing.T) {
 			storageMock: func(r *mock_repository2.MockTrackStorage, tracks []*models.TrackMeta) {
 
 			},
+			trackMock:   func(r *mock_repository2.MockTrackRepository) {},
 			expectedErr: errors.Wrap(errors.New("error in repo"), "album.usecase.DeleteAlbumOutbox error while delete"),
 		},
 	}
@@ -324,9 +491,11 @@ func TestUsecase_DeleteAlbum(t *testing.T) {
 			tc.mock(repo, tc.input, tc.tracks)
 
 			storage := mock_repository2.NewMockTrackStorage(c)
+			trackRep := mock_repository2.NewMockTrackRepository(c)
 			tc.storageMock(storage, tc.tracks)
+			tc.trackMock(trackRep)
 
-			s := NewAlbumUseCase(repo, storage)
+			s := NewAlbumUseCase(repo, storage, trackRep)
 			err := s.DeleteAlbum(tc.input)
 
 			if tc.expectedErr == nil {
@@ -363,10 +532,10 @@ func TestUsecase_AddTrack(t *testing.T) {
 				Payload: []byte{1, 2, 3},
 			},
 			mock: func(r *mock_repository.MockAlbumRepository, album_id uint64, track models.TrackObject) {
-				r.EXPECT().AddTrackToAlbumOutbox(album_id, track.ExtractMeta()).Return(uint64(10), nil)
+				r.EXPECT().AddTrackToAlbumOutbox(album_id, gomock.AssignableToTypeOf(track.ExtractMeta())).Return(uint64(10), nil)
 			},
 			storageMock: func(r *mock_repository2.MockTrackStorage, track models.TrackObject) {
-				r.EXPECT().UploadObject(&track).Return(nil)
+				r.EXPECT().UploadObject(gomock.AssignableToTypeOf(&track)).Return(nil)
 			},
 			expectedValue: uint64(10),
 			expectedErr:   nil,
@@ -383,10 +552,11 @@ func TestUsecase_AddTrack(t *testing.T) {
 				Payload: []byte{1, 2, 3},
 			},
 			storageMock: func(r *mock_repository2.MockTrackStorage, track models.TrackObject) {
-				r.EXPECT().UploadObject(&track).Return(nil)
+				r.EXPECT().UploadObject(gomock.AssignableToTypeOf(&track)).Return(nil)
+				r.EXPECT().DeleteObject(gomock.AssignableToTypeOf(track.ExtractMeta())).Return(nil)
 			},
 			mock: func(r *mock_repository.MockAlbumRepository, album_id uint64, track models.TrackObject) {
-				r.EXPECT().AddTrackToAlbumOutbox(album_id, track.ExtractMeta()).Return(uint64(0), errors.New("error in repo"))
+				r.EXPECT().AddTrackToAlbumOutbox(album_id, gomock.AssignableToTypeOf(track.ExtractMeta())).Return(uint64(0), errors.New("error in repo"))
 			},
 			expectedValue: uint64(0),
 			expectedErr:   errors.Wrap(errors.New("error in repo"), "album.usecase.AddTrack error while add"),
@@ -402,9 +572,13 @@ func TestUsecase_AddTrack(t *testing.T) {
 			repo := mock_repository.NewMockAlbumRepository(c)
 			tc.mock(repo, tc.inputId, tc.inputTrack)
 			storage := mock_repository2.NewMockTrackStorage(c)
+			trackRep := mock_repository2.NewMockTrackRepository(c)
+			trackRep.EXPECT().AcquireSource(gomock.Any(), gomock.Any()).DoAndReturn(newReference)
+			// the source is released if the track is not saved
+			trackRep.EXPECT().ReleaseSource(gomock.Any(), gomock.Any()).DoAndReturn(newReference).MaxTimes(1)
 			tc.storageMock(storage, tc.inputTrack)
 
-			s := NewAlbumUseCase(repo, storage)
+			s := NewAlbumUseCase(repo, storage, trackRep)
 			res, err := s.AddTrack(tc.inputId, &tc.inputTrack)
 
 			assert.Equal(t, tc.expectedValue, res)
@@ -418,45 +592,72 @@ func TestUsecase_AddTrack(t *testing.T) {
 }
 
 func TestUsecase_DeleteTrack(t *testing.T) {
-	type mock func(r *mock_repository.MockAlbumRepository, album_id uint64, track models.TrackMeta)
+	type mock func(r *mock_repository.MockAlbumRepository, trackId uint64)
+	type trackMock func(r *mock_repository2.MockTrackRepository, trackId uint64, track models.TrackMeta)
 	type storageMock func(r *mock_repository2.MockTrackStorage, tracks models.TrackMeta)
 
 	testTable := []struct {
 		name        string
-		albumId     uint64
+		trackId     uint64
 		inputTrack  models.TrackMeta
 		mock        mock
+		trackMock   trackMock
 		storageMock storageMock
 		expectedErr error
 	}{
 		{
 			name:    "Usual test",
-			albumId: uint64(1),
+			trackId: uint64(10),
 			inputTrack: models.TrackMeta{
 				Id:     10,
 				Source: "test_src",
 				Name:   "test_name",
 				Genre:  "test_genre",
 			},
-			mock: func(r *mock_repository.MockAlbumRepository, album_id uint64, track models.TrackMeta) {
-				r.EXPECT().DeleteTrackFromAlbumOutbox(album_id, &track).Return(nil)
+			mock: func(r *mock_repository.MockAlbumRepository, trackId uint64) {
+				r.EXPECT().DeleteTrackFromAlbumOutbox(trackId).Return(nil)
+			},
+			trackMock: func(r *mock_repository2.MockTrackRepository, trackId uint64, track models.TrackMeta) {
+				r.EXPECT().GetTrack(trackId).Return(&track, nil)
+				r.EXPECT().ReleaseSource("test_src", gomock.Any()).DoAndReturn(newReference)
 			},
 			storageMock: func(r *mock_repository2.MockTrackStorage, track models.TrackMeta) {
 				r.EXPECT().DeleteObject(&track).Return(nil)
 			},
 			expectedErr: nil,
 		},
+		{
+			name:    "Shared source test",
+			trackId: uint64(10),
+			inputTrack: models.TrackMeta{
+				Id:     10,
+				Source: "test_src",
+				Name:   "test_name",
+			},
+			mock: func(r *mock_repository.MockAlbumRepository, trackId uint64) {
+				r.EXPECT().DeleteTrackFromAlbumOutbox(trackId).Return(nil)
+			},
+			trackMock: func(r *mock_repository2.MockTrackRepository, trackId uint64, track models.TrackMeta) {
+				r.EXPECT().GetTrack(trackId).Return(&track, nil)
+				r.EXPECT().ReleaseSource("test_src", gomock.Any()).DoAndReturn(sharedReference)
+			},
+			storageMock: func(r *mock_repository2.MockTrackStorage, track models.TrackMeta) {},
+			expectedErr: nil,
+		},
 		{
 			name:    "Repo fail test",
-			albumId: uint64(2),
+			trackId: uint64(10),
 			inputTrack: models.TrackMeta{
 				Id:     10,
 				Source: "test_src",
 				Name:   "test_name",
 				Genre:  "test_genre",
 			},
-			mock: func(r *mock_repository.MockAlbumRepository, album_id uint64, track models.TrackMeta) {
-				r.EXPECT().DeleteTrackFromAlbumOutbox(album_id, &track).Return(errors.New("error in repo"))
+			mock: func(r *mock_repository.MockAlbumRepository, trackId uint64) {
+				r.EXPECT().DeleteTrackFromAlbumOutbox(trackId).Return(errors.New("error in repo"))
+			},
+			trackMock: func(r *mock_repository2.MockTrackRepository, trackId uint64, track models.TrackMeta) {
+				r.EXPECT().GetTrack(trackId).Return(&track, nil)
 			},
 			storageMock: func(r *mock_repository2.MockTrackStorage, track models.TrackMeta) {
 			},
@@ -473,13 +674,16 @@ func TestUsecase_DeleteTrack(t *testing.T) {
 			defer ctrl.Finish()
 
 			repo := mock_repository.NewMockAlbumRepository(ctrl)
-			tc.mock(repo, tc.albumId, tc.inputTrack)
+			tc.mock(repo, tc.trackId)
 
 			storage := mock_repository2.NewMockTrackStorage(ctrl)
 			tc.storageMock(storage, tc.inputTrack)
 
-			s := NewAlbumUseCase(repo, storage)
-			err := s.DeleteTrack(tc.albumId)
+			trackRep := mock_repository2.NewMockTrackRepository(ctrl)
+			tc.trackMock(trackRep, tc.trackId, tc.inputTrack)
+
+			s := NewAlbumUseCase(repo, storage, trackRep)
+			err := s.DeleteTrack(tc.trackId)
 
 			if tc.expectedErr == nil {
 				assert.Nil(t, err)
@@ -544,8 +748,9 @@ func TestUsecase_GetAllTracks(t *testing.T) {
 			tc.mock(repo, tc.albumId, tc.expectedTracks)
 
 			storage := mock_repository2.NewMockTrackStorage(ctrl)
+			trackRep := mock_repository2.NewMockTrackRepository(ctrl)
 
-			u := NewAlbumUseCase(repo, storage)
+			u := NewAlbumUseCase(repo, storage, trackRep)
 			tracks, err := u.GetAllTracks(tc.albumId)
 
 			assert.Equal(t, tc.expectedTracks, tracks)
@@ -557,3 +762,57 @@ func TestUsecase_GetAllTracks(t *testing.T) {
 		})
 	}
 }
+
+func TestUsecase_ApproveArtistCredit(t *testing.T) {
+	type mock func(r *mock_repository.MockAlbumRepository, musicianId uint64, creditId uint64)
+
+	testTable := []struct {
+		name        string
+		musicianId  uint64
+		creditId    uint64
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name:       "Usual test",
+			musicianId: 2,
+			creditId:   10,
+			mock: func(r *mock_repository.MockAlbumRepository, musicianId uint64, creditId uint64) {
+				r.EXPECT().ApproveArtistCredit(musicianId, creditId).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:       "Credit of another musician",
+			musicianId: 3,
+			creditId:   10,
+			mock: func(r *mock_repository.MockAlbumRepository, musicianId uint64, creditId uint64) {
+				r.EXPECT().ApproveArtistCredit(musicianId, creditId).Return(models.ErrNotFound)
+			},
+			expectedErr: errors.Wrap(models.ErrNotFound, "album.usecase.ApproveArtistCredit error while approve"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockAlbumRepository(ctrl)
+			tc.mock(repo, tc.musicianId, tc.creditId)
+
+			storage := mock_repository2.NewMockTrackStorage(ctrl)
+			trackRep := mock_repository2.NewMockTrackRepository(ctrl)
+
+			u := NewAlbumUseCase(repo, storage, trackRep)
+			err := u.ApproveArtistCredit(tc.musicianId, tc.creditId)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, err.Error(), tc.expectedErr.Error())
+				assert.True(t, errors.Is(err, models.ErrNotFound))
+			}
+		})
+	}
+}
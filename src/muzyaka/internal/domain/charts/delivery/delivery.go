@@ -0,0 +1,68 @@
+package delivery
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"net/http"
+	"src/internal/domain/charts/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/models"
+	"src/internal/models/dto"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// @Summary GetChart
+// @Security ApiKeyAuth
+// @Tags charts
+// @Description get top tracks, albums or musicians with movement against the previous snapshot
+// @ID get-chart
+// @Accept  json
+// @Produce  json
+// @Param period path string true "day, week or month"
+// @Param genre query string false "genre name, global chart if omitted"
+// @Param type query string false "track (default), album or musician"
+// @Param date query string false "YYYY-MM-DD, the chart as it was at the end of the day"
+// @Success 200 {object} dto.Chart
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/charts/{period} [get]
+func GetChart(useCase usecase.ChartsUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		period := chi.URLParam(r, "period")
+		genre := r.URL.Query().Get("genre")
+		kind := r.URL.Query().Get("type")
+
+		var at *time.Time
+		if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+			date, err := time.Parse(dateLayout, dateStr)
+			if err != nil {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error(err.Error()))
+				return
+			}
+			endOfDay := date.Add(24*time.Hour - time.Nanosecond)
+			at = &endOfDay
+		}
+
+		chart, err := useCase.GetChart(period, kind, genre, at)
+		if errors.Is(err, models.ErrInvalidParameter) || errors.Is(err, models.ErrInvalidGenre) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoChart(chart, genre))
+	}
+}
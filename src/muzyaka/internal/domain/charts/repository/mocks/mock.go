@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChartsRepository is a mock of ChartsRepository interface.
+type MockChartsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockChartsRepositoryMockRecorder
+}
+
+// MockChartsRepositoryMockRecorder is the mock recorder for MockChartsRepository.
+type MockChartsRepositoryMockRecorder struct {
+	mock *MockChartsRepository
+}
+
+// NewMockChartsRepository creates a new mock instance.
+func NewMockChartsRepository(ctrl *gomock.Controller) *MockChartsRepository {
+	mock := &MockChartsRepository{ctrl: ctrl}
+	mock.recorder = &MockChartsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChartsRepository) EXPECT() *MockChartsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetChart mocks base method.
+func (m *MockChartsRepository) GetChart(kind, period string, genreId *uint64, at time.Time) (*models.Chart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChart", kind, period, genreId, at)
+	ret0, _ := ret[0].(*models.Chart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChart indicates an expected call of GetChart.
+func (mr *MockChartsRepositoryMockRecorder) GetChart(kind, period, genreId, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChart", reflect.TypeOf((*MockChartsRepository)(nil).GetChart), kind, period, genreId, at)
+}
+
+// GetGenreId mocks base method.
+func (m *MockChartsRepository) GetGenreId(name string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenreId", name)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenreId indicates an expected call of GetGenreId.
+func (mr *MockChartsRepositoryMockRecorder) GetGenreId(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreId", reflect.TypeOf((*MockChartsRepository)(nil).GetGenreId), name)
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/domain/charts/repository"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"time"
+)
+
+type chartsRepository struct {
+	db *gorm.DB
+}
+
+func NewChartsRepository(db *gorm.DB) repository.ChartsRepository {
+	return &chartsRepository{db: db}
+}
+
+func (c chartsRepository) GetGenreId(name string) (uint64, error) {
+	var genres []*dao.Genre
+	tx := c.db.Where("lower(name) = lower(?)", name).Limit(1).Find(&genres)
+	if tx.Error != nil {
+		return 0, errors.Wrap(tx.Error, "database error (table genres)")
+	}
+
+	if len(genres) == 0 {
+		return 0, models.ErrInvalidGenre
+	}
+
+	return *genres[0].ID, nil
+}
+
+func (c chartsRepository) GetChart(kind string, period string, genreId *uint64, at time.Time) (*models.Chart, error) {
+	query := c.db.Where("kind = ? AND period = ? AND computed_at <= ?", kind, period, at)
+	if genreId == nil {
+		query = query.Where("genre_id IS NULL")
+	} else {
+		query = query.Where("genre_id = ?", *genreId)
+	}
+
+	var snapshots []*dao.ChartSnapshot
+	tx := query.Order("computed_at DESC").Limit(1).Find(&snapshots)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table chart_snapshots)")
+	}
+
+	if len(snapshots) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	var entries []*models.ChartEntry
+	tx = c.db.Raw(`
+		SELECT e.position, e.item_id, e.score, e.previous_position,
+		       coalesce(t.name, a.name, m.name) AS name
+		FROM chart_entries e
+		LEFT JOIN tracks t ON ? = 'track' AND t.id = e.item_id
+		LEFT JOIN albums a ON ? = 'album' AND a.id = e.item_id
+		LEFT JOIN musicians m ON ? = 'musician' AND m.id = e.item_id
+		WHERE e.snapshot_id = ? AND coalesce(t.id, a.id, m.id) IS NOT NULL
+		ORDER BY e.position`, kind, kind, kind, snapshots[0].ID).Scan(&entries)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table chart_entries)")
+	}
+
+	chart := dao.ToModelChart(snapshots[0])
+	chart.Entries = entries
+
+	return chart, nil
+}
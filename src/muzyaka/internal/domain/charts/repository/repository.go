@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+type ChartsRepository interface {
+	GetGenreId(name string) (uint64, error)
+	// GetChart returns the latest snapshot taken not later than at.
+	// Entries of deleted items are skipped.
+	GetChart(kind string, period string, genreId *uint64, at time.Time) (*models.Chart, error)
+}
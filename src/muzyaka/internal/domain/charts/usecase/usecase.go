@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"github.com/pkg/errors"
+	"slices"
+	"src/internal/domain/charts/repository"
+	"src/internal/models"
+	"time"
+)
+
+type ChartsUseCase interface {
+	// GetChart returns the chart as of at, the current one if at is nil.
+	// An empty genre means the global chart.
+	GetChart(period string, kind string, genre string, at *time.Time) (*models.Chart, error)
+}
+
+type usecase struct {
+	chartsRep repository.ChartsRepository
+	now       func() time.Time
+}
+
+func NewChartsUseCase(chartsRep repository.ChartsRepository) ChartsUseCase {
+	return &usecase{
+		chartsRep: chartsRep,
+		now:       time.Now,
+	}
+}
+
+func (u *usecase) GetChart(period string, kind string, genre string, at *time.Time) (*models.Chart, error) {
+	if _, ok := models.ChartPeriods[period]; !ok {
+		return nil, models.ErrInvalidParameter
+	}
+
+	if kind == "" {
+		kind = models.ChartKindTrack
+	}
+
+	if !slices.Contains(models.ChartKinds, kind) {
+		return nil, models.ErrInvalidParameter
+	}
+
+	var genreId *uint64
+	if genre != "" {
+		id, err := u.chartsRep.GetGenreId(genre)
+		if err != nil {
+			return nil, errors.Wrap(err, "charts.usecase.GetChart error while genre call")
+		}
+		genreId = &id
+	}
+
+	snapshotAt := u.now()
+	if at != nil {
+		snapshotAt = *at
+	}
+
+	chart, err := u.chartsRep.GetChart(kind, period, genreId, snapshotAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "charts.usecase.GetChart error while chartsRep call")
+	}
+
+	return chart, nil
+}
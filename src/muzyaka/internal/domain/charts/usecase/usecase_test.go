@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock_repository "src/internal/domain/charts/repository/mocks"
+	"src/internal/models"
+	"testing"
+	"time"
+)
+
+func TestChartsUseCase_GetChart(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-48 * time.Hour)
+	genreId := uint64(3)
+
+	type mock func(r *mock_repository.MockChartsRepository)
+
+	testTable := []struct {
+		name        string
+		period      string
+		kind        string
+		genre       string
+		at          *time.Time
+		mock        mock
+		expected    *models.Chart
+		expectedErr error
+	}{
+		{
+			name:   "Usual test",
+			period: models.ChartPeriodDay,
+			mock: func(r *mock_repository.MockChartsRepository) {
+				r.EXPECT().GetChart(models.ChartKindTrack, models.ChartPeriodDay, nil, now).
+					Return(&models.Chart{Id: 1}, nil)
+			},
+			expected:    &models.Chart{Id: 1},
+			expectedErr: nil,
+		},
+		{
+			name:   "Genre and date test",
+			period: models.ChartPeriodMonth,
+			kind:   models.ChartKindAlbum,
+			genre:  "rock",
+			at:     &past,
+			mock: func(r *mock_repository.MockChartsRepository) {
+				r.EXPECT().GetGenreId("rock").Return(genreId, nil)
+				r.EXPECT().GetChart(models.ChartKindAlbum, models.ChartPeriodMonth, &genreId, past).
+					Return(&models.Chart{Id: 2}, nil)
+			},
+			expected:    &models.Chart{Id: 2},
+			expectedErr: nil,
+		},
+		{
+			name:   "Invalid period test",
+			period: "year",
+			mock: func(r *mock_repository.MockChartsRepository) {
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:   "Invalid kind test",
+			period: models.ChartPeriodDay,
+			kind:   "genre",
+			mock: func(r *mock_repository.MockChartsRepository) {
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:   "Unknown genre test",
+			period: models.ChartPeriodDay,
+			genre:  "unknown",
+			mock: func(r *mock_repository.MockChartsRepository) {
+				r.EXPECT().GetGenreId("unknown").Return(uint64(0), models.ErrInvalidGenre)
+			},
+			expected: nil,
+			expectedErr: errors.Wrap(models.ErrInvalidGenre,
+				"charts.usecase.GetChart error while genre call"),
+		},
+		{
+			name:   "No snapshot test",
+			period: models.ChartPeriodWeek,
+			mock: func(r *mock_repository.MockChartsRepository) {
+				r.EXPECT().GetChart(models.ChartKindTrack, models.ChartPeriodWeek, nil, now).
+					Return(nil, models.ErrNotFound)
+			},
+			expected: nil,
+			expectedErr: errors.Wrap(models.ErrNotFound,
+				"charts.usecase.GetChart error while chartsRep call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockChartsRepository(ctrl)
+			tc.mock(repo)
+
+			u := &usecase{chartsRep: repo, now: func() time.Time { return now }}
+			chart, err := u.GetChart(tc.period, tc.kind, tc.genre, tc.at)
+
+			assert.Equal(t, tc.expected, chart)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChartEntry_Movement(t *testing.T) {
+	position := func(v int) *int { return &v }
+
+	assert.Equal(t, models.ChartMovementNew, (&models.ChartEntry{Position: 1}).Movement())
+	assert.Equal(t, models.ChartMovementUp, (&models.ChartEntry{Position: 1, PreviousPosition: position(3)}).Movement())
+	assert.Equal(t, models.ChartMovementDown, (&models.ChartEntry{Position: 4, PreviousPosition: position(3)}).Movement())
+	assert.Equal(t, models.ChartMovementSame, (&models.ChartEntry{Position: 3, PreviousPosition: position(3)}).Movement())
+}
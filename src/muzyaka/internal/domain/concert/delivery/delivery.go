@@ -0,0 +1,391 @@
+package delivery
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"net/http"
+	"src/internal/domain/concert/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/lib/ical"
+	"src/internal/models"
+	"src/internal/models/dto"
+	"strconv"
+	"time"
+)
+
+// @Summary ConcertCreate
+// @Security ApiKeyAuth
+// @Tags musician
+// @Description create concert
+// @ID create-concert
+// @Accept  json
+// @Produce  json
+// @Param input body dto.ConcertWithoutId true "concert info"
+// @Param musician_id   path      int  true  "Musician ID"
+// @Success 200 {object} dto.CreateConcertResponse
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/concerts [post]
+func ConcertCreate(useCase usecase.ConcertUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianID := chi.URLParam(r, "musician_id")
+		musicianIDUint, err := strconv.ParseUint(musicianID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.ConcertWithoutId
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		id, err := useCase.AddConcert(dto.ToModelConcertWithoutId(&req, 0), musicianIDUint)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.CreateConcertResponse{
+			Id: id,
+		})
+	}
+}
+
+// @Summary ConcertUpdate
+// @Security ApiKeyAuth
+// @Tags concert
+// @Description update concert
+// @ID update-concert
+// @Accept  json
+// @Produce  json
+// @Param input body dto.ConcertWithoutId true "concert info"
+// @Param id   path      int  true  "Concert ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/concert/{id} [put]
+func UpdateConcert(useCase usecase.ConcertUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		concertID := chi.URLParam(r, "id")
+		concertIDUint, err := strconv.ParseUint(concertID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.ConcertWithoutId
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.UpdateConcert(dto.ToModelConcertWithoutId(&req, concertIDUint))
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary ConcertDelete
+// @Security ApiKeyAuth
+// @Tags concert
+// @Description delete concert
+// @ID delete-concert
+// @Accept  json
+// @Produce  json
+// @Param id   path      int  true  "Concert ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/concert/{id} [delete]
+func DeleteConcert(useCase usecase.ConcertUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		aid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.DeleteConcert(aid)
+		if errors.Is(err, models.ErrNothingToDelete) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary ConcertGet
+// @Security ApiKeyAuth
+// @Tags concert
+// @Description get concert
+// @ID get-concert
+// @Accept  json
+// @Produce  json
+// @Param id   path      int  true  "Concert ID"
+// @Success 200 {object} dto.Concert
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/concert/{id} [get]
+func GetConcert(useCase usecase.ConcertUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		aid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		concert, err := useCase.GetConcert(aid)
+		if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoConcert(concert))
+	}
+}
+
+// @Summary GetUpcomingConcerts
+// @Security ApiKeyAuth
+// @Tags musician
+// @Description get concerts of the musician that have not started yet, soonest first
+// @ID get-upcoming-concerts
+// @Accept  json
+// @Produce  json
+// @Param musician_id   path      int  true  "Musician ID"
+// @Param        page    query     int  true  "number of page from 1"
+// @Param        page_size    query     int  true  "size of page, at most 100"
+// @Success 200 {object} dto.ConcertsCollection
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/concerts [get]
+func GetUpcomingConcerts(useCase usecase.ConcertUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianID := chi.URLParam(r, "musician_id")
+		musicianIDUint, err := strconv.ParseUint(musicianID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageStr := r.URL.Query().Get("page")
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageSizeStr := r.URL.Query().Get("page_size")
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		concerts, err := useCase.GetUpcomingConcerts(musicianIDUint, page, pageSize)
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoConcerts(concerts))
+	}
+}
+
+// @Summary FindConcertsNearby
+// @Security ApiKeyAuth
+// @Tags concert
+// @Description find upcoming concerts within a radius of a point, soonest first
+// @ID find-concerts-nearby
+// @Accept  json
+// @Produce  json
+// @Param        lat    query     number  true  "latitude in degrees"
+// @Param        lon    query     number  true  "longitude in degrees"
+// @Param        radius_km    query     number  false  "radius in km, 50 by default, at most 500"
+// @Param        page    query     int  true  "number of page from 1"
+// @Param        page_size    query     int  true  "size of page, at most 100"
+// @Success 200 {object} dto.NearbyConcertsCollection
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/concerts/nearby [get]
+func FindConcertsNearby(useCase usecase.ConcertUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lat, err := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		lon, err := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var radiusKm float64
+		if v := r.URL.Query().Get("radius_km"); v != "" {
+			radiusKm, err = strconv.ParseFloat(v, 64)
+			if err != nil {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error(err.Error()))
+				return
+			}
+		}
+
+		pageStr := r.URL.Query().Get("page")
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageSizeStr := r.URL.Query().Get("page_size")
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		concerts, err := useCase.FindConcertsNearby(lat, lon, radiusKm, page, pageSize)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoNearbyConcerts(concerts))
+	}
+}
+
+// @Summary ExportConcert
+// @Security ApiKeyAuth
+// @Tags concert
+// @Description export concert as iCalendar
+// @ID export-concert
+// @Produce  text/calendar
+// @Param id   path      int  true  "Concert ID"
+// @Success 200 {file} file
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/concert/{id}/ics [get]
+func ExportConcert(useCase usecase.ConcertUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		aid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		concert, err := useCase.GetConcert(aid)
+		if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", ical.ContentType)
+		w.Header().Set("Content-Disposition", "attachment; filename=\"concert-"+id+".ics\"")
+		_, _ = w.Write(ical.Encode(concert.Title, []*models.Concert{concert}, time.Now()))
+	}
+}
+
+// @Summary ExportUpcomingConcerts
+// @Security ApiKeyAuth
+// @Tags musician
+// @Description export the next 100 concerts of the musician as iCalendar
+// @ID export-upcoming-concerts
+// @Produce  text/calendar
+// @Param musician_id   path      int  true  "Musician ID"
+// @Success 200 {file} file
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/concerts/ics [get]
+func ExportUpcomingConcerts(useCase usecase.ConcertUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianID := chi.URLParam(r, "musician_id")
+		musicianIDUint, err := strconv.ParseUint(musicianID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		concerts, err := useCase.GetUpcomingConcerts(musicianIDUint, 1, usecase.MaxPageSize)
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", ical.ContentType)
+		w.Header().Set("Content-Disposition", "attachment; filename=\"concerts-"+musicianID+".ics\"")
+		_, _ = w.Write(ical.Encode("", concerts, time.Now()))
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"src/internal/domain/auth/middleware"
+	usecase3 "src/internal/domain/auth/usecase"
+	"src/internal/domain/concert/usecase"
+	usecase2 "src/internal/domain/musician/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/models"
+	"strconv"
+)
+
+func CheckConcertOwnership(next http.Handler,
+	useCase usecase.ConcertUseCase,
+	musicianUseCase usecase2.MusicianUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		concertID := chi.URLParam(r, "id")
+		concertIDUint, err := strconv.ParseUint(concertID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		userInfo, isOk := r.Context().Value(middleware.ValuesFromContext).(middleware.ContextValues)
+		if !isOk {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidContext.Error()))
+			return
+		}
+		if userInfo.Role == usecase3.AdminRole {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		musicianId, err := musicianUseCase.GetMusicianIdForUser(userInfo.Id)
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		isAllowed, err := useCase.IsConcertOwned(concertIDUint, musicianId)
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		if !isAllowed {
+			render.Status(r, http.StatusMethodNotAllowed)
+			render.JSON(w, r, response.Error(models.ErrAccessDenied.Error()))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
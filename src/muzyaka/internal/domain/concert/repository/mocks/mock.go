@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockConcertRepository is a mock of ConcertRepository interface.
+type MockConcertRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConcertRepositoryMockRecorder
+}
+
+// MockConcertRepositoryMockRecorder is the mock recorder for MockConcertRepository.
+type MockConcertRepositoryMockRecorder struct {
+	mock *MockConcertRepository
+}
+
+// NewMockConcertRepository creates a new mock instance.
+func NewMockConcertRepository(ctrl *gomock.Controller) *MockConcertRepository {
+	mock := &MockConcertRepository{ctrl: ctrl}
+	mock.recorder = &MockConcertRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConcertRepository) EXPECT() *MockConcertRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddConcert mocks base method.
+func (m *MockConcertRepository) AddConcert(concert *models.Concert, musicianId uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddConcert", concert, musicianId)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddConcert indicates an expected call of AddConcert.
+func (mr *MockConcertRepositoryMockRecorder) AddConcert(concert, musicianId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddConcert", reflect.TypeOf((*MockConcertRepository)(nil).AddConcert), concert, musicianId)
+}
+
+// DeleteConcert mocks base method.
+func (m *MockConcertRepository) DeleteConcert(id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConcert", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConcert indicates an expected call of DeleteConcert.
+func (mr *MockConcertRepositoryMockRecorder) DeleteConcert(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConcert", reflect.TypeOf((*MockConcertRepository)(nil).DeleteConcert), id)
+}
+
+// FindConcertsNearby mocks base method.
+func (m *MockConcertRepository) FindConcertsNearby(lat, lon, radiusKm float64, offset, limit int) ([]*models.NearbyConcert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindConcertsNearby", lat, lon, radiusKm, offset, limit)
+	ret0, _ := ret[0].([]*models.NearbyConcert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindConcertsNearby indicates an expected call of FindConcertsNearby.
+func (mr *MockConcertRepositoryMockRecorder) FindConcertsNearby(lat, lon, radiusKm, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindConcertsNearby", reflect.TypeOf((*MockConcertRepository)(nil).FindConcertsNearby), lat, lon, radiusKm, offset, limit)
+}
+
+// GetConcert mocks base method.
+func (m *MockConcertRepository) GetConcert(id uint64) (*models.Concert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConcert", id)
+	ret0, _ := ret[0].(*models.Concert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConcert indicates an expected call of GetConcert.
+func (mr *MockConcertRepositoryMockRecorder) GetConcert(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConcert", reflect.TypeOf((*MockConcertRepository)(nil).GetConcert), id)
+}
+
+// GetUpcomingConcerts mocks base method.
+func (m *MockConcertRepository) GetUpcomingConcerts(musicianId uint64, offset, limit int) ([]*models.Concert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpcomingConcerts", musicianId, offset, limit)
+	ret0, _ := ret[0].([]*models.Concert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpcomingConcerts indicates an expected call of GetUpcomingConcerts.
+func (mr *MockConcertRepositoryMockRecorder) GetUpcomingConcerts(musicianId, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpcomingConcerts", reflect.TypeOf((*MockConcertRepository)(nil).GetUpcomingConcerts), musicianId, offset, limit)
+}
+
+// IsConcertOwned mocks base method.
+func (m *MockConcertRepository) IsConcertOwned(concertId, musicianId uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsConcertOwned", concertId, musicianId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsConcertOwned indicates an expected call of IsConcertOwned.
+func (mr *MockConcertRepositoryMockRecorder) IsConcertOwned(concertId, musicianId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsConcertOwned", reflect.TypeOf((*MockConcertRepository)(nil).IsConcertOwned), concertId, musicianId)
+}
+
+// UpdateConcert mocks base method.
+func (m *MockConcertRepository) UpdateConcert(concert *models.Concert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConcert", concert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConcert indicates an expected call of UpdateConcert.
+func (mr *MockConcertRepositoryMockRecorder) UpdateConcert(concert interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConcert", reflect.TypeOf((*MockConcertRepository)(nil).UpdateConcert), concert)
+}
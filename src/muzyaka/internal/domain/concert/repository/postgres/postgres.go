@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/domain/concert/repository"
+	"src/internal/lib/geo"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"src/internal/models/events"
+)
+
+type concertRepository struct {
+	db *gorm.DB
+}
+
+func NewConcertRepository(db *gorm.DB) repository.ConcertRepository {
+	return &concertRepository{db: db}
+}
+
+func (c *concertRepository) GetConcert(id uint64) (*models.Concert, error) {
+	var concert dao.Concert
+
+	tx := c.db.Where("id = ?", id).Take(&concert)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	} else if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table concerts)")
+	}
+
+	return dao.ToModelConcert(&concert), nil
+}
+
+func (c *concertRepository) GetUpcomingConcerts(musicianId uint64, offset int, limit int) ([]*models.Concert, error) {
+	var concerts []*dao.Concert
+
+	tx := c.db.Where("musician_id = ? AND starts_at >= now()", musicianId).
+		Order("starts_at, id").
+		Offset(offset).
+		Limit(limit).
+		Find(&concerts)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table concerts)")
+	}
+
+	var res []*models.Concert
+	for _, v := range concerts {
+		res = append(res, dao.ToModelConcert(v))
+	}
+
+	return res, nil
+}
+
+func (c *concertRepository) UpdateConcert(concert *models.Concert) error {
+	pgConcert := dao.ToPostgresConcert(concert, 0)
+
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		// every field is replaced, sold_out and description may be zero
+		res := tx.Model(pgConcert).
+			Select("title", "description", "venue", "city", "latitude", "longitude",
+				"starts_at", "time_zone", "ticket_url", "sold_out").
+			Updates(pgConcert)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNotFound
+		}
+
+		var updated dao.Concert
+		if err := tx.Where("id = ?", pgConcert.ID).Take(&updated).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.ConcertUpdated, updated.ID, dao.ToConcertPayload(&updated))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table concerts)")
+	}
+
+	return nil
+}
+
+func (c *concertRepository) AddConcert(concert *models.Concert, musicianId uint64) (uint64, error) {
+	pgConcert := dao.ToPostgresConcert(concert, musicianId)
+
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("id").Create(pgConcert).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.ConcertCreated, pgConcert.ID, dao.ToConcertPayload(pgConcert))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if err != nil {
+		return 0, errors.Wrap(err, "database error (table concerts)")
+	}
+
+	concert.Id = pgConcert.ID
+	return pgConcert.ID, nil
+}
+
+func (c *concertRepository) DeleteConcert(id uint64) error {
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		var concert dao.Concert
+		if err := tx.Where("id = ?", id).Take(&concert).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNothingToDelete
+		} else if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&dao.Concert{}, id).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.ConcertDeleted, id,
+			events.ConcertPayload{ConcertId: id, MusicianId: concert.MusicianID})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNothingToDelete) {
+		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table concerts)")
+	}
+
+	return nil
+}
+
+func (c *concertRepository) IsConcertOwned(concertId uint64, musicianId uint64) (bool, error) {
+	var concert dao.Concert
+
+	tx := c.db.Where("id = ?", concertId).Take(&concert)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return false, nil
+	} else if tx.Error != nil {
+		return false, errors.Wrap(tx.Error, "database error (table concerts)")
+	}
+
+	return musicianId == concert.MusicianID, nil
+}
+
+// haversine is the distance in km from (?, ?) in degrees, it matches geo.Distance
+const haversine = `2 * 6371.0 * asin(least(1, sqrt(
+	power(sin(radians(latitude - ?) / 2), 2) +
+	cos(radians(?)) * cos(radians(latitude)) * power(sin(radians(longitude - ?) / 2), 2))))`
+
+func (c *concertRepository) FindConcertsNearby(lat float64, lon float64, radiusKm float64,
+	offset int, limit int) ([]*models.NearbyConcert, error) {
+	box := geo.BoundingBox(lat, lon, radiusKm)
+
+	// the box uses the indexes, the distance drops its corners
+	nearby := c.db.Model(&dao.Concert{}).
+		Select("*, "+haversine+" AS distance_km", lat, lat, lon).
+		Where("starts_at >= now()").
+		Where("latitude BETWEEN ? AND ?", box.MinLat, box.MaxLat)
+	if box.CrossesAntimeridian() {
+		nearby = nearby.Where("(longitude >= ? OR longitude <= ?)", box.MinLon, box.MaxLon)
+	} else {
+		nearby = nearby.Where("longitude BETWEEN ? AND ?", box.MinLon, box.MaxLon)
+	}
+
+	var concerts []*dao.NearbyConcert
+	tx := c.db.Table("(?) AS n", nearby).
+		Where("distance_km <= ?", radiusKm).
+		Order("starts_at, distance_km, id").
+		Offset(offset).
+		Limit(limit).
+		Find(&concerts)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table concerts)")
+	}
+
+	var res []*models.NearbyConcert
+	for _, v := range concerts {
+		res = append(res, &models.NearbyConcert{
+			Concert:    dao.ToModelConcert(&v.Concert),
+			DistanceKm: v.DistanceKm,
+		})
+	}
+
+	return res, nil
+}
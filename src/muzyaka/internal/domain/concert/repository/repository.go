@@ -0,0 +1,18 @@
+package repository
+
+import "src/internal/models"
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+type ConcertRepository interface {
+	GetConcert(id uint64) (*models.Concert, error)
+	// GetUpcomingConcerts returns concerts of the musician that have not started yet, soonest first
+	GetUpcomingConcerts(musicianId uint64, offset int, limit int) ([]*models.Concert, error)
+	UpdateConcert(concert *models.Concert) error
+	AddConcert(concert *models.Concert, musicianId uint64) (uint64, error)
+	DeleteConcert(id uint64) error
+
+	IsConcertOwned(concertId uint64, musicianId uint64) (bool, error)
+	// FindConcertsNearby returns upcoming concerts within radiusKm of the point, soonest first
+	FindConcertsNearby(lat float64, lon float64, radiusKm float64, offset int, limit int) ([]*models.NearbyConcert, error)
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"github.com/pkg/errors"
+	"math"
+	"net/url"
+	"src/internal/domain/concert/repository"
+	"src/internal/models"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const MinPageSize = 10
+const MaxPageSize = 100
+
+const (
+	MaxTitleLength = 254
+	MaxCityLength  = 100
+	MaxUrlLength   = 254
+
+	DefaultRadiusKm = 50
+	MaxRadiusKm     = 500
+)
+
+type ConcertUseCase interface {
+	GetConcert(id uint64) (*models.Concert, error)
+	GetUpcomingConcerts(musicianId uint64, page int, pageSize int) ([]*models.Concert, error)
+	// UpdateConcert and AddConcert move StartsAt to the concert time zone
+	UpdateConcert(concert *models.Concert) error
+	AddConcert(concert *models.Concert, musicianId uint64) (uint64, error)
+	DeleteConcert(id uint64) error
+
+	IsConcertOwned(concertId uint64, musicianId uint64) (bool, error)
+
+	// FindConcertsNearby uses DefaultRadiusKm if radiusKm is 0
+	FindConcertsNearby(lat float64, lon float64, radiusKm float64, page int, pageSize int) ([]*models.NearbyConcert, error)
+}
+
+type usecase struct {
+	concertRep repository.ConcertRepository
+}
+
+func NewConcertUseCase(concertRep repository.ConcertRepository) ConcertUseCase {
+	return &usecase{concertRep: concertRep}
+}
+
+func pageOffset(page int, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+
+	switch {
+	case pageSize > MaxPageSize:
+		pageSize = MaxPageSize
+	case pageSize < MinPageSize:
+		pageSize = MinPageSize
+	}
+
+	return (page - 1) * pageSize, pageSize
+}
+
+func (u *usecase) GetConcert(id uint64) (*models.Concert, error) {
+	res, err := u.concertRep.GetConcert(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "concert.usecase.GetConcert error while get")
+	}
+
+	return res, nil
+}
+
+func (u *usecase) GetUpcomingConcerts(musicianId uint64, page int, pageSize int) ([]*models.Concert, error) {
+	offset, limit := pageOffset(page, pageSize)
+
+	res, err := u.concertRep.GetUpcomingConcerts(musicianId, offset, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "concert.usecase.GetUpcomingConcerts error while get")
+	}
+
+	return res, nil
+}
+
+func (u *usecase) UpdateConcert(concert *models.Concert) error {
+	if err := validateConcert(concert); err != nil {
+		return err
+	}
+
+	if err := u.concertRep.UpdateConcert(concert); err != nil {
+		return errors.Wrap(err, "concert.usecase.UpdateConcert error while update")
+	}
+
+	return nil
+}
+
+func (u *usecase) AddConcert(concert *models.Concert, musicianId uint64) (uint64, error) {
+	if err := validateConcert(concert); err != nil {
+		return 0, err
+	}
+
+	id, err := u.concertRep.AddConcert(concert, musicianId)
+	if err != nil {
+		return 0, errors.Wrap(err, "concert.usecase.AddConcert error while add")
+	}
+
+	return id, nil
+}
+
+func (u *usecase) DeleteConcert(id uint64) error {
+	if err := u.concertRep.DeleteConcert(id); err != nil {
+		return errors.Wrap(err, "concert.usecase.DeleteConcert error while delete")
+	}
+
+	return nil
+}
+
+func (u *usecase) IsConcertOwned(concertId uint64, musicianId uint64) (bool, error) {
+	res, err := u.concertRep.IsConcertOwned(concertId, musicianId)
+	if err != nil {
+		return false, errors.Wrap(err, "concert.usecase.IsConcertOwned error while get")
+	}
+
+	return res, nil
+}
+
+func (u *usecase) FindConcertsNearby(lat float64, lon float64, radiusKm float64,
+	page int, pageSize int) ([]*models.NearbyConcert, error) {
+	if !validCoordinates(lat, lon) {
+		return nil, errors.Wrap(models.ErrInvalidParameter, "invalid coordinates")
+	}
+
+	if radiusKm == 0 {
+		radiusKm = DefaultRadiusKm
+	}
+	if !(radiusKm > 0 && radiusKm <= MaxRadiusKm) {
+		return nil, errors.Wrap(models.ErrInvalidParameter, "radius is out of range")
+	}
+
+	offset, limit := pageOffset(page, pageSize)
+
+	res, err := u.concertRep.FindConcertsNearby(lat, lon, radiusKm, offset, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "concert.usecase.FindConcertsNearby error while find")
+	}
+
+	return res, nil
+}
+
+func validCoordinates(lat float64, lon float64) bool {
+	// comparisons are false for NaN
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180 && !math.IsNaN(lat+lon)
+}
+
+func validateConcert(concert *models.Concert) error {
+	concert.Title = strings.TrimSpace(concert.Title)
+	concert.Venue = strings.TrimSpace(concert.Venue)
+	concert.City = strings.TrimSpace(concert.City)
+
+	if concert.Title == "" || concert.Venue == "" || concert.City == "" {
+		return errors.Wrap(models.ErrInvalidParameter, "title, venue and city are required")
+	}
+
+	if utf8.RuneCountInString(concert.Title) > MaxTitleLength ||
+		utf8.RuneCountInString(concert.Venue) > MaxTitleLength ||
+		utf8.RuneCountInString(concert.City) > MaxCityLength {
+		return errors.Wrap(models.ErrInvalidParameter, "title, venue or city is too long")
+	}
+
+	if !validCoordinates(concert.Latitude, concert.Longitude) {
+		return errors.Wrap(models.ErrInvalidParameter, "invalid coordinates")
+	}
+
+	if concert.StartsAt.IsZero() {
+		return errors.Wrap(models.ErrInvalidParameter, "start time is required")
+	}
+
+	// LoadLocation takes "" and "Local" for UTC and the server zone
+	if concert.TimeZone == "" || concert.TimeZone == "Local" {
+		return errors.Wrap(models.ErrInvalidParameter, "time zone is required")
+	}
+	loc, err := time.LoadLocation(concert.TimeZone)
+	if err != nil {
+		return errors.Wrap(models.ErrInvalidParameter, "unknown time zone "+concert.TimeZone)
+	}
+	concert.StartsAt = concert.StartsAt.In(loc)
+
+	if concert.TicketUrl != "" {
+		ticketUrl, err := url.Parse(concert.TicketUrl)
+		if err != nil || (ticketUrl.Scheme != "http" && ticketUrl.Scheme != "https") || ticketUrl.Host == "" ||
+			len(concert.TicketUrl) > MaxUrlLength {
+			return errors.Wrap(models.ErrInvalidParameter, "invalid ticket url")
+		}
+	}
+
+	return nil
+}
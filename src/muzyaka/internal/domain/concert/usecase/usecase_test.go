@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock_repository "src/internal/domain/concert/repository/mocks"
+	"src/internal/models"
+	"testing"
+	"time"
+)
+
+func testConcert() *models.Concert {
+	return &models.Concert{
+		Title:     " Summer Tour ",
+		Venue:     "Arena",
+		City:      "Berlin",
+		Latitude:  52.52,
+		Longitude: 13.405,
+		StartsAt:  time.Date(2030, 7, 1, 18, 0, 0, 0, time.UTC),
+		TimeZone:  "Europe/Berlin",
+		TicketUrl: "https://tickets.example.com/1",
+	}
+}
+
+func TestUsecase_AddConcert(t *testing.T) {
+	type mock func(r *mock_repository.MockConcertRepository)
+
+	testTable := []struct {
+		name        string
+		modify      func(c *models.Concert)
+		mock        mock
+		expectedId  uint64
+		expectedErr error
+	}{
+		{
+			name:   "Usual test",
+			modify: func(c *models.Concert) {},
+			mock: func(r *mock_repository.MockConcertRepository) {
+				r.EXPECT().AddConcert(gomock.Any(), uint64(1)).Return(uint64(5), nil)
+			},
+			expectedId: 5,
+		},
+		{
+			name:   "Without ticket url",
+			modify: func(c *models.Concert) { c.TicketUrl = "" },
+			mock: func(r *mock_repository.MockConcertRepository) {
+				r.EXPECT().AddConcert(gomock.Any(), uint64(1)).Return(uint64(5), nil)
+			},
+			expectedId: 5,
+		},
+		{
+			name:        "Empty title",
+			modify:      func(c *models.Concert) { c.Title = "  " },
+			mock:        func(r *mock_repository.MockConcertRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Invalid latitude",
+			modify:      func(c *models.Concert) { c.Latitude = 91 },
+			mock:        func(r *mock_repository.MockConcertRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "No start time",
+			modify:      func(c *models.Concert) { c.StartsAt = time.Time{} },
+			mock:        func(r *mock_repository.MockConcertRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Local time zone",
+			modify:      func(c *models.Concert) { c.TimeZone = "Local" },
+			mock:        func(r *mock_repository.MockConcertRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Unknown time zone",
+			modify:      func(c *models.Concert) { c.TimeZone = "Mars/Olympus" },
+			mock:        func(r *mock_repository.MockConcertRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Invalid ticket url",
+			modify:      func(c *models.Concert) { c.TicketUrl = "javascript:alert(1)" },
+			mock:        func(r *mock_repository.MockConcertRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:   "Repo fail test",
+			modify: func(c *models.Concert) {},
+			mock: func(r *mock_repository.MockConcertRepository) {
+				r.EXPECT().AddConcert(gomock.Any(), uint64(1)).Return(uint64(0), errors.New("error in repo"))
+			},
+			expectedErr: errors.New("error in repo"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init Dependencies
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockConcertRepository(ctrl)
+			tc.mock(repo)
+
+			concert := testConcert()
+			tc.modify(concert)
+
+			u := NewConcertUseCase(repo)
+			id, err := u.AddConcert(concert, 1)
+
+			assert.Equal(t, tc.expectedId, id)
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, "Summer Tour", concert.Title)
+				assert.Equal(t, "Europe/Berlin", concert.StartsAt.Location().String())
+				assert.Equal(t, 20, concert.StartsAt.Hour())
+			} else {
+				assert.ErrorContains(t, err, tc.expectedErr.Error())
+			}
+		})
+	}
+}
+
+func TestUsecase_UpdateConcert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_repository.NewMockConcertRepository(ctrl)
+	concert := testConcert()
+	concert.Id = 3
+	repo.EXPECT().UpdateConcert(concert).Return(models.ErrNotFound)
+
+	u := NewConcertUseCase(repo)
+	err := u.UpdateConcert(concert)
+
+	assert.True(t, errors.Is(err, models.ErrNotFound))
+}
+
+func TestUsecase_GetUpcomingConcerts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_repository.NewMockConcertRepository(ctrl)
+	concerts := []*models.Concert{testConcert()}
+	repo.EXPECT().GetUpcomingConcerts(uint64(1), 200, 100).Return(concerts, nil)
+
+	u := NewConcertUseCase(repo)
+	res, err := u.GetUpcomingConcerts(1, 3, 500)
+
+	assert.Nil(t, err)
+	assert.Equal(t, concerts, res)
+}
+
+func TestUsecase_FindConcertsNearby(t *testing.T) {
+	type mock func(r *mock_repository.MockConcertRepository)
+
+	testTable := []struct {
+		name        string
+		lat         float64
+		lon         float64
+		radiusKm    float64
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Default radius",
+			lat:  52.52,
+			lon:  13.405,
+			mock: func(r *mock_repository.MockConcertRepository) {
+				r.EXPECT().FindConcertsNearby(52.52, 13.405, float64(DefaultRadiusKm), 0, MinPageSize).
+					Return([]*models.NearbyConcert{}, nil)
+			},
+		},
+		{
+			name:     "Custom radius",
+			lat:      -33.87,
+			lon:      151.21,
+			radiusKm: 10,
+			mock: func(r *mock_repository.MockConcertRepository) {
+				r.EXPECT().FindConcertsNearby(-33.87, 151.21, float64(10), 0, MinPageSize).
+					Return([]*models.NearbyConcert{}, nil)
+			},
+		},
+		{
+			name:        "Radius too large",
+			radiusKm:    MaxRadiusKm + 1,
+			mock:        func(r *mock_repository.MockConcertRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Negative radius",
+			radiusKm:    -1,
+			mock:        func(r *mock_repository.MockConcertRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Invalid longitude",
+			lon:         181,
+			mock:        func(r *mock_repository.MockConcertRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name: "Repo fail test",
+			mock: func(r *mock_repository.MockConcertRepository) {
+				r.EXPECT().FindConcertsNearby(float64(0), float64(0), float64(DefaultRadiusKm), 0, MinPageSize).
+					Return(nil, errors.New("error in repo"))
+			},
+			expectedErr: errors.Wrap(errors.New("error in repo"), "concert.usecase.FindConcertsNearby error while find"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init Dependencies
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockConcertRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewConcertUseCase(repo)
+			_, err := u.FindConcertsNearby(tc.lat, tc.lon, tc.radiusKm, 1, 0)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedErr.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package delivery
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"net/http"
+	"src/internal/domain/feed/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/models"
+	"src/internal/models/dto"
+	"strconv"
+)
+
+// @Summary GetFeed
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get new albums, tracks and merch of followed musicians, newest first
+// @ID get-feed
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param        page    query     int  true  "number of page from 1"
+// @Param        page_size    query     int  true  "size of page, at most 100"
+// @Success 200 {object} dto.Feed
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/feed [get]
+func GetFeed(useCase usecase.FeedUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageStr := r.URL.Query().Get("page")
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageSizeStr := r.URL.Query().Get("page_size")
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		feed, err := useCase.GetFeed(userIDUint, page, pageSize)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoFeed(feed))
+	}
+}
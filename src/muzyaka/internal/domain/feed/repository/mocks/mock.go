@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFeedRepository is a mock of FeedRepository interface.
+type MockFeedRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedRepositoryMockRecorder
+}
+
+// MockFeedRepositoryMockRecorder is the mock recorder for MockFeedRepository.
+type MockFeedRepositoryMockRecorder struct {
+	mock *MockFeedRepository
+}
+
+// NewMockFeedRepository creates a new mock instance.
+func NewMockFeedRepository(ctrl *gomock.Controller) *MockFeedRepository {
+	mock := &MockFeedRepository{ctrl: ctrl}
+	mock.recorder = &MockFeedRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedRepository) EXPECT() *MockFeedRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddFeedItem mocks base method.
+func (m *MockFeedRepository) AddFeedItem(eventId string, item *models.FeedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFeedItem", eventId, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFeedItem indicates an expected call of AddFeedItem.
+func (mr *MockFeedRepositoryMockRecorder) AddFeedItem(eventId, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFeedItem", reflect.TypeOf((*MockFeedRepository)(nil).AddFeedItem), eventId, item)
+}
+
+// DeleteFeedItem mocks base method.
+func (m *MockFeedRepository) DeleteFeedItem(eventId, kind string, itemId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFeedItem", eventId, kind, itemId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFeedItem indicates an expected call of DeleteFeedItem.
+func (mr *MockFeedRepositoryMockRecorder) DeleteFeedItem(eventId, kind, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeedItem", reflect.TypeOf((*MockFeedRepository)(nil).DeleteFeedItem), eventId, kind, itemId)
+}
+
+// GetFeed mocks base method.
+func (m *MockFeedRepository) GetFeed(userId uint64, offset, limit int) ([]*models.FeedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", userId, offset, limit)
+	ret0, _ := ret[0].([]*models.FeedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockFeedRepositoryMockRecorder) GetFeed(userId, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockFeedRepository)(nil).GetFeed), userId, offset, limit)
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"src/internal/domain/feed/repository"
+	"src/internal/lib/inbox"
+	"src/internal/lib/kafka"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"time"
+)
+
+type feedRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedRepository(db *gorm.DB) repository.FeedRepository {
+	return &feedRepository{db: db}
+}
+
+func (f *feedRepository) AddFeedItem(eventId string, item *models.FeedItem) error {
+	err := f.db.Transaction(func(tx *gorm.DB) error {
+		if err := inbox.MarkProcessed(tx, kafka.FeedGroup, eventId); err != nil {
+			return err
+		}
+
+		pgItem := dao.ToPostgresFeedItem(item)
+
+		if pgItem.MusicianId == 0 && pgItem.AlbumId != nil {
+			var album dao.Album
+			err := tx.Where("id = ?", *pgItem.AlbumId).Take(&album).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			pgItem.MusicianId = album.MusicianID
+		}
+
+		var musicians int64
+		if err := tx.Model(&dao.Musician{}).Where("id = ?", pgItem.MusicianId).Count(&musicians).Error; err != nil {
+			return err
+		}
+		if musicians == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(pgItem).Error
+	})
+
+	if errors.Is(err, models.ErrAlreadyProcessed) {
+		return err
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table feed_items)")
+	}
+
+	return nil
+}
+
+func (f *feedRepository) DeleteFeedItem(eventId string, kind string, itemId uint64) error {
+	err := f.db.Transaction(func(tx *gorm.DB) error {
+		if err := inbox.MarkProcessed(tx, kafka.FeedGroup, eventId); err != nil {
+			return err
+		}
+
+		if kind == models.FeedItemAlbum {
+			if err := tx.Where("kind = ? AND album_id = ?", models.FeedItemTrack, itemId).
+				Delete(&dao.FeedItem{}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("kind = ? AND item_id = ?", kind, itemId).Delete(&dao.FeedItem{}).Error
+	})
+
+	if errors.Is(err, models.ErrAlreadyProcessed) {
+		return err
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table feed_items)")
+	}
+
+	return nil
+}
+
+type feedRow struct {
+	Kind         string
+	ItemId       uint64
+	MusicianId   uint64
+	MusicianName string
+	AlbumId      *uint64
+	Name         string
+	PublishedAt  time.Time
+}
+
+// tracks published together with their album are shown as the album
+const feedQuery = `
+	SELECT f.kind, f.item_id, f.musician_id, mu.name AS musician_name, f.album_id,
+	       COALESCE(a.name, t.name, m.name) AS name, f.published_at
+	FROM feed_items f
+	JOIN musician_followers mf ON mf.musician_id = f.musician_id AND mf.user_id = @user
+	JOIN musicians mu ON mu.id = f.musician_id
+	LEFT JOIN albums a ON f.kind = 'album' AND a.id = f.item_id
+	LEFT JOIN tracks t ON f.kind = 'track' AND t.id = f.item_id
+	LEFT JOIN merch m ON f.kind = 'merch' AND m.id = f.item_id
+	WHERE COALESCE(a.id, t.id, m.id) IS NOT NULL
+	  AND NOT EXISTS (SELECT 1
+	                  FROM feed_items r
+	                  WHERE f.kind = 'track'
+	                    AND r.kind = 'album'
+	                    AND r.item_id = f.album_id
+	                    AND r.published_at BETWEEN f.published_at - interval '1 minute'
+	                                           AND f.published_at + interval '1 minute')
+	ORDER BY f.published_at DESC, f.id DESC
+	OFFSET @offset LIMIT @limit`
+
+func (f *feedRepository) GetFeed(userId uint64, offset int, limit int) ([]*models.FeedItem, error) {
+	var rows []*feedRow
+	tx := f.db.Raw(feedQuery, map[string]interface{}{
+		"user":   userId,
+		"offset": offset,
+		"limit":  limit,
+	}).Scan(&rows)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table feed_items)")
+	}
+
+	res := make([]*models.FeedItem, 0, len(rows))
+	for _, v := range rows {
+		item := &models.FeedItem{
+			Kind:         v.Kind,
+			ItemId:       v.ItemId,
+			MusicianId:   v.MusicianId,
+			MusicianName: v.MusicianName,
+			Name:         v.Name,
+			PublishedAt:  v.PublishedAt,
+		}
+		if v.AlbumId != nil {
+			item.AlbumId = *v.AlbumId
+		}
+		res = append(res, item)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "src/internal/models"
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+// FeedRepository stores the feed projection. Every event is applied at most
+// once, repeated calls with the same event id return models.ErrAlreadyProcessed.
+type FeedRepository interface {
+	// AddFeedItem resolves MusicianId of tracks from their album,
+	// items of already deleted albums are skipped
+	AddFeedItem(eventId string, item *models.FeedItem) error
+	// DeleteFeedItem removes tracks together with their album
+	DeleteFeedItem(eventId string, kind string, itemId uint64) error
+
+	GetFeed(userId uint64, offset int, limit int) ([]*models.FeedItem, error)
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"github.com/pkg/errors"
+	"src/internal/domain/feed/repository"
+	"src/internal/models"
+)
+
+const MaxPageSize = 100
+
+type FeedUseCase interface {
+	// GetFeed returns releases of followed musicians, newest first
+	GetFeed(userId uint64, page int, pageSize int) ([]*models.FeedItem, error)
+}
+
+type usecase struct {
+	feedRep repository.FeedRepository
+}
+
+func NewFeedUseCase(feedRep repository.FeedRepository) FeedUseCase {
+	return &usecase{feedRep: feedRep}
+}
+
+func (u *usecase) GetFeed(userId uint64, page int, pageSize int) ([]*models.FeedItem, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, models.ErrInvalidParameter
+	}
+
+	pageSize = min(pageSize, MaxPageSize)
+
+	items, err := u.feedRep.GetFeed(userId, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "feed.usecase.GetFeed error while feedRep call")
+	}
+
+	return items, nil
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock_repository "src/internal/domain/feed/repository/mocks"
+	"src/internal/models"
+	"testing"
+)
+
+func TestFeedUseCase_GetFeed(t *testing.T) {
+	items := []*models.FeedItem{
+		{Kind: models.FeedItemAlbum, ItemId: 1, MusicianId: 2, Name: "album"},
+		{Kind: models.FeedItemMerch, ItemId: 3, MusicianId: 2, Name: "merch"},
+	}
+	dbErr := errors.New("db error")
+
+	type mock func(r *mock_repository.MockFeedRepository)
+
+	testTable := []struct {
+		name        string
+		page        int
+		pageSize    int
+		mock        mock
+		expected    []*models.FeedItem
+		expectedErr error
+	}{
+		{
+			name:     "Usual test",
+			page:     2,
+			pageSize: 10,
+			mock: func(r *mock_repository.MockFeedRepository) {
+				r.EXPECT().GetFeed(uint64(1), 10, 10).Return(items, nil)
+			},
+			expected:    items,
+			expectedErr: nil,
+		},
+		{
+			name:     "Page size limit test",
+			page:     1,
+			pageSize: 1000,
+			mock: func(r *mock_repository.MockFeedRepository) {
+				r.EXPECT().GetFeed(uint64(1), 0, MaxPageSize).Return(items, nil)
+			},
+			expected:    items,
+			expectedErr: nil,
+		},
+		{
+			name:     "Invalid page test",
+			page:     0,
+			pageSize: 10,
+			mock: func(r *mock_repository.MockFeedRepository) {
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:     "Repo fail test",
+			page:     1,
+			pageSize: 10,
+			mock: func(r *mock_repository.MockFeedRepository) {
+				r.EXPECT().GetFeed(uint64(1), 0, 10).Return(nil, dbErr)
+			},
+			expected:    nil,
+			expectedErr: errors.Wrap(dbErr, "feed.usecase.GetFeed error while feedRep call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockFeedRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewFeedUseCase(repo)
+			feed, err := u.GetFeed(1, tc.page, tc.pageSize)
+
+			assert.Equal(t, tc.expected, feed)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
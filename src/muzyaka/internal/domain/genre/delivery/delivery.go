@@ -0,0 +1,193 @@
+package delivery
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"net/http"
+	"src/internal/domain/genre/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/models"
+	"src/internal/models/dto"
+	"strconv"
+)
+
+// errorStatus maps usecase errors to response codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidParameter):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrNotFound), errors.Is(err, models.ErrNothingToDelete):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrGenreExists):
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
+
+// @Summary GetGenresTree
+// @Security ApiKeyAuth
+// @Tags genre
+// @Description get all genres with subgenres nested into their parents
+// @ID get-genres-tree
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} dto.GenresTree
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/genres [get]
+func GetGenresTree(useCase usecase.GenreUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		genres, err := useCase.GetGenres()
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoGenresTree(genres))
+	}
+}
+
+// @Summary GetGenre
+// @Security ApiKeyAuth
+// @Tags genre
+// @Description get genre by ID
+// @ID get-genre
+// @Accept  json
+// @Produce  json
+// @Param id path int true "genre ID"
+// @Success 200 {object} dto.Genre
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/genres/{id} [get]
+func GetGenre(useCase usecase.GenreUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		genreID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		genre, err := useCase.GetGenre(genreID)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoGenre(genre))
+	}
+}
+
+// @Summary AddGenre
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description add genre, parent_id makes it a subgenre
+// @ID add-genre
+// @Accept  json
+// @Produce  json
+// @Param input body dto.GenreWithoutId true "genre info"
+// @Success 200 {object} dto.CreateGenreResponse
+// @Failure 400,409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/genres [post]
+func AddGenre(useCase usecase.GenreUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.GenreWithoutId
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		id, err := useCase.AddGenre(dto.ToModelGenreWithoutId(&req, 0))
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.CreateGenreResponse{Id: id})
+	}
+}
+
+// @Summary UpdateGenre
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description rename genre or move it under another parent
+// @ID update-genre
+// @Accept  json
+// @Produce  json
+// @Param id path int true "genre ID"
+// @Param input body dto.GenreWithoutId true "genre info"
+// @Success 200 {object} response.Response
+// @Failure 400,404,409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/genres/{id} [put]
+func UpdateGenre(useCase usecase.GenreUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		genreID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.GenreWithoutId
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.UpdateGenre(dto.ToModelGenreWithoutId(&req, genreID))
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary DeleteGenre
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description delete genre without subgenres, tracks and albums lose it
+// @ID delete-genre
+// @Accept  json
+// @Produce  json
+// @Param id path int true "genre ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/genres/{id} [delete]
+func DeleteGenre(useCase usecase.GenreUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		genreID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.DeleteGenre(genreID)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
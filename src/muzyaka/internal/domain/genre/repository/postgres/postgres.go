@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/domain/genre/repository"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"src/internal/models/events"
+)
+
+type genreRepository struct {
+	db *gorm.DB
+}
+
+func NewGenreRepository(db *gorm.DB) repository.GenreRepository {
+	return &genreRepository{db: db}
+}
+
+func (g *genreRepository) GetGenres() ([]*models.Genre, error) {
+	var genres []*dao.Genre
+
+	tx := g.db.Order("name").Find(&genres)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table genres)")
+	}
+
+	res := make([]*models.Genre, 0, len(genres))
+	for _, v := range genres {
+		res = append(res, dao.ToModelGenre(v))
+	}
+
+	return res, nil
+}
+
+func (g *genreRepository) GetGenre(id uint64) (*models.Genre, error) {
+	var genre dao.Genre
+
+	tx := g.db.Where("id = ?", id).Take(&genre)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, errors.Wrap(models.ErrNotFound, "database error (table genres)")
+	} else if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table genres)")
+	}
+
+	return dao.ToModelGenre(&genre), nil
+}
+
+func (g *genreRepository) AddGenre(genre *models.Genre, validate func(genres []*models.Genre) error) (uint64, error) {
+	var pgGenre *dao.Genre
+	var validateErr error
+
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		genres, err := lockGenres(tx)
+		if err != nil {
+			return err
+		}
+
+		if validateErr = validate(genres); validateErr != nil {
+			return validateErr
+		}
+
+		pgGenre = dao.ToPostgresGenre(genre)
+		return tx.Omit("id").Create(pgGenre).Error
+	})
+
+	if validateErr != nil {
+		return 0, validateErr
+	} else if err != nil {
+		return 0, errors.Wrap(err, "database error (table genres)")
+	}
+
+	return *pgGenre.ID, nil
+}
+
+func (g *genreRepository) UpdateGenre(genre *models.Genre, validate func(genres []*models.Genre) error) error {
+	var validateErr error
+
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		genres, err := lockGenres(tx)
+		if err != nil {
+			return err
+		}
+
+		if validateErr = validate(genres); validateErr != nil {
+			return validateErr
+		}
+
+		pgGenre := dao.ToPostgresGenre(genre)
+		res := tx.Model(&dao.Genre{}).
+			Where("id = ?", genre.Id).
+			Updates(map[string]interface{}{"name": pgGenre.Name, "parent_id": pgGenre.ParentID})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNotFound
+		}
+
+		return nil
+	})
+
+	if validateErr != nil {
+		return validateErr
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table genres)")
+	}
+
+	return nil
+}
+
+// lockGenres reads all genres blocking other writes of genres until the
+// transaction ends, reads are not blocked
+func lockGenres(tx *gorm.DB) ([]*models.Genre, error) {
+	if err := tx.Exec("LOCK TABLE genres IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+		return nil, err
+	}
+
+	var genres []*dao.Genre
+	if err := tx.Find(&genres).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]*models.Genre, 0, len(genres))
+	for _, v := range genres {
+		res = append(res, dao.ToModelGenre(v))
+	}
+
+	return res, nil
+}
+
+func (g *genreRepository) DeleteGenre(id uint64) error {
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		var trackIds []uint64
+		if err := tx.Model(&dao.TrackMeta{}).Where("genre = ?", id).Pluck("id", &trackIds).Error; err != nil {
+			return err
+		}
+
+		res := tx.Delete(&dao.Genre{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNothingToDelete
+		}
+
+		if len(trackIds) == 0 {
+			return nil
+		}
+
+		// the genre is cleared from tracks by the foreign key
+		err := tx.Exec(`
+			UPDATE tracks t
+			SET genre = (SELECT tg.genre_id
+			             FROM track_genres tg
+			                      JOIN genres g ON g.id = tg.genre_id
+			             WHERE tg.track_id = t.id
+			             ORDER BY g.name
+			             LIMIT 1)
+			WHERE t.id IN ?`, trackIds).Error
+		if err != nil {
+			return err
+		}
+
+		var tracks []*dao.TrackMeta
+		if err := tx.Where("id IN ?", trackIds).Find(&tracks).Error; err != nil {
+			return err
+		}
+
+		for _, v := range tracks {
+			outbox, err := dao.NewOutbox(events.TrackUpdated, v.ID, dao.ToTrackPayload(v))
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Create(outbox).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, models.ErrNothingToDelete) {
+		return err
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table genres)")
+	}
+
+	return nil
+}
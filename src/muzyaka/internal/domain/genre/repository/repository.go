@@ -0,0 +1,18 @@
+package repository
+
+import "src/internal/models"
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+type GenreRepository interface {
+	// GetGenres returns all genres ordered by name
+	GetGenres() ([]*models.Genre, error)
+	GetGenre(id uint64) (*models.Genre, error)
+	// AddGenre and UpdateGenre call validate with all genres and write the genre
+	// in one transaction, other writes of genres wait for it. Errors of
+	// validate are returned as is.
+	AddGenre(genre *models.Genre, validate func(genres []*models.Genre) error) (uint64, error)
+	UpdateGenre(genre *models.Genre, validate func(genres []*models.Genre) error) error
+	// DeleteGenre picks another primary genre for tracks that had this one
+	DeleteGenre(id uint64) error
+}
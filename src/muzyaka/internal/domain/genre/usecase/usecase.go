@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"github.com/pkg/errors"
+	"src/internal/domain/genre/repository"
+	"src/internal/models"
+	"strings"
+	"unicode/utf8"
+)
+
+type GenreUseCase interface {
+	GetGenres() ([]*models.Genre, error)
+	GetGenre(id uint64) (*models.Genre, error)
+	// AddGenre and UpdateGenre keep genre names unique ignoring case
+	AddGenre(genre *models.Genre) (uint64, error)
+	// UpdateGenre can move the genre under another parent except its own subgenres
+	UpdateGenre(genre *models.Genre) error
+	// DeleteGenre refuses to delete genres with subgenres
+	DeleteGenre(id uint64) error
+}
+
+type usecase struct {
+	genreRep repository.GenreRepository
+}
+
+func NewGenreUseCase(genreRep repository.GenreRepository) GenreUseCase {
+	return &usecase{genreRep: genreRep}
+}
+
+func (u *usecase) GetGenres() ([]*models.Genre, error) {
+	genres, err := u.genreRep.GetGenres()
+	if err != nil {
+		return nil, errors.Wrap(err, "genre.usecase.GetGenres error while get")
+	}
+
+	return genres, nil
+}
+
+func (u *usecase) GetGenre(id uint64) (*models.Genre, error) {
+	genre, err := u.genreRep.GetGenre(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "genre.usecase.GetGenre error while get")
+	}
+
+	return genre, nil
+}
+
+func (u *usecase) AddGenre(genre *models.Genre) (uint64, error) {
+	genre.Id = 0
+	if err := normalizeGenre(genre); err != nil {
+		return 0, err
+	}
+
+	id, err := u.genreRep.AddGenre(genre, func(genres []*models.Genre) error {
+		return validateGenre(genre, genres)
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "genre.usecase.AddGenre error while add")
+	}
+
+	return id, nil
+}
+
+func (u *usecase) UpdateGenre(genre *models.Genre) error {
+	if err := normalizeGenre(genre); err != nil {
+		return err
+	}
+
+	err := u.genreRep.UpdateGenre(genre, func(genres []*models.Genre) error {
+		if findGenre(genres, genre.Id) == nil {
+			return models.ErrNotFound
+		}
+
+		return validateGenre(genre, genres)
+	})
+	if err != nil {
+		return errors.Wrap(err, "genre.usecase.UpdateGenre error while update")
+	}
+
+	return nil
+}
+
+func (u *usecase) DeleteGenre(id uint64) error {
+	genres, err := u.genreRep.GetGenres()
+	if err != nil {
+		return errors.Wrap(err, "genre.usecase.DeleteGenre error while get")
+	}
+
+	for _, v := range genres {
+		if v.ParentId == id {
+			return errors.Wrap(models.ErrInvalidParameter, "genre has subgenres")
+		}
+	}
+
+	if err := u.genreRep.DeleteGenre(id); err != nil {
+		return errors.Wrap(err, "genre.usecase.DeleteGenre error while delete")
+	}
+
+	return nil
+}
+
+func findGenre(genres []*models.Genre, id uint64) *models.Genre {
+	for _, v := range genres {
+		if v.Id == id {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// normalizeGenre trims and checks the genre name
+func normalizeGenre(genre *models.Genre) error {
+	genre.Name = strings.TrimSpace(genre.Name)
+	if genre.Name == "" || utf8.RuneCountInString(genre.Name) > models.MaxGenreNameLength {
+		return errors.Wrap(models.ErrInvalidParameter, "invalid genre name")
+	}
+
+	return nil
+}
+
+// validateGenre checks the genre against all existing genres
+func validateGenre(genre *models.Genre, genres []*models.Genre) error {
+	for _, v := range genres {
+		if v.Id != genre.Id && strings.EqualFold(v.Name, genre.Name) {
+			return models.ErrGenreExists
+		}
+	}
+
+	// walking up from the new parent must not reach the genre itself
+	visited := make(map[uint64]bool)
+	for parent := genre.ParentId; parent != 0; {
+		if parent == genre.Id || visited[parent] {
+			return errors.Wrap(models.ErrInvalidParameter, "genre can't be a subgenre of itself")
+		}
+		visited[parent] = true
+
+		v := findGenre(genres, parent)
+		if v == nil {
+			return errors.Wrap(models.ErrInvalidParameter, "unknown parent genre")
+		}
+		parent = v.ParentId
+	}
+
+	return nil
+}
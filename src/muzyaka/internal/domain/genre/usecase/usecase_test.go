@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock_repository "src/internal/domain/genre/repository/mocks"
+	"src/internal/models"
+	"testing"
+)
+
+// testGenres is Metal > Doom Metal > Funeral Doom and Pop
+func testGenres() []*models.Genre {
+	return []*models.Genre{
+		{Id: 1, Name: "Metal"},
+		{Id: 2, Name: "Doom Metal", ParentId: 1},
+		{Id: 3, Name: "Funeral Doom", ParentId: 2},
+		{Id: 4, Name: "Pop"},
+	}
+}
+
+// addValidated validates the genre against testGenres like the repository does
+func addValidated(id uint64, err error) func(*models.Genre, func([]*models.Genre) error) (uint64, error) {
+	return func(_ *models.Genre, validate func([]*models.Genre) error) (uint64, error) {
+		if validateErr := validate(testGenres()); validateErr != nil {
+			return 0, validateErr
+		}
+
+		return id, err
+	}
+}
+
+// updateValidated validates the genre against testGenres like the repository does
+func updateValidated(err error) func(*models.Genre, func([]*models.Genre) error) error {
+	return func(_ *models.Genre, validate func([]*models.Genre) error) error {
+		if validateErr := validate(testGenres()); validateErr != nil {
+			return validateErr
+		}
+
+		return err
+	}
+}
+
+func TestUsecase_AddGenre(t *testing.T) {
+	type mock func(r *mock_repository.MockGenreRepository)
+
+	testTable := []struct {
+		name        string
+		genre       *models.Genre
+		mock        mock
+		expectedId  uint64
+		expectedErr error
+	}{
+		{
+			name:  "Usual test",
+			genre: &models.Genre{Name: " Sludge Metal ", ParentId: 1},
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().AddGenre(&models.Genre{Name: "Sludge Metal", ParentId: 1}, gomock.Any()).
+					DoAndReturn(addValidated(5, nil))
+			},
+			expectedId: 5,
+		},
+		{
+			name:  "Duplicate name",
+			genre: &models.Genre{Name: "doom metal"},
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().AddGenre(gomock.Any(), gomock.Any()).DoAndReturn(addValidated(5, nil))
+			},
+			expectedErr: models.ErrGenreExists,
+		},
+		{
+			name:  "Unknown parent",
+			genre: &models.Genre{Name: "Jazz", ParentId: 10},
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().AddGenre(gomock.Any(), gomock.Any()).DoAndReturn(addValidated(5, nil))
+			},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Empty name",
+			genre:       &models.Genre{Name: "  "},
+			mock:        func(r *mock_repository.MockGenreRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:  "Repo fail test",
+			genre: &models.Genre{Name: "Jazz"},
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().AddGenre(gomock.Any(), gomock.Any()).DoAndReturn(addValidated(0, errors.New("error in repo")))
+			},
+			expectedErr: errors.New("error in repo"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init Dependencies
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockGenreRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewGenreUseCase(repo)
+			id, err := u.AddGenre(tc.genre)
+
+			assert.Equal(t, tc.expectedId, id)
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedErr.Error())
+			}
+		})
+	}
+}
+
+func TestUsecase_UpdateGenre(t *testing.T) {
+	type mock func(r *mock_repository.MockGenreRepository)
+
+	testTable := []struct {
+		name        string
+		genre       *models.Genre
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name:  "Move under another parent",
+			genre: &models.Genre{Id: 3, Name: "Funeral Doom", ParentId: 1},
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().UpdateGenre(&models.Genre{Id: 3, Name: "Funeral Doom", ParentId: 1}, gomock.Any()).
+					DoAndReturn(updateValidated(nil))
+			},
+		},
+		{
+			name:  "Rename keeping the name case",
+			genre: &models.Genre{Id: 4, Name: "POP"},
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().UpdateGenre(&models.Genre{Id: 4, Name: "POP"}, gomock.Any()).DoAndReturn(updateValidated(nil))
+			},
+		},
+		{
+			name:  "Parent is itself",
+			genre: &models.Genre{Id: 1, Name: "Metal", ParentId: 1},
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().UpdateGenre(gomock.Any(), gomock.Any()).DoAndReturn(updateValidated(nil))
+			},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:  "Parent is a subgenre",
+			genre: &models.Genre{Id: 1, Name: "Metal", ParentId: 3},
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().UpdateGenre(gomock.Any(), gomock.Any()).DoAndReturn(updateValidated(nil))
+			},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:  "Unknown genre",
+			genre: &models.Genre{Id: 10, Name: "Jazz"},
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().UpdateGenre(gomock.Any(), gomock.Any()).DoAndReturn(updateValidated(nil))
+			},
+			expectedErr: models.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init Dependencies
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockGenreRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewGenreUseCase(repo)
+			err := u.UpdateGenre(tc.genre)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			}
+		})
+	}
+}
+
+func TestUsecase_DeleteGenre(t *testing.T) {
+	type mock func(r *mock_repository.MockGenreRepository)
+
+	testTable := []struct {
+		name        string
+		id          uint64
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			id:   3,
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().GetGenres().Return(testGenres(), nil)
+				r.EXPECT().DeleteGenre(uint64(3)).Return(nil)
+			},
+		},
+		{
+			name: "Genre with subgenres",
+			id:   2,
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().GetGenres().Return(testGenres(), nil)
+			},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name: "Nothing to delete",
+			id:   10,
+			mock: func(r *mock_repository.MockGenreRepository) {
+				r.EXPECT().GetGenres().Return(testGenres(), nil)
+				r.EXPECT().DeleteGenre(uint64(10)).Return(models.ErrNothingToDelete)
+			},
+			expectedErr: models.ErrNothingToDelete,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init Dependencies
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockGenreRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewGenreUseCase(repo)
+			err := u.DeleteGenre(tc.id)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package delivery
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"net/http"
+	"src/internal/domain/auth/middleware"
+	"src/internal/domain/history/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/models"
+	"src/internal/models/dto"
+	"strconv"
+)
+
+// @Summary RecordPlay
+// @Security ApiKeyAuth
+// @Tags track
+// @Description record that the current user played the track
+// @ID record-play
+// @Accept  json
+// @Produce  json
+// @Param id path int true "track ID"
+// @Param input body dto.Play true "play info"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500,503 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/track/{id}/plays [post]
+func RecordPlay(useCase usecase.HistoryUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		trackID := chi.URLParam(r, "id")
+		trackIDUint, err := strconv.ParseUint(trackID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.Play
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		userInfo, isOk := r.Context().Value(middleware.ValuesFromContext).(middleware.ContextValues)
+		if !isOk {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidContext.Error()))
+			return
+		}
+
+		err = useCase.RecordPlay(dto.ToModelPlay(&req, userInfo.Id, trackIDUint))
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrOverloaded) {
+			render.Status(r, http.StatusServiceUnavailable)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary GetHistory
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get listening history, newest plays first
+// @ID get-history
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param        page    query     int  true  "number of page from 1"
+// @Param        page_size    query     int  true  "size of page, at most 100"
+// @Success 200 {object} dto.History
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/history [get]
+func GetHistory(useCase usecase.HistoryUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageStr := r.URL.Query().Get("page")
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageSizeStr := r.URL.Query().Get("page_size")
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		history, err := useCase.GetHistory(userIDUint, page, pageSize)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoHistory(history))
+	}
+}
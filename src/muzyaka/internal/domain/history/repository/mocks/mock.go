@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockHistoryRepository is a mock of HistoryRepository interface.
+type MockHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRepositoryMockRecorder
+}
+
+// MockHistoryRepositoryMockRecorder is the mock recorder for MockHistoryRepository.
+type MockHistoryRepositoryMockRecorder struct {
+	mock *MockHistoryRepository
+}
+
+// NewMockHistoryRepository creates a new mock instance.
+func NewMockHistoryRepository(ctrl *gomock.Controller) *MockHistoryRepository {
+	mock := &MockHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRepository) EXPECT() *MockHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddPlays mocks base method.
+func (m *MockHistoryRepository) AddPlays(plays []*models.Play) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPlays", plays)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPlays indicates an expected call of AddPlays.
+func (mr *MockHistoryRepositoryMockRecorder) AddPlays(plays interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPlays", reflect.TypeOf((*MockHistoryRepository)(nil).AddPlays), plays)
+}
+
+// GetHistory mocks base method.
+func (m *MockHistoryRepository) GetHistory(userId uint64, offset, limit int) ([]*models.Play, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", userId, offset, limit)
+	ret0, _ := ret[0].([]*models.Play)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockHistoryRepositoryMockRecorder) GetHistory(userId, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockHistoryRepository)(nil).GetHistory), userId, offset, limit)
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/domain/history/repository"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"src/internal/models/events"
+)
+
+const insertBatchSize = 500
+
+type historyRepository struct {
+	db *gorm.DB
+}
+
+func NewHistoryRepository(db *gorm.DB) repository.HistoryRepository {
+	return &historyRepository{db: db}
+}
+
+func (h historyRepository) AddPlays(plays []*models.Play) error {
+	if len(plays) == 0 {
+		return nil
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		// users and tracks may be deleted while plays wait in the buffer
+		var trackIds, userIds []uint64
+		for _, v := range plays {
+			trackIds = append(trackIds, v.TrackId)
+			userIds = append(userIds, v.UserId)
+		}
+
+		tracks, err := existingIds(tx, &dao.TrackMeta{}, trackIds)
+		if err != nil {
+			return err
+		}
+
+		users, err := existingIds(tx, &dao.User{}, userIds)
+		if err != nil {
+			return err
+		}
+
+		pgPlays := make([]*dao.Play, 0, len(plays))
+		for _, v := range plays {
+			if tracks[v.TrackId] && users[v.UserId] {
+				pgPlays = append(pgPlays, dao.ToPostgresPlay(v))
+			}
+		}
+
+		if len(pgPlays) == 0 {
+			return nil
+		}
+
+		if err := tx.CreateInBatches(pgPlays, insertBatchSize).Error; err != nil {
+			return err
+		}
+
+		outbox := make([]*dao.Outbox, 0, len(pgPlays))
+		for _, v := range pgPlays {
+			event, err := dao.NewOutbox(events.TrackPlayed, v.UserId, dao.ToPlayPayload(v))
+			if err != nil {
+				return err
+			}
+			outbox = append(outbox, event)
+		}
+
+		return tx.CreateInBatches(outbox, insertBatchSize).Error
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table plays)")
+	}
+
+	return nil
+}
+
+func (h historyRepository) GetHistory(userId uint64, offset int, limit int) ([]*models.Play, error) {
+	var plays []*dao.Play
+	tx := h.db.Where("user_id = ?", userId).
+		Order("played_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&plays)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table plays)")
+	}
+
+	res := make([]*models.Play, 0, len(plays))
+	for _, v := range plays {
+		res = append(res, dao.ToModelPlay(v))
+	}
+
+	return res, nil
+}
+
+func existingIds(tx *gorm.DB, model interface{}, ids []uint64) (map[uint64]bool, error) {
+	var found []uint64
+	if err := tx.Model(model).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, err
+	}
+
+	existing := make(map[uint64]bool, len(found))
+	for _, v := range found {
+		existing[v] = true
+	}
+
+	return existing, nil
+}
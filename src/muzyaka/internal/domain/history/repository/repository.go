@@ -0,0 +1,11 @@
+package repository
+
+import "src/internal/models"
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+type HistoryRepository interface {
+	// AddPlays stores plays in batches, plays of deleted users or tracks are skipped
+	AddPlays(plays []*models.Play) error
+	GetHistory(userId uint64, offset int, limit int) ([]*models.Play, error)
+}
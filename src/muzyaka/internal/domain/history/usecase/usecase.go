@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log/slog"
+	"src/internal/config"
+	"src/internal/domain/history/repository"
+	repository2 "src/internal/domain/track/repository"
+	"src/internal/models"
+	"sync"
+	"time"
+)
+
+const (
+	MaxPageSize = 100
+
+	// plays reported by clients with a clock slightly ahead are accepted
+	maxClockSkew = time.Minute
+	// maxDuration keeps durations in milliseconds within int4 of plays
+	maxDuration = 24 * time.Hour
+)
+
+type HistoryUseCase interface {
+	RecordPlay(play *models.Play) error
+	GetHistory(userId uint64, page int, pageSize int) ([]*models.HistoryEntry, error)
+}
+
+// HistoryRecorder buffers plays in memory and writes them in batches,
+// so a play is visible in the history after up to cfg.FlushInterval.
+type HistoryRecorder struct {
+	historyRep repository.HistoryRepository
+	trackRep   repository2.TrackRepository
+	cfg        config.History
+	now        func() time.Time
+
+	mu     sync.Mutex
+	buffer []*models.Play
+	// attempts is the number of failed writes of the first batch of buffer
+	attempts int
+	full     chan struct{}
+}
+
+func NewHistoryUseCase(historyRep repository.HistoryRepository,
+	trackRep repository2.TrackRepository,
+	cfg config.History) *HistoryRecorder {
+	return &HistoryRecorder{
+		historyRep: historyRep,
+		trackRep:   trackRep,
+		cfg:        cfg,
+		now:        time.Now,
+		full:       make(chan struct{}, 1),
+	}
+}
+
+// Run flushes the buffer every cfg.FlushInterval or as soon as a batch is full.
+// The rest of the buffer is flushed when ctx is cancelled.
+func (h *HistoryRecorder) Run(ctx context.Context, logger *slog.Logger) {
+	ticker := time.NewTicker(h.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := h.Flush(); err != nil {
+				logger.Error("history flush failed", slog.String("error", err.Error()))
+			}
+			logger.Info("history recorder stopped")
+			return
+		case <-ticker.C:
+		case <-h.full:
+		}
+
+		if err := h.Flush(); err != nil {
+			logger.Error("history flush failed", slog.String("error", err.Error()))
+		}
+	}
+}
+
+func (h *HistoryRecorder) RecordPlay(play *models.Play) error {
+	now := h.now()
+
+	if play.PlayedAt.IsZero() {
+		play.PlayedAt = now
+	}
+
+	if play.Duration < 0 || play.Duration > maxDuration || play.PlayedAt.After(now.Add(maxClockSkew)) {
+		return models.ErrInvalidParameter
+	}
+
+	_, err := h.trackRep.GetTrack(play.TrackId)
+	if err != nil {
+		return errors.Wrap(err, "history.usecase.RecordPlay error while trackRep call")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.buffer) >= h.cfg.MaxBuffered {
+		return models.ErrOverloaded
+	}
+
+	h.buffer = append(h.buffer, play)
+
+	if len(h.buffer) >= h.cfg.BatchSize {
+		select {
+		case h.full <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Flush writes buffered plays. A batch failed to write is returned to the
+// buffer and retried with the next flush, up to cfg.MaxAttempts times. Then
+// its plays are written one by one and the ones that fail are dropped, so a
+// bad play can't keep the buffer full.
+func (h *HistoryRecorder) Flush() error {
+	h.mu.Lock()
+	plays := h.buffer
+	h.buffer = nil
+	h.mu.Unlock()
+
+	var res error
+	for len(plays) > 0 {
+		n := min(len(plays), h.cfg.BatchSize)
+
+		err := h.historyRep.AddPlays(plays[:n])
+		if err != nil {
+			h.mu.Lock()
+			h.attempts++
+			if h.attempts < h.cfg.MaxAttempts {
+				h.buffer = append(plays, h.buffer...)
+				h.mu.Unlock()
+
+				return errors.Wrap(err, "history.usecase.Flush error while historyRep call")
+			}
+			h.mu.Unlock()
+
+			if dropped := h.addEach(plays[:n]); dropped > 0 {
+				res = errors.Wrapf(err, "history.usecase.Flush dropped %d plays", dropped)
+			}
+		}
+
+		h.mu.Lock()
+		h.attempts = 0
+		h.mu.Unlock()
+
+		plays = plays[n:]
+	}
+
+	return res
+}
+
+// addEach writes plays one by one and returns how many failed
+func (h *HistoryRecorder) addEach(plays []*models.Play) int {
+	var dropped int
+	for _, v := range plays {
+		if err := h.historyRep.AddPlays([]*models.Play{v}); err != nil {
+			dropped++
+		}
+	}
+
+	return dropped
+}
+
+func (h *HistoryRecorder) GetHistory(userId uint64, page int, pageSize int) ([]*models.HistoryEntry, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, models.ErrInvalidParameter
+	}
+
+	pageSize = min(pageSize, MaxPageSize)
+
+	plays, err := h.historyRep.GetHistory(userId, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "history.usecase.GetHistory error while historyRep call")
+	}
+
+	ids := make([]uint64, 0, len(plays))
+	for _, v := range plays {
+		ids = append(ids, v.TrackId)
+	}
+
+	tracks, err := h.trackRep.GetTracks(ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "history.usecase.GetHistory error while trackRep call")
+	}
+
+	byId := make(map[uint64]*models.TrackMeta, len(tracks))
+	for _, v := range tracks {
+		byId[v.Id] = v
+	}
+
+	// plays of tracks deleted meanwhile are skipped
+	entries := make([]*models.HistoryEntry, 0, len(plays))
+	for _, v := range plays {
+		if track, ok := byId[v.TrackId]; ok {
+			entries = append(entries, &models.HistoryEntry{Play: *v, Track: track})
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"src/internal/config"
+	mock_repository "src/internal/domain/history/repository/mocks"
+	mock_repository2 "src/internal/domain/track/repository/mocks"
+	"src/internal/models"
+	"testing"
+	"time"
+)
+
+var testCfg = config.History{
+	BatchSize:     2,
+	FlushInterval: time.Second,
+	MaxBuffered:   3,
+	MaxAttempts:   2,
+}
+
+func TestHistoryUseCase_RecordPlay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	type mock func(r2 *mock_repository2.MockTrackRepository)
+
+	testTable := []struct {
+		name        string
+		buffered    int
+		play        *models.Play
+		mock        mock
+		expected    *models.Play
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			play: &models.Play{UserId: 1, TrackId: 2, Duration: time.Minute, Completed: true},
+			mock: func(r2 *mock_repository2.MockTrackRepository) {
+				r2.EXPECT().GetTrack(uint64(2)).Return(&models.TrackMeta{Id: 2}, nil)
+			},
+			expected:    &models.Play{UserId: 1, TrackId: 2, PlayedAt: now, Duration: time.Minute, Completed: true},
+			expectedErr: nil,
+		},
+		{
+			name: "Negative duration test",
+			play: &models.Play{UserId: 1, TrackId: 2, Duration: -time.Second},
+			mock: func(r2 *mock_repository2.MockTrackRepository) {
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name: "Too long duration test",
+			play: &models.Play{UserId: 1, TrackId: 2, Duration: 25 * time.Hour},
+			mock: func(r2 *mock_repository2.MockTrackRepository) {
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name: "Future play test",
+			play: &models.Play{UserId: 1, TrackId: 2, PlayedAt: now.Add(time.Hour)},
+			mock: func(r2 *mock_repository2.MockTrackRepository) {
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name: "Unknown track test",
+			play: &models.Play{UserId: 1, TrackId: 2},
+			mock: func(r2 *mock_repository2.MockTrackRepository) {
+				r2.EXPECT().GetTrack(uint64(2)).Return(nil, models.ErrNotFound)
+			},
+			expected: nil,
+			expectedErr: errors.Wrap(models.ErrNotFound,
+				"history.usecase.RecordPlay error while trackRep call"),
+		},
+		{
+			name:     "Full buffer test",
+			buffered: 3,
+			play:     &models.Play{UserId: 1, TrackId: 2},
+			mock: func(r2 *mock_repository2.MockTrackRepository) {
+				r2.EXPECT().GetTrack(uint64(2)).Return(&models.TrackMeta{Id: 2}, nil)
+			},
+			expected:    nil,
+			expectedErr: models.ErrOverloaded,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trackRep := mock_repository2.NewMockTrackRepository(ctrl)
+			tc.mock(trackRep)
+
+			u := NewHistoryUseCase(mock_repository.NewMockHistoryRepository(ctrl), trackRep, testCfg)
+			u.now = func() time.Time { return now }
+			for i := 0; i < tc.buffered; i++ {
+				u.buffer = append(u.buffer, &models.Play{})
+			}
+
+			err := u.RecordPlay(tc.play)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+				assert.Len(t, u.buffer, tc.buffered)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, u.buffer[len(u.buffer)-1])
+			}
+		})
+	}
+}
+
+func TestHistoryUseCase_Flush(t *testing.T) {
+	plays := []*models.Play{{TrackId: 1}, {TrackId: 2}, {TrackId: 3}}
+
+	type mock func(r *mock_repository.MockHistoryRepository)
+
+	testTable := []struct {
+		name           string
+		attempts       int
+		mock           mock
+		expectedBuffer []*models.Play
+		expectedErr    error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockHistoryRepository) {
+				r.EXPECT().AddPlays(plays[:2]).Return(nil)
+				r.EXPECT().AddPlays(plays[2:]).Return(nil)
+			},
+			expectedBuffer: nil,
+			expectedErr:    nil,
+		},
+		{
+			name: "Repo fail test",
+			mock: func(r *mock_repository.MockHistoryRepository) {
+				r.EXPECT().AddPlays(plays[:2]).Return(nil)
+				r.EXPECT().AddPlays(plays[2:]).Return(errors.New("error"))
+			},
+			expectedBuffer: plays[2:],
+			expectedErr: errors.Wrap(errors.New("error"),
+				"history.usecase.Flush error while historyRep call"),
+		},
+		{
+			name:     "Last attempt test",
+			attempts: 1,
+			mock: func(r *mock_repository.MockHistoryRepository) {
+				r.EXPECT().AddPlays(plays[:2]).Return(errors.New("error"))
+				r.EXPECT().AddPlays(plays[:1]).Return(nil)
+				r.EXPECT().AddPlays(plays[1:2]).Return(errors.New("rejected"))
+				r.EXPECT().AddPlays(plays[2:]).Return(nil)
+			},
+			expectedBuffer: nil,
+			expectedErr: errors.Wrap(errors.New("error"),
+				"history.usecase.Flush dropped 1 plays"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			historyRep := mock_repository.NewMockHistoryRepository(ctrl)
+			tc.mock(historyRep)
+
+			u := NewHistoryUseCase(historyRep, mock_repository2.NewMockTrackRepository(ctrl), testCfg)
+			u.buffer = append([]*models.Play(nil), plays...)
+			u.attempts = tc.attempts
+
+			err := u.Flush()
+
+			assert.Equal(t, tc.expectedBuffer, u.buffer)
+			assert.Equal(t, len(tc.expectedBuffer) > 0, u.attempts > 0)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHistoryUseCase_GetHistory(t *testing.T) {
+	playedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	type mock func(r *mock_repository.MockHistoryRepository, r2 *mock_repository2.MockTrackRepository)
+
+	testTable := []struct {
+		name        string
+		page        int
+		pageSize    int
+		mock        mock
+		expected    []*models.HistoryEntry
+		expectedErr error
+	}{
+		{
+			name:     "Usual test",
+			page:     2,
+			pageSize: 500,
+			mock: func(r *mock_repository.MockHistoryRepository, r2 *mock_repository2.MockTrackRepository) {
+				r.EXPECT().GetHistory(uint64(1), 100, 100).Return([]*models.Play{
+					{Id: 5, UserId: 1, TrackId: 2, PlayedAt: playedAt, Completed: true},
+					{Id: 4, UserId: 1, TrackId: 3, PlayedAt: playedAt},
+				}, nil)
+				// the track 3 is deleted
+				r2.EXPECT().GetTracks([]uint64{2, 3}).Return([]*models.TrackMeta{{Id: 2, Name: "track"}}, nil)
+			},
+			expected: []*models.HistoryEntry{
+				{
+					Play:  models.Play{Id: 5, UserId: 1, TrackId: 2, PlayedAt: playedAt, Completed: true},
+					Track: &models.TrackMeta{Id: 2, Name: "track"},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "Invalid page test",
+			page:     0,
+			pageSize: 10,
+			mock: func(r *mock_repository.MockHistoryRepository, r2 *mock_repository2.MockTrackRepository) {
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:     "Repo fail test",
+			page:     1,
+			pageSize: 10,
+			mock: func(r *mock_repository.MockHistoryRepository, r2 *mock_repository2.MockTrackRepository) {
+				r.EXPECT().GetHistory(uint64(1), 0, 10).Return(nil, errors.New("error"))
+			},
+			expected: nil,
+			expectedErr: errors.Wrap(errors.New("error"),
+				"history.usecase.GetHistory error while historyRep call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			historyRep := mock_repository.NewMockHistoryRepository(ctrl)
+			trackRep := mock_repository2.NewMockTrackRepository(ctrl)
+			tc.mock(historyRep, trackRep)
+
+			u := NewHistoryUseCase(historyRep, trackRep, testCfg)
+			history, err := u.GetHistory(1, tc.page, tc.pageSize)
+
+			assert.Equal(t, tc.expected, history)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
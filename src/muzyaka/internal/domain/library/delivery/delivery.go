@@ -0,0 +1,228 @@
+package delivery
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"net/http"
+	"src/internal/domain/library/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/models"
+	"src/internal/models/dto"
+	"strconv"
+	"strings"
+)
+
+// @Summary AddToLibrary
+// @Security ApiKeyAuth
+// @Tags user
+// @Description like track, save album or playlist or follow musician
+// @ID add-to-library
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param kind path string true "track, album, playlist or musician"
+// @Param input body dto.LibraryAdd true "item"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/library/{kind} [post]
+func AddToLibrary(useCase usecase.LibraryUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.LibraryAdd
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.AddToLibrary(userIDUint, chi.URLParam(r, "kind"), req.Id)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary RemoveFromLibrary
+// @Security ApiKeyAuth
+// @Tags user
+// @Description remove item from library
+// @ID remove-from-library
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param kind path string true "track, album, playlist or musician"
+// @Param item_id path int true "item ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/library/{kind}/{item_id} [delete]
+func RemoveFromLibrary(useCase usecase.LibraryUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		itemID := chi.URLParam(r, "item_id")
+		itemIDUint, err := strconv.ParseUint(itemID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.RemoveFromLibrary(userIDUint, chi.URLParam(r, "kind"), itemIDUint)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNothingToDelete) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary LibraryContains
+// @Security ApiKeyAuth
+// @Tags user
+// @Description check which of the items are in library
+// @ID library-contains
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param kind path string true "track, album, playlist or musician"
+// @Param ids query string true "comma separated item IDs, at most 50"
+// @Success 200 {object} dto.LibraryContains
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/library/{kind}/contains [get]
+func Contains(useCase usecase.LibraryUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var ids []uint64
+		for _, v := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error(err.Error()))
+				return
+			}
+			ids = append(ids, id)
+		}
+
+		contains, err := useCase.Contains(userIDUint, chi.URLParam(r, "kind"), ids)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.LibraryContains{Contains: contains})
+	}
+}
+
+// @Summary GetLibrary
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get liked tracks, saved albums and playlists or followed musicians
+// @ID get-library
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param kind path string true "track, album, playlist or musician"
+// @Param sort query string false "added_at (default, newest first) or name"
+// @Param        page    query     int  true  "number of page from 1"
+// @Param        page_size    query     int  true  "size of page, at most 100"
+// @Success 200 {object} dto.Library
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/library/{kind} [get]
+func GetLibrary(useCase usecase.LibraryUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageStr := r.URL.Query().Get("page")
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageSizeStr := r.URL.Query().Get("page_size")
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		items, err := useCase.GetLibrary(userIDUint, chi.URLParam(r, "kind"),
+			r.URL.Query().Get("sort"), page, pageSize)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoLibrary(items))
+	}
+}
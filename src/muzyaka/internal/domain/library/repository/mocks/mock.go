@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLibraryRepository is a mock of LibraryRepository interface.
+type MockLibraryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLibraryRepositoryMockRecorder
+}
+
+// MockLibraryRepositoryMockRecorder is the mock recorder for MockLibraryRepository.
+type MockLibraryRepositoryMockRecorder struct {
+	mock *MockLibraryRepository
+}
+
+// NewMockLibraryRepository creates a new mock instance.
+func NewMockLibraryRepository(ctrl *gomock.Controller) *MockLibraryRepository {
+	mock := &MockLibraryRepository{ctrl: ctrl}
+	mock.recorder = &MockLibraryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLibraryRepository) EXPECT() *MockLibraryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddToLibrary mocks base method.
+func (m *MockLibraryRepository) AddToLibrary(userId uint64, kind string, itemId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToLibrary", userId, kind, itemId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToLibrary indicates an expected call of AddToLibrary.
+func (mr *MockLibraryRepositoryMockRecorder) AddToLibrary(userId, kind, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToLibrary", reflect.TypeOf((*MockLibraryRepository)(nil).AddToLibrary), userId, kind, itemId)
+}
+
+// GetContained mocks base method.
+func (m *MockLibraryRepository) GetContained(userId uint64, kind string, ids []uint64) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContained", userId, kind, ids)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContained indicates an expected call of GetContained.
+func (mr *MockLibraryRepositoryMockRecorder) GetContained(userId, kind, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContained", reflect.TypeOf((*MockLibraryRepository)(nil).GetContained), userId, kind, ids)
+}
+
+// GetLibrary mocks base method.
+func (m *MockLibraryRepository) GetLibrary(userId uint64, kind, sort string, offset, limit int) ([]*models.LibraryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLibrary", userId, kind, sort, offset, limit)
+	ret0, _ := ret[0].([]*models.LibraryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLibrary indicates an expected call of GetLibrary.
+func (mr *MockLibraryRepositoryMockRecorder) GetLibrary(userId, kind, sort, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLibrary", reflect.TypeOf((*MockLibraryRepository)(nil).GetLibrary), userId, kind, sort, offset, limit)
+}
+
+// RemoveFromLibrary mocks base method.
+func (m *MockLibraryRepository) RemoveFromLibrary(userId uint64, kind string, itemId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromLibrary", userId, kind, itemId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromLibrary indicates an expected call of RemoveFromLibrary.
+func (mr *MockLibraryRepositoryMockRecorder) RemoveFromLibrary(userId, kind, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromLibrary", reflect.TypeOf((*MockLibraryRepository)(nil).RemoveFromLibrary), userId, kind, itemId)
+}
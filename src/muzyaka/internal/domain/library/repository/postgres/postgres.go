@@ -0,0 +1,244 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/domain/library/repository"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"src/internal/models/events"
+	"time"
+)
+
+type libraryTable struct {
+	table       string
+	itemColumn  string
+	addedColumn string
+	itemTable   string
+	// itemFilter limits the items that can be added
+	itemFilter string
+	// listFilter hides items of the library that are no longer available
+	// to the user, as i and l
+	listFilter string
+
+	addEvent    string
+	removeEvent string
+	payload     func(userId uint64, itemId uint64) interface{}
+}
+
+var libraryTables = map[string]libraryTable{
+	models.LibraryTrack: {
+		table:       "user_track",
+		itemColumn:  "track_id",
+		addedColumn: "liked_at",
+		itemTable:   "tracks",
+		addEvent:    events.TrackLiked,
+		removeEvent: events.TrackDisliked,
+		payload: func(userId uint64, itemId uint64) interface{} {
+			return events.LikePayload{UserId: userId, TrackId: itemId}
+		},
+	},
+	models.LibraryAlbum: {
+		table:       "user_album",
+		itemColumn:  "album_id",
+		addedColumn: "added_at",
+		itemTable:   "albums",
+		addEvent:    events.AlbumSaved,
+		removeEvent: events.AlbumUnsaved,
+		payload: func(userId uint64, itemId uint64) interface{} {
+			return events.SavedPayload{UserId: userId, ItemId: itemId}
+		},
+	},
+	models.LibraryPlaylist: {
+		table:       "user_playlist",
+		itemColumn:  "playlist_id",
+		addedColumn: "added_at",
+		itemTable:   "playlists",
+		// daily mixes are personal, and playlists made private after they
+		// were saved are only listed for the owner
+		itemFilter:  "kind IN ('user', 'smart') AND visibility <> 'private'",
+		listFilter:  "i.visibility <> 'private' OR i.user_id = l.user_id",
+		addEvent:    events.PlaylistSaved,
+		removeEvent: events.PlaylistUnsaved,
+		payload: func(userId uint64, itemId uint64) interface{} {
+			return events.SavedPayload{UserId: userId, ItemId: itemId}
+		},
+	},
+	models.LibraryMusician: {
+		table:       "musician_followers",
+		itemColumn:  "musician_id",
+		addedColumn: "followed_at",
+		itemTable:   "musicians",
+		addEvent:    events.MusicianFollowed,
+		removeEvent: events.MusicianUnfollowed,
+		payload: func(userId uint64, itemId uint64) interface{} {
+			return events.FollowPayload{UserId: userId, MusicianId: itemId}
+		},
+	},
+}
+
+type libraryRepository struct {
+	db *gorm.DB
+}
+
+func NewLibraryRepository(db *gorm.DB) repository.LibraryRepository {
+	return &libraryRepository{db: db}
+}
+
+func getTable(kind string) (libraryTable, error) {
+	t, ok := libraryTables[kind]
+	if !ok {
+		return libraryTable{}, errors.Wrap(models.ErrInvalidParameter, kind)
+	}
+
+	return t, nil
+}
+
+func (l *libraryRepository) AddToLibrary(userId uint64, kind string, itemId uint64) error {
+	t, err := getTable(kind)
+	if err != nil {
+		return err
+	}
+
+	err = l.db.Transaction(func(tx *gorm.DB) error {
+		item := tx.Table(t.itemTable).Where("id = ?", itemId)
+		if t.itemFilter != "" {
+			item = item.Where(t.itemFilter)
+		}
+
+		var found int64
+		if err := item.Count(&found).Error; err != nil {
+			return err
+		}
+		if found == 0 {
+			return models.ErrNotFound
+		}
+
+		res := tx.Exec(fmt.Sprintf("INSERT INTO %s (user_id, %s) VALUES (?, ?) ON CONFLICT DO NOTHING",
+			t.table, t.itemColumn), userId, itemId)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		outbox, err := dao.NewOutbox(t.addEvent, userId, t.payload(userId, itemId))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrNotFound
+	} else if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("database error (table %s)", t.table))
+	}
+
+	return nil
+}
+
+func (l *libraryRepository) RemoveFromLibrary(userId uint64, kind string, itemId uint64) error {
+	t, err := getTable(kind)
+	if err != nil {
+		return err
+	}
+
+	err = l.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND %s = ?",
+			t.table, t.itemColumn), userId, itemId)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNothingToDelete
+		}
+
+		outbox, err := dao.NewOutbox(t.removeEvent, userId, t.payload(userId, itemId))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNothingToDelete) {
+		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("database error (table %s)", t.table))
+	}
+
+	return nil
+}
+
+func (l *libraryRepository) GetContained(userId uint64, kind string, ids []uint64) ([]uint64, error) {
+	t, err := getTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var contained []uint64
+	tx := l.db.Table(t.table).
+		Where("user_id = ?", userId).
+		Where(t.itemColumn+" IN ?", ids).
+		Pluck(t.itemColumn, &contained)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, fmt.Sprintf("database error (table %s)", t.table))
+	}
+
+	return contained, nil
+}
+
+type libraryRow struct {
+	ItemId  uint64
+	Name    string
+	AddedAt time.Time
+}
+
+func (l *libraryRepository) GetLibrary(userId uint64, kind string, sort string,
+	offset int, limit int) ([]*models.LibraryItem, error) {
+	t, err := getTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	order := "added_at DESC, item_id DESC"
+	if sort == models.LibrarySortName {
+		order = "name, item_id"
+	}
+
+	filter := "TRUE"
+	if t.listFilter != "" {
+		filter = t.listFilter
+	}
+
+	var rows []*libraryRow
+	tx := l.db.Raw(fmt.Sprintf(`
+		SELECT l.%[2]s AS item_id, i.name, l.%[3]s AS added_at
+		FROM %[1]s l
+		JOIN %[4]s i ON i.id = l.%[2]s
+		WHERE l.user_id = ? AND (%[6]s)
+		ORDER BY %[5]s
+		OFFSET ? LIMIT ?`, t.table, t.itemColumn, t.addedColumn, t.itemTable, order, filter),
+		userId, offset, limit).Scan(&rows)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, fmt.Sprintf("database error (table %s)", t.table))
+	}
+
+	res := make([]*models.LibraryItem, 0, len(rows))
+	for _, v := range rows {
+		res = append(res, &models.LibraryItem{
+			Kind:    kind,
+			ItemId:  v.ItemId,
+			Name:    v.Name,
+			AddedAt: v.AddedAt,
+		})
+	}
+
+	return res, nil
+}
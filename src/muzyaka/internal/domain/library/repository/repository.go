@@ -0,0 +1,17 @@
+package repository
+
+import "src/internal/models"
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+// LibraryRepository keeps every kind of models.LibraryKinds in its own table,
+// liked tracks and followed musicians are the same rows UserRepository uses
+type LibraryRepository interface {
+	// AddToLibrary does nothing if the item is already added and
+	// returns models.ErrNotFound if there is no such item
+	AddToLibrary(userId uint64, kind string, itemId uint64) error
+	RemoveFromLibrary(userId uint64, kind string, itemId uint64) error
+	// GetContained returns the subset of ids that are in the library
+	GetContained(userId uint64, kind string, ids []uint64) ([]uint64, error)
+	GetLibrary(userId uint64, kind string, sort string, offset int, limit int) ([]*models.LibraryItem, error)
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"github.com/pkg/errors"
+	"src/internal/domain/library/repository"
+	"src/internal/models"
+)
+
+const (
+	MaxPageSize    = 100
+	MaxContainsIds = 50
+)
+
+type LibraryUseCase interface {
+	AddToLibrary(userId uint64, kind string, itemId uint64) error
+	RemoveFromLibrary(userId uint64, kind string, itemId uint64) error
+	// Contains reports for every id whether it is in the library
+	Contains(userId uint64, kind string, ids []uint64) ([]bool, error)
+	// GetLibrary sorts by models.LibrarySortAdded if sort is empty
+	GetLibrary(userId uint64, kind string, sort string, page int, pageSize int) ([]*models.LibraryItem, error)
+}
+
+type usecase struct {
+	libraryRep repository.LibraryRepository
+}
+
+func NewLibraryUseCase(libraryRep repository.LibraryRepository) LibraryUseCase {
+	return &usecase{libraryRep: libraryRep}
+}
+
+func (u *usecase) AddToLibrary(userId uint64, kind string, itemId uint64) error {
+	if !models.LibraryKinds[kind] {
+		return models.ErrInvalidParameter
+	}
+
+	err := u.libraryRep.AddToLibrary(userId, kind, itemId)
+	if err != nil {
+		return errors.Wrap(err, "library.usecase.AddToLibrary error while add")
+	}
+
+	return nil
+}
+
+func (u *usecase) RemoveFromLibrary(userId uint64, kind string, itemId uint64) error {
+	if !models.LibraryKinds[kind] {
+		return models.ErrInvalidParameter
+	}
+
+	err := u.libraryRep.RemoveFromLibrary(userId, kind, itemId)
+	if err != nil {
+		return errors.Wrap(err, "library.usecase.RemoveFromLibrary error while delete")
+	}
+
+	return nil
+}
+
+func (u *usecase) Contains(userId uint64, kind string, ids []uint64) ([]bool, error) {
+	if !models.LibraryKinds[kind] || len(ids) == 0 || len(ids) > MaxContainsIds {
+		return nil, models.ErrInvalidParameter
+	}
+
+	contained, err := u.libraryRep.GetContained(userId, kind, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "library.usecase.Contains error while get")
+	}
+
+	set := make(map[uint64]bool, len(contained))
+	for _, v := range contained {
+		set[v] = true
+	}
+
+	res := make([]bool, 0, len(ids))
+	for _, v := range ids {
+		res = append(res, set[v])
+	}
+
+	return res, nil
+}
+
+func (u *usecase) GetLibrary(userId uint64, kind string, sort string,
+	page int, pageSize int) ([]*models.LibraryItem, error) {
+	if sort == "" {
+		sort = models.LibrarySortAdded
+	}
+
+	if !models.LibraryKinds[kind] || (sort != models.LibrarySortAdded && sort != models.LibrarySortName) {
+		return nil, models.ErrInvalidParameter
+	}
+
+	if page < 1 || pageSize < 1 {
+		return nil, models.ErrInvalidParameter
+	}
+
+	pageSize = min(pageSize, MaxPageSize)
+
+	items, err := u.libraryRep.GetLibrary(userId, kind, sort, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "library.usecase.GetLibrary error while get")
+	}
+
+	return items, nil
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock_repository "src/internal/domain/library/repository/mocks"
+	"src/internal/models"
+	"testing"
+	"time"
+)
+
+func TestLibraryUseCase_AddToLibrary(t *testing.T) {
+	type mock func(r *mock_repository.MockLibraryRepository)
+
+	testTable := []struct {
+		name        string
+		kind        string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			kind: models.LibraryAlbum,
+			mock: func(r *mock_repository.MockLibraryRepository) {
+				r.EXPECT().AddToLibrary(uint64(1), models.LibraryAlbum, uint64(2)).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "Invalid kind test",
+			kind:        "genre",
+			mock:        func(r *mock_repository.MockLibraryRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name: "Unknown item test",
+			kind: models.LibraryPlaylist,
+			mock: func(r *mock_repository.MockLibraryRepository) {
+				r.EXPECT().AddToLibrary(uint64(1), models.LibraryPlaylist, uint64(2)).Return(models.ErrNotFound)
+			},
+			expectedErr: errors.Wrap(models.ErrNotFound, "library.usecase.AddToLibrary error while add"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockLibraryRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewLibraryUseCase(repo)
+			err := u.AddToLibrary(1, tc.kind, 2)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLibraryUseCase_RemoveFromLibrary(t *testing.T) {
+	type mock func(r *mock_repository.MockLibraryRepository)
+
+	testTable := []struct {
+		name        string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockLibraryRepository) {
+				r.EXPECT().RemoveFromLibrary(uint64(1), models.LibraryTrack, uint64(2)).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Not in library test",
+			mock: func(r *mock_repository.MockLibraryRepository) {
+				r.EXPECT().RemoveFromLibrary(uint64(1), models.LibraryTrack, uint64(2)).Return(models.ErrNothingToDelete)
+			},
+			expectedErr: errors.Wrap(models.ErrNothingToDelete, "library.usecase.RemoveFromLibrary error while delete"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockLibraryRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewLibraryUseCase(repo)
+			err := u.RemoveFromLibrary(1, models.LibraryTrack, 2)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLibraryUseCase_Contains(t *testing.T) {
+	type mock func(r *mock_repository.MockLibraryRepository)
+
+	tooMany := make([]uint64, MaxContainsIds+1)
+
+	testTable := []struct {
+		name        string
+		ids         []uint64
+		mock        mock
+		expected    []bool
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			ids:  []uint64{3, 1, 2},
+			mock: func(r *mock_repository.MockLibraryRepository) {
+				r.EXPECT().GetContained(uint64(1), models.LibraryMusician, []uint64{3, 1, 2}).
+					Return([]uint64{2, 3}, nil)
+			},
+			expected:    []bool{true, false, true},
+			expectedErr: nil,
+		},
+		{
+			name:        "Empty ids test",
+			ids:         nil,
+			mock:        func(r *mock_repository.MockLibraryRepository) {},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Too many ids test",
+			ids:         tooMany,
+			mock:        func(r *mock_repository.MockLibraryRepository) {},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockLibraryRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewLibraryUseCase(repo)
+			res, err := u.Contains(1, models.LibraryMusician, tc.ids)
+
+			assert.Equal(t, tc.expected, res)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLibraryUseCase_GetLibrary(t *testing.T) {
+	items := []*models.LibraryItem{
+		{Kind: models.LibraryAlbum, ItemId: 2, Name: "album", AddedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	dbErr := errors.New("db error")
+
+	type mock func(r *mock_repository.MockLibraryRepository)
+
+	testTable := []struct {
+		name        string
+		sort        string
+		page        int
+		pageSize    int
+		mock        mock
+		expected    []*models.LibraryItem
+		expectedErr error
+	}{
+		{
+			name:     "Default sort test",
+			page:     2,
+			pageSize: 20,
+			mock: func(r *mock_repository.MockLibraryRepository) {
+				r.EXPECT().GetLibrary(uint64(1), models.LibraryAlbum, models.LibrarySortAdded, 20, 20).
+					Return(items, nil)
+			},
+			expected:    items,
+			expectedErr: nil,
+		},
+		{
+			name:     "Name sort test",
+			sort:     models.LibrarySortName,
+			page:     1,
+			pageSize: 1000,
+			mock: func(r *mock_repository.MockLibraryRepository) {
+				r.EXPECT().GetLibrary(uint64(1), models.LibraryAlbum, models.LibrarySortName, 0, MaxPageSize).
+					Return(items, nil)
+			},
+			expected:    items,
+			expectedErr: nil,
+		},
+		{
+			name:        "Invalid sort test",
+			sort:        "popularity",
+			page:        1,
+			pageSize:    10,
+			mock:        func(r *mock_repository.MockLibraryRepository) {},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Invalid page test",
+			page:        0,
+			pageSize:    10,
+			mock:        func(r *mock_repository.MockLibraryRepository) {},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:     "Repo fail test",
+			page:     1,
+			pageSize: 10,
+			mock: func(r *mock_repository.MockLibraryRepository) {
+				r.EXPECT().GetLibrary(uint64(1), models.LibraryAlbum, models.LibrarySortAdded, 0, 10).
+					Return(nil, dbErr)
+			},
+			expected:    nil,
+			expectedErr: errors.Wrap(dbErr, "library.usecase.GetLibrary error while get"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockLibraryRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewLibraryUseCase(repo)
+			res, err := u.GetLibrary(1, models.LibraryAlbum, tc.sort, tc.page, tc.pageSize)
+
+			assert.Equal(t, tc.expected, res)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package delivery
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/pkg/errors"
 	"net/http"
 	"src/internal/domain/merch/usecase"
 	"src/internal/lib/api/response"
@@ -44,7 +45,11 @@ func MerchCreate(merchUseCase usecase.MerchUseCase) http.HandlerFunc {
 		}
 
 		id, err := merchUseCase.AddMerch(dto.ToModelMerchWithoutId(&req, 0), musicianIDUint)
-		if err != nil {
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
@@ -90,7 +95,11 @@ func UpdateMerch(useCase usecase.MerchUseCase) http.HandlerFunc {
 		}
 
 		err = useCase.UpdateMerch(dto.ToModelMerchWithoutId(&req, merchIDUint))
-		if err != nil {
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
@@ -225,6 +234,10 @@ func GetAllMerchForMusician(useCase usecase.MerchUseCase) http.HandlerFunc {
 // @Param        q    query     string  true  "name search by q"
 // @Param        page    query     int  true  "number of page from 1"
 // @Param        page_size    query     int  true  "size of page"
+// @Param        currency    query     string  false  "currency of min_price and max_price"
+// @Param        min_price    query     int  false  "lowest variant price in minor units"
+// @Param        max_price    query     int  false  "highest variant price in minor units"
+// @Param        available    query     bool  false  "only merch with variants in stock"
 // @Success 200 {object} dto.MerchCollection
 // @Failure 400,404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -255,8 +268,19 @@ func FindMerch(useCase usecase.MerchUseCase) http.HandlerFunc {
 			return
 		}
 
-		merch, err := useCase.GetMerchByPartName(name, page, pageSize)
+		filter, err := parseMerchFilter(r)
 		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		merch, err := useCase.GetMerchByPartName(name, filter, page, pageSize)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
@@ -270,3 +294,70 @@ func FindMerch(useCase usecase.MerchUseCase) http.HandlerFunc {
 		render.JSON(w, r, dto.MerchCollection{Items: res})
 	}
 }
+
+// @Summary ClickMerch
+// @Security ApiKeyAuth
+// @Tags merch
+// @Description count a click-through and redirect to the order link
+// @ID click-merch
+// @Param id   path      int  true  "Merch ID"
+// @Success 302
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/merch/{id}/order [get]
+func ClickMerch(useCase usecase.MerchUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		aid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		link, err := useCase.ClickMerch(aid)
+		if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		http.Redirect(w, r, link, http.StatusFound)
+	}
+}
+
+func parseMerchFilter(r *http.Request) (models.MerchFilter, error) {
+	query := r.URL.Query()
+	filter := models.MerchFilter{Currency: query.Get("currency")}
+
+	if v := query.Get("min_price"); v != "" {
+		price, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, err
+		}
+		filter.MinPrice = &price
+	}
+
+	if v := query.Get("max_price"); v != "" {
+		price, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, err
+		}
+		filter.MaxPrice = &price
+	}
+
+	if v := query.Get("available"); v != "" {
+		available, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, err
+		}
+		filter.Available = available
+	}
+
+	return filter, nil
+}
@@ -93,6 +93,21 @@ func (mr *MockMerchRepositoryMockRecorder) GetMerch(id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerch", reflect.TypeOf((*MockMerchRepository)(nil).GetMerch), id)
 }
 
+// GetMerchByPartName mocks base method.
+func (m *MockMerchRepository) GetMerchByPartName(name string, filter models.MerchFilter, offset, limit int) ([]*models.Merch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchByPartName", name, filter, offset, limit)
+	ret0, _ := ret[0].([]*models.Merch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchByPartName indicates an expected call of GetMerchByPartName.
+func (mr *MockMerchRepositoryMockRecorder) GetMerchByPartName(name, filter, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchByPartName", reflect.TypeOf((*MockMerchRepository)(nil).GetMerchByPartName), name, filter, offset, limit)
+}
+
 // GetMusicianForMerch mocks base method.
 func (m *MockMerchRepository) GetMusicianForMerch(merchId uint64) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -123,6 +138,21 @@ func (mr *MockMerchRepositoryMockRecorder) IsMerchOwned(merchId, musicianId inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMerchOwned", reflect.TypeOf((*MockMerchRepository)(nil).IsMerchOwned), merchId, musicianId)
 }
 
+// RecordMerchClick mocks base method.
+func (m *MockMerchRepository) RecordMerchClick(merchId uint64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMerchClick", merchId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordMerchClick indicates an expected call of RecordMerchClick.
+func (mr *MockMerchRepositoryMockRecorder) RecordMerchClick(merchId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMerchClick", reflect.TypeOf((*MockMerchRepository)(nil).RecordMerchClick), merchId)
+}
+
 // UpdateMerch mocks base method.
 func (m *MockMerchRepository) UpdateMerch(merch *models.Merch) error {
 	m.ctrl.T.Helper()
@@ -7,6 +7,7 @@ import (
 	repository2 "src/internal/domain/merch/repository"
 	"src/internal/models"
 	"src/internal/models/dao"
+	"src/internal/models/events"
 )
 
 type merchRepository struct {
@@ -17,28 +18,65 @@ func NewMerchRepository(db *gorm.DB) repository2.MerchRepository {
 	return &merchRepository{db: db}
 }
 
-func (m *merchRepository) GetMerchByPartName(name string, offset int, limit int) ([]*models.Merch, error) {
+// loadMerch fetches photos and variants of the merch
+func (m *merchRepository) loadMerch(merch *dao.Merch) (*models.Merch, error) {
+	var photos []*dao.MerchPhotos
+	tx := m.db.Where("merch_id = ?", merch.ID).Limit(dao.MaxLimit).Find(&photos)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var variants []*dao.MerchVariant
+	tx = m.db.Where("merch_id = ?", merch.ID).Order("id").Limit(dao.MaxLimit).Find(&variants)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	return dao.ToModelMerch(merch, photos, variants), nil
+}
+
+func (m *merchRepository) GetMerchByPartName(name string, filter models.MerchFilter, offset int, limit int) ([]*models.Merch, error) {
 	var merch []*dao.Merch
 
+	// a merch matches if one of its variants matches all the variant filters
+	variants := m.db.Model(&dao.MerchVariant{}).Select("merch_id")
+	filtered := false
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		variants = variants.Where("currency = ?", filter.Currency)
+		filtered = true
+	}
+	if filter.MinPrice != nil {
+		variants = variants.Where("price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		variants = variants.Where("price <= ?", *filter.MaxPrice)
+	}
+	if filter.Available {
+		variants = variants.Where("stock > reserved")
+		filtered = true
+	}
+
 	tx := m.db.
 		Offset(offset).
 		Limit(limit).
-		Where("name LIKE ?", "%"+name+"%").
-		Order("name").
-		Find(&merch)
+		Where("name LIKE ?", "%"+name+"%")
+	if filtered {
+		tx = tx.Where("id IN (?)", variants)
+	}
+
+	tx = tx.Order("name").Find(&merch)
 	if err := tx.Error; err != nil {
-		return nil, errors.Wrap(err, "database error (table track)")
+		return nil, errors.Wrap(err, "database error (table merch)")
 	}
 
 	var modelMerch []*models.Merch
 
 	for _, v := range merch {
-		var photos []*dao.MerchPhotos
-		tx = m.db.Where("merch_id = ?", v.ID).Limit(dao.MaxLimit).Find(&photos)
-		if tx.Error != nil {
-			return nil, errors.Wrap(tx.Error, "database error (table track)")
+		res, err := m.loadMerch(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "database error (table merch)")
 		}
-		modelMerch = append(modelMerch, dao.ToModelMerch(v, photos))
+		modelMerch = append(modelMerch, res)
 	}
 
 	return modelMerch, nil
@@ -56,19 +94,18 @@ func (m *merchRepository) IsMerchOwned(merchId uint64, musicianId uint64) (bool,
 
 func (m *merchRepository) GetMerch(id uint64) (*models.Merch, error) {
 	var merch dao.Merch
-	var merchPhotos []*dao.MerchPhotos
 
 	tx := m.db.Where("id = ?", id).Take(&merch)
 	if tx.Error != nil {
 		return nil, errors.Wrap(tx.Error, "database error (table merch)")
 	}
 
-	tx = m.db.Where("merch_id = ?", id).Limit(dao.MaxLimit).Find(&merchPhotos)
-	if tx.Error != nil {
-		return nil, errors.Wrap(tx.Error, "database error (table merch)")
+	res, err := m.loadMerch(&merch)
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table merch)")
 	}
 
-	return dao.ToModelMerch(&merch, merchPhotos), nil
+	return res, nil
 }
 
 func (m *merchRepository) GetMusicianForMerch(merchId uint64) (uint64, error) {
@@ -92,13 +129,12 @@ func (m *merchRepository) GetAllMerchForMusician(musicianId uint64) ([]*models.M
 
 	var res []*models.Merch
 	for _, v := range merch {
-		var merchPhotos []*dao.MerchPhotos
-		tx = m.db.Where("merch_id = ?", v.ID).Limit(dao.MaxLimit).Find(&merchPhotos)
-		if tx.Error != nil {
-			return nil, errors.Wrap(tx.Error, "database error (table merch)")
+		modelMerch, err := m.loadMerch(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "database error (table merch)")
 		}
 
-		res = append(res, dao.ToModelMerch(v, merchPhotos))
+		res = append(res, modelMerch)
 	}
 
 	return res, nil
@@ -149,6 +185,17 @@ func (m *merchRepository) UpdateMerch(merch *models.Merch) error {
 			return err
 		}
 
+		// Updates skips a nil link
+		if pgMerch.Link == nil {
+			if err := tx.Model(pgMerch).Update("link", nil).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := syncMerchVariants(tx, merch); err != nil {
+			return err
+		}
+
 		for _, v := range filesToDelete {
 			if err := tx.
 				Where("photo_file = ? AND merch_id = ?", v.PhotoFile, v.MerchId).
@@ -161,7 +208,18 @@ func (m *merchRepository) UpdateMerch(merch *models.Merch) error {
 				return err
 			}
 		}
-		return nil
+
+		var updated dao.Merch
+		if err := tx.Where("id = ?", pgMerch.ID).Take(&updated).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.MerchUpdated, updated.ID, dao.ToMerchPayload(&updated))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
 	})
 
 	if err != nil {
@@ -186,7 +244,20 @@ func (m *merchRepository) AddMerch(merch *models.Merch, musicianId uint64) (uint
 				return err
 			}
 		}
-		return nil
+
+		pgMerchVariants := dao.ToPostgresMerchVariants(temp)
+		if len(pgMerchVariants) != 0 {
+			if err := tx.Omit("id").Create(&pgMerchVariants).Error; err != nil {
+				return err
+			}
+		}
+
+		outbox, err := dao.NewOutbox(events.MerchCreated, pgMerch.ID, dao.ToMerchPayload(pgMerch))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
 	})
 
 	if err != nil {
@@ -199,14 +270,114 @@ func (m *merchRepository) AddMerch(merch *models.Merch, musicianId uint64) (uint
 }
 
 func (m *merchRepository) DeleteMerch(id uint64) error {
-	tx := m.db.Delete(&dao.Merch{}, id)
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var merch dao.Merch
+		if err := tx.Where("id = ?", id).Take(&merch).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNothingToDelete
+		} else if err != nil {
+			return err
+		}
 
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table merch)")
-	}
+		if err := tx.Delete(&dao.Merch{}, id).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.MerchDeleted, id, events.MerchPayload{MerchId: id, MusicianId: merch.MusicianID})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
 
-	if tx.RowsAffected == 0 {
+	if errors.Is(err, models.ErrNothingToDelete) {
 		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table merch)")
+	}
+
+	return nil
+}
+
+func (m *merchRepository) RecordMerchClick(merchId uint64) (string, error) {
+	var merch dao.Merch
+
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", merchId).Take(&merch).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		// merch sold through the store only has nowhere to click through
+		if merch.Link == nil {
+			return models.ErrNotFound
+		}
+
+		if err := tx.Create(&dao.MerchClick{MerchId: merchId}).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.MerchClicked, merchId, events.MerchPayload{MerchId: merchId, MusicianId: merch.MusicianID})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNotFound) {
+		return "", models.ErrNotFound
+	} else if err != nil {
+		return "", errors.Wrap(err, "database error (table merch_clicks)")
+	}
+
+	return *merch.Link, nil
+}
+
+// syncMerchVariants matches variants by SKU: known ones are updated in place
+// so that their ids stay valid and their items stay reserved, missing ones
+// are deleted and new ones created
+func syncMerchVariants(tx *gorm.DB, merch *models.Merch) error {
+	var existing []*dao.MerchVariant
+	if err := tx.Where("merch_id = ?", merch.Id).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	bySku := make(map[string]*dao.MerchVariant, len(existing))
+	for _, v := range existing {
+		bySku[v.Sku] = v
+	}
+
+	var toCreate, toUpdate []*dao.MerchVariant
+	for _, v := range dao.ToPostgresMerchVariants(merch) {
+		old, ok := bySku[v.Sku]
+		if !ok {
+			toCreate = append(toCreate, v)
+			continue
+		}
+		delete(bySku, v.Sku)
+
+		v.ID = old.ID
+		toUpdate = append(toUpdate, v)
+	}
+
+	// deleted first to free their size and colour
+	for _, v := range bySku {
+		if err := tx.Delete(v).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, v := range toUpdate {
+		if err := tx.Model(v).Select("size", "colour", "price", "currency", "stock", "low_stock_threshold").
+			Updates(v).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(toCreate) != 0 {
+		return tx.Omit("id").Create(&toCreate).Error
 	}
 
 	return nil
@@ -7,11 +7,15 @@ import "src/internal/models"
 type MerchRepository interface {
 	GetMerch(id uint64) (*models.Merch, error)
 	GetAllMerchForMusician(musicianId uint64) ([]*models.Merch, error)
+	// UpdateMerch replaces variants matching them by SKU
 	UpdateMerch(merch *models.Merch) error
 	AddMerch(merch *models.Merch, musicianId uint64) (uint64, error)
 	DeleteMerch(id uint64) error
 	GetMusicianForMerch(merchId uint64) (uint64, error)
 
 	IsMerchOwned(merchId uint64, musicianId uint64) (bool, error)
-	GetMerchByPartName(name string, offset int, limit int) ([]*models.Merch, error)
+	GetMerchByPartName(name string, filter models.MerchFilter, offset int, limit int) ([]*models.Merch, error)
+
+	// RecordMerchClick saves a click-through and returns the order link
+	RecordMerchClick(merchId uint64) (string, error)
 }
@@ -4,14 +4,23 @@ import (
 	"github.com/pkg/errors"
 	"src/internal/domain/merch/repository"
 	"src/internal/models"
+	"strings"
+	"unicode/utf8"
 )
 
 const MinPageSize = 10
 const MaxPageSize = 100
 
+const (
+	MaxVariants     = 50
+	MaxSkuLength    = 64
+	MaxOptionLength = 32
+)
+
 type MerchUseCase interface {
 	GetMerch(id uint64) (*models.Merch, error)
 	GetAllMerchForMusician(musicianId uint64) ([]*models.Merch, error)
+	// UpdateMerch and AddMerch need an order url or variants priced in one currency
 	UpdateMerch(merch *models.Merch) error
 	AddMerch(merch *models.Merch, musicianId uint64) (uint64, error)
 	DeleteMerch(id uint64) error
@@ -19,7 +28,10 @@ type MerchUseCase interface {
 
 	IsMerchOwned(merchId uint64, musicianId uint64) (bool, error)
 
-	GetMerchByPartName(name string, page int, pageSize int) ([]*models.Merch, error)
+	GetMerchByPartName(name string, filter models.MerchFilter, page int, pageSize int) ([]*models.Merch, error)
+
+	// ClickMerch counts a click-through and returns the link to follow
+	ClickMerch(merchId uint64) (string, error)
 }
 
 type usecase struct {
@@ -30,7 +42,18 @@ func NewMerchUseCase(merchRepository repository.MerchRepository) MerchUseCase {
 	return &usecase{merchRep: merchRepository}
 }
 
-func (u *usecase) GetMerchByPartName(name string, page int, pageSize int) ([]*models.Merch, error) {
+func (u *usecase) GetMerchByPartName(name string, filter models.MerchFilter, page int, pageSize int) ([]*models.Merch, error) {
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		if _, ok := models.Currencies[filter.Currency]; !ok {
+			return nil, models.ErrInvalidParameter
+		}
+
+		if filter.MinPrice != nil && *filter.MinPrice < 0 ||
+			filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+			return nil, models.ErrInvalidParameter
+		}
+	}
+
 	if page <= 0 {
 		page = 1
 	}
@@ -43,7 +66,7 @@ func (u *usecase) GetMerchByPartName(name string, page int, pageSize int) ([]*mo
 	}
 
 	offset := (page - 1) * pageSize
-	merch, err := u.merchRep.GetMerchByPartName(name, offset, pageSize)
+	merch, err := u.merchRep.GetMerchByPartName(name, filter, offset, pageSize)
 	if err != nil {
 		return nil, errors.Wrap(err, "merch.usecase.GetMerchByPartName error while get")
 	}
@@ -92,6 +115,10 @@ func (u *usecase) GetAllMerchForMusician(musicianId uint64) ([]*models.Merch, er
 }
 
 func (u *usecase) UpdateMerch(merch *models.Merch) error {
+	if err := validateMerch(merch); err != nil {
+		return err
+	}
+
 	err := u.merchRep.UpdateMerch(merch)
 
 	if err != nil {
@@ -102,6 +129,10 @@ func (u *usecase) UpdateMerch(merch *models.Merch) error {
 }
 
 func (u *usecase) AddMerch(merch *models.Merch, musicianId uint64) (uint64, error) {
+	if err := validateMerch(merch); err != nil {
+		return 0, err
+	}
+
 	id, err := u.merchRep.AddMerch(merch, musicianId)
 
 	if err != nil {
@@ -120,3 +151,59 @@ func (u *usecase) DeleteMerch(id uint64) error {
 
 	return nil
 }
+
+func (u *usecase) ClickMerch(merchId uint64) (string, error) {
+	link, err := u.merchRep.RecordMerchClick(merchId)
+
+	if err != nil {
+		return "", errors.Wrap(err, "merch.usecase.ClickMerch error while record")
+	}
+
+	return link, nil
+}
+
+func validateMerch(merch *models.Merch) error {
+	if merch.OrderUrl == "" && len(merch.Variants) == 0 {
+		return errors.Wrap(models.ErrInvalidParameter, "merch needs an order url or variants")
+	}
+
+	if len(merch.Variants) > MaxVariants {
+		return errors.Wrap(models.ErrInvalidParameter, "too many variants")
+	}
+
+	skus := make(map[string]bool, len(merch.Variants))
+	options := make(map[string]bool, len(merch.Variants))
+
+	for _, v := range merch.Variants {
+		v.Sku = strings.TrimSpace(v.Sku)
+		if v.Sku == "" || utf8.RuneCountInString(v.Sku) > MaxSkuLength || skus[v.Sku] {
+			return errors.Wrap(models.ErrInvalidParameter, "variant sku is empty, too long or repeated")
+		}
+		skus[v.Sku] = true
+
+		if utf8.RuneCountInString(v.Size) > MaxOptionLength || utf8.RuneCountInString(v.Colour) > MaxOptionLength {
+			return errors.Wrap(models.ErrInvalidParameter, "variant size or colour is too long")
+		}
+
+		option := v.Size + "\x00" + v.Colour
+		if options[option] {
+			return errors.Wrap(models.ErrInvalidParameter, "variants repeat size and colour")
+		}
+		options[option] = true
+
+		if _, ok := models.Currencies[v.Price.Currency]; !ok {
+			return errors.Wrap(models.ErrInvalidParameter, "unsupported currency "+v.Price.Currency)
+		}
+
+		// prices of one merch are compared with each other
+		if v.Price.Currency != merch.Variants[0].Price.Currency {
+			return errors.Wrap(models.ErrInvalidParameter, "variants are priced in different currencies")
+		}
+
+		if v.Price.Amount < 0 || v.Stock < 0 || v.LowStockThreshold < 0 {
+			return errors.Wrap(models.ErrInvalidParameter, "variant price or stock is negative")
+		}
+	}
+
+	return nil
+}
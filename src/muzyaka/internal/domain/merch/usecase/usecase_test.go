@@ -167,6 +167,68 @@ func TestUsecase_AddMerch(t *testing.T) {
 			expectedErr: errors.Wrap(errors.New("error in repo"),
 				"merch.usecase.AddMerch error while add"),
 		},
+		{
+			name: "Store merch test",
+			inputMerch: &models.Merch{
+				Name: "T-shirt",
+				Variants: []*models.MerchVariant{
+					{Sku: "TS-S", Size: "S", Price: models.Price{Amount: 1999, Currency: "USD"}, Stock: 3},
+					{Sku: "TS-M", Size: "M", Price: models.Price{Amount: 1999, Currency: "USD"}},
+				},
+			},
+			mock: func(r *mock_repository.MockMerchRepository, merch *models.Merch) {
+				r.EXPECT().AddMerch(merch, uint64(0)).Return(uint64(2), nil)
+			},
+			expectedValue: uint64(2),
+			expectedErr:   nil,
+		},
+		{
+			name:       "No order url and variants test",
+			inputMerch: &models.Merch{Name: "Nothing to sell"},
+			mock: func(r *mock_repository.MockMerchRepository, merch *models.Merch) {
+			},
+			expectedValue: uint64(0),
+			expectedErr:   errors.Wrap(models.ErrInvalidParameter, "merch needs an order url or variants"),
+		},
+		{
+			name: "Repeated sku test",
+			inputMerch: &models.Merch{
+				Name: "T-shirt",
+				Variants: []*models.MerchVariant{
+					{Sku: "TS", Size: "S", Price: models.Price{Amount: 1999, Currency: "USD"}},
+					{Sku: " TS ", Size: "M", Price: models.Price{Amount: 1999, Currency: "USD"}},
+				},
+			},
+			mock: func(r *mock_repository.MockMerchRepository, merch *models.Merch) {
+			},
+			expectedValue: uint64(0),
+			expectedErr:   errors.Wrap(models.ErrInvalidParameter, "variant sku is empty, too long or repeated"),
+		},
+		{
+			name: "Mixed currencies test",
+			inputMerch: &models.Merch{
+				Name: "T-shirt",
+				Variants: []*models.MerchVariant{
+					{Sku: "TS-S", Size: "S", Price: models.Price{Amount: 1999, Currency: "USD"}},
+					{Sku: "TS-M", Size: "M", Price: models.Price{Amount: 1799, Currency: "EUR"}},
+				},
+			},
+			mock: func(r *mock_repository.MockMerchRepository, merch *models.Merch) {
+			},
+			expectedValue: uint64(0),
+			expectedErr:   errors.Wrap(models.ErrInvalidParameter, "variants are priced in different currencies"),
+		},
+		{
+			name: "Unknown currency test",
+			inputMerch: &models.Merch{
+				Name:     "Poster",
+				Variants: []*models.MerchVariant{{Sku: "P", Price: models.Price{Amount: 500, Currency: "XXX"}}},
+			},
+			mock: func(r *mock_repository.MockMerchRepository, merch *models.Merch) {
+			},
+			expectedValue: uint64(0),
+			expectedErr:   errors.Wrap(models.ErrInvalidParameter, "unsupported currency XXX"),
+		},
 	}
 
 	for _, tc := range testTable {
@@ -239,3 +301,120 @@ func TestUsecase_DeleteMerch(t *testing.T) {
 		})
 	}
 }
+
+func TestUsecase_ClickMerch(t *testing.T) {
+	type mock func(r *mock_repository.MockMerchRepository, id uint64)
+
+	testTable := []struct {
+		name         string
+		id           uint64
+		mock         mock
+		expectedLink string
+		expectedErr  error
+	}{
+		{
+			name: "Usual test",
+			id:   1,
+			mock: func(r *mock_repository.MockMerchRepository, id uint64) {
+				r.EXPECT().RecordMerchClick(id).Return("http://example.com/order", nil)
+			},
+			expectedLink: "http://example.com/order",
+			expectedErr:  nil,
+		},
+		{
+			name: "Not found test",
+			id:   2,
+			mock: func(r *mock_repository.MockMerchRepository, id uint64) {
+				r.EXPECT().RecordMerchClick(id).Return("", models.ErrNotFound)
+			},
+			expectedLink: "",
+			expectedErr:  errors.Wrap(models.ErrNotFound, "merch.usecase.ClickMerch error while record"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockMerchRepository(ctrl)
+			tc.mock(repo, tc.id)
+
+			u := NewMerchUseCase(repo)
+			link, err := u.ClickMerch(tc.id)
+
+			assert.Equal(t, tc.expectedLink, link)
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, err.Error(), tc.expectedErr.Error())
+			}
+		})
+	}
+}
+
+func TestUsecase_GetMerchByPartName(t *testing.T) {
+	price := func(v int64) *int64 { return &v }
+
+	type mock func(r *mock_repository.MockMerchRepository, filter models.MerchFilter)
+
+	testTable := []struct {
+		name        string
+		filter      models.MerchFilter
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name:   "Price range test",
+			filter: models.MerchFilter{Currency: "USD", MinPrice: price(1000), MaxPrice: price(3000), Available: true},
+			mock: func(r *mock_repository.MockMerchRepository, filter models.MerchFilter) {
+				r.EXPECT().GetMerchByPartName("shirt", filter, 10, 10).Return(nil, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "Price without currency test",
+			filter:      models.MerchFilter{MaxPrice: price(3000)},
+			mock:        func(r *mock_repository.MockMerchRepository, filter models.MerchFilter) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Reversed range test",
+			filter:      models.MerchFilter{Currency: "USD", MinPrice: price(3000), MaxPrice: price(1000)},
+			mock:        func(r *mock_repository.MockMerchRepository, filter models.MerchFilter) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockMerchRepository(ctrl)
+			tc.mock(repo, tc.filter)
+
+			u := NewMerchUseCase(repo)
+			_, err := u.GetMerchByPartName("shirt", tc.filter, 2, 10)
+
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestMerch_Availability(t *testing.T) {
+	assert.Equal(t, models.MerchExternal, (&models.Merch{}).Availability())
+	assert.Equal(t, models.MerchOutOfStock, (&models.Merch{Variants: []*models.MerchVariant{{Stock: 0}}}).Availability())
+	assert.Equal(t, models.MerchLowStock, (&models.Merch{Variants: []*models.MerchVariant{{Stock: 0}, {Stock: 5}}}).Availability())
+	assert.Equal(t, models.MerchInStock, (&models.Merch{Variants: []*models.MerchVariant{{Stock: 3, LowStockThreshold: 2}}}).Availability())
+}
+
+func TestPrice_String(t *testing.T) {
+	assert.Equal(t, "19.99 USD", models.Price{Amount: 1999, Currency: "USD"}.String())
+	assert.Equal(t, "0.05 EUR", models.Price{Amount: 5, Currency: "EUR"}.String())
+	assert.Equal(t, "1500 JPY", models.Price{Amount: 1500, Currency: "JPY"}.String())
+}
@@ -0,0 +1,109 @@
+package delivery
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"net/http"
+	"src/internal/domain/moderation/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/models"
+	"src/internal/models/dto"
+	"strconv"
+)
+
+// errorStatus maps usecase errors to response codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidParameter):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
+
+// @Summary GetDuplicateReports
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description get tracks sounding like earlier tracks of other musicians
+// @ID get-duplicate-reports
+// @Accept  json
+// @Produce  json
+// @Param        status    query     string  false  "pending, confirmed or dismissed, pending by default"
+// @Param        page    query     int  true  "number of page from 1"
+// @Param        page_size    query     int  true  "size of page"
+// @Success 200 {object} dto.DuplicateReportsCollection
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/admin/moderation/duplicates [get]
+func GetDuplicateReports(useCase usecase.ModerationUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		reports, err := useCase.GetDuplicateReports(r.URL.Query().Get("status"), page, pageSize)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoDuplicateReports(reports))
+	}
+}
+
+// @Summary ResolveDuplicateReport
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description confirm or dismiss a duplicate report
+// @ID resolve-duplicate-report
+// @Accept  json
+// @Produce  json
+// @Param id path int true "report ID"
+// @Param input body dto.ResolveReport true "resolution"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/admin/moderation/duplicates/{id} [put]
+func ResolveDuplicateReport(useCase usecase.ModerationUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reportID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.ResolveReport
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.ResolveDuplicateReport(reportID, req.Status)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
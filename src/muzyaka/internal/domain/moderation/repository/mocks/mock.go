@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockModerationRepository is a mock of ModerationRepository interface.
+type MockModerationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationRepositoryMockRecorder
+}
+
+// MockModerationRepositoryMockRecorder is the mock recorder for MockModerationRepository.
+type MockModerationRepositoryMockRecorder struct {
+	mock *MockModerationRepository
+}
+
+// NewMockModerationRepository creates a new mock instance.
+func NewMockModerationRepository(ctrl *gomock.Controller) *MockModerationRepository {
+	mock := &MockModerationRepository{ctrl: ctrl}
+	mock.recorder = &MockModerationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerationRepository) EXPECT() *MockModerationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddDuplicateReport mocks base method.
+func (m *MockModerationRepository) AddDuplicateReport(report *models.DuplicateReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDuplicateReport", report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDuplicateReport indicates an expected call of AddDuplicateReport.
+func (mr *MockModerationRepositoryMockRecorder) AddDuplicateReport(report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDuplicateReport", reflect.TypeOf((*MockModerationRepository)(nil).AddDuplicateReport), report)
+}
+
+// GetDuplicateReports mocks base method.
+func (m *MockModerationRepository) GetDuplicateReports(status string, offset, limit int) ([]*models.DuplicateReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicateReports", status, offset, limit)
+	ret0, _ := ret[0].([]*models.DuplicateReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicateReports indicates an expected call of GetDuplicateReports.
+func (mr *MockModerationRepositoryMockRecorder) GetDuplicateReports(status, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicateReports", reflect.TypeOf((*MockModerationRepository)(nil).GetDuplicateReports), status, offset, limit)
+}
+
+// SetDuplicateReportStatus mocks base method.
+func (m *MockModerationRepository) SetDuplicateReportStatus(id uint64, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDuplicateReportStatus", id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDuplicateReportStatus indicates an expected call of SetDuplicateReportStatus.
+func (mr *MockModerationRepositoryMockRecorder) SetDuplicateReportStatus(id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDuplicateReportStatus", reflect.TypeOf((*MockModerationRepository)(nil).SetDuplicateReportStatus), id, status)
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"src/internal/domain/moderation/repository"
+	"src/internal/models"
+	"src/internal/models/dao"
+)
+
+type moderationRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationRepository(db *gorm.DB) repository.ModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+func (m *moderationRepository) AddDuplicateReport(report *models.DuplicateReport) error {
+	// a dismissed report is not raised again when the track is processed again
+	tx := m.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Omit("id", "created_at").
+		Create(dao.ToPostgresDuplicateReport(report))
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "database error (table duplicate_reports)")
+	}
+
+	return nil
+}
+
+func (m *moderationRepository) GetDuplicateReports(status string, offset int, limit int) ([]*models.DuplicateReport, error) {
+	var reports []*dao.DuplicateReport
+
+	tx := m.db.Where("status = ?", status).Order("id").Offset(offset).Limit(limit).Find(&reports)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table duplicate_reports)")
+	}
+
+	res := make([]*models.DuplicateReport, 0, len(reports))
+	for _, v := range reports {
+		res = append(res, dao.ToModelDuplicateReport(v))
+	}
+
+	return res, nil
+}
+
+func (m *moderationRepository) SetDuplicateReportStatus(id uint64, status string) error {
+	tx := m.db.Model(&dao.DuplicateReport{}).Where("id = ?", id).Update("status", status)
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "database error (table duplicate_reports)")
+	}
+
+	if tx.RowsAffected == 0 {
+		return errors.Wrap(models.ErrNotFound, "database error (table duplicate_reports)")
+	}
+
+	return nil
+}
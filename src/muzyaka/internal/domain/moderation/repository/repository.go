@@ -0,0 +1,13 @@
+package repository
+
+import "src/internal/models"
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+type ModerationRepository interface {
+	// AddDuplicateReport keeps the existing report of the same tracks
+	AddDuplicateReport(report *models.DuplicateReport) error
+	// GetDuplicateReports returns reports with the status, oldest first
+	GetDuplicateReports(status string, offset int, limit int) ([]*models.DuplicateReport, error)
+	SetDuplicateReportStatus(id uint64, status string) error
+}
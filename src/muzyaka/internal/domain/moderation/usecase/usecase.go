@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"github.com/pkg/errors"
+	"src/internal/domain/moderation/repository"
+	"src/internal/models"
+)
+
+const (
+	MaxPageSize = 100
+	MinPageSize = 1
+)
+
+type ModerationUseCase interface {
+	// GetDuplicateReports returns pending reports if status is empty
+	GetDuplicateReports(status string, page int, pageSize int) ([]*models.DuplicateReport, error)
+	// ResolveDuplicateReport confirms or dismisses a report, a confirmed
+	// track is left for the admin to delete
+	ResolveDuplicateReport(id uint64, status string) error
+}
+
+type usecase struct {
+	moderationRep repository.ModerationRepository
+}
+
+func NewModerationUseCase(moderationRep repository.ModerationRepository) ModerationUseCase {
+	return &usecase{moderationRep: moderationRep}
+}
+
+func (u *usecase) GetDuplicateReports(status string, page int, pageSize int) ([]*models.DuplicateReport, error) {
+	if status == "" {
+		status = models.ReportPending
+	} else if !models.ReportStatuses[status] {
+		return nil, errors.Wrap(models.ErrInvalidParameter, "moderation.usecase.GetDuplicateReports invalid status")
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	switch {
+	case pageSize > MaxPageSize:
+		pageSize = MaxPageSize
+	case pageSize < MinPageSize:
+		pageSize = MinPageSize
+	}
+
+	reports, err := u.moderationRep.GetDuplicateReports(status, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "moderation.usecase.GetDuplicateReports error while get")
+	}
+
+	return reports, nil
+}
+
+func (u *usecase) ResolveDuplicateReport(id uint64, status string) error {
+	if status != models.ReportConfirmed && status != models.ReportDismissed {
+		return errors.Wrap(models.ErrInvalidParameter, "moderation.usecase.ResolveDuplicateReport invalid status")
+	}
+
+	if err := u.moderationRep.SetDuplicateReportStatus(id, status); err != nil {
+		return errors.Wrap(err, "moderation.usecase.ResolveDuplicateReport error while update")
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock_repository "src/internal/domain/moderation/repository/mocks"
+	"src/internal/models"
+	"testing"
+)
+
+func TestUsecase_GetDuplicateReports(t *testing.T) {
+	type mock func(r *mock_repository.MockModerationRepository)
+
+	reports := []*models.DuplicateReport{{Id: 1, TrackId: 3, OriginalTrackId: 2, Similarity: 0.9, Status: models.ReportPending}}
+
+	testTable := []struct {
+		name            string
+		status          string
+		page            int
+		pageSize        int
+		mock            mock
+		expectedReports []*models.DuplicateReport
+		expectedErr     error
+	}{
+		{
+			name:     "Pending by default",
+			page:     2,
+			pageSize: 10,
+			mock: func(r *mock_repository.MockModerationRepository) {
+				r.EXPECT().GetDuplicateReports(models.ReportPending, 10, 10).Return(reports, nil)
+			},
+			expectedReports: reports,
+		},
+		{
+			name:     "Page size is limited",
+			status:   models.ReportDismissed,
+			page:     0,
+			pageSize: 1000,
+			mock: func(r *mock_repository.MockModerationRepository) {
+				r.EXPECT().GetDuplicateReports(models.ReportDismissed, 0, MaxPageSize).Return(nil, nil)
+			},
+		},
+		{
+			name:        "Unknown status",
+			status:      "closed",
+			page:        1,
+			pageSize:    10,
+			mock:        func(r *mock_repository.MockModerationRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:     "Repo fail test",
+			page:     1,
+			pageSize: 10,
+			mock: func(r *mock_repository.MockModerationRepository) {
+				r.EXPECT().GetDuplicateReports(models.ReportPending, 0, 10).Return(nil, errors.New("error in repo"))
+			},
+			expectedErr: errors.New("error in repo"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockModerationRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewModerationUseCase(repo)
+			res, err := u.GetDuplicateReports(tc.status, tc.page, tc.pageSize)
+
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedReports, res)
+			} else {
+				assert.Equal(t, tc.expectedErr.Error(), errors.Cause(err).Error())
+			}
+		})
+	}
+}
+
+func TestUsecase_ResolveDuplicateReport(t *testing.T) {
+	type mock func(r *mock_repository.MockModerationRepository)
+
+	testTable := []struct {
+		name        string
+		status      string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name:   "Confirm test",
+			status: models.ReportConfirmed,
+			mock: func(r *mock_repository.MockModerationRepository) {
+				r.EXPECT().SetDuplicateReportStatus(uint64(1), models.ReportConfirmed).Return(nil)
+			},
+		},
+		{
+			name:   "Unknown report",
+			status: models.ReportDismissed,
+			mock: func(r *mock_repository.MockModerationRepository) {
+				r.EXPECT().SetDuplicateReportStatus(uint64(1), models.ReportDismissed).
+					Return(errors.Wrap(models.ErrNotFound, "database error (table duplicate_reports)"))
+			},
+			expectedErr: models.ErrNotFound,
+		},
+		{
+			name:        "Pending is not a resolution",
+			status:      models.ReportPending,
+			mock:        func(r *mock_repository.MockModerationRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockModerationRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewModerationUseCase(repo)
+			err := u.ResolveDuplicateReport(1, tc.status)
+
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"src/internal/domain/musician/repository"
 	"src/internal/models"
 	"src/internal/models/dao"
+	"src/internal/models/events"
 )
 
 type musicianRepository struct {
@@ -31,7 +32,16 @@ func (m musicianRepository) GetMusician(id uint64) (*models.Musician, error) {
 		return nil, errors.Wrap(tx.Error, "database error (table musician)")
 	}
 
-	return dao.ToModelMusician(&musician, musicianPhotots), nil
+	var followers int64
+	tx = m.db.Model(&dao.MusicianFollower{}).Where("musician_id = ?", id).Count(&followers)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table musician_followers)")
+	}
+
+	res := dao.ToModelMusician(&musician, musicianPhotots)
+	res.Followers = followers
+
+	return res, nil
 }
 
 func (m musicianRepository) GetMusicianIdForUser(userId uint64) (uint64, error) {
@@ -100,7 +110,18 @@ func (m musicianRepository) UpdateMusician(musician *models.Musician) error {
 				return err
 			}
 		}
-		return nil
+
+		var updated dao.Musician
+		if err := tx.Where("id = ?", pgMusician.ID).Take(&updated).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.MusicianUpdated, updated.ID, dao.ToMusicianPayload(&updated))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
 	})
 
 	if err != nil {
@@ -125,7 +146,12 @@ func (m musicianRepository) AddMusician(musician *models.Musician) (uint64, erro
 			return err
 		}
 
-		return nil
+		outbox, err := dao.NewOutbox(events.MusicianCreated, pgMusician.ID, dao.ToMusicianPayload(pgMusician))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
 	})
 
 	if err != nil {
@@ -136,14 +162,61 @@ func (m musicianRepository) AddMusician(musician *models.Musician) (uint64, erro
 }
 
 func (m musicianRepository) DeleteMusician(id uint64) error {
-	tx := m.db.Delete(&dao.Musician{}, id)
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var albums []*dao.Album
+		if err := tx.Find(&albums, "musician_id = ?", id).Error; err != nil {
+			return err
+		}
 
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table musician)")
-	}
+		// tracks and albums are removed by cascade, but consumers still have to know about it
+		for _, album := range albums {
+			var tracks []*dao.TrackMeta
+			if err := tx.Find(&tracks, "album_id = ?", album.ID).Error; err != nil {
+				return err
+			}
+
+			for _, v := range tracks {
+				outbox, err := dao.NewOutbox(events.TrackDeleted, v.ID, events.TrackPayload{TrackId: v.ID})
+				if err != nil {
+					return err
+				}
+
+				if err := tx.Create(outbox).Error; err != nil {
+					return err
+				}
+			}
+
+			outbox, err := dao.NewOutbox(events.AlbumDeleted, album.ID, events.AlbumPayload{AlbumId: album.ID})
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Create(outbox).Error; err != nil {
+				return err
+			}
+		}
 
-	if tx.RowsAffected == 0 {
+		res := tx.Delete(&dao.Musician{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNothingToDelete
+		}
+
+		outbox, err := dao.NewOutbox(events.MusicianDeleted, id, events.MusicianPayload{MusicianId: id})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNothingToDelete) {
 		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table musician)")
 	}
 
 	return nil
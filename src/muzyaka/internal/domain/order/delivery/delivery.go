@@ -0,0 +1,553 @@
+package delivery
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"src/internal/domain/order/payment_provider"
+	"src/internal/domain/order/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/models"
+	"src/internal/models/dto"
+	"strconv"
+)
+
+// MaxWebhookSize limits the size of a payment notification
+const MaxWebhookSize = 64 << 10
+
+// errorStatus maps usecase errors to response codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidParameter):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrAccessDenied):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrNotFound), errors.Is(err, models.ErrNothingToDelete):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrOutOfStock), errors.Is(err, models.ErrInvalidTransition):
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
+
+// @Summary GetCart
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get the cart with totals per currency
+// @ID get-cart
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Success 200 {object} dto.Cart
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/cart [get]
+func GetCart(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		cart, err := useCase.GetCart(userIDUint)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoCart(cart))
+	}
+}
+
+// @Summary SetCartItem
+// @Security ApiKeyAuth
+// @Tags user
+// @Description set the quantity of a merch variant in the cart, 0 removes it
+// @ID set-cart-item
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param variant_id path int true "merch variant ID"
+// @Param input body dto.SetCartItemRequest true "quantity, at most 99"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/cart/{variant_id} [put]
+func SetCartItem(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		variantID := chi.URLParam(r, "variant_id")
+		variantIDUint, err := strconv.ParseUint(variantID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.SetCartItemRequest
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.SetCartItem(userIDUint, variantIDUint, req.Quantity)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary RemoveCartItem
+// @Security ApiKeyAuth
+// @Tags user
+// @Description remove a merch variant from the cart
+// @ID remove-cart-item
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param variant_id path int true "merch variant ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/cart/{variant_id} [delete]
+func RemoveCartItem(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		variantID := chi.URLParam(r, "variant_id")
+		variantIDUint, err := strconv.ParseUint(variantID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.RemoveCartItem(userIDUint, variantIDUint)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary Checkout
+// @Security ApiKeyAuth
+// @Tags user
+// @Description reserve the cart and create an order with a payment for each musician and currency
+// @ID checkout
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Success 200 {object} dto.OrdersCollection
+// @Failure 400,404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/checkout [post]
+func Checkout(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		orders, err := useCase.Checkout(userIDUint)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoOrders(orders))
+	}
+}
+
+// @Summary GetUserOrders
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get orders of the user, newest first
+// @ID get-user-orders
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param        page    query     int  true  "number of page from 1"
+// @Param        page_size    query     int  true  "size of page, at most 100"
+// @Success 200 {object} dto.OrdersCollection
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/orders [get]
+func GetUserOrders(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageStr := r.URL.Query().Get("page")
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageSizeStr := r.URL.Query().Get("page_size")
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		orders, err := useCase.GetUserOrders(userIDUint, page, pageSize)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoOrders(orders))
+	}
+}
+
+// @Summary GetUserOrder
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get an order of the user
+// @ID get-user-order
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param order_id path int true "order ID"
+// @Success 200 {object} dto.Order
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/orders/{order_id} [get]
+func GetUserOrder(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		orderID := chi.URLParam(r, "order_id")
+		orderIDUint, err := strconv.ParseUint(orderID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		order, err := useCase.GetUserOrder(userIDUint, orderIDUint)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoOrder(order))
+	}
+}
+
+// @Summary CancelOrder
+// @Security ApiKeyAuth
+// @Tags user
+// @Description cancel a pending order and release its items
+// @ID cancel-order
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param order_id path int true "order ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/orders/{order_id}/cancel [post]
+func CancelOrder(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		orderID := chi.URLParam(r, "order_id")
+		orderIDUint, err := strconv.ParseUint(orderID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.CancelOrder(userIDUint, orderIDUint)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary GetMusicianOrders
+// @Security ApiKeyAuth
+// @Tags musician
+// @Description get orders of the musician's merch, newest first
+// @ID get-musician-orders
+// @Accept  json
+// @Produce  json
+// @Param musician_id path int true "musician ID"
+// @Param status query string false "pending, paid, shipped, cancelled or refunded"
+// @Param        page    query     int  true  "number of page from 1"
+// @Param        page_size    query     int  true  "size of page, at most 100"
+// @Success 200 {object} dto.OrdersCollection
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/orders [get]
+func GetMusicianOrders(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianID := chi.URLParam(r, "musician_id")
+		musicianIDUint, err := strconv.ParseUint(musicianID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageStr := r.URL.Query().Get("page")
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageSizeStr := r.URL.Query().Get("page_size")
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		orders, err := useCase.GetMusicianOrders(musicianIDUint, r.URL.Query().Get("status"), page, pageSize)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoOrders(orders))
+	}
+}
+
+// @Summary GetMusicianOrder
+// @Security ApiKeyAuth
+// @Tags musician
+// @Description get an order of the musician's merch
+// @ID get-musician-order
+// @Accept  json
+// @Produce  json
+// @Param musician_id path int true "musician ID"
+// @Param order_id path int true "order ID"
+// @Success 200 {object} dto.Order
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/orders/{order_id} [get]
+func GetMusicianOrder(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianID := chi.URLParam(r, "musician_id")
+		musicianIDUint, err := strconv.ParseUint(musicianID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		orderID := chi.URLParam(r, "order_id")
+		orderIDUint, err := strconv.ParseUint(orderID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		order, err := useCase.GetMusicianOrder(musicianIDUint, orderIDUint)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoOrder(order))
+	}
+}
+
+// @Summary UpdateOrderStatus
+// @Security ApiKeyAuth
+// @Tags musician
+// @Description ship, cancel or refund an order, refunds go through the payment provider
+// @ID update-order-status
+// @Accept  json
+// @Produce  json
+// @Param musician_id path int true "musician ID"
+// @Param order_id path int true "order ID"
+// @Param input body dto.UpdateOrderStatusRequest true "new status"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/orders/{order_id}/status [put]
+func UpdateOrderStatus(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianID := chi.URLParam(r, "musician_id")
+		musicianIDUint, err := strconv.ParseUint(musicianID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		orderID := chi.URLParam(r, "order_id")
+		orderIDUint, err := strconv.ParseUint(orderID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.UpdateOrderStatusRequest
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.UpdateOrderStatus(musicianIDUint, orderIDUint, req.Status)
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary PaymentWebhook
+// @Tags payments
+// @Description payment provider notification, signed with HMAC-SHA256 of the body
+// @ID payment-webhook
+// @Accept  json
+// @Produce  json
+// @Param X-Payment-Signature header string true "hex signature"
+// @Success 200 {object} response.Response
+// @Failure 400,401,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/payments/webhook [post]
+func PaymentWebhook(useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxWebhookSize))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.HandlePaymentWebhook(body, r.Header.Get(payment_provider.SignatureHeader))
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary FakePay
+// @Tags payments
+// @Description complete a payment of the fake provider, mounted only if payments.fake_pay_endpoint is set
+// @ID fake-pay
+// @Accept  json
+// @Produce  json
+// @Param payment_id path string true "payment ID"
+// @Param status query string false "succeeded (default) or failed"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/payments/fake/{payment_id} [post]
+func FakePay(provider *payment_provider.FakeProvider, useCase usecase.OrderUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := r.URL.Query().Get("status")
+		if status == "" {
+			status = models.PaymentSucceeded
+		}
+
+		body, signature, err := provider.Notify(chi.URLParam(r, "payment_id"), status)
+		if err == nil {
+			err = useCase.HandlePaymentWebhook(body, signature)
+		}
+
+		if err != nil {
+			render.Status(r, errorStatus(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
@@ -0,0 +1,55 @@
+package payment_provider
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"src/internal/config"
+	"src/internal/models"
+	"testing"
+)
+
+func TestFakeProvider(t *testing.T) {
+	provider := NewFakeProvider(config.Payments{WebhookSecret: "secret", PaymentUrl: "/pay/"})
+
+	payment, err := provider.CreatePayment(1, models.Price{Amount: 1999, Currency: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, "/pay/"+payment.Id, payment.Url)
+
+	body, signature, err := provider.Notify(payment.Id, models.PaymentSucceeded)
+	require.NoError(t, err)
+
+	event, err := provider.ParseWebhook(body, signature)
+	require.NoError(t, err)
+	assert.Equal(t, payment.Id, event.PaymentId)
+	assert.Equal(t, models.PaymentSucceeded, event.Status)
+	assert.NotEmpty(t, event.Id)
+
+	_, err = provider.ParseWebhook(body, sign([]byte("other"), body))
+	assert.True(t, errors.Is(err, models.ErrAccessDenied))
+
+	_, err = provider.ParseWebhook(body, "not hex")
+	assert.True(t, errors.Is(err, models.ErrAccessDenied))
+
+	malformed := []byte(`{"id":"1","payment_id":"x","status":"lost"}`)
+	_, err = provider.ParseWebhook(malformed, sign([]byte("secret"), malformed))
+	assert.True(t, errors.Is(err, models.ErrInvalidParameter))
+
+	require.NoError(t, provider.Refund(payment.Id))
+	require.NoError(t, provider.Refund(payment.Id))
+
+	assert.True(t, errors.Is(provider.Refund("unknown"), models.ErrNotFound))
+
+	_, _, err = provider.Notify("unknown", models.PaymentSucceeded)
+	assert.True(t, errors.Is(err, models.ErrNotFound))
+
+	// payments are known after a restart, but not to a provider with another secret
+	restarted := NewFakeProvider(config.Payments{WebhookSecret: "secret", PaymentUrl: "/pay/"})
+	_, _, err = restarted.Notify(payment.Id, models.PaymentFailed)
+	assert.NoError(t, err)
+
+	other := NewFakeProvider(config.Payments{WebhookSecret: "other", PaymentUrl: "/pay/"})
+	_, _, err = other.Notify(payment.Id, models.PaymentSucceeded)
+	assert.True(t, errors.Is(err, models.ErrNotFound))
+	assert.True(t, errors.Is(other.Refund(payment.Id), models.ErrNotFound))
+}
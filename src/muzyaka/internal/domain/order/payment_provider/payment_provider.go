@@ -0,0 +1,46 @@
+package payment_provider
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"src/internal/models"
+)
+
+const SignatureHeader = "X-Payment-Signature"
+
+//go:generate mockgen -source=payment_provider.go -destination=mocks/mock.go
+type PaymentProvider interface {
+	// CreatePayment starts a payment of amount for the order
+	CreatePayment(orderId uint64, amount models.Price) (*models.Payment, error)
+	// Refund returns the money of a payment, refunding it twice is not an error
+	Refund(paymentId string) error
+	// ParseWebhook checks the signature of a notification and decodes it.
+	// Returns models.ErrAccessDenied for a wrong signature.
+	ParseWebhook(body []byte, signature string) (*models.PaymentEvent, error)
+}
+
+// notification is the body of a webhook request
+type notification struct {
+	Id        string `json:"id"`
+	PaymentId string `json:"payment_id"`
+	Status    string `json:"status"`
+}
+
+// sign returns the hex HMAC-SHA256 of body
+func sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func verify(secret []byte, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
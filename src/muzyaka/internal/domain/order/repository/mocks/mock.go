@@ -0,0 +1,196 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrderRepository is a mock of OrderRepository interface.
+type MockOrderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderRepositoryMockRecorder
+}
+
+// MockOrderRepositoryMockRecorder is the mock recorder for MockOrderRepository.
+type MockOrderRepositoryMockRecorder struct {
+	mock *MockOrderRepository
+}
+
+// NewMockOrderRepository creates a new mock instance.
+func NewMockOrderRepository(ctrl *gomock.Controller) *MockOrderRepository {
+	mock := &MockOrderRepository{ctrl: ctrl}
+	mock.recorder = &MockOrderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ApplyPaymentEvent mocks base method.
+func (m *MockOrderRepository) ApplyPaymentEvent(event *models.PaymentEvent, refund func(string) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyPaymentEvent", event, refund)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyPaymentEvent indicates an expected call of ApplyPaymentEvent.
+func (mr *MockOrderRepositoryMockRecorder) ApplyPaymentEvent(event, refund interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyPaymentEvent", reflect.TypeOf((*MockOrderRepository)(nil).ApplyPaymentEvent), event, refund)
+}
+
+// CreateOrders mocks base method.
+func (m *MockOrderRepository) CreateOrders(userId uint64) ([]*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrders", userId)
+	ret0, _ := ret[0].([]*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrders indicates an expected call of CreateOrders.
+func (mr *MockOrderRepositoryMockRecorder) CreateOrders(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrders", reflect.TypeOf((*MockOrderRepository)(nil).CreateOrders), userId)
+}
+
+// ExpireOrders mocks base method.
+func (m *MockOrderRepository) ExpireOrders(createdBefore time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireOrders", createdBefore, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireOrders indicates an expected call of ExpireOrders.
+func (mr *MockOrderRepositoryMockRecorder) ExpireOrders(createdBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireOrders", reflect.TypeOf((*MockOrderRepository)(nil).ExpireOrders), createdBefore, limit)
+}
+
+// GetCart mocks base method.
+func (m *MockOrderRepository) GetCart(userId uint64) ([]*models.CartItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCart", userId)
+	ret0, _ := ret[0].([]*models.CartItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCart indicates an expected call of GetCart.
+func (mr *MockOrderRepositoryMockRecorder) GetCart(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCart", reflect.TypeOf((*MockOrderRepository)(nil).GetCart), userId)
+}
+
+// GetMusicianOrders mocks base method.
+func (m *MockOrderRepository) GetMusicianOrders(musicianId uint64, status string, offset, limit int) ([]*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMusicianOrders", musicianId, status, offset, limit)
+	ret0, _ := ret[0].([]*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMusicianOrders indicates an expected call of GetMusicianOrders.
+func (mr *MockOrderRepositoryMockRecorder) GetMusicianOrders(musicianId, status, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMusicianOrders", reflect.TypeOf((*MockOrderRepository)(nil).GetMusicianOrders), musicianId, status, offset, limit)
+}
+
+// GetOrder mocks base method.
+func (m *MockOrderRepository) GetOrder(orderId uint64) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", orderId)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrderRepositoryMockRecorder) GetOrder(orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderRepository)(nil).GetOrder), orderId)
+}
+
+// GetUserOrders mocks base method.
+func (m *MockOrderRepository) GetUserOrders(userId uint64, offset, limit int) ([]*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOrders", userId, offset, limit)
+	ret0, _ := ret[0].([]*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserOrders indicates an expected call of GetUserOrders.
+func (mr *MockOrderRepositoryMockRecorder) GetUserOrders(userId, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOrders", reflect.TypeOf((*MockOrderRepository)(nil).GetUserOrders), userId, offset, limit)
+}
+
+// RemoveCartItem mocks base method.
+func (m *MockOrderRepository) RemoveCartItem(userId, variantId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCartItem", userId, variantId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCartItem indicates an expected call of RemoveCartItem.
+func (mr *MockOrderRepositoryMockRecorder) RemoveCartItem(userId, variantId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCartItem", reflect.TypeOf((*MockOrderRepository)(nil).RemoveCartItem), userId, variantId)
+}
+
+// SetCartItem mocks base method.
+func (m *MockOrderRepository) SetCartItem(userId, variantId uint64, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCartItem", userId, variantId, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCartItem indicates an expected call of SetCartItem.
+func (mr *MockOrderRepositoryMockRecorder) SetCartItem(userId, variantId, quantity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCartItem", reflect.TypeOf((*MockOrderRepository)(nil).SetCartItem), userId, variantId, quantity)
+}
+
+// SetOrderPayment mocks base method.
+func (m *MockOrderRepository) SetOrderPayment(orderId uint64, payment *models.Payment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOrderPayment", orderId, payment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOrderPayment indicates an expected call of SetOrderPayment.
+func (mr *MockOrderRepositoryMockRecorder) SetOrderPayment(orderId, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrderPayment", reflect.TypeOf((*MockOrderRepository)(nil).SetOrderPayment), orderId, payment)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderRepository) UpdateOrderStatus(orderId uint64, from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", orderId, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderRepositoryMockRecorder) UpdateOrderStatus(orderId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdateOrderStatus), orderId, from, to)
+}
@@ -0,0 +1,432 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"src/internal/domain/order/repository"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"src/internal/models/events"
+	"time"
+)
+
+type orderRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) repository.OrderRepository {
+	return &orderRepository{db: db}
+}
+
+const cartQuery = `
+	SELECT v.*, m.name AS merch_name, m.musician_id, c.quantity
+	FROM cart_items c
+	         JOIN merch_variants v ON v.id = c.variant_id
+	         JOIN merch m ON m.id = v.merch_id
+	WHERE c.user_id = ?
+	ORDER BY v.id`
+
+func (o *orderRepository) GetCart(userId uint64) ([]*models.CartItem, error) {
+	var lines []*dao.CartLine
+	if err := o.db.Raw(cartQuery, userId).Scan(&lines).Error; err != nil {
+		return nil, errors.Wrap(err, "database error (table cart_items)")
+	}
+
+	var res []*models.CartItem
+	for _, v := range lines {
+		res = append(res, dao.ToModelCartItem(v))
+	}
+
+	return res, nil
+}
+
+func (o *orderRepository) SetCartItem(userId uint64, variantId uint64, quantity int) error {
+	err := o.db.Transaction(func(tx *gorm.DB) error {
+		var variant dao.MerchVariant
+		if err := tx.Where("id = ?", variantId).Take(&variant).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "variant_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"quantity"}),
+		}).Create(&dao.CartItem{UserId: userId, VariantId: variantId, Quantity: quantity}).Error
+	})
+
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table cart_items)")
+	}
+
+	return nil
+}
+
+func (o *orderRepository) RemoveCartItem(userId uint64, variantId uint64) error {
+	res := o.db.Where("user_id = ? AND variant_id = ?", userId, variantId).Delete(&dao.CartItem{})
+	if res.Error != nil {
+		return errors.Wrap(res.Error, "database error (table cart_items)")
+	}
+
+	if res.RowsAffected == 0 {
+		return models.ErrNothingToDelete
+	}
+
+	return nil
+}
+
+type orderKey struct {
+	musicianId uint64
+	currency   string
+}
+
+func (o *orderRepository) CreateOrders(userId uint64) ([]*models.Order, error) {
+	var res []*models.Order
+
+	err := o.db.Transaction(func(tx *gorm.DB) error {
+		// variants are locked in id order, so concurrent checkouts can't deadlock.
+		// A concurrent checkout of the same cart waits for the lock and finds
+		// the cart empty.
+		var lines []*dao.CartLine
+		if err := tx.Raw(cartQuery+" FOR UPDATE OF c, v", userId).Scan(&lines).Error; err != nil {
+			return err
+		}
+
+		var keys []orderKey
+		groups := make(map[orderKey][]*dao.CartLine)
+		for _, v := range lines {
+			if v.Stock-v.Reserved < v.Quantity {
+				return errors.Wrapf(models.ErrOutOfStock, "sku %s", v.Sku)
+			}
+
+			if err := tx.Model(&dao.MerchVariant{}).Where("id = ?", v.ID).
+				Update("reserved", gorm.Expr("reserved + ?", v.Quantity)).Error; err != nil {
+				return err
+			}
+
+			key := orderKey{musicianId: v.MusicianId, currency: v.Currency}
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], v)
+		}
+
+		for _, key := range keys {
+			order := &dao.Order{
+				UserId:     &userId,
+				MusicianId: key.musicianId,
+				Status:     models.OrderPending,
+				Currency:   key.currency,
+			}
+			for _, v := range groups[key] {
+				order.Total += v.Price * int64(v.Quantity)
+			}
+
+			if err := tx.Create(order).Error; err != nil {
+				return err
+			}
+
+			var items []*dao.OrderItem
+			for _, v := range groups[key] {
+				items = append(items, dao.ToPostgresOrderItem(v, order.ID))
+			}
+			if err := tx.Omit("id").Create(&items).Error; err != nil {
+				return err
+			}
+
+			outbox, err := dao.NewOutbox(events.OrderCreated, order.ID, dao.ToOrderPayload(order))
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(outbox).Error; err != nil {
+				return err
+			}
+
+			res = append(res, dao.ToModelOrder(order, items))
+		}
+
+		return tx.Where("user_id = ?", userId).Delete(&dao.CartItem{}).Error
+	})
+
+	if errors.Is(err, models.ErrOutOfStock) {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Wrap(err, "database error (table orders)")
+	}
+
+	return res, nil
+}
+
+func (o *orderRepository) SetOrderPayment(orderId uint64, payment *models.Payment) error {
+	tx := o.db.Model(&dao.Order{}).Where("id = ?", orderId).Updates(map[string]interface{}{
+		"payment_id":  payment.Id,
+		"payment_url": payment.Url,
+		"updated_at":  gorm.Expr("now()"),
+	})
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "database error (table orders)")
+	}
+
+	if tx.RowsAffected == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// loadOrders fetches the items of the orders
+func (o *orderRepository) loadOrders(orders []*dao.Order) ([]*models.Order, error) {
+	var res []*models.Order
+
+	for _, v := range orders {
+		var items []*dao.OrderItem
+		if err := o.db.Where("order_id = ?", v.ID).Order("id").Find(&items).Error; err != nil {
+			return nil, err
+		}
+
+		res = append(res, dao.ToModelOrder(v, items))
+	}
+
+	return res, nil
+}
+
+func (o *orderRepository) GetOrder(orderId uint64) (*models.Order, error) {
+	var order dao.Order
+
+	tx := o.db.Where("id = ?", orderId).Take(&order)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	} else if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table orders)")
+	}
+
+	res, err := o.loadOrders([]*dao.Order{&order})
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table order_items)")
+	}
+
+	return res[0], nil
+}
+
+func (o *orderRepository) GetUserOrders(userId uint64, offset int, limit int) ([]*models.Order, error) {
+	var orders []*dao.Order
+
+	tx := o.db.Where("user_id = ?", userId).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&orders)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table orders)")
+	}
+
+	res, err := o.loadOrders(orders)
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table order_items)")
+	}
+
+	return res, nil
+}
+
+func (o *orderRepository) GetMusicianOrders(musicianId uint64, status string, offset int, limit int) ([]*models.Order, error) {
+	var orders []*dao.Order
+
+	tx := o.db.Where("musician_id = ?", musicianId)
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+
+	tx = tx.Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&orders)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table orders)")
+	}
+
+	res, err := o.loadOrders(orders)
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table order_items)")
+	}
+
+	return res, nil
+}
+
+func (o *orderRepository) UpdateOrderStatus(orderId uint64, from string, to string) error {
+	err := o.db.Transaction(func(tx *gorm.DB) error {
+		var order dao.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", orderId).Take(&order).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if order.Status != from {
+			return models.ErrInvalidTransition
+		}
+
+		return changeOrderStatus(tx, &order, to)
+	})
+
+	if errors.Is(err, models.ErrNotFound) || errors.Is(err, models.ErrInvalidTransition) {
+		return err
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table orders)")
+	}
+
+	return nil
+}
+
+func (o *orderRepository) ExpireOrders(createdBefore time.Time, limit int) (int, error) {
+	var count int
+
+	err := o.db.Transaction(func(tx *gorm.DB) error {
+		// orders locked by a payment or a user are expired with the next call
+		var orders []*dao.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND created_at < ?", models.OrderPending, createdBefore).
+			Order("id").
+			Limit(limit).
+			Find(&orders).Error; err != nil {
+			return err
+		}
+
+		for _, v := range orders {
+			if err := changeOrderStatus(tx, v, models.OrderCancelled); err != nil {
+				return err
+			}
+		}
+		count = len(orders)
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, errors.Wrap(err, "database error (table orders)")
+	}
+
+	return count, nil
+}
+
+func (o *orderRepository) ApplyPaymentEvent(event *models.PaymentEvent, refund func(paymentId string) error) error {
+	refundPending := false
+
+	err := o.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&dao.PaymentEvent{
+			EventId:   event.Id,
+			PaymentId: event.PaymentId,
+			Status:    event.Status,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			// a redelivery retries the refund that failed before
+			var stored dao.PaymentEvent
+			if err := tx.Where("event_id = ?", event.Id).Take(&stored).Error; err != nil {
+				return err
+			}
+
+			if !stored.RefundPending {
+				return models.ErrAlreadyProcessed
+			}
+			refundPending = true
+
+			return nil
+		}
+
+		var order dao.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("payment_id = ?", event.PaymentId).Take(&order).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if order.Status != models.OrderPending {
+			// the order was cancelled before the user paid for it
+			if event.Status == models.PaymentSucceeded && order.Status == models.OrderCancelled {
+				refundPending = true
+				return tx.Model(&dao.PaymentEvent{}).Where("event_id = ?", event.Id).
+					Update("refund_pending", true).Error
+			}
+			return nil
+		}
+
+		to := models.OrderPaid
+		if event.Status == models.PaymentFailed {
+			to = models.OrderCancelled
+		}
+
+		return changeOrderStatus(tx, &order, to)
+	})
+
+	if errors.Is(err, models.ErrAlreadyProcessed) || errors.Is(err, models.ErrNotFound) {
+		return err
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table payment_events)")
+	}
+
+	if !refundPending {
+		return nil
+	}
+
+	// the provider is called without holding the order, refunding twice is harmless
+	if err := refund(event.PaymentId); err != nil {
+		return err
+	}
+
+	err = o.db.Model(&dao.PaymentEvent{}).Where("event_id = ?", event.Id).
+		Update("refund_pending", false).Error
+	if err != nil {
+		return errors.Wrap(err, "database error (table payment_events)")
+	}
+
+	return nil
+}
+
+// changeOrderStatus updates the locked order and releases its reserved items
+// once they are shipped or returned to stock
+func changeOrderStatus(tx *gorm.DB, order *dao.Order, to string) error {
+	from := order.Status
+
+	if err := tx.Model(order).Updates(map[string]interface{}{
+		"status":     to,
+		"updated_at": gorm.Expr("now()"),
+	}).Error; err != nil {
+		return err
+	}
+	order.Status = to
+
+	// shipped items leave the stock, which may be set below them meanwhile.
+	// Items of deleted variants are lost.
+	var set string
+	switch {
+	case models.Restocks(from, to):
+		set = "reserved = v.reserved - i.quantity"
+	case to == models.OrderShipped:
+		set = "stock = GREATEST(v.stock - i.quantity, 0), reserved = v.reserved - i.quantity"
+	}
+
+	if set != "" {
+		if err := tx.Exec(`
+			UPDATE merch_variants v
+			SET `+set+`
+			FROM order_items i
+			WHERE i.order_id = ? AND v.id = i.variant_id`, order.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	outbox, err := dao.NewOutbox(events.OrderStatusChanged, order.ID, dao.ToOrderPayload(order))
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(outbox).Error
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+type OrderRepository interface {
+	GetCart(userId uint64) ([]*models.CartItem, error)
+	// SetCartItem replaces the quantity of the variant in the cart
+	SetCartItem(userId uint64, variantId uint64, quantity int) error
+	RemoveCartItem(userId uint64, variantId uint64) error
+
+	// CreateOrders reserves the items of the cart, creates a pending order
+	// for each musician and currency and empties the cart. Returns
+	// models.ErrOutOfStock if a variant has fewer items in stock than the
+	// cart holds.
+	CreateOrders(userId uint64) ([]*models.Order, error)
+	SetOrderPayment(orderId uint64, payment *models.Payment) error
+
+	GetOrder(orderId uint64) (*models.Order, error)
+	GetUserOrders(userId uint64, offset int, limit int) ([]*models.Order, error)
+	// GetMusicianOrders returns orders in any status if status is empty
+	GetMusicianOrders(musicianId uint64, status string, offset int, limit int) ([]*models.Order, error)
+
+	// UpdateOrderStatus returns models.ErrInvalidTransition if the order is no
+	// longer in status from. Items go back to stock if models.Restocks says so.
+	UpdateOrderStatus(orderId uint64, from string, to string) error
+	// ExpireOrders cancels up to limit pending orders created before
+	// createdBefore and returns how many were cancelled
+	ExpireOrders(createdBefore time.Time, limit int) (int, error)
+
+	// ApplyPaymentEvent records the event and pays or cancels the pending order
+	// of the payment. If a payment of a cancelled order succeeded, the event is
+	// recorded as waiting for a refund and refund is called after the commit,
+	// a failed refund is retried with the next delivery. Returns
+	// models.ErrAlreadyProcessed for events seen before.
+	ApplyPaymentEvent(event *models.PaymentEvent, refund func(paymentId string) error) error
+}
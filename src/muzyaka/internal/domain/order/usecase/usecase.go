@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"github.com/pkg/errors"
+	"src/internal/domain/order/payment_provider"
+	"src/internal/domain/order/repository"
+	"src/internal/models"
+)
+
+const MinPageSize = 10
+const MaxPageSize = 100
+
+type OrderUseCase interface {
+	GetCart(userId uint64) (*models.Cart, error)
+	// SetCartItem removes the variant from the cart if quantity is 0
+	SetCartItem(userId uint64, variantId uint64, quantity int) error
+	RemoveCartItem(userId uint64, variantId uint64) error
+
+	// Checkout turns the cart into pending orders, one per musician and
+	// currency, and starts a payment for each of them
+	Checkout(userId uint64) ([]*models.Order, error)
+
+	GetUserOrder(userId uint64, orderId uint64) (*models.Order, error)
+	GetUserOrders(userId uint64, page int, pageSize int) ([]*models.Order, error)
+	// CancelOrder cancels a pending order of the user
+	CancelOrder(userId uint64, orderId uint64) error
+
+	GetMusicianOrder(musicianId uint64, orderId uint64) (*models.Order, error)
+	GetMusicianOrders(musicianId uint64, status string, page int, pageSize int) ([]*models.Order, error)
+	// UpdateOrderStatus refunds the payment when the order is refunded
+	UpdateOrderStatus(musicianId uint64, orderId uint64, status string) error
+
+	// HandlePaymentWebhook applies a payment notification, notifications
+	// delivered more than once are applied once. A notification failed to
+	// apply is applied again when the provider retries it.
+	HandlePaymentWebhook(body []byte, signature string) error
+}
+
+type usecase struct {
+	orderRep repository.OrderRepository
+	payments payment_provider.PaymentProvider
+}
+
+func NewOrderUseCase(orderRep repository.OrderRepository, payments payment_provider.PaymentProvider) OrderUseCase {
+	return &usecase{orderRep: orderRep, payments: payments}
+}
+
+func pageOffset(page int, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+
+	switch {
+	case pageSize > MaxPageSize:
+		pageSize = MaxPageSize
+	case pageSize < MinPageSize:
+		pageSize = MinPageSize
+	}
+
+	return (page - 1) * pageSize, pageSize
+}
+
+func (u *usecase) GetCart(userId uint64) (*models.Cart, error) {
+	items, err := u.orderRep.GetCart(userId)
+	if err != nil {
+		return nil, errors.Wrap(err, "order.usecase.GetCart error while get")
+	}
+
+	cart := &models.Cart{Items: items}
+
+	totals := make(map[string]int)
+	for _, v := range items {
+		price := v.Variant.Price
+		i, ok := totals[price.Currency]
+		if !ok {
+			i = len(cart.Totals)
+			totals[price.Currency] = i
+			cart.Totals = append(cart.Totals, models.Price{Currency: price.Currency})
+		}
+		cart.Totals[i].Amount += price.Amount * int64(v.Quantity)
+	}
+
+	return cart, nil
+}
+
+func (u *usecase) SetCartItem(userId uint64, variantId uint64, quantity int) error {
+	if quantity < 0 || quantity > models.MaxCartQuantity {
+		return models.ErrInvalidParameter
+	}
+
+	if quantity == 0 {
+		err := u.orderRep.RemoveCartItem(userId, variantId)
+		if err != nil && !errors.Is(err, models.ErrNothingToDelete) {
+			return errors.Wrap(err, "order.usecase.SetCartItem error while remove")
+		}
+
+		return nil
+	}
+
+	if err := u.orderRep.SetCartItem(userId, variantId, quantity); err != nil {
+		return errors.Wrap(err, "order.usecase.SetCartItem error while set")
+	}
+
+	return nil
+}
+
+func (u *usecase) RemoveCartItem(userId uint64, variantId uint64) error {
+	if err := u.orderRep.RemoveCartItem(userId, variantId); err != nil {
+		return errors.Wrap(err, "order.usecase.RemoveCartItem error while remove")
+	}
+
+	return nil
+}
+
+func (u *usecase) Checkout(userId uint64) ([]*models.Order, error) {
+	orders, err := u.orderRep.CreateOrders(userId)
+	if err != nil {
+		return nil, errors.Wrap(err, "order.usecase.Checkout error while create orders")
+	}
+
+	if len(orders) == 0 {
+		return nil, errors.Wrap(models.ErrInvalidParameter, "cart is empty")
+	}
+
+	for _, order := range orders {
+		payment, err := u.payments.CreatePayment(order.Id, order.Total)
+		if err == nil {
+			err = u.orderRep.SetOrderPayment(order.Id, payment)
+		}
+
+		if err != nil {
+			// the items go back to the cart, so the user can try again
+			u.cancelAll(userId, orders)
+			return nil, errors.Wrap(err, "order.usecase.Checkout error while create payment")
+		}
+
+		order.PaymentId = payment.Id
+		order.PaymentUrl = payment.Url
+	}
+
+	return orders, nil
+}
+
+// cancelAll releases the items of orders that can't be paid and puts them back
+// to the cart, payments already created are refunded by HandlePaymentWebhook
+// if they are completed anyway
+func (u *usecase) cancelAll(userId uint64, orders []*models.Order) {
+	for _, order := range orders {
+		if err := u.orderRep.UpdateOrderStatus(order.Id, models.OrderPending, models.OrderCancelled); err != nil {
+			continue
+		}
+
+		for _, v := range order.Items {
+			_ = u.orderRep.SetCartItem(userId, v.VariantId, v.Quantity)
+		}
+	}
+}
+
+func (u *usecase) GetUserOrder(userId uint64, orderId uint64) (*models.Order, error) {
+	order, err := u.orderRep.GetOrder(orderId)
+	if err != nil {
+		return nil, errors.Wrap(err, "order.usecase.GetUserOrder error while get")
+	}
+
+	if order.UserId != userId {
+		return nil, models.ErrNotFound
+	}
+
+	return order, nil
+}
+
+func (u *usecase) GetUserOrders(userId uint64, page int, pageSize int) ([]*models.Order, error) {
+	offset, limit := pageOffset(page, pageSize)
+
+	orders, err := u.orderRep.GetUserOrders(userId, offset, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "order.usecase.GetUserOrders error while get")
+	}
+
+	return orders, nil
+}
+
+func (u *usecase) CancelOrder(userId uint64, orderId uint64) error {
+	order, err := u.GetUserOrder(userId, orderId)
+	if err != nil {
+		return err
+	}
+
+	// paid orders are refunded by the musician
+	if order.Status != models.OrderPending {
+		return models.ErrInvalidTransition
+	}
+
+	if err := u.orderRep.UpdateOrderStatus(orderId, order.Status, models.OrderCancelled); err != nil {
+		return errors.Wrap(err, "order.usecase.CancelOrder error while update")
+	}
+
+	return nil
+}
+
+func (u *usecase) GetMusicianOrder(musicianId uint64, orderId uint64) (*models.Order, error) {
+	order, err := u.orderRep.GetOrder(orderId)
+	if err != nil {
+		return nil, errors.Wrap(err, "order.usecase.GetMusicianOrder error while get")
+	}
+
+	if order.MusicianId != musicianId {
+		return nil, models.ErrNotFound
+	}
+
+	return order, nil
+}
+
+func (u *usecase) GetMusicianOrders(musicianId uint64, status string, page int, pageSize int) ([]*models.Order, error) {
+	if status != "" && !models.OrderStatuses[status] {
+		return nil, models.ErrInvalidParameter
+	}
+
+	offset, limit := pageOffset(page, pageSize)
+
+	orders, err := u.orderRep.GetMusicianOrders(musicianId, status, offset, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "order.usecase.GetMusicianOrders error while get")
+	}
+
+	return orders, nil
+}
+
+func (u *usecase) UpdateOrderStatus(musicianId uint64, orderId uint64, status string) error {
+	order, err := u.GetMusicianOrder(musicianId, orderId)
+	if err != nil {
+		return err
+	}
+
+	// orders are paid through the payment provider only
+	if status == models.OrderPaid || !models.CanTransition(order.Status, status) {
+		return models.ErrInvalidTransition
+	}
+
+	// refunded before the status changes, refunding twice is harmless
+	if status == models.OrderRefunded && order.PaymentId != "" {
+		if err := u.payments.Refund(order.PaymentId); err != nil {
+			return errors.Wrap(err, "order.usecase.UpdateOrderStatus error while refund")
+		}
+	}
+
+	if err := u.orderRep.UpdateOrderStatus(orderId, order.Status, status); err != nil {
+		return errors.Wrap(err, "order.usecase.UpdateOrderStatus error while update")
+	}
+
+	return nil
+}
+
+func (u *usecase) HandlePaymentWebhook(body []byte, signature string) error {
+	event, err := u.payments.ParseWebhook(body, signature)
+	if err != nil {
+		return errors.Wrap(err, "order.usecase.HandlePaymentWebhook error while parse")
+	}
+
+	// payments of cancelled orders are refunded
+	err = u.orderRep.ApplyPaymentEvent(event, u.payments.Refund)
+	if errors.Is(err, models.ErrAlreadyProcessed) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "order.usecase.HandlePaymentWebhook error while apply")
+	}
+
+	return nil
+}
@@ -0,0 +1,418 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock_payment_provider "src/internal/domain/order/payment_provider/mocks"
+	mock_repository "src/internal/domain/order/repository/mocks"
+	"src/internal/models"
+	"testing"
+)
+
+func TestUsecase_GetCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	items := []*models.CartItem{
+		{MerchId: 1, Variant: &models.MerchVariant{Id: 1, Price: models.Price{Amount: 1999, Currency: "USD"}}, Quantity: 2},
+		{MerchId: 2, Variant: &models.MerchVariant{Id: 2, Price: models.Price{Amount: 1500, Currency: "EUR"}}, Quantity: 1},
+		{MerchId: 3, Variant: &models.MerchVariant{Id: 3, Price: models.Price{Amount: 500, Currency: "USD"}}, Quantity: 3},
+	}
+
+	repo := mock_repository.NewMockOrderRepository(ctrl)
+	repo.EXPECT().GetCart(uint64(1)).Return(items, nil)
+
+	u := NewOrderUseCase(repo, mock_payment_provider.NewMockPaymentProvider(ctrl))
+	cart, err := u.GetCart(1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, items, cart.Items)
+	assert.Equal(t, []models.Price{{Amount: 5498, Currency: "USD"}, {Amount: 1500, Currency: "EUR"}}, cart.Totals)
+}
+
+func TestUsecase_SetCartItem(t *testing.T) {
+	type mock func(r *mock_repository.MockOrderRepository)
+
+	testTable := []struct {
+		name        string
+		quantity    int
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name:     "Usual test",
+			quantity: 2,
+			mock: func(r *mock_repository.MockOrderRepository) {
+				r.EXPECT().SetCartItem(uint64(1), uint64(5), 2).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "Zero removes test",
+			quantity: 0,
+			mock: func(r *mock_repository.MockOrderRepository) {
+				r.EXPECT().RemoveCartItem(uint64(1), uint64(5)).Return(models.ErrNothingToDelete)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "Too many test",
+			quantity:    models.MaxCartQuantity + 1,
+			mock:        func(r *mock_repository.MockOrderRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:     "Unknown variant test",
+			quantity: 1,
+			mock: func(r *mock_repository.MockOrderRepository) {
+				r.EXPECT().SetCartItem(uint64(1), uint64(5), 1).Return(models.ErrNotFound)
+			},
+			expectedErr: errors.Wrap(models.ErrNotFound, "order.usecase.SetCartItem error while set"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init Dependencies
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockOrderRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewOrderUseCase(repo, mock_payment_provider.NewMockPaymentProvider(ctrl))
+			err := u.SetCartItem(1, 5, tc.quantity)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, tc.expectedErr.Error(), err.Error())
+			}
+		})
+	}
+}
+
+func TestUsecase_Checkout(t *testing.T) {
+	type mock func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider)
+
+	newOrders := func() []*models.Order {
+		return []*models.Order{
+			{Id: 1, UserId: 1, MusicianId: 1, Status: models.OrderPending, Total: models.Price{Amount: 1999, Currency: "USD"},
+				Items: []*models.OrderItem{{VariantId: 7, Quantity: 2}}},
+			{Id: 2, UserId: 1, MusicianId: 2, Status: models.OrderPending, Total: models.Price{Amount: 1500, Currency: "EUR"},
+				Items: []*models.OrderItem{{VariantId: 8, Quantity: 1}}},
+		}
+	}
+
+	testTable := []struct {
+		name           string
+		mock           mock
+		expectedOrders []*models.Order
+		expectedErr    error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				r.EXPECT().CreateOrders(uint64(1)).Return(newOrders(), nil)
+				p.EXPECT().CreatePayment(uint64(1), models.Price{Amount: 1999, Currency: "USD"}).
+					Return(&models.Payment{Id: "p1", Url: "/pay/p1"}, nil)
+				r.EXPECT().SetOrderPayment(uint64(1), &models.Payment{Id: "p1", Url: "/pay/p1"}).Return(nil)
+				p.EXPECT().CreatePayment(uint64(2), models.Price{Amount: 1500, Currency: "EUR"}).
+					Return(&models.Payment{Id: "p2", Url: "/pay/p2"}, nil)
+				r.EXPECT().SetOrderPayment(uint64(2), &models.Payment{Id: "p2", Url: "/pay/p2"}).Return(nil)
+			},
+			expectedOrders: []*models.Order{
+				{Id: 1, UserId: 1, MusicianId: 1, Status: models.OrderPending, Total: models.Price{Amount: 1999, Currency: "USD"},
+					Items: []*models.OrderItem{{VariantId: 7, Quantity: 2}}, PaymentId: "p1", PaymentUrl: "/pay/p1"},
+				{Id: 2, UserId: 1, MusicianId: 2, Status: models.OrderPending, Total: models.Price{Amount: 1500, Currency: "EUR"},
+					Items: []*models.OrderItem{{VariantId: 8, Quantity: 1}}, PaymentId: "p2", PaymentUrl: "/pay/p2"},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Empty cart test",
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				r.EXPECT().CreateOrders(uint64(1)).Return(nil, nil)
+			},
+			expectedOrders: nil,
+			expectedErr:    errors.Wrap(models.ErrInvalidParameter, "cart is empty"),
+		},
+		{
+			name: "Out of stock test",
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				r.EXPECT().CreateOrders(uint64(1)).Return(nil, models.ErrOutOfStock)
+			},
+			expectedOrders: nil,
+			expectedErr:    errors.Wrap(models.ErrOutOfStock, "order.usecase.Checkout error while create orders"),
+		},
+		{
+			name: "Payment fail test",
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				r.EXPECT().CreateOrders(uint64(1)).Return(newOrders(), nil)
+				p.EXPECT().CreatePayment(uint64(1), gomock.Any()).Return(&models.Payment{Id: "p1"}, nil)
+				r.EXPECT().SetOrderPayment(uint64(1), gomock.Any()).Return(nil)
+				p.EXPECT().CreatePayment(uint64(2), gomock.Any()).Return(nil, errors.New("provider is down"))
+				r.EXPECT().UpdateOrderStatus(uint64(1), models.OrderPending, models.OrderCancelled).Return(nil)
+				r.EXPECT().SetCartItem(uint64(1), uint64(7), 2).Return(nil)
+				r.EXPECT().UpdateOrderStatus(uint64(2), models.OrderPending, models.OrderCancelled).Return(nil)
+				r.EXPECT().SetCartItem(uint64(1), uint64(8), 1).Return(nil)
+			},
+			expectedOrders: nil,
+			expectedErr: errors.Wrap(errors.New("provider is down"),
+				"order.usecase.Checkout error while create payment"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init Dependencies
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockOrderRepository(ctrl)
+			payments := mock_payment_provider.NewMockPaymentProvider(ctrl)
+			tc.mock(repo, payments)
+
+			u := NewOrderUseCase(repo, payments)
+			orders, err := u.Checkout(1)
+
+			assert.Equal(t, tc.expectedOrders, orders)
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, tc.expectedErr.Error(), err.Error())
+			}
+		})
+	}
+}
+
+func TestUsecase_CancelOrder(t *testing.T) {
+	type mock func(r *mock_repository.MockOrderRepository)
+
+	testTable := []struct {
+		name        string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockOrderRepository) {
+				r.EXPECT().GetOrder(uint64(3)).Return(&models.Order{Id: 3, UserId: 1, Status: models.OrderPending}, nil)
+				r.EXPECT().UpdateOrderStatus(uint64(3), models.OrderPending, models.OrderCancelled).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Other user test",
+			mock: func(r *mock_repository.MockOrderRepository) {
+				r.EXPECT().GetOrder(uint64(3)).Return(&models.Order{Id: 3, UserId: 2, Status: models.OrderPending}, nil)
+			},
+			expectedErr: models.ErrNotFound,
+		},
+		{
+			name: "Paid test",
+			mock: func(r *mock_repository.MockOrderRepository) {
+				r.EXPECT().GetOrder(uint64(3)).Return(&models.Order{Id: 3, UserId: 1, Status: models.OrderPaid}, nil)
+			},
+			expectedErr: models.ErrInvalidTransition,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init Dependencies
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockOrderRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewOrderUseCase(repo, mock_payment_provider.NewMockPaymentProvider(ctrl))
+			err := u.CancelOrder(1, 3)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, tc.expectedErr.Error(), err.Error())
+			}
+		})
+	}
+}
+
+func TestUsecase_UpdateOrderStatus(t *testing.T) {
+	type mock func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider)
+
+	testTable := []struct {
+		name        string
+		status      string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name:   "Ship test",
+			status: models.OrderShipped,
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				r.EXPECT().GetOrder(uint64(3)).Return(&models.Order{Id: 3, MusicianId: 1, Status: models.OrderPaid}, nil)
+				r.EXPECT().UpdateOrderStatus(uint64(3), models.OrderPaid, models.OrderShipped).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "Refund test",
+			status: models.OrderRefunded,
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				r.EXPECT().GetOrder(uint64(3)).
+					Return(&models.Order{Id: 3, MusicianId: 1, Status: models.OrderShipped, PaymentId: "p3"}, nil)
+				p.EXPECT().Refund("p3").Return(nil)
+				r.EXPECT().UpdateOrderStatus(uint64(3), models.OrderShipped, models.OrderRefunded).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "Refund fail test",
+			status: models.OrderRefunded,
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				r.EXPECT().GetOrder(uint64(3)).
+					Return(&models.Order{Id: 3, MusicianId: 1, Status: models.OrderPaid, PaymentId: "p3"}, nil)
+				p.EXPECT().Refund("p3").Return(errors.New("provider is down"))
+			},
+			expectedErr: errors.Wrap(errors.New("provider is down"), "order.usecase.UpdateOrderStatus error while refund"),
+		},
+		{
+			name:   "Mark paid test",
+			status: models.OrderPaid,
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				r.EXPECT().GetOrder(uint64(3)).Return(&models.Order{Id: 3, MusicianId: 1, Status: models.OrderPending}, nil)
+			},
+			expectedErr: models.ErrInvalidTransition,
+		},
+		{
+			name:   "Ship pending test",
+			status: models.OrderShipped,
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				r.EXPECT().GetOrder(uint64(3)).Return(&models.Order{Id: 3, MusicianId: 1, Status: models.OrderPending}, nil)
+			},
+			expectedErr: models.ErrInvalidTransition,
+		},
+		{
+			name:   "Other musician test",
+			status: models.OrderShipped,
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				r.EXPECT().GetOrder(uint64(3)).Return(&models.Order{Id: 3, MusicianId: 2, Status: models.OrderPaid}, nil)
+			},
+			expectedErr: models.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init Dependencies
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockOrderRepository(ctrl)
+			payments := mock_payment_provider.NewMockPaymentProvider(ctrl)
+			tc.mock(repo, payments)
+
+			u := NewOrderUseCase(repo, payments)
+			err := u.UpdateOrderStatus(1, 3, tc.status)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, tc.expectedErr.Error(), err.Error())
+			}
+		})
+	}
+}
+
+func TestUsecase_HandlePaymentWebhook(t *testing.T) {
+	type mock func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider)
+
+	succeeded := &models.PaymentEvent{Id: "e1", PaymentId: "p1", Status: models.PaymentSucceeded}
+
+	testTable := []struct {
+		name        string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				p.EXPECT().ParseWebhook([]byte("body"), "sig").Return(succeeded, nil)
+				r.EXPECT().ApplyPaymentEvent(succeeded, gomock.Any()).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Repeated event test",
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				p.EXPECT().ParseWebhook([]byte("body"), "sig").Return(succeeded, nil)
+				r.EXPECT().ApplyPaymentEvent(succeeded, gomock.Any()).Return(models.ErrAlreadyProcessed)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Paid after cancel test",
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				p.EXPECT().ParseWebhook([]byte("body"), "sig").Return(succeeded, nil)
+				r.EXPECT().ApplyPaymentEvent(succeeded, gomock.Any()).
+					DoAndReturn(func(event *models.PaymentEvent, refund func(string) error) error {
+						return refund(event.PaymentId)
+					})
+				p.EXPECT().Refund("p1").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Refund fail test",
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				p.EXPECT().ParseWebhook([]byte("body"), "sig").Return(succeeded, nil)
+				r.EXPECT().ApplyPaymentEvent(succeeded, gomock.Any()).
+					DoAndReturn(func(event *models.PaymentEvent, refund func(string) error) error {
+						return refund(event.PaymentId)
+					})
+				p.EXPECT().Refund("p1").Return(errors.New("provider is down"))
+			},
+			expectedErr: errors.Wrap(errors.New("provider is down"),
+				"order.usecase.HandlePaymentWebhook error while apply"),
+		},
+		{
+			name: "Bad signature test",
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				p.EXPECT().ParseWebhook([]byte("body"), "sig").Return(nil, models.ErrAccessDenied)
+			},
+			expectedErr: errors.Wrap(models.ErrAccessDenied, "order.usecase.HandlePaymentWebhook error while parse"),
+		},
+		{
+			name: "Unknown payment test",
+			mock: func(r *mock_repository.MockOrderRepository, p *mock_payment_provider.MockPaymentProvider) {
+				p.EXPECT().ParseWebhook([]byte("body"), "sig").Return(succeeded, nil)
+				r.EXPECT().ApplyPaymentEvent(succeeded, gomock.Any()).Return(models.ErrNotFound)
+			},
+			expectedErr: errors.Wrap(models.ErrNotFound, "order.usecase.HandlePaymentWebhook error while apply"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init Dependencies
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockOrderRepository(ctrl)
+			payments := mock_payment_provider.NewMockPaymentProvider(ctrl)
+			tc.mock(repo, payments)
+
+			u := NewOrderUseCase(repo, payments)
+			err := u.HandlePaymentWebhook([]byte("body"), "sig")
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, tc.expectedErr.Error(), err.Error())
+			}
+		})
+	}
+}
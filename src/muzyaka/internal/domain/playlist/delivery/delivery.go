@@ -3,17 +3,26 @@ package delivery
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"io"
 	"net/http"
+	"src/internal/domain/auth/middleware"
 	"src/internal/domain/playlist/usecase"
 	"src/internal/lib/api/response"
+	"src/internal/lib/playlistfmt"
+	"src/internal/models"
 	"src/internal/models/dto"
 	"strconv"
+	"time"
 )
 
+// MaxImportSize limits the size of an imported playlist file
+const MaxImportSize = 1 << 20
+
 // @Summary PlaylistCreate
 // @Security ApiKeyAuth
 // @Tags playlist
-// @Description create playlist
+// @Description create playlist, a smart one if rules are set, smart playlists are private by default
 // @ID create-playlist
 // @Accept  json
 // @Produce  json
@@ -44,7 +53,11 @@ func PlaylistCreate(useCase usecase.PlaylistUseCase) http.HandlerFunc {
 		}
 
 		id, err := useCase.AddPlaylist(dto.ToModelPlaylistWithoutId(&req, 0), aid)
-		if err != nil {
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
@@ -89,7 +102,11 @@ func UpdatePlaylist(useCase usecase.PlaylistUseCase) http.HandlerFunc {
 		}
 
 		err = useCase.UpdatedPlaylist(dto.ToModelPlaylistWithoutId(&req, aid))
-		if err != nil {
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
@@ -207,7 +224,14 @@ func AddTrack(useCase usecase.PlaylistUseCase) http.HandlerFunc {
 			return
 		}
 
-		err = useCase.AddTrack(playlistIDUint, req.TrackId)
+		userInfo, isOk := r.Context().Value(middleware.ValuesFromContext).(middleware.ContextValues)
+		if !isOk {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidContext.Error()))
+			return
+		}
+
+		err = useCase.AddTrack(playlistIDUint, req.TrackId, userInfo.Id)
 		if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
@@ -272,7 +296,7 @@ func DeleteTrack(useCase usecase.PlaylistUseCase) http.HandlerFunc {
 // @Accept  json
 // @Produce  json
 // @Param playlist_id path int true "playlist ID"
-// @Success 200 {array} dto.TracksMetaCollection
+// @Success 200 {object} dto.PlaylistTracksCollection
 // @Failure 400,404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Failure default {object} response.Response
@@ -288,20 +312,14 @@ func GetAllTracksForPlaylist(useCase usecase.PlaylistUseCase) http.HandlerFunc {
 			return
 		}
 
-		tracks, err := useCase.GetAllTracks(playlistIDUint)
+		tracks, err := useCase.GetPlaylistTracks(playlistIDUint)
 		if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
 		}
 
-		var res []*dto.TrackMeta
-
-		for _, v := range tracks {
-			res = append(res, dto.ToDtoTrackMeta(v))
-		}
-
-		render.JSON(w, r, dto.TracksMetaCollection{Tracks: res})
+		render.JSON(w, r, dto.ToDtoPlaylistTracks(tracks))
 	}
 }
 
@@ -345,3 +363,358 @@ func GetAllPlaylists(useCase usecase.PlaylistUseCase) http.HandlerFunc {
 		render.JSON(w, r, dto.PlaylistsCollection{Playlists: res})
 	}
 }
+
+// @Summary UpdatePlaylistSharing
+// @Security ApiKeyAuth
+// @Tags playlist
+// @Description change playlist visibility and collaborative mode, turning collaboration off revokes invites
+// @ID update-playlist-sharing
+// @Accept  json
+// @Produce  json
+// @Param id path int true "playlist ID"
+// @Param input body dto.PlaylistSharing true "sharing settings"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/playlist/{id}/sharing [put]
+func UpdateSharing(useCase usecase.PlaylistUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		aid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.PlaylistSharing
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.UpdatePlaylistSharing(aid, req.Visibility, req.Collaborative)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary CreatePlaylistInvite
+// @Security ApiKeyAuth
+// @Tags playlist
+// @Description create an invite link for a collaborative playlist
+// @ID create-playlist-invite
+// @Accept  json
+// @Produce  json
+// @Param id path int true "playlist ID"
+// @Param input body dto.CreateInviteRequest false "invite settings"
+// @Success 200 {object} dto.PlaylistInvite
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/playlist/{id}/invites [post]
+func CreateInvite(useCase usecase.PlaylistUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		aid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.CreateInviteRequest
+		if r.ContentLength != 0 {
+			err = render.DecodeJSON(r.Body, &req)
+			if err != nil {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error(err.Error()))
+				return
+			}
+		}
+
+		userInfo, isOk := r.Context().Value(middleware.ValuesFromContext).(middleware.ContextValues)
+		if !isOk {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidContext.Error()))
+			return
+		}
+
+		invite, err := useCase.CreateInvite(aid, userInfo.Id, time.Duration(req.TtlHours)*time.Hour)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoPlaylistInvite(invite))
+	}
+}
+
+// @Summary AcceptPlaylistInvite
+// @Security ApiKeyAuth
+// @Tags playlist
+// @Description join a collaborative playlist by invite token
+// @ID accept-playlist-invite
+// @Accept  json
+// @Produce  json
+// @Param token path string true "invite token"
+// @Success 200 {object} dto.AcceptInviteResponse
+// @Failure 400,404,410 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/playlist/invites/{token} [post]
+func AcceptInvite(useCase usecase.PlaylistUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := chi.URLParam(r, "token")
+
+		userInfo, isOk := r.Context().Value(middleware.ValuesFromContext).(middleware.ContextValues)
+		if !isOk {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidContext.Error()))
+			return
+		}
+
+		playlistId, err := useCase.AcceptInvite(token, userInfo.Id)
+		if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrExpired) {
+			render.Status(r, http.StatusGone)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.AcceptInviteResponse{PlaylistId: playlistId})
+	}
+}
+
+// @Summary GetPlaylistCollaborators
+// @Security ApiKeyAuth
+// @Tags playlist
+// @Description get collaborators of the playlist
+// @ID get-playlist-collaborators
+// @Accept  json
+// @Produce  json
+// @Param id path int true "playlist ID"
+// @Success 200 {object} dto.Collaborators
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/playlist/{id}/collaborators [get]
+func GetCollaborators(useCase usecase.PlaylistUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		aid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		ids, err := useCase.GetCollaborators(aid)
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.Collaborators{UserIds: ids})
+	}
+}
+
+// @Summary RemovePlaylistCollaborator
+// @Security ApiKeyAuth
+// @Tags playlist
+// @Description remove a collaborator from the playlist
+// @ID remove-playlist-collaborator
+// @Accept  json
+// @Produce  json
+// @Param id path int true "playlist ID"
+// @Param user_id path int true "collaborator ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/playlist/{id}/collaborators/{user_id} [delete]
+func RemoveCollaborator(useCase usecase.PlaylistUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		aid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		userId := chi.URLParam(r, "user_id")
+		uid, err := strconv.ParseUint(userId, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.RemoveCollaborator(aid, uid)
+		if errors.Is(err, models.ErrNothingToDelete) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary ExportPlaylist
+// @Security ApiKeyAuth
+// @Tags playlist
+// @Description export playlist as m3u8, xspf or jspf file
+// @ID export-playlist
+// @Produce  plain
+// @Param id path int true "playlist ID"
+// @Param format query string true "m3u8, xspf or jspf"
+// @Success 200 {string} string
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/playlist/{id}/export [get]
+func ExportPlaylist(useCase usecase.PlaylistUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		aid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if !playlistfmt.IsSupported(format) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidParameter.Error()))
+			return
+		}
+
+		doc, err := useCase.ExportPlaylist(aid)
+		if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		data, err := playlistfmt.Encode(format, doc)
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", playlistfmt.ContentType(format))
+		w.Header().Set("Content-Disposition", "attachment; filename=\"playlist-"+id+"."+format+"\"")
+		_, _ = w.Write(data)
+	}
+}
+
+// @Summary ImportPlaylist
+// @Security ApiKeyAuth
+// @Tags playlist
+// @Description create playlist from m3u8, xspf or jspf file, entries are matched by title, musician and duration
+// @ID import-playlist
+// @Accept  plain
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param format query string true "m3u8, xspf or jspf"
+// @Param input body string true "playlist file"
+// @Success 200 {object} dto.PlaylistImportResponse
+// @Failure 400,413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/playlist/import [post]
+func ImportPlaylist(useCase usecase.PlaylistUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := chi.URLParam(r, "user_id")
+		uid, err := strconv.ParseUint(userId, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if !playlistfmt.IsSupported(format) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidParameter.Error()))
+			return
+		}
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxImportSize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			render.Status(r, http.StatusRequestEntityTooLarge)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		doc, err := playlistfmt.Decode(format, data)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		res, err := useCase.ImportPlaylist(doc, uid)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoPlaylistImportResult(res))
+	}
+}
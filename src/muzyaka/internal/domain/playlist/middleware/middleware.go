@@ -3,6 +3,7 @@ package middleware
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/pkg/errors"
 	"net/http"
 	"src/internal/domain/auth/middleware"
 	usecase2 "src/internal/domain/auth/usecase"
@@ -49,3 +50,97 @@ func CheckPlaylistOwnership(next http.Handler, useCase usecase.PlaylistUseCase)
 		next.ServeHTTP(w, r)
 	}
 }
+
+// CheckPlaylistReadAccess hides private playlists from users that are not
+// their owners or collaborators. The playlist is taken from the id or
+// playlist_id URL parameter.
+func CheckPlaylistReadAccess(next http.Handler, useCase usecase.PlaylistUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID := chi.URLParam(r, "id")
+		if playlistID == "" {
+			playlistID = chi.URLParam(r, "playlist_id")
+		}
+
+		playlistIDUint, err := strconv.ParseUint(playlistID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		userInfo, isOk := r.Context().Value(middleware.ValuesFromContext).(middleware.ContextValues)
+		if !isOk {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidContext.Error()))
+			return
+		}
+		if userInfo.Role == usecase2.AdminRole {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		isAllowed, err := useCase.CanReadPlaylist(playlistIDUint, userInfo.Id)
+		if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(models.ErrNotFound.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		// private playlists look like missing ones
+		if !isAllowed {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(models.ErrNotFound.Error()))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+// CheckPlaylistTracksEditAccess lets the owner and collaborators of
+// collaborative playlists change the tracks
+func CheckPlaylistTracksEditAccess(next http.Handler, useCase usecase.PlaylistUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID := chi.URLParam(r, "id")
+		playlistIDUint, err := strconv.ParseUint(playlistID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		userInfo, isOk := r.Context().Value(middleware.ValuesFromContext).(middleware.ContextValues)
+		if !isOk {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidContext.Error()))
+			return
+		}
+		if userInfo.Role == usecase2.AdminRole {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		isAllowed, err := useCase.CanEditPlaylistTracks(playlistIDUint, userInfo.Id)
+		if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(models.ErrNotFound.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		if !isAllowed {
+			render.Status(r, http.StatusMethodNotAllowed)
+			render.JSON(w, r, response.Error(models.ErrAccessDenied.Error()))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
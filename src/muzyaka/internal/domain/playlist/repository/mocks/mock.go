@@ -7,6 +7,7 @@ package mock_repository
 import (
 	reflect "reflect"
 	models "src/internal/models"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,6 +35,35 @@ func (m *MockPlaylistRepository) EXPECT() *MockPlaylistRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AcceptInvite mocks base method.
+func (m *MockPlaylistRepository) AcceptInvite(token string, userId uint64, now time.Time) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvite", token, userId, now)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptInvite indicates an expected call of AcceptInvite.
+func (mr *MockPlaylistRepositoryMockRecorder) AcceptInvite(token, userId, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvite", reflect.TypeOf((*MockPlaylistRepository)(nil).AcceptInvite), token, userId, now)
+}
+
+// AddInvite mocks base method.
+func (m *MockPlaylistRepository) AddInvite(invite *models.PlaylistInvite) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddInvite", invite)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddInvite indicates an expected call of AddInvite.
+func (mr *MockPlaylistRepositoryMockRecorder) AddInvite(invite interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddInvite", reflect.TypeOf((*MockPlaylistRepository)(nil).AddInvite), invite)
+}
+
 // AddPlaylist mocks base method.
 func (m *MockPlaylistRepository) AddPlaylist(playlist *models.Playlist, userId uint64) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -50,17 +80,17 @@ func (mr *MockPlaylistRepositoryMockRecorder) AddPlaylist(playlist, userId inter
 }
 
 // AddTrackToPlaylist mocks base method.
-func (m *MockPlaylistRepository) AddTrackToPlaylist(playlistId, trackId uint64) error {
+func (m *MockPlaylistRepository) AddTrackToPlaylist(playlistId, trackId, addedBy uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddTrackToPlaylist", playlistId, trackId)
+	ret := m.ctrl.Call(m, "AddTrackToPlaylist", playlistId, trackId, addedBy)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddTrackToPlaylist indicates an expected call of AddTrackToPlaylist.
-func (mr *MockPlaylistRepositoryMockRecorder) AddTrackToPlaylist(playlistId, trackId interface{}) *gomock.Call {
+func (mr *MockPlaylistRepositoryMockRecorder) AddTrackToPlaylist(playlistId, trackId, addedBy interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTrackToPlaylist", reflect.TypeOf((*MockPlaylistRepository)(nil).AddTrackToPlaylist), playlistId, trackId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTrackToPlaylist", reflect.TypeOf((*MockPlaylistRepository)(nil).AddTrackToPlaylist), playlistId, trackId, addedBy)
 }
 
 // DeletePlaylist mocks base method.
@@ -121,6 +151,21 @@ func (mr *MockPlaylistRepositoryMockRecorder) GetAllTracks(playlistId interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTracks", reflect.TypeOf((*MockPlaylistRepository)(nil).GetAllTracks), playlistId)
 }
 
+// GetCollaborators mocks base method.
+func (m *MockPlaylistRepository) GetCollaborators(playlistId uint64) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollaborators", playlistId)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollaborators indicates an expected call of GetCollaborators.
+func (mr *MockPlaylistRepositoryMockRecorder) GetCollaborators(playlistId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollaborators", reflect.TypeOf((*MockPlaylistRepository)(nil).GetCollaborators), playlistId)
+}
+
 // GetPlaylist mocks base method.
 func (m *MockPlaylistRepository) GetPlaylist(id uint64) (*models.Playlist, error) {
 	m.ctrl.T.Helper()
@@ -136,6 +181,36 @@ func (mr *MockPlaylistRepositoryMockRecorder) GetPlaylist(id interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaylist", reflect.TypeOf((*MockPlaylistRepository)(nil).GetPlaylist), id)
 }
 
+// GetPlaylistAccess mocks base method.
+func (m *MockPlaylistRepository) GetPlaylistAccess(playlistId, userId uint64) (*models.PlaylistAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlaylistAccess", playlistId, userId)
+	ret0, _ := ret[0].(*models.PlaylistAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlaylistAccess indicates an expected call of GetPlaylistAccess.
+func (mr *MockPlaylistRepositoryMockRecorder) GetPlaylistAccess(playlistId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaylistAccess", reflect.TypeOf((*MockPlaylistRepository)(nil).GetPlaylistAccess), playlistId, userId)
+}
+
+// GetPlaylistTracks mocks base method.
+func (m *MockPlaylistRepository) GetPlaylistTracks(playlistId uint64) ([]*models.PlaylistTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlaylistTracks", playlistId)
+	ret0, _ := ret[0].([]*models.PlaylistTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlaylistTracks indicates an expected call of GetPlaylistTracks.
+func (mr *MockPlaylistRepositoryMockRecorder) GetPlaylistTracks(playlistId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaylistTracks", reflect.TypeOf((*MockPlaylistRepository)(nil).GetPlaylistTracks), playlistId)
+}
+
 // GetUserForPlaylist mocks base method.
 func (m *MockPlaylistRepository) GetUserForPlaylist(playlistId uint64) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -151,6 +226,21 @@ func (mr *MockPlaylistRepositoryMockRecorder) GetUserForPlaylist(playlistId inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForPlaylist", reflect.TypeOf((*MockPlaylistRepository)(nil).GetUserForPlaylist), playlistId)
 }
 
+// ImportPlaylist mocks base method.
+func (m *MockPlaylistRepository) ImportPlaylist(playlist *models.Playlist, userId uint64, trackIds []uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportPlaylist", playlist, userId, trackIds)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportPlaylist indicates an expected call of ImportPlaylist.
+func (mr *MockPlaylistRepositoryMockRecorder) ImportPlaylist(playlist, userId, trackIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportPlaylist", reflect.TypeOf((*MockPlaylistRepository)(nil).ImportPlaylist), playlist, userId, trackIds)
+}
+
 // IsPlaylistOwned mocks base method.
 func (m *MockPlaylistRepository) IsPlaylistOwned(playlistId, userId uint64) (bool, error) {
 	m.ctrl.T.Helper()
@@ -166,6 +256,34 @@ func (mr *MockPlaylistRepositoryMockRecorder) IsPlaylistOwned(playlistId, userId
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPlaylistOwned", reflect.TypeOf((*MockPlaylistRepository)(nil).IsPlaylistOwned), playlistId, userId)
 }
 
+// RemoveCollaborator mocks base method.
+func (m *MockPlaylistRepository) RemoveCollaborator(playlistId, userId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCollaborator", playlistId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCollaborator indicates an expected call of RemoveCollaborator.
+func (mr *MockPlaylistRepositoryMockRecorder) RemoveCollaborator(playlistId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCollaborator", reflect.TypeOf((*MockPlaylistRepository)(nil).RemoveCollaborator), playlistId, userId)
+}
+
+// ReplaceDailyMixes mocks base method.
+func (m *MockPlaylistRepository) ReplaceDailyMixes(userId uint64, mixes []*models.DailyMix) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceDailyMixes", userId, mixes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceDailyMixes indicates an expected call of ReplaceDailyMixes.
+func (mr *MockPlaylistRepositoryMockRecorder) ReplaceDailyMixes(userId, mixes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceDailyMixes", reflect.TypeOf((*MockPlaylistRepository)(nil).ReplaceDailyMixes), userId, mixes)
+}
+
 // UpdatePlaylist mocks base method.
 func (m *MockPlaylistRepository) UpdatePlaylist(playlist *models.Playlist) error {
 	m.ctrl.T.Helper()
@@ -179,3 +297,17 @@ func (mr *MockPlaylistRepositoryMockRecorder) UpdatePlaylist(playlist interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePlaylist", reflect.TypeOf((*MockPlaylistRepository)(nil).UpdatePlaylist), playlist)
 }
+
+// UpdatePlaylistSharing mocks base method.
+func (m *MockPlaylistRepository) UpdatePlaylistSharing(playlistId uint64, visibility string, collaborative bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePlaylistSharing", playlistId, visibility, collaborative)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePlaylistSharing indicates an expected call of UpdatePlaylistSharing.
+func (mr *MockPlaylistRepositoryMockRecorder) UpdatePlaylistSharing(playlistId, visibility, collaborative interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePlaylistSharing", reflect.TypeOf((*MockPlaylistRepository)(nil).UpdatePlaylistSharing), playlistId, visibility, collaborative)
+}
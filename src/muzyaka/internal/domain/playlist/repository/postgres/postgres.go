@@ -3,9 +3,12 @@ package postgres
 import (
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"src/internal/domain/playlist/repository"
 	"src/internal/models"
 	"src/internal/models/dao"
+	"src/internal/models/events"
+	"time"
 )
 
 type playlistRepository struct {
@@ -40,7 +43,8 @@ func (p playlistRepository) IsPlaylistOwned(playlistId uint64, userId uint64) (b
 		return false, errors.Wrap(tx.Error, "database error (table playlist)")
 	}
 
-	return playlist.UserID == userId, nil
+	// daily mixes are managed by the service
+	return playlist.UserID == userId && playlist.Kind != models.PlaylistKindDailyMix, nil
 }
 
 func (p playlistRepository) GetUserForPlaylist(playlistId uint64) (uint64, error) {
@@ -55,6 +59,20 @@ func (p playlistRepository) GetUserForPlaylist(playlistId uint64) (uint64, error
 }
 
 func (p playlistRepository) GetAllTracks(playlistId uint64) ([]uint64, error) {
+	var playlist dao.Playlist
+	if err := p.db.Select(smartColumns).Where("id = ?", playlistId).Take(&playlist).Error; err != nil {
+		return nil, errors.Wrap(err, "database error (table playlist)")
+	}
+
+	if playlist.Kind == models.PlaylistKindSmart {
+		ids, err := smartTrackIds(p.db, &playlist)
+		if err != nil {
+			return nil, errors.Wrap(err, "database error (table track)")
+		}
+
+		return ids, nil
+	}
+
 	var relations []*dao.PlaylistTrack
 	tx := p.db.Limit(dao.MaxLimit).Find(&relations, "playlist_id = ?", playlistId)
 	if tx.Error != nil {
@@ -69,6 +87,44 @@ func (p playlistRepository) GetAllTracks(playlistId uint64) ([]uint64, error) {
 	return ids, nil
 }
 
+func (p playlistRepository) GetPlaylistTracks(playlistId uint64) ([]*models.PlaylistTrack, error) {
+	var playlist dao.Playlist
+	if err := p.db.Select(smartColumns).Where("id = ?", playlistId).Take(&playlist).Error; err != nil {
+		return nil, errors.Wrap(err, "database error (table playlist)")
+	}
+
+	// smart playlists keep the order of their rules
+	if playlist.Kind == models.PlaylistKindSmart {
+		ids, err := smartTrackIds(p.db, &playlist)
+		if err != nil {
+			return nil, errors.Wrap(err, "database error (table track)")
+		}
+
+		res := make([]*models.PlaylistTrack, 0, len(ids))
+		for _, id := range ids {
+			res = append(res, &models.PlaylistTrack{TrackId: id})
+		}
+
+		return res, nil
+	}
+
+	var relations []*dao.PlaylistTrack
+	tx := p.db.Where("playlist_id = ?", playlistId).
+		Order("added_at, track_id").
+		Limit(dao.MaxLimit).
+		Find(&relations)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track_playlist)")
+	}
+
+	res := make([]*models.PlaylistTrack, 0, len(relations))
+	for _, v := range relations {
+		res = append(res, dao.ToModelPlaylistTrack(v))
+	}
+
+	return res, nil
+}
+
 func (p playlistRepository) GetPlaylist(id uint64) (*models.Playlist, error) {
 	var playlist dao.Playlist
 
@@ -83,10 +139,27 @@ func (p playlistRepository) GetPlaylist(id uint64) (*models.Playlist, error) {
 func (p playlistRepository) UpdatePlaylist(playlist *models.Playlist) error {
 	pgPlaylist := dao.ToPostgresPlaylist(playlist, 0)
 
-	tx := p.db.Omit("id", "user_id").Updates(&pgPlaylist)
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		// sharing settings are changed by UpdatePlaylistSharing only
+		if err := tx.Omit("id", "user_id", "kind", "visibility", "collaborative").Updates(&pgPlaylist).Error; err != nil {
+			return err
+		}
 
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table playlist)")
+		var updated dao.Playlist
+		if err := tx.Where("id = ?", pgPlaylist.ID).Take(&updated).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.PlaylistUpdated, updated.ID, dao.ToPlaylistPayload(&updated))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table playlist)")
 	}
 
 	return nil
@@ -95,54 +168,425 @@ func (p playlistRepository) UpdatePlaylist(playlist *models.Playlist) error {
 func (p playlistRepository) AddPlaylist(playlist *models.Playlist, userId uint64) (uint64, error) {
 	pgPlaylist := dao.ToPostgresPlaylist(playlist, userId)
 
-	tx := p.db.Create(&pgPlaylist)
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&pgPlaylist).Error; err != nil {
+			return err
+		}
 
-	if tx.Error != nil {
-		return 0, errors.Wrap(tx.Error, "database error (table playlist)")
+		outbox, err := dao.NewOutbox(events.PlaylistCreated, pgPlaylist.ID, dao.ToPlaylistPayload(pgPlaylist))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if err != nil {
+		return 0, errors.Wrap(err, "database error (table playlist)")
 	}
 
 	playlist.Id = pgPlaylist.ID
+	playlist.Kind = pgPlaylist.Kind
+
+	return pgPlaylist.ID, nil
+}
+
+func (p playlistRepository) ImportPlaylist(playlist *models.Playlist, userId uint64, trackIds []uint64) (uint64, error) {
+	pgPlaylist := dao.ToPostgresPlaylist(playlist, userId)
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&pgPlaylist).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.PlaylistCreated, pgPlaylist.ID, dao.ToPlaylistPayload(pgPlaylist))
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(outbox).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i, trackId := range trackIds {
+			// keeps the file order when tracks are sorted by added_at
+			if err := tx.Create(&dao.PlaylistTrack{
+				TrackId:    trackId,
+				PlaylistId: pgPlaylist.ID,
+				AddedBy:    &userId,
+				AddedAt:    now.Add(time.Duration(i) * time.Microsecond),
+			}).Error; err != nil {
+				return err
+			}
+
+			outbox, err := dao.NewOutbox(events.PlaylistTrackAdded, pgPlaylist.ID, events.PlaylistTrackPayload{
+				PlaylistId: pgPlaylist.ID,
+				TrackId:    trackId,
+				AddedBy:    userId,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Create(outbox).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, errors.Wrap(err, "database error (table playlist)")
+	}
+
+	playlist.Id = pgPlaylist.ID
+	playlist.Kind = pgPlaylist.Kind
 
 	return pgPlaylist.ID, nil
 }
 
 func (p playlistRepository) DeletePlaylist(id uint64) error {
-	tx := p.db.Delete(&dao.Playlist{}, id)
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&dao.Playlist{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNothingToDelete
+		}
+
+		outbox, err := dao.NewOutbox(events.PlaylistDeleted, id, events.PlaylistPayload{PlaylistId: id})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
 
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table playlist)")
+	if errors.Is(err, models.ErrNothingToDelete) {
+		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table playlist)")
 	}
 
-	if tx.RowsAffected == 0 {
+	return nil
+}
+
+func (p playlistRepository) AddTrackToPlaylist(playlistId uint64, trackId uint64, addedBy uint64) error {
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&dao.PlaylistTrack{
+			TrackId:    trackId,
+			PlaylistId: playlistId,
+			AddedBy:    &addedBy,
+		}).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.PlaylistTrackAdded, playlistId, events.PlaylistTrackPayload{
+			PlaylistId: playlistId,
+			TrackId:    trackId,
+			AddedBy:    addedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table playlist)")
+	}
+
+	return nil
+}
+
+func (p playlistRepository) DeleteTrackFromPlaylist(playlistId uint64, trackId uint64) error {
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&dao.PlaylistTrack{},
+			"track_id = ? AND playlist_id = ?", trackId, playlistId)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNothingToDelete
+		}
+
+		outbox, err := dao.NewOutbox(events.PlaylistTrackRemoved, playlistId, events.PlaylistTrackPayload{
+			PlaylistId: playlistId,
+			TrackId:    trackId,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNothingToDelete) {
 		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table playlist)")
 	}
 
 	return nil
 }
 
-func (p playlistRepository) AddTrackToPlaylist(playlistId uint64, trackId uint64) error {
-	tx := p.db.Create(&dao.PlaylistTrack{
-		TrackId:    trackId,
-		PlaylistId: playlistId,
+func (p playlistRepository) ReplaceDailyMixes(userId uint64, mixes []*models.DailyMix) error {
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		var old []*dao.Playlist
+		if err := tx.Where("user_id = ? AND kind = ?", userId, models.PlaylistKindDailyMix).Find(&old).Error; err != nil {
+			return err
+		}
+
+		for _, v := range old {
+			if err := tx.Delete(&dao.Playlist{}, v.ID).Error; err != nil {
+				return err
+			}
+
+			outbox, err := dao.NewOutbox(events.PlaylistDeleted, v.ID, events.PlaylistPayload{PlaylistId: v.ID})
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Create(outbox).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, v := range mixes {
+			if len(v.TrackIds) == 0 {
+				continue
+			}
+
+			pgPlaylist := dao.ToPostgresPlaylist(&v.Playlist, userId)
+			pgPlaylist.Kind = models.PlaylistKindDailyMix
+			pgPlaylist.Visibility = models.PlaylistPrivate
+
+			// a mix gets the cover of its first track's album
+			if len(pgPlaylist.CoverFile) == 0 {
+				var album dao.Album
+				if err := tx.Joins("JOIN tracks ON tracks.album_id = albums.id").
+					Where("tracks.id = ?", v.TrackIds[0]).
+					Take(&album).Error; err != nil {
+					return err
+				}
+				pgPlaylist.CoverFile = album.Cover
+			}
+
+			if err := tx.Create(pgPlaylist).Error; err != nil {
+				return err
+			}
+
+			now := time.Now()
+			var relations []*dao.PlaylistTrack
+			for _, trackId := range v.TrackIds {
+				relations = append(relations, &dao.PlaylistTrack{TrackId: trackId, PlaylistId: pgPlaylist.ID, AddedAt: now})
+			}
+
+			if err := tx.Create(&relations).Error; err != nil {
+				return err
+			}
+
+			// tracks of generated mixes don't get their own playlist.track_added events
+			outbox, err := dao.NewOutbox(events.PlaylistCreated, pgPlaylist.ID, dao.ToPlaylistPayload(pgPlaylist))
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Create(outbox).Error; err != nil {
+				return err
+			}
+
+			v.Id = pgPlaylist.ID
+			v.Kind = pgPlaylist.Kind
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table playlist)")
+	}
+
+	return nil
+}
+
+func (p playlistRepository) GetPlaylistAccess(playlistId uint64, userId uint64) (*models.PlaylistAccess, error) {
+	var playlist dao.Playlist
+	tx := p.db.Where("id = ?", playlistId).Take(&playlist)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	} else if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table playlist)")
+	}
+
+	var collaborators int64
+	tx = p.db.Model(&dao.PlaylistCollaborator{}).
+		Where("playlist_id = ? AND user_id = ?", playlistId, userId).
+		Count(&collaborators)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table playlist_collaborators)")
+	}
+
+	return &models.PlaylistAccess{
+		OwnerId:        playlist.UserID,
+		Kind:           playlist.Kind,
+		Visibility:     playlist.Visibility,
+		Collaborative:  playlist.Collaborative,
+		IsCollaborator: collaborators > 0,
+	}, nil
+}
+
+func (p playlistRepository) UpdatePlaylistSharing(playlistId uint64, visibility string, collaborative bool) error {
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&dao.Playlist{}).
+			Where("id = ?", playlistId).
+			Updates(map[string]interface{}{"visibility": visibility, "collaborative": collaborative})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNotFound
+		}
+
+		if !collaborative {
+			if err := tx.Where("playlist_id = ?", playlistId).Delete(&dao.PlaylistInvite{}).Error; err != nil {
+				return err
+			}
+		}
+
+		var updated dao.Playlist
+		if err := tx.Where("id = ?", playlistId).Take(&updated).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.PlaylistUpdated, updated.ID, dao.ToPlaylistPayload(&updated))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
 	})
 
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table playlist)")
+	}
+
+	return nil
+}
+
+func (p playlistRepository) AddInvite(invite *models.PlaylistInvite) error {
+	tx := p.db.Create(dao.ToPostgresPlaylistInvite(invite))
 	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table playlist)")
+		return errors.Wrap(tx.Error, "database error (table playlist_invites)")
 	}
 
 	return nil
 }
 
-func (p playlistRepository) DeleteTrackFromPlaylist(playlistId uint64, trackId uint64) error {
-	tx := p.db.Delete(&dao.PlaylistTrack{},
-		"track_id = ? AND playlist_id = ?", trackId, playlistId)
+func (p playlistRepository) AcceptInvite(token string, userId uint64, now time.Time) (uint64, error) {
+	var invite dao.PlaylistInvite
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token = ?", token).Take(&invite).Error; err != nil {
+			return err
+		}
+
+		if !invite.ExpiresAt.After(now) {
+			return models.ErrExpired
+		}
+
+		var playlist dao.Playlist
+		if err := tx.Where("id = ?", invite.PlaylistId).Take(&playlist).Error; err != nil {
+			return err
+		}
+
+		if playlist.UserID == userId {
+			return nil
+		}
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&dao.PlaylistCollaborator{
+			PlaylistId: invite.PlaylistId,
+			UserId:     userId,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		outbox, err := dao.NewOutbox(events.PlaylistCollaboratorAdded, invite.PlaylistId, events.CollaboratorPayload{
+			PlaylistId: invite.PlaylistId,
+			UserId:     userId,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, models.ErrNotFound
+	} else if errors.Is(err, models.ErrExpired) {
+		return 0, models.ErrExpired
+	} else if err != nil {
+		return 0, errors.Wrap(err, "database error (table playlist_invites)")
+	}
 
+	return invite.PlaylistId, nil
+}
+
+func (p playlistRepository) GetCollaborators(playlistId uint64) ([]uint64, error) {
+	var ids []uint64
+	tx := p.db.Model(&dao.PlaylistCollaborator{}).
+		Where("playlist_id = ?", playlistId).
+		Order("joined_at").
+		Limit(dao.MaxLimit).
+		Pluck("user_id", &ids)
 	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table playlist)")
+		return nil, errors.Wrap(tx.Error, "database error (table playlist_collaborators)")
 	}
 
-	if tx.RowsAffected == 0 {
+	return ids, nil
+}
+
+func (p playlistRepository) RemoveCollaborator(playlistId uint64, userId uint64) error {
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&dao.PlaylistCollaborator{}, "playlist_id = ? AND user_id = ?", playlistId, userId)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNothingToDelete
+		}
+
+		outbox, err := dao.NewOutbox(events.PlaylistCollaboratorRemoved, playlistId, events.CollaboratorPayload{
+			PlaylistId: playlistId,
+			UserId:     userId,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNothingToDelete) {
 		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table playlist_collaborators)")
 	}
 
 	return nil
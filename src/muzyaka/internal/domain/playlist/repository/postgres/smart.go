@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"strings"
+)
+
+var smartSorts = map[string]string{
+	// track ids grow with uploads
+	models.SmartSortNewest:     "t.id DESC",
+	models.SmartSortName:       "t.name, t.id",
+	models.SmartSortMostPlayed: "(SELECT count(*) FROM plays pl WHERE pl.track_id = t.id) DESC, t.id",
+	models.SmartSortRandom:     "random()",
+}
+
+// smartColumns are the columns of a playlist needed to get its tracks
+var smartColumns = []string{"id", "user_id", "kind", "rules"}
+
+// smartTrackIds evaluates the rules of a smart playlist for its owner
+func smartTrackIds(db *gorm.DB, playlist *dao.Playlist) ([]uint64, error) {
+	if playlist.Rules == nil {
+		return nil, errors.New("smart playlist has no rules")
+	}
+
+	query, args, err := buildSmartQuery(dao.ToModelSmartRules(playlist.Rules), playlist.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint64
+	if err := db.Raw(query, args...).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func buildSmartQuery(rules *models.SmartRules, userId uint64) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	for _, c := range rules.Conditions {
+		condition, conditionArgs, err := buildSmartCondition(c, userId)
+		if err != nil {
+			return "", nil, err
+		}
+
+		conditions = append(conditions, "("+condition+")")
+		args = append(args, conditionArgs...)
+	}
+
+	join := " AND "
+	if rules.Match == models.SmartMatchAny {
+		join = " OR "
+	}
+
+	order, ok := smartSorts[rules.Sort]
+	if !ok {
+		order = smartSorts[models.SmartSortNewest]
+	}
+
+	limit := rules.Limit
+	if limit <= 0 || limit > dao.MaxLimit {
+		limit = dao.MaxLimit
+	}
+
+	query := `
+		SELECT t.id
+		FROM tracks t
+		         JOIN albums a ON a.id = t.album_id
+		         JOIN musicians m ON m.id = a.musician_id
+		         LEFT JOIN genres g ON g.id = t.genre`
+	if len(conditions) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(conditions, join)
+	}
+	query += "\n\t\tORDER BY " + order + "\n\t\tLIMIT ?"
+
+	return query, append(args, limit), nil
+}
+
+func buildSmartCondition(c models.SmartCondition, userId uint64) (string, []interface{}, error) {
+	switch c.Field + " " + c.Op {
+	case models.SmartFieldGenre + " " + models.SmartOpEq:
+		return "lower(g.name) = lower(?)", []interface{}{c.Value}, nil
+	case models.SmartFieldGenre + " " + models.SmartOpNeq:
+		return "g.name IS NULL OR lower(g.name) <> lower(?)", []interface{}{c.Value}, nil
+	case models.SmartFieldGenre + " " + models.SmartOpIn:
+		values := make([]string, 0, len(c.Values))
+		for _, v := range c.Values {
+			values = append(values, strings.ToLower(v))
+		}
+
+		return "lower(g.name) IN ?", []interface{}{values}, nil
+	case models.SmartFieldMusician + " " + models.SmartOpEq:
+		return "lower(m.name) = lower(?)", []interface{}{c.Value}, nil
+	case models.SmartFieldMusician + " " + models.SmartOpFollowed:
+		return "a.musician_id IN (SELECT musician_id FROM musician_followers WHERE user_id = ?)",
+			[]interface{}{userId}, nil
+	case models.SmartFieldName + " " + models.SmartOpContains:
+		return "t.name ILIKE ? ESCAPE '\\'", []interface{}{"%" + escapeLike(c.Value) + "%"}, nil
+	case models.SmartFieldLiked + " " + models.SmartOpAny:
+		return "t.id IN (SELECT track_id FROM user_track WHERE user_id = ?)", []interface{}{userId}, nil
+	case models.SmartFieldLiked + " " + models.SmartOpWithinDays:
+		return "t.id IN (SELECT track_id FROM user_track WHERE user_id = ? AND liked_at >= now() - make_interval(days => ?))",
+			[]interface{}{userId, c.Days}, nil
+	case models.SmartFieldPlayed + " " + models.SmartOpAny:
+		return "t.id IN (SELECT track_id FROM plays WHERE user_id = ?)", []interface{}{userId}, nil
+	case models.SmartFieldPlayed + " " + models.SmartOpWithinDays:
+		return "t.id IN (SELECT track_id FROM plays WHERE user_id = ? AND played_at >= now() - make_interval(days => ?))",
+			[]interface{}{userId, c.Days}, nil
+	}
+
+	return "", nil, errors.Wrapf(models.ErrInvalidParameter, "unsupported condition %s %s", c.Field, c.Op)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(s)
+}
@@ -1,6 +1,9 @@
 package repository
 
-import "src/internal/models"
+import (
+	"src/internal/models"
+	"time"
+)
 
 //go:generate mockgen -source=repository.go -destination=mocks/mock.go
 
@@ -8,12 +11,28 @@ type PlaylistRepository interface {
 	GetPlaylist(id uint64) (*models.Playlist, error)
 	UpdatePlaylist(playlist *models.Playlist) error
 	AddPlaylist(playlist *models.Playlist, userId uint64) (uint64, error)
+	// ImportPlaylist creates the playlist with the tracks in one transaction
+	ImportPlaylist(playlist *models.Playlist, userId uint64, trackIds []uint64) (uint64, error)
 	DeletePlaylist(id uint64) error
-	AddTrackToPlaylist(playlistId uint64, trackId uint64) error
+	AddTrackToPlaylist(playlistId uint64, trackId uint64, addedBy uint64) error
 	DeleteTrackFromPlaylist(playlistId uint64, trackId uint64) error
 	GetAllTracks(playlistId uint64) ([]uint64, error)
+	// GetPlaylistTracks returns tracks in the order they were added
+	GetPlaylistTracks(playlistId uint64) ([]*models.PlaylistTrack, error)
 	GetUserForPlaylist(playlistId uint64) (uint64, error)
 
 	IsPlaylistOwned(playlistId uint64, userId uint64) (bool, error)
 	GetAllPlaylistsForUser(userId uint64) ([]*models.Playlist, error)
+	// ReplaceDailyMixes deletes daily mixes of the user and saves the new ones
+	ReplaceDailyMixes(userId uint64, mixes []*models.DailyMix) error
+
+	GetPlaylistAccess(playlistId uint64, userId uint64) (*models.PlaylistAccess, error)
+	// UpdatePlaylistSharing drops pending invites when collaboration is turned off
+	UpdatePlaylistSharing(playlistId uint64, visibility string, collaborative bool) error
+	AddInvite(invite *models.PlaylistInvite) error
+	// AcceptInvite adds the user to collaborators and returns the playlist id.
+	// Unknown tokens return models.ErrNotFound, expired ones models.ErrExpired.
+	AcceptInvite(token string, userId uint64, now time.Time) (uint64, error)
+	GetCollaborators(playlistId uint64) ([]uint64, error)
+	RemoveCollaborator(playlistId uint64, userId uint64) error
 }
@@ -1,35 +1,93 @@
 package usecase
 
 import (
+	"github.com/hashicorp/go-uuid"
 	"github.com/pkg/errors"
 	"src/internal/domain/playlist/repository"
 	repository2 "src/internal/domain/track/repository"
 	"src/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultInviteTTL = 7 * 24 * time.Hour
+	MaxInviteTTL     = 30 * 24 * time.Hour
+)
+
+const (
+	MaxImportEntries  = 1000
+	DefaultImportName = "Imported playlist"
+	// MatchCandidates is how many catalogue tracks with the same name are compared
+	MatchCandidates = 20
+	// MaxDurationDiff is the largest difference in seconds for a track to match
+	MaxDurationDiff = 5
+)
+
+const (
+	MaxSmartConditions = 10
+	DefaultSmartLimit  = 50
+	MaxSmartLimit      = 100
+	MaxSmartDays       = 3650
 )
 
 type PlaylistUseCase interface {
+	// UpdatedPlaylist changes rules of smart playlists only
 	UpdatedPlaylist(playlist *models.Playlist) error
+	// AddPlaylist creates a smart playlist if rules are set. Smart playlists are
+	// private unless visibility is set, as their rules may reveal what the
+	// owner likes and plays.
 	AddPlaylist(playlist *models.Playlist, userId uint64) (uint64, error)
 	DeletePlaylist(id uint64) error
 	GetPlaylist(id uint64) (*models.Playlist, error)
-	AddTrack(playlistId uint64, trackId uint64) error
+	// AddTrack remembers userId as the one who added the track
+	AddTrack(playlistId uint64, trackId uint64, userId uint64) error
 	DeleteTrack(playlistId uint64, trackId uint64) error
 	GetAllTracks(playlistId uint64) ([]*models.TrackMeta, error)
+	GetPlaylistTracks(playlistId uint64) ([]*models.PlaylistTrack, error)
 	GetUserForPlaylist(playlistId uint64) (uint64, error)
 
 	IsPlaylistOwned(playlistId uint64, userId uint64) (bool, error)
 	GetAllPlaylistsForUser(userId uint64) ([]*models.Playlist, error)
+
+	// CanReadPlaylist lets anyone read public and unlisted playlists,
+	// private ones are readable by the owner and collaborators
+	CanReadPlaylist(playlistId uint64, userId uint64) (bool, error)
+	// CanEditPlaylistTracks lets collaborators of collaborative playlists
+	// add and remove tracks besides the owner
+	CanEditPlaylistTracks(playlistId uint64, userId uint64) (bool, error)
+	UpdatePlaylistSharing(playlistId uint64, visibility string, collaborative bool) error
+
+	// CreateInvite makes an invite link to a collaborative playlist valid for ttl,
+	// DefaultInviteTTL if ttl is 0
+	CreateInvite(playlistId uint64, userId uint64, ttl time.Duration) (*models.PlaylistInvite, error)
+	AcceptInvite(token string, userId uint64) (uint64, error)
+	GetCollaborators(playlistId uint64) ([]uint64, error)
+	RemoveCollaborator(playlistId uint64, userId uint64) error
+
+	ExportPlaylist(playlistId uint64) (*models.PlaylistDocument, error)
+	// ImportPlaylist creates a playlist of the entries found in the catalogue,
+	// the rest are returned as unmatched
+	ImportPlaylist(doc *models.PlaylistDocument, userId uint64) (*models.PlaylistImportResult, error)
 }
 
 type usecase struct {
 	playlistRep repository.PlaylistRepository
 	trackRep    repository2.TrackRepository
+	now         func() time.Time
+	newToken    func() (string, error)
 }
 
 // TODO: добавить ограничение на количество загружаемых сущностей
 
 func NewPlaylistUseCase(rep repository.PlaylistRepository, trackRep repository2.TrackRepository) PlaylistUseCase {
-	return &usecase{playlistRep: rep, trackRep: trackRep}
+	return &usecase{
+		playlistRep: rep,
+		trackRep:    trackRep,
+		now:         time.Now,
+		newToken:    uuid.GenerateUUID,
+	}
 }
 
 func (u *usecase) GetAllPlaylistsForUser(userId uint64) ([]*models.Playlist, error) {
@@ -79,6 +137,21 @@ func (u *usecase) GetAllTracks(playlistId uint64) ([]*models.TrackMeta, error) {
 }
 
 func (u *usecase) UpdatedPlaylist(playlist *models.Playlist) error {
+	if playlist.Rules != nil {
+		current, err := u.playlistRep.GetPlaylist(playlist.Id)
+		if err != nil {
+			return errors.Wrap(err, "playlist.usecase.UpdatedPlaylist error while get")
+		}
+
+		if current.Kind != models.PlaylistKindSmart {
+			return models.ErrInvalidParameter
+		}
+
+		if err := validateRules(playlist.Rules); err != nil {
+			return err
+		}
+	}
+
 	err := u.playlistRep.UpdatePlaylist(playlist)
 
 	if err != nil {
@@ -89,6 +162,21 @@ func (u *usecase) UpdatedPlaylist(playlist *models.Playlist) error {
 }
 
 func (u *usecase) AddPlaylist(playlist *models.Playlist, userId uint64) (uint64, error) {
+	if playlist.Visibility != "" && !models.PlaylistVisibilities[playlist.Visibility] {
+		return 0, models.ErrInvalidParameter
+	}
+
+	if playlist.Rules != nil {
+		if err := validateRules(playlist.Rules); err != nil {
+			return 0, err
+		}
+		playlist.Kind = models.PlaylistKindSmart
+
+		if playlist.Visibility == "" {
+			playlist.Visibility = models.PlaylistPrivate
+		}
+	}
+
 	id, err := u.playlistRep.AddPlaylist(playlist, userId)
 
 	if err != nil {
@@ -119,8 +207,8 @@ func (u *usecase) GetPlaylist(id uint64) (*models.Playlist, error) {
 	return res, nil
 }
 
-func (u *usecase) AddTrack(playlistId uint64, trackId uint64) error {
-	err := u.playlistRep.AddTrackToPlaylist(playlistId, trackId)
+func (u *usecase) AddTrack(playlistId uint64, trackId uint64, userId uint64) error {
+	err := u.playlistRep.AddTrackToPlaylist(playlistId, trackId, userId)
 
 	if err != nil {
 		return errors.Wrap(err, "playlist.usecase.AddTrack error while add")
@@ -137,3 +225,355 @@ func (u *usecase) DeleteTrack(playlistId uint64, trackId uint64) error {
 
 	return nil
 }
+
+func (u *usecase) GetPlaylistTracks(playlistId uint64) ([]*models.PlaylistTrack, error) {
+	tracks, err := u.playlistRep.GetPlaylistTracks(playlistId)
+	if err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.GetPlaylistTracks error while get")
+	}
+
+	ids := make([]uint64, 0, len(tracks))
+	for _, v := range tracks {
+		ids = append(ids, v.TrackId)
+	}
+
+	found, err := u.trackRep.GetTracks(ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.GetPlaylistTracks error while track get")
+	}
+
+	byId := make(map[uint64]*models.TrackMeta, len(found))
+	for _, v := range found {
+		byId[v.Id] = v
+	}
+
+	// tracks deleted since the playlist was read are skipped
+	res := make([]*models.PlaylistTrack, 0, len(tracks))
+	for _, v := range tracks {
+		if track, ok := byId[v.TrackId]; ok {
+			v.Track = track
+			res = append(res, v)
+		}
+	}
+
+	return res, nil
+}
+
+func (u *usecase) CanReadPlaylist(playlistId uint64, userId uint64) (bool, error) {
+	access, err := u.playlistRep.GetPlaylistAccess(playlistId, userId)
+	if err != nil {
+		return false, errors.Wrap(err, "playlist.usecase.CanReadPlaylist error while get")
+	}
+
+	return access.Visibility != models.PlaylistPrivate ||
+		access.OwnerId == userId ||
+		access.IsCollaborator, nil
+}
+
+func (u *usecase) CanEditPlaylistTracks(playlistId uint64, userId uint64) (bool, error) {
+	access, err := u.playlistRep.GetPlaylistAccess(playlistId, userId)
+	if err != nil {
+		return false, errors.Wrap(err, "playlist.usecase.CanEditPlaylistTracks error while get")
+	}
+
+	if access.Kind != models.PlaylistKindUser {
+		return false, nil
+	}
+
+	return access.OwnerId == userId || (access.Collaborative && access.IsCollaborator), nil
+}
+
+func (u *usecase) UpdatePlaylistSharing(playlistId uint64, visibility string, collaborative bool) error {
+	if !models.PlaylistVisibilities[visibility] {
+		return models.ErrInvalidParameter
+	}
+
+	err := u.playlistRep.UpdatePlaylistSharing(playlistId, visibility, collaborative)
+	if err != nil {
+		return errors.Wrap(err, "playlist.usecase.UpdatePlaylistSharing error while update")
+	}
+
+	return nil
+}
+
+func (u *usecase) CreateInvite(playlistId uint64, userId uint64, ttl time.Duration) (*models.PlaylistInvite, error) {
+	if ttl == 0 {
+		ttl = DefaultInviteTTL
+	}
+
+	if ttl < 0 || ttl > MaxInviteTTL {
+		return nil, models.ErrInvalidParameter
+	}
+
+	access, err := u.playlistRep.GetPlaylistAccess(playlistId, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.CreateInvite error while get")
+	}
+
+	if !access.Collaborative {
+		return nil, models.ErrInvalidParameter
+	}
+
+	token, err := u.newToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.CreateInvite error while token generation")
+	}
+
+	invite := &models.PlaylistInvite{
+		Token:      token,
+		PlaylistId: playlistId,
+		CreatedBy:  userId,
+		ExpiresAt:  u.now().Add(ttl),
+	}
+
+	if err := u.playlistRep.AddInvite(invite); err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.CreateInvite error while add")
+	}
+
+	return invite, nil
+}
+
+func (u *usecase) AcceptInvite(token string, userId uint64) (uint64, error) {
+	playlistId, err := u.playlistRep.AcceptInvite(token, userId, u.now())
+	if err != nil {
+		return 0, errors.Wrap(err, "playlist.usecase.AcceptInvite error while accept")
+	}
+
+	return playlistId, nil
+}
+
+func (u *usecase) GetCollaborators(playlistId uint64) ([]uint64, error) {
+	ids, err := u.playlistRep.GetCollaborators(playlistId)
+	if err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.GetCollaborators error while get")
+	}
+
+	return ids, nil
+}
+
+func (u *usecase) RemoveCollaborator(playlistId uint64, userId uint64) error {
+	err := u.playlistRep.RemoveCollaborator(playlistId, userId)
+	if err != nil {
+		return errors.Wrap(err, "playlist.usecase.RemoveCollaborator error while delete")
+	}
+
+	return nil
+}
+
+func (u *usecase) ExportPlaylist(playlistId uint64) (*models.PlaylistDocument, error) {
+	playlist, err := u.playlistRep.GetPlaylist(playlistId)
+	if err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.ExportPlaylist error while get")
+	}
+
+	tracks, err := u.playlistRep.GetPlaylistTracks(playlistId)
+	if err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.ExportPlaylist error while get tracks")
+	}
+
+	ids := make([]uint64, 0, len(tracks))
+	for _, v := range tracks {
+		ids = append(ids, v.TrackId)
+	}
+
+	details, err := u.trackRep.GetTracksDetails(ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.ExportPlaylist error while get details")
+	}
+
+	doc := &models.PlaylistDocument{
+		Name:        playlist.Name,
+		Description: playlist.Description,
+		Entries:     make([]*models.PlaylistEntry, 0, len(details)),
+	}
+
+	for _, v := range details {
+		doc.Entries = append(doc.Entries, &models.PlaylistEntry{
+			Title:    v.Name,
+			Musician: v.Musician,
+			Duration: v.Duration,
+			Location: "/api/track/" + strconv.FormatUint(v.Id, 10),
+		})
+	}
+
+	return doc, nil
+}
+
+func (u *usecase) ImportPlaylist(doc *models.PlaylistDocument, userId uint64) (*models.PlaylistImportResult, error) {
+	if len(doc.Entries) > MaxImportEntries {
+		return nil, models.ErrInvalidParameter
+	}
+
+	candidates, err := u.findCandidates(doc.Entries)
+	if err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.ImportPlaylist error while search")
+	}
+
+	res := &models.PlaylistImportResult{Unmatched: []*models.PlaylistEntry{}}
+	var trackIds []uint64
+	added := make(map[uint64]bool)
+
+	for _, entry := range doc.Entries {
+		var track *models.TrackDetails
+
+		if title := models.FoldName(entry.Title); title != "" {
+			track = matchEntry(entry, candidates[title])
+		}
+
+		if track == nil {
+			res.Unmatched = append(res.Unmatched, entry)
+			continue
+		}
+
+		res.Matched++
+		// a playlist holds a track once
+		if !added[track.Id] {
+			added[track.Id] = true
+			trackIds = append(trackIds, track.Id)
+		}
+	}
+
+	name := strings.TrimSpace(doc.Name)
+	if name == "" {
+		name = DefaultImportName
+	}
+
+	playlist := &models.Playlist{
+		Name:        name,
+		Description: doc.Description,
+		Kind:        models.PlaylistKindUser,
+	}
+
+	id, err := u.playlistRep.ImportPlaylist(playlist, userId, trackIds)
+	if err != nil {
+		return nil, errors.Wrap(err, "playlist.usecase.ImportPlaylist error while import")
+	}
+
+	res.PlaylistId = id
+
+	return res, nil
+}
+
+// findCandidates looks up tracks for all entries at once and groups them by
+// their folded name
+func (u *usecase) findCandidates(entries []*models.PlaylistEntry) (map[string][]*models.TrackDetails, error) {
+	var lookups []*models.TrackLookup
+	seen := make(map[models.TrackLookup]bool, len(entries))
+	for _, v := range entries {
+		lookup := models.TrackLookup{Name: models.FoldName(v.Title), Musician: models.FoldName(v.Musician)}
+		if lookup.Name == "" || seen[lookup] {
+			continue
+		}
+		seen[lookup] = true
+		lookups = append(lookups, &lookup)
+	}
+
+	res := make(map[string][]*models.TrackDetails, len(lookups))
+	if len(lookups) == 0 {
+		return res, nil
+	}
+
+	tracks, err := u.trackRep.FindTracksByNames(lookups, MatchCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range tracks {
+		name := models.FoldName(v.Name)
+		res[name] = append(res[name], v)
+	}
+
+	return res, nil
+}
+
+// matchEntry picks the candidate with the closest duration among those by the
+// same musician, durations are compared only when both are known
+func matchEntry(entry *models.PlaylistEntry, candidates []*models.TrackDetails) *models.TrackDetails {
+	var best *models.TrackDetails
+	bestDiff := 0
+
+	title, musician := models.FoldName(entry.Title), models.FoldName(entry.Musician)
+	for _, c := range candidates {
+		if models.FoldName(c.Name) != title {
+			continue
+		}
+
+		if musician != "" && models.FoldName(c.Musician) != musician {
+			continue
+		}
+
+		// unknown durations rank after the close known ones
+		diff := MaxDurationDiff
+		if entry.Duration > 0 && c.Duration > 0 {
+			diff = entry.Duration - c.Duration
+			if diff < 0 {
+				diff = -diff
+			}
+
+			if diff > MaxDurationDiff {
+				continue
+			}
+		}
+
+		if best == nil || diff < bestDiff {
+			best, bestDiff = c, diff
+		}
+	}
+
+	return best
+}
+
+// validateRules checks the rules and fills in the defaults
+func validateRules(rules *models.SmartRules) error {
+	if rules.Match == "" {
+		rules.Match = models.SmartMatchAll
+	}
+
+	if rules.Sort == "" {
+		rules.Sort = models.SmartSortNewest
+	}
+
+	if rules.Limit == 0 {
+		rules.Limit = DefaultSmartLimit
+	}
+
+	if rules.Match != models.SmartMatchAll && rules.Match != models.SmartMatchAny {
+		return errors.Wrap(models.ErrInvalidParameter, "unknown match "+rules.Match)
+	}
+
+	if !models.SmartSorts[rules.Sort] {
+		return errors.Wrap(models.ErrInvalidParameter, "unknown sort "+rules.Sort)
+	}
+
+	if rules.Limit < 0 || rules.Limit > MaxSmartLimit {
+		return errors.Wrap(models.ErrInvalidParameter, "limit is out of range")
+	}
+
+	if len(rules.Conditions) == 0 || len(rules.Conditions) > MaxSmartConditions {
+		return errors.Wrap(models.ErrInvalidParameter, "wrong number of conditions")
+	}
+
+	for _, c := range rules.Conditions {
+		if !models.SmartOps[c.Field][c.Op] {
+			return errors.Wrap(models.ErrInvalidParameter, "unsupported condition "+c.Field+" "+c.Op)
+		}
+
+		var valid bool
+		switch c.Op {
+		case models.SmartOpEq, models.SmartOpNeq, models.SmartOpContains:
+			valid = strings.TrimSpace(c.Value) != "" && len(c.Values) == 0 && c.Days == 0
+		case models.SmartOpIn:
+			valid = len(c.Values) > 0 && len(c.Values) <= MaxSmartConditions && c.Value == "" && c.Days == 0
+		case models.SmartOpWithinDays:
+			valid = c.Days > 0 && c.Days <= MaxSmartDays && c.Value == "" && len(c.Values) == 0
+		default:
+			valid = c.Value == "" && len(c.Values) == 0 && c.Days == 0
+		}
+
+		if !valid {
+			return errors.Wrap(models.ErrInvalidParameter, "wrong arguments of condition "+c.Field+" "+c.Op)
+		}
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	mock_repository2 "src/internal/domain/track/repository/mocks"
 	"src/internal/models"
 	"testing"
+	"time"
 )
 
 func TestUsecase_UpdatedPlaylist(t *testing.T) {
@@ -108,6 +109,76 @@ func TestUsecase_AddPlaylist(t *testing.T) {
 			expectedErr: errors.Wrap(errors.New("error in repo"),
 				"playlist.usecase.AddPlaylist error while add"),
 		},
+		{
+			name: "Smart playlist test",
+			inputPlaylist: &models.Playlist{
+				Name: "Liked rock",
+				Rules: &models.SmartRules{Conditions: []models.SmartCondition{
+					{Field: models.SmartFieldGenre, Op: models.SmartOpEq, Value: "Rock"},
+					{Field: models.SmartFieldLiked, Op: models.SmartOpWithinDays, Days: 30},
+				}},
+			},
+			mock: func(r *mock_repository.MockPlaylistRepository, playlist *models.Playlist) {
+				r.EXPECT().AddPlaylist(&models.Playlist{
+					Name:       "Liked rock",
+					Kind:       models.PlaylistKindSmart,
+					Visibility: models.PlaylistPrivate,
+					Rules: &models.SmartRules{
+						Match: models.SmartMatchAll,
+						Conditions: []models.SmartCondition{
+							{Field: models.SmartFieldGenre, Op: models.SmartOpEq, Value: "Rock"},
+							{Field: models.SmartFieldLiked, Op: models.SmartOpWithinDays, Days: 30},
+						},
+						Sort:  models.SmartSortNewest,
+						Limit: DefaultSmartLimit,
+					},
+				}, uint64(0)).Return(uint64(2), nil)
+			},
+			expectedID:  uint64(2),
+			expectedErr: nil,
+		},
+		{
+			name: "Public smart playlist test",
+			inputPlaylist: &models.Playlist{
+				Name:       "Rock",
+				Visibility: models.PlaylistPublic,
+				Rules: &models.SmartRules{Conditions: []models.SmartCondition{
+					{Field: models.SmartFieldGenre, Op: models.SmartOpEq, Value: "Rock"},
+				}},
+			},
+			mock: func(r *mock_repository.MockPlaylistRepository, playlist *models.Playlist) {
+				r.EXPECT().AddPlaylist(gomock.Any(), uint64(0)).
+					DoAndReturn(func(playlist *models.Playlist, userId uint64) (uint64, error) {
+						assert.Equal(t, models.PlaylistPublic, playlist.Visibility)
+						return 3, nil
+					})
+			},
+			expectedID:  uint64(3),
+			expectedErr: nil,
+		},
+		{
+			name: "Invalid rules test",
+			inputPlaylist: &models.Playlist{
+				Name:  "Broken",
+				Rules: &models.SmartRules{},
+			},
+			mock: func(r *mock_repository.MockPlaylistRepository, playlist *models.Playlist) {
+			},
+			expectedID: uint64(0),
+			expectedErr: errors.Wrap(models.ErrInvalidParameter,
+				"wrong number of conditions"),
+		},
+		{
+			name: "Invalid visibility test",
+			inputPlaylist: &models.Playlist{
+				Name:       "Hidden Playlist",
+				Visibility: "friends",
+			},
+			mock: func(r *mock_repository.MockPlaylistRepository, playlist *models.Playlist) {
+			},
+			expectedID:  uint64(0),
+			expectedErr: models.ErrInvalidParameter,
+		},
 	}
 
 	for _, tc := range testTable {
@@ -267,7 +338,7 @@ func TestUsecase_AddTrack(t *testing.T) {
 			playlistId: 1,
 			trackId:    10,
 			mock: func(r *mock_repository.MockPlaylistRepository, playlistId uint64, trackId uint64) {
-				r.EXPECT().AddTrackToPlaylist(playlistId, trackId).Return(nil)
+				r.EXPECT().AddTrackToPlaylist(playlistId, trackId, uint64(5)).Return(nil)
 			},
 			expectedErr: nil,
 		},
@@ -276,7 +347,7 @@ func TestUsecase_AddTrack(t *testing.T) {
 			playlistId: 2,
 			trackId:    20,
 			mock: func(r *mock_repository.MockPlaylistRepository, playlistId uint64, trackId uint64) {
-				r.EXPECT().AddTrackToPlaylist(playlistId, trackId).Return(errors.New("error in repo"))
+				r.EXPECT().AddTrackToPlaylist(playlistId, trackId, uint64(5)).Return(errors.New("error in repo"))
 			},
 			expectedErr: errors.Wrap(errors.New("error in repo"),
 				"playlist.usecase.AddTrack error while add"),
@@ -295,7 +366,7 @@ func TestUsecase_AddTrack(t *testing.T) {
 			trackRepo := mock_repository2.NewMockTrackRepository(ctrl)
 
 			u := NewPlaylistUseCase(repo, trackRepo)
-			err := u.AddTrack(tc.playlistId, tc.trackId)
+			err := u.AddTrack(tc.playlistId, tc.trackId, 5)
 
 			if tc.expectedErr == nil {
 				assert.Nil(t, err)
@@ -439,3 +510,461 @@ func TestUsecase_GetAllTracks(t *testing.T) {
 		})
 	}
 }
+
+func TestUsecase_CanReadPlaylist(t *testing.T) {
+	testTable := []struct {
+		name     string
+		access   *models.PlaylistAccess
+		expected bool
+	}{
+		{
+			name:     "Public test",
+			access:   &models.PlaylistAccess{OwnerId: 2, Visibility: models.PlaylistPublic},
+			expected: true,
+		},
+		{
+			name:     "Unlisted test",
+			access:   &models.PlaylistAccess{OwnerId: 2, Visibility: models.PlaylistUnlisted},
+			expected: true,
+		},
+		{
+			name:     "Private of other user test",
+			access:   &models.PlaylistAccess{OwnerId: 2, Visibility: models.PlaylistPrivate},
+			expected: false,
+		},
+		{
+			name:     "Private of owner test",
+			access:   &models.PlaylistAccess{OwnerId: 1, Visibility: models.PlaylistPrivate},
+			expected: true,
+		},
+		{
+			name:     "Private of collaborator test",
+			access:   &models.PlaylistAccess{OwnerId: 2, Visibility: models.PlaylistPrivate, IsCollaborator: true},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockPlaylistRepository(ctrl)
+			repo.EXPECT().GetPlaylistAccess(uint64(10), uint64(1)).Return(tc.access, nil)
+
+			u := NewPlaylistUseCase(repo, mock_repository2.NewMockTrackRepository(ctrl))
+			ok, err := u.CanReadPlaylist(10, 1)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, ok)
+		})
+	}
+}
+
+func TestUsecase_CanEditPlaylistTracks(t *testing.T) {
+	testTable := []struct {
+		name     string
+		access   *models.PlaylistAccess
+		expected bool
+	}{
+		{
+			name:     "Owner test",
+			access:   &models.PlaylistAccess{OwnerId: 1, Kind: models.PlaylistKindUser},
+			expected: true,
+		},
+		{
+			name: "Collaborator test",
+			access: &models.PlaylistAccess{OwnerId: 2, Kind: models.PlaylistKindUser,
+				Collaborative: true, IsCollaborator: true},
+			expected: true,
+		},
+		{
+			name: "Collaboration turned off test",
+			access: &models.PlaylistAccess{OwnerId: 2, Kind: models.PlaylistKindUser,
+				Collaborative: false, IsCollaborator: true},
+			expected: false,
+		},
+		{
+			name:     "Other user test",
+			access:   &models.PlaylistAccess{OwnerId: 2, Kind: models.PlaylistKindUser, Collaborative: true},
+			expected: false,
+		},
+		{
+			name:     "Daily mix test",
+			access:   &models.PlaylistAccess{OwnerId: 1, Kind: models.PlaylistKindDailyMix},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockPlaylistRepository(ctrl)
+			repo.EXPECT().GetPlaylistAccess(uint64(10), uint64(1)).Return(tc.access, nil)
+
+			u := NewPlaylistUseCase(repo, mock_repository2.NewMockTrackRepository(ctrl))
+			ok, err := u.CanEditPlaylistTracks(10, 1)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, ok)
+		})
+	}
+}
+
+func TestUsecase_CreateInvite(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	type mock func(r *mock_repository.MockPlaylistRepository)
+
+	testTable := []struct {
+		name        string
+		ttl         time.Duration
+		mock        mock
+		expected    *models.PlaylistInvite
+		expectedErr error
+	}{
+		{
+			name: "Default ttl test",
+			ttl:  0,
+			mock: func(r *mock_repository.MockPlaylistRepository) {
+				r.EXPECT().GetPlaylistAccess(uint64(10), uint64(1)).
+					Return(&models.PlaylistAccess{OwnerId: 1, Collaborative: true}, nil)
+				r.EXPECT().AddInvite(&models.PlaylistInvite{
+					Token:      "token",
+					PlaylistId: 10,
+					CreatedBy:  1,
+					ExpiresAt:  now.Add(DefaultInviteTTL),
+				}).Return(nil)
+			},
+			expected: &models.PlaylistInvite{
+				Token:      "token",
+				PlaylistId: 10,
+				CreatedBy:  1,
+				ExpiresAt:  now.Add(DefaultInviteTTL),
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "Too long ttl test",
+			ttl:         MaxInviteTTL + time.Hour,
+			mock:        func(r *mock_repository.MockPlaylistRepository) {},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name: "Not collaborative test",
+			ttl:  time.Hour,
+			mock: func(r *mock_repository.MockPlaylistRepository) {
+				r.EXPECT().GetPlaylistAccess(uint64(10), uint64(1)).
+					Return(&models.PlaylistAccess{OwnerId: 1}, nil)
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockPlaylistRepository(ctrl)
+			tc.mock(repo)
+
+			u := &usecase{
+				playlistRep: repo,
+				trackRep:    mock_repository2.NewMockTrackRepository(ctrl),
+				now:         func() time.Time { return now },
+				newToken:    func() (string, error) { return "token", nil },
+			}
+			invite, err := u.CreateInvite(10, 1, tc.ttl)
+
+			assert.Equal(t, tc.expected, invite)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUsecase_AcceptInvite(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_repository.NewMockPlaylistRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().AcceptInvite("token", uint64(1), now).Return(uint64(10), nil),
+		repo.EXPECT().AcceptInvite("old", uint64(1), now).Return(uint64(0), models.ErrExpired),
+	)
+
+	u := &usecase{playlistRep: repo, now: func() time.Time { return now }}
+
+	id, err := u.AcceptInvite("token", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), id)
+
+	_, err = u.AcceptInvite("old", 1)
+	assert.ErrorIs(t, err, models.ErrExpired)
+}
+
+func TestUsecase_GetPlaylistTracks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_repository.NewMockPlaylistRepository(ctrl)
+	trackRepo := mock_repository2.NewMockTrackRepository(ctrl)
+
+	repo.EXPECT().GetPlaylistTracks(uint64(10)).
+		Return([]*models.PlaylistTrack{{TrackId: 2}, {TrackId: 3}, {TrackId: 1}}, nil)
+	trackRepo.EXPECT().GetTracks([]uint64{2, 3, 1}).Return([]*models.TrackMeta{{Id: 1}, {Id: 2}}, nil)
+
+	u := NewPlaylistUseCase(repo, trackRepo)
+	tracks, err := u.GetPlaylistTracks(10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.PlaylistTrack{
+		{TrackId: 2, Track: &models.TrackMeta{Id: 2}},
+		{TrackId: 1, Track: &models.TrackMeta{Id: 1}},
+	}, tracks)
+}
+
+func TestUsecase_ExportPlaylist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_repository.NewMockPlaylistRepository(ctrl)
+	trackRepo := mock_repository2.NewMockTrackRepository(ctrl)
+
+	repo.EXPECT().GetPlaylist(uint64(10)).Return(&models.Playlist{Id: 10, Name: "Mix", Description: "d"}, nil)
+	repo.EXPECT().GetPlaylistTracks(uint64(10)).Return([]*models.PlaylistTrack{{TrackId: 2}, {TrackId: 1}}, nil)
+	trackRepo.EXPECT().GetTracksDetails([]uint64{2, 1}).Return([]*models.TrackDetails{
+		{TrackMeta: models.TrackMeta{Id: 2, Name: "Second"}, Musician: "Band", Duration: 200},
+		{TrackMeta: models.TrackMeta{Id: 1, Name: "First"}, Musician: "Band"},
+	}, nil)
+
+	u := NewPlaylistUseCase(repo, trackRepo)
+	doc, err := u.ExportPlaylist(10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.PlaylistDocument{
+		Name:        "Mix",
+		Description: "d",
+		Entries: []*models.PlaylistEntry{
+			{Title: "Second", Musician: "Band", Duration: 200, Location: "/api/track/2"},
+			{Title: "First", Musician: "Band", Location: "/api/track/1"},
+		},
+	}, doc)
+}
+
+func TestUsecase_ImportPlaylist(t *testing.T) {
+	type mock func(r *mock_repository.MockPlaylistRepository, tr *mock_repository2.MockTrackRepository)
+
+	song := func(id uint64, musician string, duration int) *models.TrackDetails {
+		return &models.TrackDetails{
+			TrackMeta: models.TrackMeta{Id: id, Name: "Song"},
+			Musician:  musician,
+			Duration:  duration,
+		}
+	}
+
+	testTable := []struct {
+		name        string
+		doc         *models.PlaylistDocument
+		mock        mock
+		expected    *models.PlaylistImportResult
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			doc: &models.PlaylistDocument{Entries: []*models.PlaylistEntry{
+				{Title: "Song", Musician: "band", Duration: 181},
+				{Title: "Missing"},
+				{Title: ""},
+			}},
+			mock: func(r *mock_repository.MockPlaylistRepository, tr *mock_repository2.MockTrackRepository) {
+				tr.EXPECT().FindTracksByNames([]*models.TrackLookup{{Name: "song", Musician: "band"}, {Name: "missing"}},
+					MatchCandidates).
+					Return([]*models.TrackDetails{song(1, "Other", 180), song(2, "Band", 0), song(3, "Band", 183)}, nil)
+				r.EXPECT().ImportPlaylist(&models.Playlist{Name: DefaultImportName, Kind: models.PlaylistKindUser},
+					uint64(5), []uint64{3}).Return(uint64(10), nil)
+			},
+			expected: &models.PlaylistImportResult{
+				PlaylistId: 10,
+				Matched:    1,
+				Unmatched:  []*models.PlaylistEntry{{Title: "Missing"}, {Title: ""}},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Duration too far test",
+			doc: &models.PlaylistDocument{Name: "Trip", Entries: []*models.PlaylistEntry{
+				{Title: "Song", Duration: 100},
+				{Title: " song "},
+			}},
+			mock: func(r *mock_repository.MockPlaylistRepository, tr *mock_repository2.MockTrackRepository) {
+				tr.EXPECT().FindTracksByNames([]*models.TrackLookup{{Name: "song"}}, MatchCandidates).
+					Return([]*models.TrackDetails{song(1, "Band", 180)}, nil)
+				r.EXPECT().ImportPlaylist(&models.Playlist{Name: "Trip", Kind: models.PlaylistKindUser},
+					uint64(5), []uint64{1}).Return(uint64(11), nil)
+			},
+			expected: &models.PlaylistImportResult{
+				PlaylistId: 11,
+				Matched:    1,
+				Unmatched:  []*models.PlaylistEntry{{Title: "Song", Duration: 100}},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Too many entries test",
+			doc:  &models.PlaylistDocument{Entries: make([]*models.PlaylistEntry, MaxImportEntries+1)},
+			mock: func(r *mock_repository.MockPlaylistRepository, tr *mock_repository2.MockTrackRepository) {
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name: "Search fail test",
+			doc:  &models.PlaylistDocument{Entries: []*models.PlaylistEntry{{Title: "Song"}}},
+			mock: func(r *mock_repository.MockPlaylistRepository, tr *mock_repository2.MockTrackRepository) {
+				tr.EXPECT().FindTracksByNames([]*models.TrackLookup{{Name: "song"}}, MatchCandidates).
+					Return(nil, errors.New("error in repo"))
+			},
+			expected: nil,
+			expectedErr: errors.Wrap(errors.New("error in repo"),
+				"playlist.usecase.ImportPlaylist error while search"),
+		},
+		{
+			name: "Repo fail test",
+			doc:  &models.PlaylistDocument{Name: "Empty"},
+			mock: func(r *mock_repository.MockPlaylistRepository, tr *mock_repository2.MockTrackRepository) {
+				r.EXPECT().ImportPlaylist(gomock.Any(), uint64(5), nil).Return(uint64(0), errors.New("error in repo"))
+			},
+			expected: nil,
+			expectedErr: errors.Wrap(errors.New("error in repo"),
+				"playlist.usecase.ImportPlaylist error while import"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockPlaylistRepository(ctrl)
+			trackRepo := mock_repository2.NewMockTrackRepository(ctrl)
+			tc.mock(repo, trackRepo)
+
+			u := NewPlaylistUseCase(repo, trackRepo)
+			res, err := u.ImportPlaylist(tc.doc, 5)
+
+			assert.Equal(t, tc.expected, res)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUsecase_UpdatedPlaylistRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_repository.NewMockPlaylistRepository(ctrl)
+	rules := &models.SmartRules{Conditions: []models.SmartCondition{
+		{Field: models.SmartFieldMusician, Op: models.SmartOpFollowed},
+	}}
+
+	gomock.InOrder(
+		repo.EXPECT().GetPlaylist(uint64(1)).Return(&models.Playlist{Id: 1, Kind: models.PlaylistKindUser}, nil),
+		repo.EXPECT().GetPlaylist(uint64(2)).Return(&models.Playlist{Id: 2, Kind: models.PlaylistKindSmart}, nil),
+		repo.EXPECT().UpdatePlaylist(gomock.Any()).Return(nil),
+	)
+
+	u := NewPlaylistUseCase(repo, mock_repository2.NewMockTrackRepository(ctrl))
+
+	err := u.UpdatedPlaylist(&models.Playlist{Id: 1, Rules: rules})
+	assert.ErrorIs(t, err, models.ErrInvalidParameter)
+
+	err = u.UpdatedPlaylist(&models.Playlist{Id: 2, Rules: rules})
+	assert.NoError(t, err)
+}
+
+func TestValidateRules(t *testing.T) {
+	condition := func(field string, op string) models.SmartCondition {
+		return models.SmartCondition{Field: field, Op: op}
+	}
+
+	testTable := []struct {
+		name  string
+		rules *models.SmartRules
+		valid bool
+	}{
+		{
+			name: "Followed newest test",
+			rules: &models.SmartRules{Match: models.SmartMatchAny, Sort: models.SmartSortNewest, Limit: 50,
+				Conditions: []models.SmartCondition{condition(models.SmartFieldMusician, models.SmartOpFollowed)}},
+			valid: true,
+		},
+		{
+			name: "Genre in test",
+			rules: &models.SmartRules{Conditions: []models.SmartCondition{
+				{Field: models.SmartFieldGenre, Op: models.SmartOpIn, Values: []string{"Rock", "Pop"}}}},
+			valid: true,
+		},
+		{
+			name: "Unknown match test",
+			rules: &models.SmartRules{Match: "none",
+				Conditions: []models.SmartCondition{condition(models.SmartFieldLiked, models.SmartOpAny)}},
+			valid: false,
+		},
+		{
+			name: "Unknown sort test",
+			rules: &models.SmartRules{Sort: "oldest",
+				Conditions: []models.SmartCondition{condition(models.SmartFieldLiked, models.SmartOpAny)}},
+			valid: false,
+		},
+		{
+			name: "Limit too big test",
+			rules: &models.SmartRules{Limit: MaxSmartLimit + 1,
+				Conditions: []models.SmartCondition{condition(models.SmartFieldLiked, models.SmartOpAny)}},
+			valid: false,
+		},
+		{
+			name:  "Unsupported operator test",
+			rules: &models.SmartRules{Conditions: []models.SmartCondition{condition(models.SmartFieldName, models.SmartOpEq)}},
+			valid: false,
+		},
+		{
+			name:  "Missing value test",
+			rules: &models.SmartRules{Conditions: []models.SmartCondition{condition(models.SmartFieldGenre, models.SmartOpEq)}},
+			valid: false,
+		},
+		{
+			name: "Days out of range test",
+			rules: &models.SmartRules{Conditions: []models.SmartCondition{
+				{Field: models.SmartFieldPlayed, Op: models.SmartOpWithinDays, Days: MaxSmartDays + 1}}},
+			valid: false,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRules(tc.rules)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, models.ErrInvalidParameter)
+			}
+		})
+	}
+}
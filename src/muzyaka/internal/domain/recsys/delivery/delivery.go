@@ -1,6 +1,7 @@
 package delivery
 
 import (
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"net/http"
 	usecase2 "src/internal/domain/recsys/usecase"
@@ -19,7 +20,7 @@ import (
 // @Param        id    query     uint64  true  "id of song"
 // @Param        page    query     int  true  "number of page from 1"
 // @Param        page_size    query     int  true  "size of page"
-// @Success 200 {object} dto.TracksMetaCollection
+// @Success 200 {object} dto.RecommendedTracks
 // @Failure 400,404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Failure default {object} response.Response
@@ -50,7 +51,51 @@ func GetRecommendedTracks(useCase usecase2.RecSysUseCase) http.HandlerFunc {
 			return
 		}
 
-		tracks, err := useCase.GetSameTracks(id, page, pageSize)
+		recs, err := useCase.GetSameTracks(id, page, pageSize)
+
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoRecommendedTracks(recs))
+	}
+}
+
+// @Summary GetTrendingTracks
+// @Security ApiKeyAuth
+// @Tags track
+// @Description get trending tracks pushed by the recommendation service
+// @ID get-trending-tracks
+// @Accept  json
+// @Produce  json
+// @Param        page    query     int  true  "number of page from 1"
+// @Param        page_size    query     int  true  "size of page"
+// @Success 200 {object} dto.TracksMetaCollection
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/track/trending [get]
+func GetTrendingTracks(useCase usecase2.RecSysUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pageStr := r.URL.Query().Get("page")
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageSizeStr := r.URL.Query().Get("page_size")
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		tracks, err := useCase.GetTrendingTracks(page, pageSize)
 
 		if err != nil {
 			render.Status(r, http.StatusInternalServerError)
@@ -66,3 +111,56 @@ func GetRecommendedTracks(useCase usecase2.RecSysUseCase) http.HandlerFunc {
 		render.JSON(w, r, dto.TracksMetaCollection{Tracks: res})
 	}
 }
+
+// @Summary GetUserRecommendations
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get personal recommendations based on liked tracks, playlists, listening history and followed musicians
+// @ID get-user-recommendations
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "user ID"
+// @Param        page    query     int  true  "number of page from 1"
+// @Param        page_size    query     int  true  "size of page"
+// @Success 200 {object} dto.RecommendedTracks
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/user/{user_id}/recommendations [get]
+func GetUserRecommendations(useCase usecase2.RecSysUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "user_id")
+		userIDUint, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageStr := r.URL.Query().Get("page")
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		pageSizeStr := r.URL.Query().Get("page_size")
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		recs, err := useCase.GetUserRecommendations(userIDUint, page, pageSize)
+
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoRecommendedTracks(recs))
+	}
+}
@@ -47,3 +47,17 @@ func (mr *MockRecSysProviderMockRecorder) GetRecs(id, page, pageSize interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecs", reflect.TypeOf((*MockRecSysProvider)(nil).GetRecs), id, page, pageSize)
 }
+
+// Source mocks base method.
+func (m *MockRecSysProvider) Source() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Source")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Source indicates an expected call of Source.
+func (mr *MockRecSysProviderMockRecorder) Source() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Source", reflect.TypeOf((*MockRecSysProvider)(nil).Source))
+}
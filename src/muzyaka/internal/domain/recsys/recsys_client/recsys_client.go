@@ -1,54 +1,142 @@
 package recsys_client
 
 import (
+	"context"
+	"fmt"
 	"github.com/go-chi/render"
 	"github.com/pkg/errors"
+	"math/rand"
 	"net/http"
 	url2 "net/url"
+	"src/internal/config"
+	"src/internal/lib/breaker"
+	"src/internal/models"
 	"strconv"
+	"time"
 )
 
 //go:generate mockgen -source=recsys_client.go -destination=mocks/mock.go
 type RecSysProvider interface {
 	GetRecs(id uint64, page int, pageSize int) ([]uint64, error)
+	// Source is the recommendation source the results are labelled with
+	Source() string
 }
 
 type RecSysResponse struct {
 	Ids []uint64 `json:"ids"`
 }
 
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d", e.code)
+}
+
 type recsysRemote struct {
-	addr string
+	addr           string
+	client         *http.Client
+	timeout        time.Duration
+	retries        int
+	retryBaseDelay time.Duration
+	breaker        *breaker.Breaker
 }
 
-func NewRecSysClient(addr string) RecSysProvider {
-	return &recsysRemote{addr: addr}
+func NewRecSysClient(cfg config.RecSys) RecSysProvider {
+	return &recsysRemote{
+		addr:           cfg.Address,
+		client:         &http.Client{},
+		timeout:        cfg.Timeout,
+		retries:        cfg.Retries,
+		retryBaseDelay: cfg.RetryBaseDelay,
+		breaker:        breaker.New(cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
 }
 
-func (r recsysRemote) GetRecs(id uint64, page int, pageSize int) ([]uint64, error) {
-	request, err := http.NewRequest("GET", r.addr, nil)
-	if err != nil {
+func (r *recsysRemote) Source() string {
+	return models.RecSourceRemote
+}
+
+func (r *recsysRemote) GetRecs(id uint64, page int, pageSize int) ([]uint64, error) {
+	if err := r.breaker.Allow(); err != nil {
 		return nil, errors.Wrap(err, "recsys.recsys_client.GetRecs error")
 	}
+
+	var err error
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(jitter(r.retryBaseDelay, attempt))
+		}
+
+		var ids []uint64
+		ids, err = r.getRecs(id, page, pageSize)
+		if err == nil {
+			r.breaker.Success()
+			return ids, nil
+		}
+
+		if !isRetryable(err) {
+			// the service answered, it is up
+			r.breaker.Success()
+			return nil, errors.Wrap(err, "recsys.recsys_client.GetRecs error")
+		}
+	}
+
+	r.breaker.Failure()
+	return nil, errors.Wrap(err, "recsys.recsys_client.GetRecs error")
+}
+
+func (r *recsysRemote) getRecs(id uint64, page int, pageSize int) ([]uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, r.addr, nil)
+	if err != nil {
+		return nil, err
+	}
 	request.URL.RawQuery = url2.Values{
 		"id":        {strconv.FormatUint(id, 10)},
 		"page":      {strconv.Itoa(page)},
 		"page_size": {strconv.Itoa(pageSize)},
 	}.Encode()
 
-	client := http.DefaultClient
-	respGot, err := client.Do(request)
+	respGot, err := r.client.Do(request)
 	if err != nil {
-		return nil, errors.Wrap(err, "recsys.recsys_client.GetRecs error")
+		return nil, err
 	}
 	defer respGot.Body.Close()
 
+	if respGot.StatusCode != http.StatusOK {
+		return nil, &statusError{code: respGot.StatusCode}
+	}
+
 	var resp RecSysResponse
 	err = render.DecodeJSON(respGot.Body, &resp)
-
 	if err != nil {
-		return nil, errors.Wrap(err, "recsys.recsys_client.GetRecs error")
+		return nil, err
 	}
 
 	return resp.Ids, nil
 }
+
+// isRetryable is false only for client errors, everything else may be caused
+// by an overloaded or restarting service
+func isRetryable(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code >= http.StatusInternalServerError || statusErr.code == http.StatusTooManyRequests
+	}
+
+	return true
+}
+
+// jitter returns a random delay up to base * 2^(attempt-1)
+func jitter(base time.Duration, attempt int) time.Duration {
+	ceiling := base << (attempt - 1)
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
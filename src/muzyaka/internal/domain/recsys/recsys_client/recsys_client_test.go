@@ -0,0 +1,147 @@
+package recsys_client
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"src/internal/config"
+	mock_repository "src/internal/domain/recsys/repository/mocks"
+	"src/internal/lib/breaker"
+	"src/internal/models"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(addr string) *recsysRemote {
+	return NewRecSysClient(config.RecSys{
+		Address:          addr,
+		Timeout:          100 * time.Millisecond,
+		Retries:          2,
+		RetryBaseDelay:   time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Hour,
+	}).(*recsysRemote)
+}
+
+func TestRecSysRemote_GetRecs(t *testing.T) {
+	testTable := []struct {
+		name          string
+		statuses      []int
+		expectedIds   []uint64
+		expectedCalls int32
+		expectErr     bool
+	}{
+		{
+			name:          "Usual test",
+			statuses:      []int{http.StatusOK},
+			expectedIds:   []uint64{1, 2},
+			expectedCalls: 1,
+			expectErr:     false,
+		},
+		{
+			name:          "Retry test",
+			statuses:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedIds:   []uint64{1, 2},
+			expectedCalls: 2,
+			expectErr:     false,
+		},
+		{
+			name:          "Client error is not retried",
+			statuses:      []int{http.StatusBadRequest},
+			expectedIds:   nil,
+			expectedCalls: 1,
+			expectErr:     true,
+		},
+		{
+			name: "Retries exhausted",
+			statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError,
+				http.StatusInternalServerError},
+			expectedIds:   nil,
+			expectedCalls: 3,
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := calls.Add(1)
+				status := tc.statuses[call-1]
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					_, _ = w.Write([]byte(`{"ids":[1,2]}`))
+				}
+			}))
+			defer srv.Close()
+
+			ids, err := newTestClient(srv.URL).GetRecs(1, 1, 10)
+
+			assert.Equal(t, tc.expectedIds, ids)
+			assert.Equal(t, tc.expectedCalls, calls.Load())
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRecSysRemote_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	client.retries = 0
+
+	start := time.Now()
+	_, err := client.GetRecs(1, 1, 10)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRecSysRemote_CircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	client.retries = 0
+
+	_, err := client.GetRecs(1, 1, 10)
+	assert.Error(t, err)
+	_, err = client.GetRecs(1, 1, 10)
+	assert.Error(t, err)
+
+	_, err = client.GetRecs(1, 1, 10)
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRecSysLocal_GetRecs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_repository.NewMockRecSysRepository(ctrl)
+	repo.EXPECT().GetNeighbourTracks(uint64(1), 20, 10).Return([]uint64{5, 6}, nil)
+	repo.EXPECT().GetNeighbourTracks(uint64(2), 0, 10).Return(nil, errors.New("error"))
+
+	local := NewLocalRecSys(repo)
+	assert.Equal(t, models.RecSourceLocal, local.Source())
+
+	ids, err := local.GetRecs(1, 3, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{5, 6}, ids)
+
+	_, err = local.GetRecs(2, 1, 10)
+	assert.EqualError(t, err, errors.Wrap(errors.New("error"), "recsys.recsys_client.GetRecs error").Error())
+}
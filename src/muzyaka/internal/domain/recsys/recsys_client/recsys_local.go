@@ -0,0 +1,29 @@
+package recsys_client
+
+import (
+	"github.com/pkg/errors"
+	"src/internal/domain/recsys/repository"
+	"src/internal/models"
+)
+
+// recsysLocal serves neighbours precomputed by the recsys_builder job
+type recsysLocal struct {
+	recsRep repository.RecSysRepository
+}
+
+func NewLocalRecSys(recsRep repository.RecSysRepository) RecSysProvider {
+	return &recsysLocal{recsRep: recsRep}
+}
+
+func (r recsysLocal) Source() string {
+	return models.RecSourceLocal
+}
+
+func (r recsysLocal) GetRecs(id uint64, page int, pageSize int) ([]uint64, error) {
+	ids, err := r.recsRep.GetNeighbourTracks(id, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "recsys.recsys_client.GetRecs error")
+	}
+
+	return ids, nil
+}
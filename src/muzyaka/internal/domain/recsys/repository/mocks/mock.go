@@ -0,0 +1,183 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRecSysRepository is a mock of RecSysRepository interface.
+type MockRecSysRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecSysRepositoryMockRecorder
+}
+
+// MockRecSysRepositoryMockRecorder is the mock recorder for MockRecSysRepository.
+type MockRecSysRepositoryMockRecorder struct {
+	mock *MockRecSysRepository
+}
+
+// NewMockRecSysRepository creates a new mock instance.
+func NewMockRecSysRepository(ctrl *gomock.Controller) *MockRecSysRepository {
+	mock := &MockRecSysRepository{ctrl: ctrl}
+	mock.recorder = &MockRecSysRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecSysRepository) EXPECT() *MockRecSysRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetActiveUsers mocks base method.
+func (m *MockRecSysRepository) GetActiveUsers(afterId uint64, limit int) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveUsers", afterId, limit)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveUsers indicates an expected call of GetActiveUsers.
+func (mr *MockRecSysRepositoryMockRecorder) GetActiveUsers(afterId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveUsers", reflect.TypeOf((*MockRecSysRepository)(nil).GetActiveUsers), afterId, limit)
+}
+
+// GetFallbackTracks mocks base method.
+func (m *MockRecSysRepository) GetFallbackTracks(trackId uint64, offset, limit int) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFallbackTracks", trackId, offset, limit)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFallbackTracks indicates an expected call of GetFallbackTracks.
+func (mr *MockRecSysRepositoryMockRecorder) GetFallbackTracks(trackId, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFallbackTracks", reflect.TypeOf((*MockRecSysRepository)(nil).GetFallbackTracks), trackId, offset, limit)
+}
+
+// GetLikedTracks mocks base method.
+func (m *MockRecSysRepository) GetLikedTracks(userId uint64) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLikedTracks", userId)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLikedTracks indicates an expected call of GetLikedTracks.
+func (mr *MockRecSysRepositoryMockRecorder) GetLikedTracks(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLikedTracks", reflect.TypeOf((*MockRecSysRepository)(nil).GetLikedTracks), userId)
+}
+
+// GetNeighbourTracks mocks base method.
+func (m *MockRecSysRepository) GetNeighbourTracks(trackId uint64, offset, limit int) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNeighbourTracks", trackId, offset, limit)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNeighbourTracks indicates an expected call of GetNeighbourTracks.
+func (mr *MockRecSysRepositoryMockRecorder) GetNeighbourTracks(trackId, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNeighbourTracks", reflect.TypeOf((*MockRecSysRepository)(nil).GetNeighbourTracks), trackId, offset, limit)
+}
+
+// GetSimilarTracks mocks base method.
+func (m *MockRecSysRepository) GetSimilarTracks(trackId uint64, offset, limit int) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSimilarTracks", trackId, offset, limit)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSimilarTracks indicates an expected call of GetSimilarTracks.
+func (mr *MockRecSysRepositoryMockRecorder) GetSimilarTracks(trackId, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimilarTracks", reflect.TypeOf((*MockRecSysRepository)(nil).GetSimilarTracks), trackId, offset, limit)
+}
+
+// GetTracksInfo mocks base method.
+func (m *MockRecSysRepository) GetTracksInfo(ids []uint64) ([]*models.TrackInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTracksInfo", ids)
+	ret0, _ := ret[0].([]*models.TrackInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTracksInfo indicates an expected call of GetTracksInfo.
+func (mr *MockRecSysRepositoryMockRecorder) GetTracksInfo(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracksInfo", reflect.TypeOf((*MockRecSysRepository)(nil).GetTracksInfo), ids)
+}
+
+// GetTrendingTracks mocks base method.
+func (m *MockRecSysRepository) GetTrendingTracks(offset, limit int) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrendingTracks", offset, limit)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrendingTracks indicates an expected call of GetTrendingTracks.
+func (mr *MockRecSysRepositoryMockRecorder) GetTrendingTracks(offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrendingTracks", reflect.TypeOf((*MockRecSysRepository)(nil).GetTrendingTracks), offset, limit)
+}
+
+// GetUserSeeds mocks base method.
+func (m *MockRecSysRepository) GetUserSeeds(userId uint64, limit int) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSeeds", userId, limit)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSeeds indicates an expected call of GetUserSeeds.
+func (mr *MockRecSysRepositoryMockRecorder) GetUserSeeds(userId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSeeds", reflect.TypeOf((*MockRecSysRepository)(nil).GetUserSeeds), userId, limit)
+}
+
+// SaveSimilarTracks mocks base method.
+func (m *MockRecSysRepository) SaveSimilarTracks(eventId string, similar *models.SimilarTracks) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSimilarTracks", eventId, similar)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSimilarTracks indicates an expected call of SaveSimilarTracks.
+func (mr *MockRecSysRepositoryMockRecorder) SaveSimilarTracks(eventId, similar interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSimilarTracks", reflect.TypeOf((*MockRecSysRepository)(nil).SaveSimilarTracks), eventId, similar)
+}
+
+// SaveTrendingTracks mocks base method.
+func (m *MockRecSysRepository) SaveTrendingTracks(eventId string, trending *models.TrendingTracks) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTrendingTracks", eventId, trending)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTrendingTracks indicates an expected call of SaveTrendingTracks.
+func (mr *MockRecSysRepositoryMockRecorder) SaveTrendingTracks(eventId, trending interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTrendingTracks", reflect.TypeOf((*MockRecSysRepository)(nil).SaveTrendingTracks), eventId, trending)
+}
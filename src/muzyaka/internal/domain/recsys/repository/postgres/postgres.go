@@ -0,0 +1,319 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"src/internal/domain/recsys/repository"
+	"src/internal/lib/inbox"
+	"src/internal/lib/kafka"
+	"src/internal/models"
+	"src/internal/models/dao"
+)
+
+const (
+	// only the latest completed plays describe the current taste
+	recentPlaysLimit = 200
+	// a followed musician is represented by their latest tracks
+	followedTracksLimit = 5
+)
+
+type recSysRepository struct {
+	db *gorm.DB
+}
+
+func NewRecSysRepository(db *gorm.DB) repository.RecSysRepository {
+	return &recSysRepository{db: db}
+}
+
+func (r *recSysRepository) SaveSimilarTracks(eventId string, similar *models.SimilarTracks) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := inbox.MarkProcessed(tx, kafka.RecSysGroup, eventId); err != nil {
+			return err
+		}
+
+		// results computed earlier than the stored ones are stale
+		var newer int64
+		if err := tx.Model(&dao.SimilarTrack{}).
+			Where("track_id = ? AND computed_at > ?", similar.TrackId, similar.ComputedAt).
+			Count(&newer).Error; err != nil {
+			return err
+		}
+		if newer > 0 {
+			return nil
+		}
+
+		if err := tx.Where("track_id = ?", similar.TrackId).Delete(&dao.SimilarTrack{}).Error; err != nil {
+			return err
+		}
+
+		ids := []uint64{similar.TrackId}
+		for _, v := range similar.Tracks {
+			ids = append(ids, v.TrackId)
+		}
+
+		existing, err := existingTracks(tx, ids)
+		if err != nil {
+			return err
+		}
+		if !existing[similar.TrackId] {
+			return nil
+		}
+
+		var pgSimilar []*dao.SimilarTrack
+		for _, v := range similar.Tracks {
+			if !existing[v.TrackId] || v.TrackId == similar.TrackId {
+				continue
+			}
+			pgSimilar = append(pgSimilar, &dao.SimilarTrack{
+				TrackId:        similar.TrackId,
+				SimilarTrackId: v.TrackId,
+				Position:       len(pgSimilar) + 1,
+				Score:          v.Score,
+				ComputedAt:     similar.ComputedAt,
+			})
+		}
+
+		if len(pgSimilar) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&pgSimilar).Error
+	})
+
+	if errors.Is(err, models.ErrAlreadyProcessed) {
+		return err
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table similar_tracks)")
+	}
+
+	return nil
+}
+
+func (r *recSysRepository) SaveTrendingTracks(eventId string, trending *models.TrendingTracks) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := inbox.MarkProcessed(tx, kafka.RecSysGroup, eventId); err != nil {
+			return err
+		}
+
+		var newer int64
+		if err := tx.Model(&dao.TrendingTrack{}).
+			Where("computed_at > ?", trending.ComputedAt).
+			Count(&newer).Error; err != nil {
+			return err
+		}
+		if newer > 0 {
+			return nil
+		}
+
+		if err := tx.Where("1 = 1").Delete(&dao.TrendingTrack{}).Error; err != nil {
+			return err
+		}
+
+		ids := make([]uint64, 0, len(trending.Tracks))
+		for _, v := range trending.Tracks {
+			ids = append(ids, v.TrackId)
+		}
+
+		existing, err := existingTracks(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		var pgTrending []*dao.TrendingTrack
+		for _, v := range trending.Tracks {
+			if !existing[v.TrackId] {
+				continue
+			}
+			pgTrending = append(pgTrending, &dao.TrendingTrack{
+				TrackId:    v.TrackId,
+				Position:   len(pgTrending) + 1,
+				Score:      v.Score,
+				ComputedAt: trending.ComputedAt,
+			})
+		}
+
+		if len(pgTrending) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&pgTrending).Error
+	})
+
+	if errors.Is(err, models.ErrAlreadyProcessed) {
+		return err
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table trending_tracks)")
+	}
+
+	return nil
+}
+
+func (r *recSysRepository) GetSimilarTracks(trackId uint64, offset int, limit int) ([]uint64, error) {
+	var ids []uint64
+	tx := r.db.Model(&dao.SimilarTrack{}).
+		Where("track_id = ?", trackId).
+		Order("position").
+		Offset(offset).
+		Limit(limit).
+		Pluck("similar_track_id", &ids)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table similar_tracks)")
+	}
+
+	return ids, nil
+}
+
+func (r *recSysRepository) GetTrendingTracks(offset int, limit int) ([]uint64, error) {
+	var ids []uint64
+	tx := r.db.Model(&dao.TrendingTrack{}).
+		Order("position").
+		Offset(offset).
+		Limit(limit).
+		Pluck("track_id", &ids)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table trending_tracks)")
+	}
+
+	return ids, nil
+}
+
+func (r *recSysRepository) GetNeighbourTracks(trackId uint64, offset int, limit int) ([]uint64, error) {
+	var ids []uint64
+	tx := r.db.Model(&dao.TrackNeighbour{}).
+		Where("track_id = ?", trackId).
+		Order("position").
+		Offset(offset).
+		Limit(limit).
+		Pluck("neighbour_id", &ids)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track_neighbours)")
+	}
+
+	return ids, nil
+}
+
+func (r *recSysRepository) GetFallbackTracks(trackId uint64, offset int, limit int) ([]uint64, error) {
+	var ids []uint64
+	tx := r.db.Raw(`
+		SELECT t.id
+		FROM tracks t
+		JOIN albums a ON a.id = t.album_id
+		JOIN tracks src ON src.id = ?
+		JOIN albums src_album ON src_album.id = src.album_id
+		WHERE t.id <> src.id
+		  AND (t.genre = src.genre OR t.album_id = src.album_id OR a.musician_id = src_album.musician_id)
+		ORDER BY COALESCE(t.genre = src.genre, FALSE)::int
+		       + (t.album_id = src.album_id)::int
+		       + (a.musician_id = src_album.musician_id)::int DESC,
+		         t.id
+		OFFSET ? LIMIT ?`, trackId, offset, limit).Scan(&ids)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table tracks)")
+	}
+
+	return ids, nil
+}
+
+func (r *recSysRepository) GetUserSeeds(userId uint64, limit int) ([]uint64, error) {
+	var ids []uint64
+	tx := r.db.Raw(`
+		SELECT track_id
+		FROM (SELECT track_id, liked_at AS at FROM user_track WHERE user_id = ?
+		      UNION ALL
+		      SELECT tp.track_id, tp.added_at
+		      FROM track_playlist tp
+		      JOIN playlists p ON p.id = tp.playlist_id
+		      WHERE p.user_id = ? AND p.kind = ?
+		      UNION ALL
+		      SELECT track_id, played_at
+		      FROM (SELECT track_id, played_at FROM plays
+		            WHERE user_id = ? AND completed
+		            ORDER BY played_at DESC
+		            LIMIT ?) recent
+		      UNION ALL
+		      SELECT latest.id, mf.followed_at
+		      FROM musician_followers mf
+		      CROSS JOIN LATERAL (SELECT t.id
+		                          FROM tracks t
+		                          JOIN albums a ON a.id = t.album_id
+		                          WHERE a.musician_id = mf.musician_id
+		                          ORDER BY t.id DESC
+		                          LIMIT ?) latest
+		      WHERE mf.user_id = ?) seeds
+		GROUP BY track_id
+		ORDER BY MAX(at) DESC, track_id DESC
+		LIMIT ?`, userId, userId, models.PlaylistKindUser, userId, recentPlaysLimit,
+		followedTracksLimit, userId, limit).Scan(&ids)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table user_track)")
+	}
+
+	return ids, nil
+}
+
+func (r *recSysRepository) GetLikedTracks(userId uint64) ([]uint64, error) {
+	var ids []uint64
+	tx := r.db.Model(&dao.UserTrack{}).Where("user_id = ?", userId).Pluck("track_id", &ids)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table user_track)")
+	}
+
+	return ids, nil
+}
+
+func (r *recSysRepository) GetTracksInfo(ids []uint64) ([]*models.TrackInfo, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var info []*models.TrackInfo
+	tx := r.db.Raw(`
+		SELECT t.id, COALESCE(t.genre, 0) AS genre_id, a.musician_id
+		FROM tracks t
+		JOIN albums a ON a.id = t.album_id
+		WHERE t.id IN ?`, ids).Scan(&info)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table tracks)")
+	}
+
+	return info, nil
+}
+
+func (r *recSysRepository) GetActiveUsers(afterId uint64, limit int) ([]uint64, error) {
+	var ids []uint64
+	tx := r.db.Raw(`
+		SELECT u.id
+		FROM users u
+		WHERE u.id > ?
+		  AND (EXISTS (SELECT 1 FROM user_track ut WHERE ut.user_id = u.id)
+		    OR EXISTS (SELECT 1 FROM playlists p WHERE p.user_id = u.id AND p.kind = ?)
+		    OR EXISTS (SELECT 1 FROM plays pl WHERE pl.user_id = u.id AND pl.completed)
+		    OR EXISTS (SELECT 1 FROM musician_followers mf WHERE mf.user_id = u.id))
+		ORDER BY u.id
+		LIMIT ?`, afterId, models.PlaylistKindUser, limit).Scan(&ids)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table users)")
+	}
+
+	return ids, nil
+}
+
+// existingTracks skips ids of tracks deleted after the results were computed
+func existingTracks(tx *gorm.DB, ids []uint64) (map[uint64]bool, error) {
+	existing := make(map[uint64]bool, len(ids))
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	var found []uint64
+	if err := tx.Model(&dao.TrackMeta{}).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, err
+	}
+
+	for _, v := range found {
+		existing[v] = true
+	}
+
+	return existing, nil
+}
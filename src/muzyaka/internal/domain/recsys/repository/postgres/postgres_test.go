@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	postgres2 "src/internal/domain/album/repository/postgres"
+	"src/internal/lib/testhelpers"
+	"src/internal/models"
+	"testing"
+	"time"
+)
+
+func TestRepo_SaveSimilarTracks(t *testing.T) {
+	ctx := context.Background()
+	pgContainer, err := testhelpers.CreatePostgresContainer(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer func() {
+		if err := pgContainer.Terminate(ctx); err != nil {
+			log.Fatalf("error terminating postgres container: %s", err)
+		}
+	}()
+
+	db, err := gorm.Open(postgres.Open(pgContainer.ConnectionString), &gorm.Config{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = db.Exec("insert into genres (name) values ('test')").Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = db.Exec("insert into musicians (name, description) values ('test', 'test')").Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	albumRepository := postgres2.NewAlbumRepository(db)
+	_, err = albumRepository.AddAlbumWithTracksOutbox(&models.Album{
+		Name:      "TestName",
+		CoverFile: []byte("TestCover"),
+		Type:      "LP",
+	}, []*models.TrackMeta{
+		{Source: "TestSrc1", Name: "TestName1", Genre: "test"},
+		{Source: "TestSrc2", Name: "TestName2", Genre: "test"},
+		{Source: "TestSrc3", Name: "TestName3", Genre: "test"},
+	}, 1)
+	require.NoError(t, err)
+
+	repository := NewRecSysRepository(db)
+	computedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	similar := &models.SimilarTracks{
+		TrackId: 1,
+		Tracks: []*models.ScoredTrack{
+			{TrackId: 3, Score: 0.9},
+			{TrackId: 100, Score: 0.8}, // unknown track is skipped
+			{TrackId: 2, Score: 0.5},
+		},
+		ComputedAt: computedAt,
+	}
+
+	err = repository.SaveSimilarTracks("e1", similar)
+	assert.NoError(t, err)
+
+	ids, err := repository.GetSimilarTracks(1, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 2}, ids)
+
+	// same event delivered again
+	err = repository.SaveSimilarTracks("e1", similar)
+	assert.ErrorIs(t, err, models.ErrAlreadyProcessed)
+
+	// stale results do not replace newer ones
+	err = repository.SaveSimilarTracks("e2", &models.SimilarTracks{
+		TrackId:    1,
+		Tracks:     []*models.ScoredTrack{{TrackId: 2, Score: 1}},
+		ComputedAt: computedAt.Add(-time.Hour),
+	})
+	assert.NoError(t, err)
+
+	ids, err = repository.GetSimilarTracks(1, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 2}, ids)
+
+	err = repository.SaveSimilarTracks("e3", &models.SimilarTracks{
+		TrackId:    1,
+		Tracks:     []*models.ScoredTrack{{TrackId: 2, Score: 1}},
+		ComputedAt: computedAt.Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	ids, err = repository.GetSimilarTracks(1, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2}, ids)
+}
+
+func TestRepo_SaveTrendingTracks(t *testing.T) {
+	ctx := context.Background()
+	pgContainer, err := testhelpers.CreatePostgresContainer(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer func() {
+		if err := pgContainer.Terminate(ctx); err != nil {
+			log.Fatalf("error terminating postgres container: %s", err)
+		}
+	}()
+
+	db, err := gorm.Open(postgres.Open(pgContainer.ConnectionString), &gorm.Config{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = db.Exec("insert into genres (name) values ('test')").Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = db.Exec("insert into musicians (name, description) values ('test', 'test')").Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	albumRepository := postgres2.NewAlbumRepository(db)
+	_, err = albumRepository.AddAlbumWithTracksOutbox(&models.Album{
+		Name:      "TestName",
+		CoverFile: []byte("TestCover"),
+		Type:      "LP",
+	}, []*models.TrackMeta{
+		{Source: "TestSrc1", Name: "TestName1", Genre: "test"},
+		{Source: "TestSrc2", Name: "TestName2", Genre: "test"},
+	}, 1)
+	require.NoError(t, err)
+
+	repository := NewRecSysRepository(db)
+
+	err = repository.SaveTrendingTracks("e1", &models.TrendingTracks{
+		Tracks:     []*models.ScoredTrack{{TrackId: 2, Score: 10}, {TrackId: 1, Score: 5}},
+		ComputedAt: time.Now(),
+	})
+	assert.NoError(t, err)
+
+	ids, err := repository.GetTrendingTracks(0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 1}, ids)
+
+	ids, err = repository.GetTrendingTracks(1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1}, ids)
+}
+
+func TestRepo_GetFallbackTracks(t *testing.T) {
+	ctx := context.Background()
+	pgContainer, err := testhelpers.CreatePostgresContainer(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer func() {
+		if err := pgContainer.Terminate(ctx); err != nil {
+			log.Fatalf("error terminating postgres container: %s", err)
+		}
+	}()
+
+	db, err := gorm.Open(postgres.Open(pgContainer.ConnectionString), &gorm.Config{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = db.Exec("insert into genres (name) values ('rock'), ('pop')").Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = db.Exec("insert into musicians (name, description) values ('test', 'test'), ('other', 'other')").Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	albumRepository := postgres2.NewAlbumRepository(db)
+	// tracks 1, 2
+	_, err = albumRepository.AddAlbumWithTracksOutbox(&models.Album{
+		Name: "First", CoverFile: []byte("TestCover"), Type: "LP",
+	}, []*models.TrackMeta{
+		{Source: "TestSrc1", Name: "TestName1", Genre: "rock"},
+		{Source: "TestSrc2", Name: "TestName2", Genre: "pop"},
+	}, 1)
+	require.NoError(t, err)
+
+	// track 3, same musician and genre
+	_, err = albumRepository.AddAlbumWithTracksOutbox(&models.Album{
+		Name: "Second", CoverFile: []byte("TestCover"), Type: "LP",
+	}, []*models.TrackMeta{
+		{Source: "TestSrc3", Name: "TestName3", Genre: "rock"},
+	}, 1)
+	require.NoError(t, err)
+
+	// tracks 4, 5 of another musician
+	_, err = albumRepository.AddAlbumWithTracksOutbox(&models.Album{
+		Name: "Third", CoverFile: []byte("TestCover"), Type: "LP",
+	}, []*models.TrackMeta{
+		{Source: "TestSrc4", Name: "TestName4", Genre: "rock"},
+		{Source: "TestSrc5", Name: "TestName5", Genre: "pop"},
+	}, 2)
+	require.NoError(t, err)
+
+	repository := NewRecSysRepository(db)
+
+	ids, err := repository.GetFallbackTracks(1, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4}, ids)
+
+	ids, err = repository.GetFallbackTracks(1, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3}, ids)
+}
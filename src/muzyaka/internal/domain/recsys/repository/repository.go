@@ -0,0 +1,31 @@
+package repository
+
+import "src/internal/models"
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+// RecSysRepository stores results pushed by the recommendation service.
+// Save methods are idempotent: an event id is applied only once, repeated
+// calls return models.ErrAlreadyProcessed.
+type RecSysRepository interface {
+	SaveSimilarTracks(eventId string, similar *models.SimilarTracks) error
+	SaveTrendingTracks(eventId string, trending *models.TrendingTracks) error
+
+	GetSimilarTracks(trackId uint64, offset int, limit int) ([]uint64, error)
+	GetTrendingTracks(offset int, limit int) ([]uint64, error)
+	// GetNeighbourTracks returns tracks computed by the built-in recommender
+	GetNeighbourTracks(trackId uint64, offset int, limit int) ([]uint64, error)
+
+	// GetFallbackTracks ranks tracks by how many of genre, album and musician
+	// they share with trackId. It is used when the recommendation service is down.
+	GetFallbackTracks(trackId uint64, offset int, limit int) ([]uint64, error)
+
+	// GetUserSeeds returns tracks that describe the user taste: liked tracks,
+	// tracks of the user's own playlists, recently completed plays and latest
+	// tracks of followed musicians, the most recently interacted with first
+	GetUserSeeds(userId uint64, limit int) ([]uint64, error)
+	GetLikedTracks(userId uint64) ([]uint64, error)
+	GetTracksInfo(ids []uint64) ([]*models.TrackInfo, error)
+	// GetActiveUsers pages through users with at least one seed track
+	GetActiveUsers(afterId uint64, limit int) ([]uint64, error)
+}
@@ -1,42 +1,461 @@
 package usecase
 
 import (
+	"fmt"
 	"github.com/pkg/errors"
+	"math"
+	"sort"
+	repository3 "src/internal/domain/playlist/repository"
 	"src/internal/domain/recsys/recsys_client"
+	repository2 "src/internal/domain/recsys/repository"
 	"src/internal/domain/track/repository"
 	"src/internal/models"
 )
 
+// Page size limits, the same as the recommendation service uses
+const (
+	MinPageSize = 10
+	MaxPageSize = 100
+)
+
+// Personal recommendations settings
+const (
+	maxSeeds         = 20
+	tracksPerSeed    = 20
+	maxPersonal      = 200
+	musicianPenalty  = 0.5
+	genrePenalty     = 0.8
+	dailyMixesCount  = 3
+	dailyMixSize     = 30
+	seedsPerDailyMix = 10
+)
+
 type RecSysUseCase interface {
-	GetSameTracks(id uint64, page int, pageSize int) ([]*models.TrackMeta, error)
+	GetSameTracks(id uint64, page int, pageSize int) (*models.Recommendations, error)
+	GetTrendingTracks(page int, pageSize int) ([]*models.TrackMeta, error)
+	GetUserRecommendations(userId uint64, page int, pageSize int) (*models.Recommendations, error)
+	GenerateDailyMixes(userId uint64) error
 }
 
 type usecase struct {
 	recsProvider recsys_client.RecSysProvider
+	recsRep      repository2.RecSysRepository
 	trackRep     repository.TrackRepository
+	playlistRep  repository3.PlaylistRepository
 }
 
-func NewRecSysUseCase(recs recsys_client.RecSysProvider, repo repository.TrackRepository) RecSysUseCase {
+func NewRecSysUseCase(recs recsys_client.RecSysProvider,
+	recsRep repository2.RecSysRepository,
+	repo repository.TrackRepository,
+	playlistRep repository3.PlaylistRepository) RecSysUseCase {
 	return &usecase{
 		recsProvider: recs,
+		recsRep:      recsRep,
 		trackRep:     repo,
+		playlistRep:  playlistRep,
 	}
 }
 
-func (u *usecase) GetSameTracks(id uint64, page int, pageSize int) ([]*models.TrackMeta, error) {
-	trackIds, err := u.recsProvider.GetRecs(id, page, pageSize)
+// GetSameTracks prefers similar tracks pushed by the recommendation service
+// and asks the configured provider only when nothing was pushed for the track.
+// If the provider fails or has nothing, tracks are picked by a local heuristic.
+func (u *usecase) GetSameTracks(id uint64, page int, pageSize int) (*models.Recommendations, error) {
+	page, pageSize = normalizePage(page, pageSize)
+
+	trackIds, err := u.recsRep.GetSimilarTracks(id, (page-1)*pageSize, pageSize)
 	if err != nil {
-		return nil, errors.Wrap(err, "recsys.usecase.GetSameTracks error while GetRecs call")
+		return nil, errors.Wrap(err, "recsys.usecase.GetSameTracks error while recsRep call")
 	}
+	source := models.RecSourcePrecomputed
 
-	var tracks []*models.TrackMeta
+	if len(trackIds) == 0 {
+		pushed, err := u.hasSimilarTracks(id, page)
+		if err != nil {
+			return nil, errors.Wrap(err, "recsys.usecase.GetSameTracks error while recsRep call")
+		}
+
+		if !pushed {
+			trackIds, source, err = u.getProvidedOrFallback(id, page, pageSize)
+			if err != nil {
+				return nil, errors.Wrap(err, "recsys.usecase.GetSameTracks error while fallback call")
+			}
+		}
+	}
+
+	tracks, err := u.getTracks(trackIds)
+	if err != nil {
+		return nil, errors.Wrap(err, "recsys.usecase.GetSameTracks error while trackRep call")
+	}
+
+	return &models.Recommendations{
+		Tracks: tracks,
+		Source: source,
+	}, nil
+}
 
-	for _, v := range trackIds {
-		track, err := u.trackRep.GetTrack(v)
+func (u *usecase) GetTrendingTracks(page int, pageSize int) ([]*models.TrackMeta, error) {
+	page, pageSize = normalizePage(page, pageSize)
+
+	trackIds, err := u.recsRep.GetTrendingTracks((page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "recsys.usecase.GetTrendingTracks error while recsRep call")
+	}
+
+	tracks, err := u.getTracks(trackIds)
+	if err != nil {
+		return nil, errors.Wrap(err, "recsys.usecase.GetTrendingTracks error while trackRep call")
+	}
+
+	return tracks, nil
+}
+
+// GetUserRecommendations collects tracks similar to the user's seed tracks,
+// drops already liked ones and spreads the rest across musicians and genres.
+// Users without seeds get trending tracks.
+func (u *usecase) GetUserRecommendations(userId uint64, page int, pageSize int) (*models.Recommendations, error) {
+	page, pageSize = normalizePage(page, pageSize)
+
+	seeds, err := u.recsRep.GetUserSeeds(userId, maxSeeds)
+	if err != nil {
+		return nil, errors.Wrap(err, "recsys.usecase.GetUserRecommendations error while recsRep call")
+	}
+
+	if len(seeds) == 0 {
+		tracks, err := u.GetTrendingTracks(page, pageSize)
 		if err != nil {
-			return nil, errors.Wrap(err, "recsys.usecase.GetSameTracks error while trackRep call")
+			return nil, errors.Wrap(err, "recsys.usecase.GetUserRecommendations error while trending call")
+		}
+
+		return &models.Recommendations{
+			Tracks: tracks,
+			Source: models.RecSourceTrending,
+		}, nil
+	}
+
+	exclude, err := u.getExcluded(userId, seeds)
+	if err != nil {
+		return nil, errors.Wrap(err, "recsys.usecase.GetUserRecommendations error while recsRep call")
+	}
+
+	trackIds, err := u.getPersonalTracks(seeds, exclude, maxPersonal)
+	if err != nil {
+		return nil, errors.Wrap(err, "recsys.usecase.GetUserRecommendations error while candidates call")
+	}
+
+	trackIds = paginate(trackIds, page, pageSize)
+
+	tracks, err := u.getTracks(trackIds)
+	if err != nil {
+		return nil, errors.Wrap(err, "recsys.usecase.GetUserRecommendations error while trackRep call")
+	}
+
+	return &models.Recommendations{
+		Tracks: tracks,
+		Source: models.RecSourcePersonal,
+	}, nil
+}
+
+// GenerateDailyMixes builds a mix for each of the user's favourite genres
+// and replaces the previous mixes.
+func (u *usecase) GenerateDailyMixes(userId uint64) error {
+	seeds, err := u.recsRep.GetUserSeeds(userId, maxSeeds*dailyMixesCount)
+	if err != nil {
+		return errors.Wrap(err, "recsys.usecase.GenerateDailyMixes error while recsRep call")
+	}
+
+	exclude, err := u.getExcluded(userId, seeds)
+	if err != nil {
+		return errors.Wrap(err, "recsys.usecase.GenerateDailyMixes error while recsRep call")
+	}
+
+	groups, err := u.groupByGenre(seeds)
+	if err != nil {
+		return errors.Wrap(err, "recsys.usecase.GenerateDailyMixes error while recsRep call")
+	}
+
+	var mixes []*models.DailyMix
+	for _, group := range groups {
+		if len(mixes) == dailyMixesCount {
+			break
+		}
+
+		if len(group) > seedsPerDailyMix {
+			group = group[:seedsPerDailyMix]
+		}
+
+		trackIds, err := u.getPersonalTracks(group, exclude, dailyMixSize)
+		if err != nil {
+			return errors.Wrap(err, "recsys.usecase.GenerateDailyMixes error while candidates call")
+		}
+
+		if len(trackIds) == 0 {
+			continue
+		}
+
+		// mixes don't repeat each other
+		for _, v := range trackIds {
+			exclude[v] = true
+		}
+
+		mixes = append(mixes, &models.DailyMix{
+			Playlist: models.Playlist{
+				Name:        fmt.Sprintf("Daily Mix %d", len(mixes)+1),
+				Description: "Made for you from the tracks you like",
+				Kind:        models.PlaylistKindDailyMix,
+			},
+			TrackIds: trackIds,
+		})
+	}
+
+	err = u.playlistRep.ReplaceDailyMixes(userId, mixes)
+	if err != nil {
+		return errors.Wrap(err, "recsys.usecase.GenerateDailyMixes error while playlistRep call")
+	}
+
+	return nil
+}
+
+// getExcluded returns liked tracks and seeds, they are never recommended
+func (u *usecase) getExcluded(userId uint64, seeds []uint64) (map[uint64]bool, error) {
+	liked, err := u.recsRep.GetLikedTracks(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := make(map[uint64]bool, len(liked)+len(seeds))
+	for _, v := range liked {
+		exclude[v] = true
+	}
+	for _, v := range seeds {
+		exclude[v] = true
+	}
+
+	return exclude, nil
+}
+
+// getPersonalTracks scores candidates by reciprocal rank summed over seeds and diversifies them
+func (u *usecase) getPersonalTracks(seeds []uint64, exclude map[uint64]bool, limit int) ([]uint64, error) {
+	scores := make(map[uint64]float64)
+	for _, seed := range seeds {
+		similar, err := u.getSimilarIds(seed, tracksPerSeed)
+		if err != nil {
+			return nil, err
+		}
+
+		for rank, v := range similar {
+			if exclude[v] {
+				continue
+			}
+			scores[v] += 1 / float64(rank+1)
 		}
-		tracks = append(tracks, track)
 	}
+
+	candidates := make([]*models.ScoredTrack, 0, len(scores))
+	ids := make([]uint64, 0, len(scores))
+	for id, score := range scores {
+		candidates = append(candidates, &models.ScoredTrack{TrackId: id, Score: score})
+		ids = append(ids, id)
+	}
+
+	info, err := u.recsRep.GetTracksInfo(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return diversify(candidates, info, limit), nil
+}
+
+// getSimilarIds reads pushed, precomputed and fallback tracks in that order.
+// It runs for every seed of every user, so it never calls the remote service.
+func (u *usecase) getSimilarIds(id uint64, limit int) ([]uint64, error) {
+	sources := []func(uint64, int, int) ([]uint64, error){
+		u.recsRep.GetSimilarTracks,
+		u.recsRep.GetNeighbourTracks,
+		u.recsRep.GetFallbackTracks,
+	}
+
+	for _, source := range sources {
+		ids, err := source(id, 0, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ids) > 0 {
+			return ids, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// groupByGenre groups seeds by genre, the largest group first
+func (u *usecase) groupByGenre(seeds []uint64) ([][]uint64, error) {
+	info, err := u.recsRep.GetTracksInfo(seeds)
+	if err != nil {
+		return nil, err
+	}
+
+	genres := make(map[uint64]uint64, len(info))
+	for _, v := range info {
+		genres[v.Id] = v.GenreId
+	}
+
+	byGenre := make(map[uint64][]uint64)
+	var order []uint64
+	for _, v := range seeds {
+		genre, ok := genres[v]
+		if !ok {
+			continue
+		}
+		if _, ok := byGenre[genre]; !ok {
+			order = append(order, genre)
+		}
+		byGenre[genre] = append(byGenre[genre], v)
+	}
+
+	groups := make([][]uint64, 0, len(order))
+	for _, v := range order {
+		groups = append(groups, byGenre[v])
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i]) > len(groups[j])
+	})
+
+	return groups, nil
+}
+
+// diversify greedily picks the best candidate, lowering scores of candidates
+// that share a musician or a genre with already picked ones
+func diversify(candidates []*models.ScoredTrack, info []*models.TrackInfo, limit int) []uint64 {
+	byId := make(map[uint64]*models.TrackInfo, len(info))
+	for _, v := range info {
+		byId[v.Id] = v
+	}
+
+	remaining := make([]*models.ScoredTrack, 0, len(candidates))
+	for _, v := range candidates {
+		// deleted tracks have no info
+		if _, ok := byId[v.TrackId]; ok {
+			remaining = append(remaining, v)
+		}
+	}
+
+	// stable order for equal scores
+	sort.Slice(remaining, func(i, j int) bool {
+		if remaining[i].Score != remaining[j].Score {
+			return remaining[i].Score > remaining[j].Score
+		}
+		return remaining[i].TrackId < remaining[j].TrackId
+	})
+
+	musicians := make(map[uint64]int)
+	genres := make(map[uint64]int)
+	var picked []uint64
+
+	for len(picked) < limit && len(remaining) > 0 {
+		best := 0
+		bestScore := -1.0
+		for i, v := range remaining {
+			track := byId[v.TrackId]
+			score := v.Score *
+				math.Pow(musicianPenalty, float64(musicians[track.MusicianId])) *
+				math.Pow(genrePenalty, float64(genres[track.GenreId]))
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		track := byId[remaining[best].TrackId]
+		musicians[track.MusicianId]++
+		genres[track.GenreId]++
+		picked = append(picked, track.Id)
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+
+	return picked
+}
+
+func paginate(ids []uint64, page int, pageSize int) []uint64 {
+	start := (page - 1) * pageSize
+	if start >= len(ids) {
+		return nil
+	}
+
+	end := start + pageSize
+	if end > len(ids) {
+		end = len(ids)
+	}
+
+	return ids[start:end]
+}
+
+// hasSimilarTracks tells an empty page past the end of a pushed list
+// from a track without pushed results
+func (u *usecase) hasSimilarTracks(id uint64, page int) (bool, error) {
+	if page == 1 {
+		return false, nil
+	}
+
+	ids, err := u.recsRep.GetSimilarTracks(id, 0, 1)
+	if err != nil {
+		return false, err
+	}
+
+	return len(ids) > 0, nil
+}
+
+func (u *usecase) getProvidedOrFallback(id uint64, page int, pageSize int) ([]uint64, string, error) {
+	trackIds, err := u.recsProvider.GetRecs(id, page, pageSize)
+	if err == nil && len(trackIds) > 0 {
+		return trackIds, u.recsProvider.Source(), nil
+	}
+
+	trackIds, err = u.recsRep.GetFallbackTracks(id, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return trackIds, models.RecSourceFallback, nil
+}
+
+// getTracks keeps the order of ids and skips tracks that were deleted after
+// the recommendations were made
+func (u *usecase) getTracks(ids []uint64) ([]*models.TrackMeta, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	found, err := u.trackRep.GetTracks(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[uint64]*models.TrackMeta, len(found))
+	for _, v := range found {
+		byId[v.Id] = v
+	}
+
+	var tracks []*models.TrackMeta
+	for _, v := range ids {
+		if track, ok := byId[v]; ok {
+			tracks = append(tracks, track)
+		}
+	}
+
 	return tracks, nil
 }
+
+func normalizePage(page int, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < MinPageSize {
+		pageSize = MinPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	return page, pageSize
+}
@@ -4,67 +4,179 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	mock_repository3 "src/internal/domain/playlist/repository/mocks"
 	mock_remote "src/internal/domain/recsys/recsys_client/mocks"
+	mock_repository2 "src/internal/domain/recsys/repository/mocks"
 	mock_repository "src/internal/domain/track/repository/mocks"
 	"src/internal/models"
 	"testing"
 )
 
 func TestRecSysUseCase_GetSameTracks(t *testing.T) {
-	type mock func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository, id uint64)
+	type mock func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+		r3 *mock_repository2.MockRecSysRepository, id uint64)
 
 	testTable := []struct {
-		name           string
-		id             uint64
-		mock           mock
-		expectedTracks []*models.TrackMeta
-		expectedErr    error
+		name        string
+		id          uint64
+		page        int
+		pageSize    int
+		mock        mock
+		expected    *models.Recommendations
+		expectedErr error
 	}{
 		{
-			name: "Usual test",
-			id:   1,
-			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository, id uint64) {
+			name:     "Usual test",
+			id:       1,
+			page:     1,
+			pageSize: 10,
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository, id uint64) {
+				r3.EXPECT().GetSimilarTracks(id, 0, 10).Return(nil, nil)
 				r.EXPECT().GetRecs(id, 1, 10).Return([]uint64{1, 2, 3}, nil)
+				r.EXPECT().Source().Return(models.RecSourceLocal)
 
 				track1 := &models.TrackMeta{Id: 1, Name: "TrackMeta 1"}
 				track2 := &models.TrackMeta{Id: 2, Name: "TrackMeta 2"}
 				track3 := &models.TrackMeta{Id: 3, Name: "TrackMeta 3"}
 
-				r2.EXPECT().GetTrack(uint64(1)).Return(track1, nil)
-				r2.EXPECT().GetTrack(uint64(2)).Return(track2, nil)
-				r2.EXPECT().GetTrack(uint64(3)).Return(track3, nil)
+				r2.EXPECT().GetTracks([]uint64{1, 2, 3}).Return([]*models.TrackMeta{track3, track1, track2}, nil)
+			},
+			expected: &models.Recommendations{
+				Tracks: []*models.TrackMeta{
+					{Id: 1, Name: "TrackMeta 1"},
+					{Id: 2, Name: "TrackMeta 2"},
+					{Id: 3, Name: "TrackMeta 3"},
+				},
+				Source: models.RecSourceLocal,
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "Pushed tracks test",
+			id:       1,
+			page:     2,
+			pageSize: 10,
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository, id uint64) {
+				r3.EXPECT().GetSimilarTracks(id, 10, 10).Return([]uint64{4}, nil)
+				r2.EXPECT().GetTracks([]uint64{4}).Return([]*models.TrackMeta{{Id: 4, Name: "TrackMeta 4"}}, nil)
 			},
-			expectedTracks: []*models.TrackMeta{
-				{Id: 1, Name: "TrackMeta 1"},
-				{Id: 2, Name: "TrackMeta 2"},
-				{Id: 3, Name: "TrackMeta 3"},
+			expected: &models.Recommendations{
+				Tracks: []*models.TrackMeta{
+					{Id: 4, Name: "TrackMeta 4"},
+				},
+				Source: models.RecSourcePrecomputed,
 			},
 			expectedErr: nil,
 		},
 		{
-			name: "GetRecs fails",
-			id:   2,
-			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository, id uint64) {
+			name:     "End of pushed tracks test",
+			id:       1,
+			page:     3,
+			pageSize: 10,
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository, id uint64) {
+				r3.EXPECT().GetSimilarTracks(id, 20, 10).Return(nil, nil)
+				r3.EXPECT().GetSimilarTracks(id, 0, 1).Return([]uint64{2}, nil)
+			},
+			expected: &models.Recommendations{
+				Tracks: nil,
+				Source: models.RecSourcePrecomputed,
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "Fallback test",
+			id:       2,
+			page:     1,
+			pageSize: 10,
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository, id uint64) {
+				r3.EXPECT().GetSimilarTracks(id, 0, 10).Return(nil, nil)
 				r.EXPECT().GetRecs(id, 1, 10).Return(nil, errors.New("error in GetRecs call"))
+				r3.EXPECT().GetFallbackTracks(id, 0, 10).Return([]uint64{5}, nil)
+				r2.EXPECT().GetTracks([]uint64{5}).Return([]*models.TrackMeta{{Id: 5}}, nil)
 			},
-			expectedTracks: nil,
-			expectedErr:    errors.Wrap(errors.New("error in GetRecs call"), "recsys.usecase.GetSameTracks error while GetRecs call"),
+			expected: &models.Recommendations{
+				Tracks: []*models.TrackMeta{{Id: 5}},
+				Source: models.RecSourceFallback,
+			},
+			expectedErr: nil,
 		},
 		{
-			name: "GetTrack fails",
-			id:   3,
-			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository, id uint64) {
-				r.EXPECT().GetRecs(id, 1, 10).Return([]uint64{1, 2, 3}, nil)
+			name:     "Fallback fails",
+			id:       2,
+			page:     1,
+			pageSize: 10,
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository, id uint64) {
+				r3.EXPECT().GetSimilarTracks(id, 0, 10).Return(nil, nil)
+				r.EXPECT().GetRecs(id, 1, 10).Return(nil, errors.New("error in GetRecs call"))
+				r3.EXPECT().GetFallbackTracks(id, 0, 10).Return(nil, errors.New("error"))
+			},
+			expected:    nil,
+			expectedErr: errors.Wrap(errors.New("error"), "recsys.usecase.GetSameTracks error while fallback call"),
+		},
+		{
+			name:     "GetSimilarTracks fails",
+			id:       2,
+			page:     1,
+			pageSize: 10,
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository, id uint64) {
+				r3.EXPECT().GetSimilarTracks(id, 0, 10).Return(nil, errors.New("error"))
+			},
+			expected:    nil,
+			expectedErr: errors.Wrap(errors.New("error"), "recsys.usecase.GetSameTracks error while recsRep call"),
+		},
+		{
+			name:     "Missing track is skipped",
+			id:       3,
+			page:     1,
+			pageSize: 10,
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository, id uint64) {
+				r3.EXPECT().GetSimilarTracks(id, 0, 10).Return([]uint64{1, 2}, nil)
 
-				track1 := &models.TrackMeta{Id: 1, Name: "TrackMeta 1"}
-				track2 := &models.TrackMeta{Id: 2, Name: "TrackMeta 2"}
+				r2.EXPECT().GetTracks([]uint64{1, 2}).Return([]*models.TrackMeta{{Id: 2}}, nil)
+			},
+			expected: &models.Recommendations{
+				Tracks: []*models.TrackMeta{{Id: 2}},
+				Source: models.RecSourcePrecomputed,
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "GetTracks fails",
+			id:       3,
+			page:     1,
+			pageSize: 10,
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository, id uint64) {
+				r3.EXPECT().GetSimilarTracks(id, 0, 10).Return([]uint64{1, 2, 3}, nil)
 
-				r2.EXPECT().GetTrack(uint64(1)).Return(track1, nil)
-				r2.EXPECT().GetTrack(uint64(2)).Return(track2, nil)
-				r2.EXPECT().GetTrack(uint64(3)).Return(nil, errors.New("error in GetTrack call"))
+				r2.EXPECT().GetTracks([]uint64{1, 2, 3}).Return(nil, errors.New("error in GetTracks call"))
 			},
-			expectedTracks: nil,
-			expectedErr:    errors.Wrap(errors.New("error in GetTrack call"), "recsys.usecase.GetSameTracks error while trackRep call"),
+			expected:    nil,
+			expectedErr: errors.Wrap(errors.New("error in GetTracks call"), "recsys.usecase.GetSameTracks error while trackRep call"),
+		},
+		{
+			name:     "Page is normalized",
+			id:       3,
+			page:     0,
+			pageSize: -1,
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository, id uint64) {
+				r3.EXPECT().GetSimilarTracks(id, 0, MinPageSize).Return(nil, nil)
+				r.EXPECT().GetRecs(id, 1, MinPageSize).Return(nil, nil)
+				r3.EXPECT().GetFallbackTracks(id, 0, MinPageSize).Return(nil, nil)
+			},
+			expected: &models.Recommendations{
+				Tracks: nil,
+				Source: models.RecSourceFallback,
+			},
+			expectedErr: nil,
 		},
 	}
 
@@ -75,12 +187,13 @@ func TestRecSysUseCase_GetSameTracks(t *testing.T) {
 
 			recsProvider := mock_remote.NewMockRecSysProvider(ctrl)
 			trackRep := mock_repository.NewMockTrackRepository(ctrl)
-			tc.mock(recsProvider, trackRep, tc.id)
+			recsRep := mock_repository2.NewMockRecSysRepository(ctrl)
+			tc.mock(recsProvider, trackRep, recsRep, tc.id)
 
-			u := NewRecSysUseCase(recsProvider, trackRep)
-			tracks, err := u.GetSameTracks(tc.id, 1, 10)
+			u := NewRecSysUseCase(recsProvider, recsRep, trackRep, nil)
+			recs, err := u.GetSameTracks(tc.id, tc.page, tc.pageSize)
 
-			assert.Equal(t, tc.expectedTracks, tracks)
+			assert.Equal(t, tc.expected, recs)
 
 			if tc.expectedErr != nil {
 				assert.Error(t, err)
@@ -91,3 +204,265 @@ func TestRecSysUseCase_GetSameTracks(t *testing.T) {
 		})
 	}
 }
+
+func TestRecSysUseCase_GetTrendingTracks(t *testing.T) {
+	type mock func(r2 *mock_repository.MockTrackRepository, r3 *mock_repository2.MockRecSysRepository)
+
+	testTable := []struct {
+		name           string
+		mock           mock
+		expectedTracks []*models.TrackMeta
+		expectedErr    error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r2 *mock_repository.MockTrackRepository, r3 *mock_repository2.MockRecSysRepository) {
+				r3.EXPECT().GetTrendingTracks(0, 10).Return([]uint64{2, 1}, nil)
+				r2.EXPECT().GetTracks([]uint64{2, 1}).Return([]*models.TrackMeta{{Id: 1}, {Id: 2}}, nil)
+			},
+			expectedTracks: []*models.TrackMeta{{Id: 2}, {Id: 1}},
+			expectedErr:    nil,
+		},
+		{
+			name: "Repo fail test",
+			mock: func(r2 *mock_repository.MockTrackRepository, r3 *mock_repository2.MockRecSysRepository) {
+				r3.EXPECT().GetTrendingTracks(0, 10).Return(nil, errors.New("error"))
+			},
+			expectedTracks: nil,
+			expectedErr:    errors.Wrap(errors.New("error"), "recsys.usecase.GetTrendingTracks error while recsRep call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trackRep := mock_repository.NewMockTrackRepository(ctrl)
+			recsRep := mock_repository2.NewMockRecSysRepository(ctrl)
+			tc.mock(trackRep, recsRep)
+
+			u := NewRecSysUseCase(mock_remote.NewMockRecSysProvider(ctrl), recsRep, trackRep, nil)
+			tracks, err := u.GetTrendingTracks(1, 10)
+
+			assert.Equal(t, tc.expectedTracks, tracks)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// catalog answers GetTracksInfo calls with ids in any order
+func catalog(info ...*models.TrackInfo) func(ids []uint64) ([]*models.TrackInfo, error) {
+	return func(ids []uint64) ([]*models.TrackInfo, error) {
+		var res []*models.TrackInfo
+		for _, id := range ids {
+			for _, v := range info {
+				if v.Id == id {
+					res = append(res, v)
+				}
+			}
+		}
+		return res, nil
+	}
+}
+
+func TestRecSysUseCase_GetUserRecommendations(t *testing.T) {
+	type mock func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+		r3 *mock_repository2.MockRecSysRepository)
+
+	testTable := []struct {
+		name        string
+		mock        mock
+		expected    *models.Recommendations
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository) {
+				r3.EXPECT().GetUserSeeds(uint64(1), 20).Return([]uint64{10, 11}, nil)
+				r3.EXPECT().GetLikedTracks(uint64(1)).Return([]uint64{12}, nil)
+				r3.EXPECT().GetSimilarTracks(uint64(10), 0, 20).Return([]uint64{1, 2, 12}, nil)
+				r3.EXPECT().GetSimilarTracks(uint64(11), 0, 20).Return([]uint64{2, 3}, nil)
+				r3.EXPECT().GetTracksInfo(gomock.Any()).DoAndReturn(catalog(
+					&models.TrackInfo{Id: 1, GenreId: 1, MusicianId: 1},
+					&models.TrackInfo{Id: 2, GenreId: 1, MusicianId: 1},
+					&models.TrackInfo{Id: 3, GenreId: 2, MusicianId: 2},
+				))
+
+				r2.EXPECT().GetTracks([]uint64{2, 3, 1}).Return([]*models.TrackMeta{{Id: 1}, {Id: 2}, {Id: 3}}, nil)
+			},
+			expected: &models.Recommendations{
+				Tracks: []*models.TrackMeta{{Id: 2}, {Id: 3}, {Id: 1}},
+				Source: models.RecSourcePersonal,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Seeds without pushed tracks test",
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository) {
+				r3.EXPECT().GetUserSeeds(uint64(1), 20).Return([]uint64{10, 11}, nil)
+				r3.EXPECT().GetLikedTracks(uint64(1)).Return(nil, nil)
+				r3.EXPECT().GetSimilarTracks(uint64(10), 0, 20).Return(nil, nil)
+				r3.EXPECT().GetNeighbourTracks(uint64(10), 0, 20).Return([]uint64{1}, nil)
+				r3.EXPECT().GetSimilarTracks(uint64(11), 0, 20).Return(nil, nil)
+				r3.EXPECT().GetNeighbourTracks(uint64(11), 0, 20).Return(nil, nil)
+				r3.EXPECT().GetFallbackTracks(uint64(11), 0, 20).Return([]uint64{2}, nil)
+				r3.EXPECT().GetTracksInfo(gomock.Any()).DoAndReturn(catalog(
+					&models.TrackInfo{Id: 1, GenreId: 1, MusicianId: 1},
+					&models.TrackInfo{Id: 2, GenreId: 2, MusicianId: 2},
+				))
+
+				r2.EXPECT().GetTracks([]uint64{1, 2}).Return([]*models.TrackMeta{{Id: 2}, {Id: 1}}, nil)
+			},
+			expected: &models.Recommendations{
+				Tracks: []*models.TrackMeta{{Id: 1}, {Id: 2}},
+				Source: models.RecSourcePersonal,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "No seeds test",
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository) {
+				r3.EXPECT().GetUserSeeds(uint64(1), 20).Return(nil, nil)
+				r3.EXPECT().GetTrendingTracks(0, 10).Return([]uint64{5}, nil)
+				r2.EXPECT().GetTracks([]uint64{5}).Return([]*models.TrackMeta{{Id: 5}}, nil)
+			},
+			expected: &models.Recommendations{
+				Tracks: []*models.TrackMeta{{Id: 5}},
+				Source: models.RecSourceTrending,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Seeds fail test",
+			mock: func(r *mock_remote.MockRecSysProvider, r2 *mock_repository.MockTrackRepository,
+				r3 *mock_repository2.MockRecSysRepository) {
+				r3.EXPECT().GetUserSeeds(uint64(1), 20).Return(nil, errors.New("error"))
+			},
+			expected: nil,
+			expectedErr: errors.Wrap(errors.New("error"),
+				"recsys.usecase.GetUserRecommendations error while recsRep call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			recsProvider := mock_remote.NewMockRecSysProvider(ctrl)
+			trackRep := mock_repository.NewMockTrackRepository(ctrl)
+			recsRep := mock_repository2.NewMockRecSysRepository(ctrl)
+			tc.mock(recsProvider, trackRep, recsRep)
+
+			u := NewRecSysUseCase(recsProvider, recsRep, trackRep, nil)
+			recs, err := u.GetUserRecommendations(1, 1, 10)
+
+			assert.Equal(t, tc.expected, recs)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRecSysUseCase_GenerateDailyMixes(t *testing.T) {
+	type mock func(r3 *mock_repository2.MockRecSysRepository, r4 *mock_repository3.MockPlaylistRepository)
+
+	testTable := []struct {
+		name        string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r3 *mock_repository2.MockRecSysRepository, r4 *mock_repository3.MockPlaylistRepository) {
+				r3.EXPECT().GetUserSeeds(uint64(1), 60).Return([]uint64{20, 10, 11}, nil)
+				r3.EXPECT().GetLikedTracks(uint64(1)).Return(nil, nil)
+				r3.EXPECT().GetTracksInfo(gomock.Any()).DoAndReturn(catalog(
+					&models.TrackInfo{Id: 10, GenreId: 1, MusicianId: 1},
+					&models.TrackInfo{Id: 11, GenreId: 1, MusicianId: 1},
+					&models.TrackInfo{Id: 20, GenreId: 2, MusicianId: 2},
+					&models.TrackInfo{Id: 1, GenreId: 1, MusicianId: 3},
+					&models.TrackInfo{Id: 5, GenreId: 2, MusicianId: 4},
+				)).AnyTimes()
+				r3.EXPECT().GetSimilarTracks(uint64(10), 0, 20).Return([]uint64{1}, nil)
+				r3.EXPECT().GetSimilarTracks(uint64(11), 0, 20).Return([]uint64{10}, nil)
+				r3.EXPECT().GetSimilarTracks(uint64(20), 0, 20).Return([]uint64{1, 5}, nil)
+
+				r4.EXPECT().ReplaceDailyMixes(uint64(1), []*models.DailyMix{
+					{
+						Playlist: models.Playlist{
+							Name:        "Daily Mix 1",
+							Description: "Made for you from the tracks you like",
+							Kind:        models.PlaylistKindDailyMix,
+						},
+						TrackIds: []uint64{1},
+					},
+					{
+						Playlist: models.Playlist{
+							Name:        "Daily Mix 2",
+							Description: "Made for you from the tracks you like",
+							Kind:        models.PlaylistKindDailyMix,
+						},
+						TrackIds: []uint64{5},
+					},
+				}).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "No seeds test",
+			mock: func(r3 *mock_repository2.MockRecSysRepository, r4 *mock_repository3.MockPlaylistRepository) {
+				r3.EXPECT().GetUserSeeds(uint64(1), 60).Return(nil, nil)
+				r3.EXPECT().GetLikedTracks(uint64(1)).Return(nil, nil)
+				r3.EXPECT().GetTracksInfo(gomock.Any()).Return(nil, nil)
+				r4.EXPECT().ReplaceDailyMixes(uint64(1), gomock.Nil()).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Playlist repo fail test",
+			mock: func(r3 *mock_repository2.MockRecSysRepository, r4 *mock_repository3.MockPlaylistRepository) {
+				r3.EXPECT().GetUserSeeds(uint64(1), 60).Return(nil, nil)
+				r3.EXPECT().GetLikedTracks(uint64(1)).Return(nil, nil)
+				r3.EXPECT().GetTracksInfo(gomock.Any()).Return(nil, nil)
+				r4.EXPECT().ReplaceDailyMixes(uint64(1), gomock.Nil()).Return(errors.New("error"))
+			},
+			expectedErr: errors.Wrap(errors.New("error"),
+				"recsys.usecase.GenerateDailyMixes error while playlistRep call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			recsRep := mock_repository2.NewMockRecSysRepository(ctrl)
+			playlistRep := mock_repository3.NewMockPlaylistRepository(ctrl)
+			tc.mock(recsRep, playlistRep)
+
+			u := NewRecSysUseCase(mock_remote.NewMockRecSysProvider(ctrl), recsRep,
+				mock_repository.NewMockTrackRepository(ctrl), playlistRep)
+			err := u.GenerateDailyMixes(1)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package delivery
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"src/internal/domain/stats/usecase"
+	"src/internal/lib/api/response"
+	"src/internal/models"
+	"src/internal/models/dto"
+	"strconv"
+	"time"
+)
+
+const formatCSV = "csv"
+
+// @Summary GetMusicianStats
+// @Security ApiKeyAuth
+// @Tags musician
+// @Description get daily plays, likes, playlist adds and listeners of tracks and albums,
+// @Description merch click-throughs and top playlists. format=csv exports the daily series.
+// @ID get-musician-stats
+// @Accept  json
+// @Produce  json,text/csv
+// @Param musician_id path int true "musician ID"
+// @Param from query string false "YYYY-MM-DD, 30 days before to by default"
+// @Param to query string false "YYYY-MM-DD, today by default"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} dto.MusicianStats
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/stats [get]
+func GetMusicianStats(useCase usecase.StatsUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianID := chi.URLParam(r, "musician_id")
+		musicianIDUint, err := strconv.ParseUint(musicianID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		from, err := parseDate(r.URL.Query().Get("from"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		to, err := parseDate(r.URL.Query().Get("to"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		stats, err := useCase.GetMusicianStats(musicianIDUint, from, to)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		if r.URL.Query().Get("format") == formatCSV {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition",
+				fmt.Sprintf("attachment; filename=\"musician-%d-stats.csv\"", musicianIDUint))
+			_ = writeStatsCSV(w, stats)
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoMusicianStats(stats))
+	}
+}
+
+func parseDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(dto.DateLayout, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &date, nil
+}
+
+// writeStatsCSV writes one row per item and day
+func writeStatsCSV(w io.Writer, stats *models.MusicianStats) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{
+		"type", "id", "name", "day", "plays", "skips", "likes", "playlist_adds", "listeners", "clicks",
+	}); err != nil {
+		return err
+	}
+
+	groups := []struct {
+		kind  string
+		items []*models.ItemStats
+	}{
+		{kind: "track", items: stats.Tracks},
+		{kind: "album", items: stats.Albums},
+		{kind: "merch", items: stats.Merch},
+	}
+
+	for _, group := range groups {
+		for _, item := range group.items {
+			for _, p := range item.Series {
+				if err := writer.Write([]string{
+					group.kind,
+					strconv.FormatUint(item.Id, 10),
+					item.Name,
+					p.Day.Format(dto.DateLayout),
+					strconv.FormatInt(p.Plays, 10),
+					strconv.FormatInt(p.Skips, 10),
+					strconv.FormatInt(p.Likes, 10),
+					strconv.FormatInt(p.PlaylistAdds, 10),
+					strconv.FormatInt(p.Listeners, 10),
+					strconv.FormatInt(p.Clicks, 10),
+				}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
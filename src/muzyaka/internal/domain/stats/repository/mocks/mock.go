@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	models "src/internal/models"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStatsRepository is a mock of StatsRepository interface.
+type MockStatsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsRepositoryMockRecorder
+}
+
+// MockStatsRepositoryMockRecorder is the mock recorder for MockStatsRepository.
+type MockStatsRepositoryMockRecorder struct {
+	mock *MockStatsRepository
+}
+
+// NewMockStatsRepository creates a new mock instance.
+func NewMockStatsRepository(ctrl *gomock.Controller) *MockStatsRepository {
+	mock := &MockStatsRepository{ctrl: ctrl}
+	mock.recorder = &MockStatsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsRepository) EXPECT() *MockStatsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAlbumStats mocks base method.
+func (m *MockStatsRepository) GetAlbumStats(musicianId uint64, from, to time.Time) ([]*models.ItemStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlbumStats", musicianId, from, to)
+	ret0, _ := ret[0].([]*models.ItemStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlbumStats indicates an expected call of GetAlbumStats.
+func (mr *MockStatsRepositoryMockRecorder) GetAlbumStats(musicianId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlbumStats", reflect.TypeOf((*MockStatsRepository)(nil).GetAlbumStats), musicianId, from, to)
+}
+
+// GetMerchStats mocks base method.
+func (m *MockStatsRepository) GetMerchStats(musicianId uint64, from, to time.Time) ([]*models.ItemStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchStats", musicianId, from, to)
+	ret0, _ := ret[0].([]*models.ItemStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchStats indicates an expected call of GetMerchStats.
+func (mr *MockStatsRepositoryMockRecorder) GetMerchStats(musicianId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchStats", reflect.TypeOf((*MockStatsRepository)(nil).GetMerchStats), musicianId, from, to)
+}
+
+// GetTopPlaylists mocks base method.
+func (m *MockStatsRepository) GetTopPlaylists(musicianId uint64, limit int) ([]*models.PlaylistReach, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopPlaylists", musicianId, limit)
+	ret0, _ := ret[0].([]*models.PlaylistReach)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopPlaylists indicates an expected call of GetTopPlaylists.
+func (mr *MockStatsRepositoryMockRecorder) GetTopPlaylists(musicianId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopPlaylists", reflect.TypeOf((*MockStatsRepository)(nil).GetTopPlaylists), musicianId, limit)
+}
+
+// GetTrackStats mocks base method.
+func (m *MockStatsRepository) GetTrackStats(musicianId uint64, from, to time.Time) ([]*models.ItemStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrackStats", musicianId, from, to)
+	ret0, _ := ret[0].([]*models.ItemStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrackStats indicates an expected call of GetTrackStats.
+func (mr *MockStatsRepositoryMockRecorder) GetTrackStats(musicianId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrackStats", reflect.TypeOf((*MockStatsRepository)(nil).GetTrackStats), musicianId, from, to)
+}
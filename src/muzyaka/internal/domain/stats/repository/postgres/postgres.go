@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/domain/stats/repository"
+	"src/internal/models"
+	"time"
+)
+
+type statsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) repository.StatsRepository {
+	return &statsRepository{db: db}
+}
+
+// statsRow is an item joined with one of its days, Day is nil for items without activity
+type statsRow struct {
+	Id           uint64
+	Name         string
+	Day          *time.Time
+	Plays        int64
+	Skips        int64
+	Likes        int64
+	PlaylistAdds int64
+	Listeners    int64
+	Clicks       int64
+}
+
+func (s statsRepository) GetTrackStats(musicianId uint64, from time.Time, to time.Time) ([]*models.ItemStats, error) {
+	var rows []*statsRow
+	tx := s.db.Raw(`
+		SELECT t.id, t.name, st.day, coalesce(st.plays, 0) AS plays, coalesce(st.skips, 0) AS skips,
+		       coalesce(st.likes, 0) AS likes, coalesce(st.playlist_adds, 0) AS playlist_adds,
+		       coalesce(st.listeners, 0) AS listeners
+		FROM tracks t
+		JOIN albums a ON a.id = t.album_id
+		LEFT JOIN track_daily_stats st ON st.track_id = t.id AND st.day BETWEEN ?::date AND ?::date
+		WHERE a.musician_id = ?
+		ORDER BY t.id, st.day`, day(from), day(to), musicianId).Scan(&rows)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track_daily_stats)")
+	}
+
+	return groupRows(rows), nil
+}
+
+func (s statsRepository) GetAlbumStats(musicianId uint64, from time.Time, to time.Time) ([]*models.ItemStats, error) {
+	var rows []*statsRow
+	tx := s.db.Raw(`
+		SELECT a.id, a.name, st.day, coalesce(st.plays, 0) AS plays, coalesce(st.skips, 0) AS skips,
+		       coalesce(st.likes, 0) AS likes, coalesce(st.playlist_adds, 0) AS playlist_adds,
+		       coalesce(st.listeners, 0) AS listeners
+		FROM albums a
+		LEFT JOIN album_daily_stats st ON st.album_id = a.id AND st.day BETWEEN ?::date AND ?::date
+		WHERE a.musician_id = ?
+		ORDER BY a.id, st.day`, day(from), day(to), musicianId).Scan(&rows)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table album_daily_stats)")
+	}
+
+	return groupRows(rows), nil
+}
+
+func (s statsRepository) GetMerchStats(musicianId uint64, from time.Time, to time.Time) ([]*models.ItemStats, error) {
+	var rows []*statsRow
+	tx := s.db.Raw(`
+		SELECT m.id, m.name, st.day, coalesce(st.clicks, 0) AS clicks
+		FROM merch m
+		LEFT JOIN merch_daily_stats st ON st.merch_id = m.id AND st.day BETWEEN ?::date AND ?::date
+		WHERE m.musician_id = ?
+		ORDER BY m.id, st.day`, day(from), day(to), musicianId).Scan(&rows)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table merch_daily_stats)")
+	}
+
+	return groupRows(rows), nil
+}
+
+func (s statsRepository) GetTopPlaylists(musicianId uint64, limit int) ([]*models.PlaylistReach, error) {
+	var playlists []*models.PlaylistReach
+	tx := s.db.Raw(`
+		SELECT p.id AS playlist_id, p.name, count(*) AS tracks
+		FROM track_playlist tp
+		JOIN playlists p ON p.id = tp.playlist_id
+		JOIN tracks t ON t.id = tp.track_id
+		JOIN albums a ON a.id = t.album_id
+		WHERE a.musician_id = ? AND p.kind = ? AND p.visibility <> 'private'
+		GROUP BY p.id, p.name
+		ORDER BY tracks DESC, p.id
+		LIMIT ?`, musicianId, models.PlaylistKindUser, limit).Scan(&playlists)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track_playlist)")
+	}
+
+	return playlists, nil
+}
+
+// day formats a date as text not to depend on the session time zone
+func day(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// groupRows turns rows ordered by item into series
+func groupRows(rows []*statsRow) []*models.ItemStats {
+	var res []*models.ItemStats
+
+	for _, v := range rows {
+		if len(res) == 0 || res[len(res)-1].Id != v.Id {
+			res = append(res, &models.ItemStats{Id: v.Id, Name: v.Name, Series: []*models.StatsPoint{}})
+		}
+
+		if v.Day == nil {
+			continue
+		}
+
+		item := res[len(res)-1]
+		item.Series = append(item.Series, &models.StatsPoint{
+			Day:          *v.Day,
+			Plays:        v.Plays,
+			Skips:        v.Skips,
+			Likes:        v.Likes,
+			PlaylistAdds: v.PlaylistAdds,
+			Listeners:    v.Listeners,
+			Clicks:       v.Clicks,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
+// StatsRepository reads daily rollups for days in [from, to]
+type StatsRepository interface {
+	GetTrackStats(musicianId uint64, from time.Time, to time.Time) ([]*models.ItemStats, error)
+	GetAlbumStats(musicianId uint64, from time.Time, to time.Time) ([]*models.ItemStats, error)
+	GetMerchStats(musicianId uint64, from time.Time, to time.Time) ([]*models.ItemStats, error)
+	GetTopPlaylists(musicianId uint64, limit int) ([]*models.PlaylistReach, error)
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"github.com/pkg/errors"
+	"src/internal/domain/stats/repository"
+	"src/internal/models"
+	"time"
+)
+
+const (
+	DefaultDays = 30
+	MaxDays     = 366
+
+	topPlaylistsLimit = 10
+	day               = 24 * time.Hour
+)
+
+type StatsUseCase interface {
+	// GetMusicianStats returns stats for days in [from, to], the last
+	// DefaultDays days by default
+	GetMusicianStats(musicianId uint64, from *time.Time, to *time.Time) (*models.MusicianStats, error)
+}
+
+type usecase struct {
+	statsRep repository.StatsRepository
+	now      func() time.Time
+}
+
+func NewStatsUseCase(statsRep repository.StatsRepository) StatsUseCase {
+	return &usecase{
+		statsRep: statsRep,
+		now:      time.Now,
+	}
+}
+
+func (u *usecase) GetMusicianStats(musicianId uint64, from *time.Time, to *time.Time) (*models.MusicianStats, error) {
+	end := u.now().UTC().Truncate(day)
+	if to != nil {
+		end = to.UTC().Truncate(day)
+	}
+
+	start := end.Add(-(DefaultDays - 1) * day)
+	if from != nil {
+		start = from.UTC().Truncate(day)
+	}
+
+	if start.After(end) || end.Sub(start) >= MaxDays*day {
+		return nil, models.ErrInvalidParameter
+	}
+
+	tracks, err := u.statsRep.GetTrackStats(musicianId, start, end)
+	if err != nil {
+		return nil, errors.Wrap(err, "stats.usecase.GetMusicianStats error while tracks call")
+	}
+
+	albums, err := u.statsRep.GetAlbumStats(musicianId, start, end)
+	if err != nil {
+		return nil, errors.Wrap(err, "stats.usecase.GetMusicianStats error while albums call")
+	}
+
+	merch, err := u.statsRep.GetMerchStats(musicianId, start, end)
+	if err != nil {
+		return nil, errors.Wrap(err, "stats.usecase.GetMusicianStats error while merch call")
+	}
+
+	playlists, err := u.statsRep.GetTopPlaylists(musicianId, topPlaylistsLimit)
+	if err != nil {
+		return nil, errors.Wrap(err, "stats.usecase.GetMusicianStats error while playlists call")
+	}
+
+	return &models.MusicianStats{
+		MusicianId:   musicianId,
+		From:         start,
+		To:           end,
+		Tracks:       tracks,
+		Albums:       albums,
+		Merch:        merch,
+		TopPlaylists: playlists,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock_repository "src/internal/domain/stats/repository/mocks"
+	"src/internal/models"
+	"testing"
+	"time"
+)
+
+func TestStatsUseCase_GetMusicianStats(t *testing.T) {
+	now := time.Date(2024, 5, 31, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)
+	monthAgo := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	yearAgo := today.Add(-MaxDays * day)
+	dbErr := errors.New("db error")
+
+	tracks := []*models.ItemStats{{Id: 1, Name: "track"}}
+	albums := []*models.ItemStats{{Id: 2, Name: "album"}}
+	merch := []*models.ItemStats{{Id: 3, Name: "merch"}}
+	playlists := []*models.PlaylistReach{{PlaylistId: 4}}
+
+	type mock func(r *mock_repository.MockStatsRepository)
+
+	testTable := []struct {
+		name        string
+		from        *time.Time
+		to          *time.Time
+		mock        mock
+		expected    *models.MusicianStats
+		expectedErr error
+	}{
+		{
+			name: "Default range test",
+			mock: func(r *mock_repository.MockStatsRepository) {
+				r.EXPECT().GetTrackStats(uint64(1), monthAgo, today).Return(tracks, nil)
+				r.EXPECT().GetAlbumStats(uint64(1), monthAgo, today).Return(albums, nil)
+				r.EXPECT().GetMerchStats(uint64(1), monthAgo, today).Return(merch, nil)
+				r.EXPECT().GetTopPlaylists(uint64(1), topPlaylistsLimit).Return(playlists, nil)
+			},
+			expected: &models.MusicianStats{
+				MusicianId:   1,
+				From:         monthAgo,
+				To:           today,
+				Tracks:       tracks,
+				Albums:       albums,
+				Merch:        merch,
+				TopPlaylists: playlists,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Explicit range test",
+			from: &from,
+			to:   &to,
+			mock: func(r *mock_repository.MockStatsRepository) {
+				r.EXPECT().GetTrackStats(uint64(1), from, to).Return(nil, nil)
+				r.EXPECT().GetAlbumStats(uint64(1), from, to).Return(nil, nil)
+				r.EXPECT().GetMerchStats(uint64(1), from, to).Return(nil, nil)
+				r.EXPECT().GetTopPlaylists(uint64(1), topPlaylistsLimit).Return(nil, nil)
+			},
+			expected:    &models.MusicianStats{MusicianId: 1, From: from, To: to},
+			expectedErr: nil,
+		},
+		{
+			name: "Reversed range test",
+			from: &to,
+			to:   &from,
+			mock: func(r *mock_repository.MockStatsRepository) {
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name: "Too long range test",
+			from: &yearAgo,
+			mock: func(r *mock_repository.MockStatsRepository) {
+			},
+			expected:    nil,
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name: "Repo fail test",
+			mock: func(r *mock_repository.MockStatsRepository) {
+				r.EXPECT().GetTrackStats(uint64(1), monthAgo, today).Return(tracks, nil)
+				r.EXPECT().GetAlbumStats(uint64(1), monthAgo, today).Return(nil, dbErr)
+			},
+			expected:    nil,
+			expectedErr: errors.Wrap(dbErr, "stats.usecase.GetMusicianStats error while albums call"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockStatsRepository(ctrl)
+			tc.mock(repo)
+
+			u := &usecase{statsRep: repo, now: func() time.Time { return now }}
+			stats, err := u.GetMusicianStats(1, tc.from, tc.to)
+
+			assert.Equal(t, tc.expected, stats)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,14 +1,18 @@
 package delivery
 
 import (
+	"bytes"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/pkg/errors"
 	"net/http"
 	"src/internal/domain/track/usecase"
 	"src/internal/lib/api/response"
+	"src/internal/lib/audio"
 	"src/internal/models"
 	"src/internal/models/dto"
 	"strconv"
+	"time"
 )
 
 // @Summary UpdateTrack
@@ -44,7 +48,16 @@ func UpdateTrack(useCase usecase.TrackUseCase) http.HandlerFunc {
 		}
 
 		err = useCase.UpdateTrack(dto.ToModelTrackObjectWithoutId(&req, trackIDUint, ""))
-		if err != nil {
+		if errors.Is(err, models.ErrInvalidGenre) || errors.Is(err, models.ErrInvalidMusician) ||
+			errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
@@ -91,11 +104,15 @@ func GetTrack(useCase usecase.TrackUseCase) http.HandlerFunc {
 // @Summary FindTracks
 // @Security ApiKeyAuth
 // @Tags track
-// @Description find tracks
+// @Description find tracks by name, genres, moods and lyrics, at least one of them is required.
+// @Description A genre matches its subgenres and tracks of albums with the genre.
 // @ID find-tracks
 // @Accept  json
 // @Produce  json
-// @Param        q    query     string  true  "name search by q"
+// @Param        q    query     string  false  "name search by q"
+// @Param        genre    query     []string  false  "any of genres" collectionFormat(multi)
+// @Param        mood    query     []string  false  "any of moods" collectionFormat(multi)
+// @Param        lyrics    query     string  false  "words in lyrics of any language"
 // @Param        page    query     int  true  "number of page from 1"
 // @Param        page_size    query     int  true  "size of page"
 // @Success 200 {object} dto.TracksMetaCollection
@@ -105,11 +122,11 @@ func GetTrack(useCase usecase.TrackUseCase) http.HandlerFunc {
 // @Router /api/track [get]
 func FindTracks(useCase usecase.TrackUseCase) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		name := r.URL.Query().Get("q")
-		if name == "" {
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, response.Error(models.ErrInvalidParameter.Error()))
-			return
+		filter := &models.TrackFilter{
+			Name:   r.URL.Query().Get("q"),
+			Genres: r.URL.Query()["genre"],
+			Moods:  r.URL.Query()["mood"],
+			Lyrics: r.URL.Query().Get("lyrics"),
 		}
 
 		pageStr := r.URL.Query().Get("page")
@@ -128,8 +145,12 @@ func FindTracks(useCase usecase.TrackUseCase) http.HandlerFunc {
 			return
 		}
 
-		tracks, err := useCase.GetTracksByPartName(name, page, pageSize)
-		if err != nil {
+		tracks, err := useCase.FindTracks(filter, page, pageSize)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error(err.Error()))
 			return
@@ -168,3 +189,230 @@ func GetGenres(useCase usecase.TrackUseCase) http.HandlerFunc {
 		render.JSON(w, r, dto.Genres{Genres: genres})
 	}
 }
+
+// @Summary GetLyrics
+// @Security ApiKeyAuth
+// @Tags track
+// @Description get lyrics of the track, offsets of synced lines are in milliseconds
+// @ID get-lyrics
+// @Accept  json
+// @Produce  json
+// @Param id path int true "track ID"
+// @Param lang query string false "language, any if omitted"
+// @Success 200 {object} dto.Lyrics
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/track/{id}/lyrics [get]
+func GetLyrics(useCase usecase.TrackUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		trackID := chi.URLParam(r, "id")
+		trackIDUint, err := strconv.ParseUint(trackID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		lyrics, err := useCase.GetLyrics(trackIDUint, r.URL.Query().Get("lang"))
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoLyrics(lyrics))
+	}
+}
+
+// @Summary SetLyrics
+// @Security ApiKeyAuth
+// @Tags track
+// @Description set plain or LRC lyrics of the track, lyrics in the same language are replaced
+// @ID set-lyrics
+// @Accept  json
+// @Produce  json
+// @Param id path int true "track ID"
+// @Param input body dto.SetLyricsRequest true "lyrics"
+// @Success 200 {object} response.Response
+// @Failure 400,404,405 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/track/{id}/lyrics [put]
+func SetLyrics(useCase usecase.TrackUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		trackID := chi.URLParam(r, "id")
+		trackIDUint, err := strconv.ParseUint(trackID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var req dto.SetLyricsRequest
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.SetLyrics(trackIDUint, req.Language, req.Lyrics)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary DeleteLyrics
+// @Security ApiKeyAuth
+// @Tags track
+// @Description delete lyrics of the track in the language
+// @ID delete-lyrics
+// @Accept  json
+// @Produce  json
+// @Param id path int true "track ID"
+// @Param language path string true "language"
+// @Success 200 {object} response.Response
+// @Failure 400,404,405 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/track/{id}/lyrics/{language} [delete]
+func DeleteLyrics(useCase usecase.TrackUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		trackID := chi.URLParam(r, "id")
+		trackIDUint, err := strconv.ParseUint(trackID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		err = useCase.DeleteLyrics(trackIDUint, chi.URLParam(r, "language"))
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNothingToDelete) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary GetWaveform
+// @Tags track
+// @Description get peaks of the track for a waveform, the smallest resolution
+// @Description with at least the points is returned, or the largest one
+// @ID get-waveform
+// @Accept  json
+// @Produce  json
+// @Param id path int true "track ID"
+// @Param        points    query     int  false  "wanted number of peaks"
+// @Success 200 {object} dto.Waveform
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/track/{id}/waveform [get]
+func GetWaveform(useCase usecase.TrackUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		trackID := chi.URLParam(r, "id")
+		trackIDUint, err := strconv.ParseUint(trackID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		var points int
+		if v := r.URL.Query().Get("points"); v != "" {
+			points, err = strconv.Atoi(v)
+			if err != nil {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error(err.Error()))
+				return
+			}
+		}
+
+		waveform, err := useCase.GetWaveform(trackIDUint, points)
+		if errors.Is(err, models.ErrInvalidParameter) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.ToDtoWaveform(waveform))
+	}
+}
+
+// @Summary GetPreview
+// @Tags track
+// @Description get a short WAV clip of the track, range requests are supported
+// @ID get-preview
+// @Produce  audio/wav
+// @Param id path int true "track ID"
+// @Success 200 {file} file
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/track/{id}/preview [get]
+func GetPreview(useCase usecase.TrackUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		trackID := chi.URLParam(r, "id")
+		trackIDUint, err := strconv.ParseUint(trackID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		preview, err := useCase.GetPreview(trackIDUint)
+		if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", audio.WavContentType)
+		http.ServeContent(w, r, "preview.wav", time.Time{}, bytes.NewReader(preview))
+	}
+}
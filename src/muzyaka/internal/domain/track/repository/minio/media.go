@@ -0,0 +1,96 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"io"
+	"src/internal/lib/audio"
+	"src/internal/models"
+	"src/internal/models/dao"
+)
+
+const (
+	previewSuffix  = ".preview.wav"
+	waveformSuffix = ".waveform.json"
+)
+
+func isNotFound(err error) bool {
+	return minio.ToErrorResponse(err).Code == "NoSuchKey"
+}
+
+func (t trackStorage) SourceTag(track *models.TrackMeta) (string, error) {
+	info, err := t.client.StatObject(context.TODO(), TrackBucket, track.Source, minio.StatObjectOptions{})
+	if isNotFound(err) {
+		return "", models.ErrNotFound
+	} else if err != nil {
+		return "", errors.Wrap(err, "track.minio failed to stat")
+	}
+
+	return info.ETag, nil
+}
+
+func (t trackStorage) putMedia(name string, payload []byte, contentType string) error {
+	_, err := t.client.PutObject(context.TODO(),
+		TrackBucket,
+		name,
+		bytes.NewReader(payload),
+		int64(len(payload)),
+		minio.PutObjectOptions{ContentType: contentType})
+
+	if err != nil {
+		return errors.Wrap(err, "track.minio failed to put")
+	}
+	return nil
+}
+
+func (t trackStorage) loadMedia(name string) ([]byte, error) {
+	obj, err := t.client.GetObject(context.TODO(), TrackBucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "track.minio failed to get")
+	}
+	defer obj.Close()
+
+	// errors of GetObject are returned on the first read
+	payload, err := io.ReadAll(obj)
+	if isNotFound(err) {
+		return nil, models.ErrNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "track.minio failed to get")
+	}
+
+	return payload, nil
+}
+
+func (t trackStorage) SavePreview(track *models.TrackMeta, preview []byte) error {
+	return t.putMedia(track.Source+previewSuffix, preview, audio.WavContentType)
+}
+
+func (t trackStorage) LoadPreview(track *models.TrackMeta) ([]byte, error) {
+	return t.loadMedia(track.Source + previewSuffix)
+}
+
+func (t trackStorage) SaveWaveform(track *models.TrackMeta, waveform *models.Waveform) error {
+	payload, err := json.Marshal(dao.ToStorageWaveform(waveform))
+	if err != nil {
+		return errors.Wrap(err, "track.minio failed to marshal waveform")
+	}
+
+	return t.putMedia(track.Source+waveformSuffix, payload, "application/json")
+}
+
+func (t trackStorage) LoadWaveform(track *models.TrackMeta) (*models.Waveform, error) {
+	payload, err := t.loadMedia(track.Source + waveformSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	var waveform dao.Waveform
+	if err := json.Unmarshal(payload, &waveform); err != nil {
+		return nil, errors.Wrap(err, "track.minio failed to unmarshal waveform")
+	}
+
+	return dao.ToModelWaveform(&waveform), nil
+}
@@ -55,13 +55,8 @@ func (t trackStorage) LoadObject(track *models.TrackMeta) (*models.TrackObject,
 		return nil, errors.Wrap(err, "album.minio failed to get")
 	}
 	ret := models.TrackObject{
-		TrackMeta: models.TrackMeta{
-			Id:     track.Id,
-			Source: track.Source,
-			Name:   track.Name,
-			Genre:  track.Genre,
-		},
-		Payload: buffer,
+		TrackMeta: *track,
+		Payload:   buffer,
 	}
 
 	return &ret, nil
@@ -70,9 +65,11 @@ func (t trackStorage) LoadObject(track *models.TrackMeta) (*models.TrackObject,
 func (t trackStorage) DeleteObject(track *models.TrackMeta) error {
 	ctx := context.TODO()
 
-	err := t.client.RemoveObject(ctx, TrackBucket, track.Source, minio.RemoveObjectOptions{})
-	if err != nil {
-		return errors.Wrap(err, "album.minio failed to delete")
+	for _, name := range []string{track.Source, track.Source + previewSuffix, track.Source + waveformSuffix} {
+		err := t.client.RemoveObject(ctx, TrackBucket, name, minio.RemoveObjectOptions{})
+		if err != nil {
+			return errors.Wrap(err, "album.minio failed to delete")
+		}
 	}
 
 	return nil
@@ -34,18 +34,107 @@ func (m *MockTrackRepository) EXPECT() *MockTrackRepositoryMockRecorder {
 	return m.recorder
 }
 
-// DeleteTrack mocks base method.
-func (m *MockTrackRepository) DeleteTrack(trackId uint64) error {
+// AcquireSource mocks base method.
+func (m *MockTrackRepository) AcquireSource(source string, upload func() error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTrack", trackId)
+	ret := m.ctrl.Call(m, "AcquireSource", source, upload)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteTrack indicates an expected call of DeleteTrack.
-func (mr *MockTrackRepositoryMockRecorder) DeleteTrack(trackId interface{}) *gomock.Call {
+// AcquireSource indicates an expected call of AcquireSource.
+func (mr *MockTrackRepositoryMockRecorder) AcquireSource(source, upload interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrack", reflect.TypeOf((*MockTrackRepository)(nil).DeleteTrack), trackId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireSource", reflect.TypeOf((*MockTrackRepository)(nil).AcquireSource), source, upload)
+}
+
+// DeleteLyrics mocks base method.
+func (m *MockTrackRepository) DeleteLyrics(trackId uint64, language string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLyrics", trackId, language)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLyrics indicates an expected call of DeleteLyrics.
+func (mr *MockTrackRepositoryMockRecorder) DeleteLyrics(trackId, language interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLyrics", reflect.TypeOf((*MockTrackRepository)(nil).DeleteLyrics), trackId, language)
+}
+
+// FindFingerprints mocks base method.
+func (m *MockTrackRepository) FindFingerprints(trackId uint64, keys []uint32, limit int) ([]*models.Fingerprint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFingerprints", trackId, keys, limit)
+	ret0, _ := ret[0].([]*models.Fingerprint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFingerprints indicates an expected call of FindFingerprints.
+func (mr *MockTrackRepositoryMockRecorder) FindFingerprints(trackId, keys, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFingerprints", reflect.TypeOf((*MockTrackRepository)(nil).FindFingerprints), trackId, keys, limit)
+}
+
+// FindTracks mocks base method.
+func (m *MockTrackRepository) FindTracks(filter *models.TrackFilter, offset, limit int) ([]*models.TrackMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTracks", filter, offset, limit)
+	ret0, _ := ret[0].([]*models.TrackMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTracks indicates an expected call of FindTracks.
+func (mr *MockTrackRepositoryMockRecorder) FindTracks(filter, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTracks", reflect.TypeOf((*MockTrackRepository)(nil).FindTracks), filter, offset, limit)
+}
+
+// FindTracksByNames mocks base method.
+func (m *MockTrackRepository) FindTracksByNames(lookups []*models.TrackLookup, limit int) ([]*models.TrackDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTracksByNames", lookups, limit)
+	ret0, _ := ret[0].([]*models.TrackDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTracksByNames indicates an expected call of FindTracksByNames.
+func (mr *MockTrackRepositoryMockRecorder) FindTracksByNames(lookups, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTracksByNames", reflect.TypeOf((*MockTrackRepository)(nil).FindTracksByNames), lookups, limit)
+}
+
+// GetGenres mocks base method.
+func (m *MockTrackRepository) GetGenres() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenres")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenres indicates an expected call of GetGenres.
+func (mr *MockTrackRepositoryMockRecorder) GetGenres() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenres", reflect.TypeOf((*MockTrackRepository)(nil).GetGenres))
+}
+
+// GetLyrics mocks base method.
+func (m *MockTrackRepository) GetLyrics(trackId uint64, language string) (*models.Lyrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLyrics", trackId, language)
+	ret0, _ := ret[0].(*models.Lyrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLyrics indicates an expected call of GetLyrics.
+func (mr *MockTrackRepositoryMockRecorder) GetLyrics(trackId, language interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLyrics", reflect.TypeOf((*MockTrackRepository)(nil).GetLyrics), trackId, language)
 }
 
 // GetTrack mocks base method.
@@ -63,6 +152,21 @@ func (mr *MockTrackRepositoryMockRecorder) GetTrack(id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrack", reflect.TypeOf((*MockTrackRepository)(nil).GetTrack), id)
 }
 
+// GetTracks mocks base method.
+func (m *MockTrackRepository) GetTracks(ids []uint64) ([]*models.TrackMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTracks", ids)
+	ret0, _ := ret[0].([]*models.TrackMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTracks indicates an expected call of GetTracks.
+func (mr *MockTrackRepositoryMockRecorder) GetTracks(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracks", reflect.TypeOf((*MockTrackRepository)(nil).GetTracks), ids)
+}
+
 // GetTracksByPartName mocks base method.
 func (m *MockTrackRepository) GetTracksByPartName(name string, offset, limit int) ([]*models.TrackMeta, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +182,120 @@ func (mr *MockTrackRepositoryMockRecorder) GetTracksByPartName(name, offset, lim
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracksByPartName", reflect.TypeOf((*MockTrackRepository)(nil).GetTracksByPartName), name, offset, limit)
 }
 
+// GetTracksDetails mocks base method.
+func (m *MockTrackRepository) GetTracksDetails(ids []uint64) ([]*models.TrackDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTracksDetails", ids)
+	ret0, _ := ret[0].([]*models.TrackDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTracksDetails indicates an expected call of GetTracksDetails.
+func (mr *MockTrackRepositoryMockRecorder) GetTracksDetails(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracksDetails", reflect.TypeOf((*MockTrackRepository)(nil).GetTracksDetails), ids)
+}
+
+// GetUnhashedTracks mocks base method.
+func (m *MockTrackRepository) GetUnhashedTracks(afterId uint64, limit int) ([]*models.TrackMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnhashedTracks", afterId, limit)
+	ret0, _ := ret[0].([]*models.TrackMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnhashedTracks indicates an expected call of GetUnhashedTracks.
+func (mr *MockTrackRepositoryMockRecorder) GetUnhashedTracks(afterId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnhashedTracks", reflect.TypeOf((*MockTrackRepository)(nil).GetUnhashedTracks), afterId, limit)
+}
+
+// ReleaseSource mocks base method.
+func (m *MockTrackRepository) ReleaseSource(source string, remove func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseSource", source, remove)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseSource indicates an expected call of ReleaseSource.
+func (mr *MockTrackRepositoryMockRecorder) ReleaseSource(source, remove interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseSource", reflect.TypeOf((*MockTrackRepository)(nil).ReleaseSource), source, remove)
+}
+
+// ReplaceSource mocks base method.
+func (m *MockTrackRepository) ReplaceSource(id uint64, from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceSource", id, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceSource indicates an expected call of ReplaceSource.
+func (mr *MockTrackRepositoryMockRecorder) ReplaceSource(id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceSource", reflect.TypeOf((*MockTrackRepository)(nil).ReplaceSource), id, from, to)
+}
+
+// SaveFingerprint mocks base method.
+func (m *MockTrackRepository) SaveFingerprint(fingerprint *models.Fingerprint, keys []uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFingerprint", fingerprint, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFingerprint indicates an expected call of SaveFingerprint.
+func (mr *MockTrackRepositoryMockRecorder) SaveFingerprint(fingerprint, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFingerprint", reflect.TypeOf((*MockTrackRepository)(nil).SaveFingerprint), fingerprint, keys)
+}
+
+// SaveLoudness mocks base method.
+func (m *MockTrackRepository) SaveLoudness(id uint64, loudness *models.Loudness) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLoudness", id, loudness)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLoudness indicates an expected call of SaveLoudness.
+func (mr *MockTrackRepositoryMockRecorder) SaveLoudness(id, loudness interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLoudness", reflect.TypeOf((*MockTrackRepository)(nil).SaveLoudness), id, loudness)
+}
+
+// SaveLyrics mocks base method.
+func (m *MockTrackRepository) SaveLyrics(lyrics *models.Lyrics) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLyrics", lyrics)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLyrics indicates an expected call of SaveLyrics.
+func (mr *MockTrackRepositoryMockRecorder) SaveLyrics(lyrics interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLyrics", reflect.TypeOf((*MockTrackRepository)(nil).SaveLyrics), lyrics)
+}
+
+// UpdateDuration mocks base method.
+func (m *MockTrackRepository) UpdateDuration(id uint64, duration int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDuration", id, duration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDuration indicates an expected call of UpdateDuration.
+func (mr *MockTrackRepositoryMockRecorder) UpdateDuration(id, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDuration", reflect.TypeOf((*MockTrackRepository)(nil).UpdateDuration), id, duration)
+}
+
 // UpdateTrack mocks base method.
 func (m *MockTrackRepository) UpdateTrack(track *models.TrackMeta) error {
 	m.ctrl.T.Helper()
@@ -63,6 +63,79 @@ func (mr *MockTrackStorageMockRecorder) LoadObject(track interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadObject", reflect.TypeOf((*MockTrackStorage)(nil).LoadObject), track)
 }
 
+// LoadPreview mocks base method.
+func (m *MockTrackStorage) LoadPreview(track *models.TrackMeta) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadPreview", track)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadPreview indicates an expected call of LoadPreview.
+func (mr *MockTrackStorageMockRecorder) LoadPreview(track interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPreview", reflect.TypeOf((*MockTrackStorage)(nil).LoadPreview), track)
+}
+
+// LoadWaveform mocks base method.
+func (m *MockTrackStorage) LoadWaveform(track *models.TrackMeta) (*models.Waveform, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadWaveform", track)
+	ret0, _ := ret[0].(*models.Waveform)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadWaveform indicates an expected call of LoadWaveform.
+func (mr *MockTrackStorageMockRecorder) LoadWaveform(track interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadWaveform", reflect.TypeOf((*MockTrackStorage)(nil).LoadWaveform), track)
+}
+
+// SavePreview mocks base method.
+func (m *MockTrackStorage) SavePreview(track *models.TrackMeta, preview []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePreview", track, preview)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePreview indicates an expected call of SavePreview.
+func (mr *MockTrackStorageMockRecorder) SavePreview(track, preview interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePreview", reflect.TypeOf((*MockTrackStorage)(nil).SavePreview), track, preview)
+}
+
+// SaveWaveform mocks base method.
+func (m *MockTrackStorage) SaveWaveform(track *models.TrackMeta, waveform *models.Waveform) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWaveform", track, waveform)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWaveform indicates an expected call of SaveWaveform.
+func (mr *MockTrackStorageMockRecorder) SaveWaveform(track, waveform interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWaveform", reflect.TypeOf((*MockTrackStorage)(nil).SaveWaveform), track, waveform)
+}
+
+// SourceTag mocks base method.
+func (m *MockTrackStorage) SourceTag(track *models.TrackMeta) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SourceTag", track)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SourceTag indicates an expected call of SourceTag.
+func (mr *MockTrackStorageMockRecorder) SourceTag(track interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SourceTag", reflect.TypeOf((*MockTrackStorage)(nil).SourceTag), track)
+}
+
 // UploadObject mocks base method.
 func (m *MockTrackStorage) UploadObject(track *models.TrackObject) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"gorm.io/gorm"
+	"src/internal/models"
+	"src/internal/models/dao"
+)
+
+// SaveAlbumArtists replaces musicians linked to the album
+func SaveAlbumArtists(tx *gorm.DB, albumId uint64, artists []*models.Artist) error {
+	return saveArtists(tx, albumId, nil, artists)
+}
+
+// SaveTrackArtists replaces musicians linked to the track
+func SaveTrackArtists(tx *gorm.DB, trackId uint64, artists []*models.Artist) error {
+	var track dao.TrackMeta
+	if err := tx.Select("album_id").Where("id = ?", trackId).Take(&track).Error; err != nil {
+		return err
+	}
+
+	return saveArtists(tx, track.AlbumID, &trackId, artists)
+}
+
+// saveArtists skips the album's own musician and updates links in place, so
+// credit ids stay stable. A link keeps its approval while its role is the
+// same, new ones wait for it. Declined links are kept, so a musician who
+// declined is not added again.
+func saveArtists(tx *gorm.DB, albumId uint64, trackId *uint64, artists []*models.Artist) error {
+	var album dao.Album
+	if err := tx.Select("musician_id").Where("id = ?", albumId).Take(&album).Error; err != nil {
+		return err
+	}
+
+	scope := tx.Where("album_id = ?", albumId)
+	if trackId == nil {
+		scope = scope.Where("track_id IS NULL")
+	} else {
+		scope = scope.Where("track_id = ?", *trackId)
+	}
+
+	var existing []*dao.ReleaseArtist
+	if err := scope.Find(&existing).Error; err != nil {
+		return err
+	}
+
+	byMusician := make(map[uint64]*dao.ReleaseArtist, len(existing))
+	for _, v := range existing {
+		byMusician[v.MusicianID] = v
+	}
+
+	var rows []*dao.ReleaseArtist
+	kept := make(map[uint64]bool, len(artists))
+	for _, v := range artists {
+		if v.MusicianId == album.MusicianID || kept[v.MusicianId] {
+			continue
+		}
+
+		stored, ok := byMusician[v.MusicianId]
+		if ok && stored.Declined {
+			continue
+		}
+
+		kept[v.MusicianId] = true
+		position := len(kept) - 1
+		if !ok {
+			rows = append(rows, &dao.ReleaseArtist{
+				AlbumID:    albumId,
+				TrackID:    trackId,
+				MusicianID: v.MusicianId,
+				Role:       v.Role,
+				Position:   position,
+			})
+			continue
+		}
+
+		if stored.Role == v.Role && stored.Position == position {
+			continue
+		}
+
+		err := tx.Model(stored).Updates(map[string]interface{}{
+			"role":     v.Role,
+			"approved": stored.Approved && stored.Role == v.Role,
+			"position": position,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	var removed []uint64
+	for _, v := range existing {
+		if !v.Declined && !kept[v.MusicianID] {
+			removed = append(removed, v.ID)
+		}
+	}
+
+	if len(removed) > 0 {
+		if err := tx.Delete(&dao.ReleaseArtist{}, removed).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(rows))
+	for _, v := range rows {
+		ids = append(ids, v.MusicianID)
+	}
+
+	var count int64
+	if err := tx.Model(&dao.Musician{}).Where("id IN ?", ids).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count != int64(len(ids)) {
+		return models.ErrInvalidMusician
+	}
+
+	return tx.Create(&rows).Error
+}
+
+// LoadAlbumArtists returns artists of every album, the primary artist first
+func LoadAlbumArtists(db *gorm.DB, albumIds []uint64) (map[uint64][]*models.Artist, error) {
+	res := make(map[uint64][]*models.Artist)
+	if len(albumIds) == 0 {
+		return res, nil
+	}
+
+	var rows []*dao.ArtistRow
+	tx := db.Raw(`
+		SELECT a.id AS owner_id, m.id AS musician_id, m.name, ? AS role, TRUE AS approved, -1 AS position
+		FROM albums a
+		         JOIN musicians m ON m.id = a.musician_id
+		WHERE a.id IN ?
+		UNION ALL
+		SELECT ra.album_id, m.id, m.name, ra.role::TEXT, ra.approved, ra.position
+		FROM release_artists ra
+		         JOIN musicians m ON m.id = ra.musician_id
+		WHERE ra.album_id IN ?
+		  AND ra.track_id IS NULL
+		  AND NOT ra.declined
+		ORDER BY owner_id, position`, models.ArtistPrimary, albumIds, albumIds).Scan(&rows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	for _, v := range rows {
+		res[v.OwnerID] = append(res[v.OwnerID], dao.ToModelArtist(v))
+	}
+
+	return res, nil
+}
+
+// loadTrackArtists returns musicians linked to every track
+func loadTrackArtists(db *gorm.DB, trackIds []uint64) (map[uint64][]*models.Artist, error) {
+	var rows []*dao.ArtistRow
+	tx := db.Raw(`
+		SELECT ra.track_id AS owner_id, m.id AS musician_id, m.name, ra.role, ra.approved
+		FROM release_artists ra
+		         JOIN musicians m ON m.id = ra.musician_id
+		WHERE ra.track_id IN ?
+		  AND NOT ra.declined
+		ORDER BY ra.position`, trackIds).Scan(&rows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	res := make(map[uint64][]*models.Artist)
+	for _, v := range rows {
+		res[v.OwnerID] = append(res[v.OwnerID], dao.ToModelArtist(v))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/models"
+	"src/internal/models/dao"
+)
+
+func (t trackRepository) SaveFingerprint(fingerprint *models.Fingerprint, keys []uint32) error {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var track dao.TrackMeta
+		if err := tx.Where("id = ?", fingerprint.TrackId).Take(&track).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if err := tx.Where("track_id = ?", fingerprint.TrackId).Delete(&dao.Fingerprint{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("track_id = ?", fingerprint.TrackId).Delete(&dao.FingerprintKey{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(dao.ToPostgresFingerprint(fingerprint)).Error; err != nil {
+			return err
+		}
+
+		if len(keys) == 0 {
+			return nil
+		}
+
+		rows := make([]*dao.FingerprintKey, 0, len(keys))
+		for _, v := range keys {
+			rows = append(rows, &dao.FingerprintKey{Key: v, TrackID: fingerprint.TrackId})
+		}
+
+		return tx.CreateInBatches(rows, 1000).Error
+	})
+
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table track_fingerprints)")
+	}
+
+	return nil
+}
+
+func (t trackRepository) FindFingerprints(trackId uint64, keys []uint32, limit int) ([]*models.Fingerprint, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	var ids []uint64
+	tx := t.db.Raw(`
+SELECT k.track_id
+FROM track_fingerprint_keys k
+         JOIN tracks t ON t.id = k.track_id
+         JOIN albums a ON a.id = t.album_id
+WHERE k.key IN ?
+  AND a.musician_id <> (SELECT a.musician_id FROM tracks t JOIN albums a ON a.id = t.album_id WHERE t.id = ?)
+GROUP BY k.track_id
+ORDER BY COUNT(*) DESC, k.track_id
+LIMIT ?`, keys, trackId, limit).Scan(&ids)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track_fingerprint_keys)")
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var rows []*dao.Fingerprint
+	tx = t.db.Where("track_id IN ?", ids).Find(&rows)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track_fingerprints)")
+	}
+
+	byTrack := make(map[uint64]*dao.Fingerprint, len(rows))
+	for _, v := range rows {
+		byTrack[v.TrackID] = v
+	}
+
+	// the ones sharing most keys first
+	res := make([]*models.Fingerprint, 0, len(rows))
+	for _, id := range ids {
+		if v, ok := byTrack[id]; ok {
+			res = append(res, dao.ToModelFingerprint(v))
+		}
+	}
+
+	return res, nil
+}
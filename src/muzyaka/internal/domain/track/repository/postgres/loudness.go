@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/lib/loudness"
+	"src/internal/models"
+	"src/internal/models/dao"
+)
+
+func (t trackRepository) SaveLoudness(id uint64, l *models.Loudness) error {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var track dao.TrackMeta
+		if err := tx.Where("id = ?", id).Take(&track).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		err := tx.Model(&dao.TrackMeta{}).Where("id = ?", id).Updates(loudnessColumns(l)).Error
+		if err != nil {
+			return err
+		}
+
+		return UpdateAlbumLoudness(tx, track.AlbumID)
+	})
+
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table track)")
+	}
+
+	return nil
+}
+
+func loudnessColumns(l *models.Loudness) map[string]interface{} {
+	if l == nil {
+		return map[string]interface{}{"loudness": nil, "true_peak": nil}
+	}
+
+	return map[string]interface{}{"loudness": l.Integrated, "true_peak": l.TruePeak}
+}
+
+// UpdateAlbumLoudness combines loudness of the album tracks weighted by their
+// duration, it is cleared while any of them is not measured
+func UpdateAlbumLoudness(tx *gorm.DB, albumId uint64) error {
+	var tracks []*dao.TrackMeta
+	if err := tx.Where("album_id = ?", albumId).Find(&tracks).Error; err != nil {
+		return err
+	}
+
+	var album *models.Loudness
+	if len(tracks) > 0 {
+		measured := make([]*models.Loudness, 0, len(tracks))
+		durations := make([]float64, 0, len(tracks))
+		for _, v := range tracks {
+			l := dao.ToModelLoudness(v.Loudness, v.TruePeak)
+			if l == nil {
+				break
+			}
+
+			// tracks shorter than a second have no duration
+			duration := 1.0
+			if v.Duration != nil {
+				duration = float64(*v.Duration)
+			}
+
+			measured = append(measured, l)
+			durations = append(durations, duration)
+		}
+
+		if len(measured) == len(tracks) {
+			album = loudness.Combine(measured, durations)
+		}
+	}
+
+	return tx.Model(&dao.Album{}).Where("id = ?", albumId).Updates(loudnessColumns(album)).Error
+}
+
+// loadAlbumLoudness skips albums with tracks that are not measured
+func loadAlbumLoudness(db *gorm.DB, albumIds []uint64) (map[uint64]*models.Loudness, error) {
+	var albums []*dao.Album
+	tx := db.Select("id", "loudness", "true_peak").Where("id IN ? AND loudness IS NOT NULL", albumIds).Find(&albums)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	res := make(map[uint64]*models.Loudness, len(albums))
+	for _, v := range albums {
+		res[v.ID] = dao.ToModelLoudness(v.Loudness, v.TruePeak)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"src/internal/models/events"
+)
+
+func (t trackRepository) GetLyrics(trackId uint64, language string) (*models.Lyrics, error) {
+	var languages []string
+	tx := t.db.Model(&dao.TrackLyrics{}).Where("track_id = ?", trackId).Order("language").Pluck("language", &languages)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track_lyrics)")
+	}
+
+	if len(languages) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	if language == "" {
+		language = languages[0]
+	}
+
+	var lyrics dao.TrackLyrics
+	tx = t.db.Where("track_id = ? AND language = ?", trackId, language).Take(&lyrics)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	} else if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track_lyrics)")
+	}
+
+	res := dao.ToModelLyrics(&lyrics)
+	res.Languages = languages
+	return res, nil
+}
+
+func (t trackRepository) SaveLyrics(lyrics *models.Lyrics) error {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var track dao.TrackMeta
+		if err := tx.Where("id = ?", lyrics.TrackId).Take(&track).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "track_id"}, {Name: "language"}},
+			DoUpdates: clause.AssignmentColumns([]string{"synced", "lines", "text"}),
+		}).Create(dao.ToPostgresLyrics(lyrics)).Error
+		if err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.TrackUpdated, track.ID, dao.ToTrackPayload(&track))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table track_lyrics)")
+	}
+
+	return nil
+}
+
+func (t trackRepository) DeleteLyrics(trackId uint64, language string) error {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("track_id = ? AND language = ?", trackId, language).Delete(&dao.TrackLyrics{})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNothingToDelete
+		}
+
+		var track dao.TrackMeta
+		if err := tx.Where("id = ?", trackId).Take(&track).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.TrackUpdated, track.ID, dao.ToTrackPayload(&track))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNothingToDelete) {
+		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table track_lyrics)")
+	}
+
+	return nil
+}
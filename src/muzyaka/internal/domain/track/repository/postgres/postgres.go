@@ -1,11 +1,14 @@
 package postgres
 
 import (
+	"database/sql"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 	"src/internal/domain/track/repository"
 	"src/internal/models"
 	"src/internal/models/dao"
+	"src/internal/models/events"
+	"strings"
 )
 
 type trackRepository struct {
@@ -44,50 +47,267 @@ func (t trackRepository) GetTracksByPartName(name string, offset int, limit int)
 		return nil, errors.Wrap(err, "database error (table track)")
 	}
 
-	var modelTracks []*models.TrackMeta
+	modelTracks, err := LoadTracks(t.db, tracks)
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table track)")
+	}
 
-	for _, v := range tracks {
-		var genre dao.Genre
-		tx = t.db.Where("id = ?", v.GenreRefer).Limit(1).Find(&genre)
-		if tx.Error != nil {
-			return nil, errors.Wrap(tx.Error, "database error (table track)")
+	return modelTracks, nil
+}
+
+// genreSubtreeQuery selects ids of genres with the given lowercase names and of all their subgenres
+const genreSubtreeQuery = `
+WITH RECURSIVE subtree AS (SELECT id
+                           FROM genres
+                           WHERE lower(name) IN @genres
+                           UNION
+                           SELECT g.id
+                           FROM genres g
+                                    JOIN subtree s ON g.parent_id = s.id)
+SELECT id
+FROM subtree`
+
+func (t trackRepository) FindTracks(filter *models.TrackFilter, offset int, limit int) ([]*models.TrackMeta, error) {
+	var tracks []*dao.TrackMeta
+
+	query := t.db.
+		Offset(offset).
+		Limit(limit).
+		Order("name").
+		Order("id")
+
+	if filter.Name != "" {
+		query = query.Where("name LIKE ?", "%"+filter.Name+"%")
+	}
+
+	if len(filter.Genres) > 0 {
+		genres := make([]string, 0, len(filter.Genres))
+		for _, v := range filter.Genres {
+			genres = append(genres, strings.ToLower(v))
 		}
-		modelTracks = append(modelTracks, dao.ToModelTrack(v, &genre))
+
+		// a track matches by its primary genre, any of its genres or genres of its album
+		query = query.Where(`(genre IN (`+genreSubtreeQuery+`)
+			OR id IN (SELECT track_id FROM track_genres WHERE genre_id IN (`+genreSubtreeQuery+`))
+			OR album_id IN (SELECT album_id FROM album_genres WHERE genre_id IN (`+genreSubtreeQuery+`)))`,
+			sql.Named("genres", genres))
 	}
 
-	return modelTracks, nil
+	if len(filter.Moods) > 0 {
+		query = query.Where("id IN (SELECT track_id FROM track_moods WHERE mood IN ?)", filter.Moods)
+	}
+
+	if filter.Lyrics != "" {
+		query = query.Where("id IN (SELECT track_id FROM track_lyrics WHERE search @@ plainto_tsquery('simple', ?))", filter.Lyrics)
+	}
+
+	if err := query.Find(&tracks).Error; err != nil {
+		return nil, errors.Wrap(err, "database error (table track)")
+	}
+
+	res, err := LoadTracks(t.db, tracks)
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table track)")
+	}
+
+	return res, nil
 }
 
 func (t trackRepository) GetTrack(id uint64) (*models.TrackMeta, error) {
 	var track dao.TrackMeta
 
 	tx := t.db.Where("id = ?", id).Take(&track)
-	if tx.Error != nil {
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, errors.Wrap(models.ErrNotFound, "database error (table track)")
+	} else if tx.Error != nil {
 		return nil, errors.Wrap(tx.Error, "database error (table track)")
 	}
 
-	var genre dao.Genre
-	tx = t.db.Where("id = ?", track.GenreRefer).Limit(1).Find(&genre)
-	if tx.Error != nil {
-		return nil, errors.Wrap(tx.Error, "database error (table track)")
+	res, err := LoadTracks(t.db, []*dao.TrackMeta{&track})
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table track)")
+	}
+
+	return res[0], nil
+}
+
+func (t trackRepository) GetTracks(ids []uint64) ([]*models.TrackMeta, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var tracks []*dao.TrackMeta
+	if err := t.db.Where("id IN ?", ids).Find(&tracks).Error; err != nil {
+		return nil, errors.Wrap(err, "database error (table track)")
 	}
 
-	return dao.ToModelTrack(&track, &genre), nil
+	res, err := LoadTracks(t.db, tracks)
+	if err != nil {
+		return nil, errors.Wrap(err, "database error (table track)")
+	}
+
+	return res, nil
 }
 
 func (t trackRepository) UpdateTrack(track *models.TrackMeta) error {
-	var pgGenre dao.Genre
-	tx := t.db.Where("name = ?", track.Genre).Limit(1).Find(&pgGenre)
+	genres, err := ResolveGenres(t.db, track.AllGenres())
+	if err != nil {
+		return errors.Wrap(err, "database error (table track)")
+	}
+
+	pgTrack := dao.ToPostgresTrack(track, PrimaryGenre(genres), 0)
+
+	err = t.db.Transaction(func(tx *gorm.DB) error {
+		var stored dao.TrackMeta
+		if err := tx.Select("source", "album_id").Where("id = ?", pgTrack.ID).Take(&stored).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Omit("id", "album_id", "genre", "isrc", "explicit").Updates(&pgTrack).Error; err != nil {
+			return err
+		}
+
+		// Updates skips zero fields, so the ones that can be cleared are set separately
+		err := tx.Model(&dao.TrackMeta{}).Where("id = ?", pgTrack.ID).Updates(map[string]interface{}{
+			"genre":    pgTrack.GenreRefer,
+			"isrc":     pgTrack.Isrc,
+			"explicit": pgTrack.Explicit,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := SaveTrackTags(tx, pgTrack.ID, genres, track.Moods); err != nil {
+			return err
+		}
+
+		if err := SaveTrackCredits(tx, pgTrack.ID, track.FeaturedArtists, track.Credits); err != nil {
+			return err
+		}
+
+		if err := SaveTrackArtists(tx, pgTrack.ID, track.Artists); err != nil {
+			return err
+		}
+
+		sourceChanged := pgTrack.Source != "" && pgTrack.Source != stored.Source
+		if sourceChanged {
+			err := tx.Model(&dao.TrackMeta{}).Where("id = ?", pgTrack.ID).Updates(loudnessColumns(nil)).Error
+			if err != nil {
+				return err
+			}
+
+			if err := UpdateAlbumLoudness(tx, stored.AlbumID); err != nil {
+				return err
+			}
+		}
+
+		var updated dao.TrackMeta
+		if err := tx.Where("id = ?", pgTrack.ID).Take(&updated).Error; err != nil {
+			return err
+		}
+
+		payload := dao.ToTrackPayload(&updated)
+		payload.SourceChanged = sourceChanged
+
+		outbox, err := dao.NewOutbox(events.TrackUpdated, updated.ID, payload)
+		if err != nil {
+			return err
+		}
 
+		return tx.Create(outbox).Error
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table track)")
+	}
+
+	return nil
+}
+
+func (t trackRepository) UpdateDuration(id uint64, duration int) error {
+	tx := t.db.Model(&dao.TrackMeta{}).Where("id = ?", id).Update("duration", duration)
 	if tx.Error != nil {
 		return errors.Wrap(tx.Error, "database error (table track)")
 	}
 
-	pgTrack := dao.ToPostgresTrack(track, pgGenre.ID, 0)
-
-	if err := t.db.Omit("id", "album_id").Updates(&pgTrack).Error; err != nil {
-		return errors.Wrap(err, "database error (table track)")
+	if tx.RowsAffected == 0 {
+		return errors.Wrap(models.ErrNotFound, "database error (table track)")
 	}
 
 	return nil
 }
+
+const trackDetailsQuery = `
+SELECT t.id, t.source, t.name, g.name AS genre, m.name AS musician, t.duration
+FROM tracks t
+         JOIN albums a ON a.id = t.album_id
+         JOIN musicians m ON m.id = a.musician_id
+         LEFT JOIN genres g ON g.id = t.genre`
+
+func (t trackRepository) FindTracksByNames(lookups []*models.TrackLookup, limit int) ([]*models.TrackDetails, error) {
+	if len(lookups) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, 0, len(lookups))
+	vars := make([]interface{}, 0, 3*len(lookups)+1)
+	for i, v := range lookups {
+		values = append(values, "(?::INT, ?, ?)")
+		vars = append(vars, i, models.FoldName(v.Name), models.FoldName(v.Musician))
+	}
+	vars = append(vars, limit)
+
+	var tracks []*dao.TrackDetails
+
+	// the musician is filtered before the limit, so common names don't
+	// crowd out the track by the right musician
+	tx := t.db.Raw(`
+WITH lookups (n, name, musician) AS (VALUES `+strings.Join(values, ", ")+`),
+     candidates AS (SELECT t.id, row_number() OVER (PARTITION BY l.n ORDER BY t.id) AS rank
+                    FROM lookups l
+                             JOIN tracks t ON lower(btrim(t.name)) = l.name
+                             JOIN albums a ON a.id = t.album_id
+                             JOIN musicians m ON m.id = a.musician_id
+                    WHERE l.musician = ''
+                       OR lower(btrim(m.name)) = l.musician)`+trackDetailsQuery+`
+WHERE t.id IN (SELECT id FROM candidates WHERE rank <= ?)
+ORDER BY t.id`, vars...).Scan(&tracks)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track)")
+	}
+
+	res := make([]*models.TrackDetails, 0, len(tracks))
+	for _, v := range tracks {
+		res = append(res, dao.ToModelTrackDetails(v))
+	}
+
+	return res, nil
+}
+
+func (t trackRepository) GetTracksDetails(ids []uint64) ([]*models.TrackDetails, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var tracks []*dao.TrackDetails
+
+	tx := t.db.Raw(trackDetailsQuery+`
+WHERE t.id IN ?`, ids).Scan(&tracks)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track)")
+	}
+
+	byId := make(map[uint64]*dao.TrackDetails, len(tracks))
+	for _, v := range tracks {
+		byId[v.ID] = v
+	}
+
+	res := make([]*models.TrackDetails, 0, len(tracks))
+	for _, id := range ids {
+		if v, ok := byId[id]; ok {
+			res = append(res, dao.ToModelTrackDetails(v))
+		}
+	}
+
+	return res, nil
+}
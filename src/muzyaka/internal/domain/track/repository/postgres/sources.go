@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"src/internal/models/events"
+	"time"
+)
+
+const (
+	// removalTimeout is how long new references of a source wait for the
+	// removal of its object, a longer removal is considered abandoned
+	removalTimeout = time.Minute
+	removalPoll    = 100 * time.Millisecond
+)
+
+// errSourceRemoving is returned while the object of the source is removed
+var errSourceRemoving = errors.New("source is being removed")
+
+func (t trackRepository) AcquireSource(source string, upload func() error) error {
+	stored, err := t.addSourceReference(source)
+	for waited := time.Duration(0); errors.Is(err, errSourceRemoving) && waited < removalTimeout; waited += removalPoll {
+		time.Sleep(removalPoll)
+		stored, err = t.addSourceReference(source)
+	}
+
+	if errors.Is(err, errSourceRemoving) {
+		return errors.Wrap(models.ErrOverloaded, "database error (table track_sources)")
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table track_sources)")
+	}
+
+	if stored {
+		return nil
+	}
+
+	// the object is named after its content, so references taken meanwhile
+	// upload the same object until one of them marks it stored
+	if uploadErr := upload(); uploadErr != nil {
+		if err := t.dropSourceReference(source); err != nil {
+			return errors.Wrap(err, "database error (table track_sources)")
+		}
+
+		return uploadErr
+	}
+
+	err = t.db.Model(&dao.TrackSource{}).
+		Where("source = ? AND removing_at IS NULL", source).
+		Update("stored", true).Error
+	if err != nil {
+		return errors.Wrap(err, "database error (table track_sources)")
+	}
+
+	return nil
+}
+
+// addSourceReference counts a new reference and reports whether the object is
+// stored already. A removal abandoned for removalTimeout is taken over.
+func (t trackRepository) addSourceReference(source string) (bool, error) {
+	var stored bool
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&dao.TrackSource{Source: source}).Error
+		if err != nil {
+			return err
+		}
+
+		var row dao.TrackSource
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("source = ?", source).Take(&row).Error; err != nil {
+			return err
+		}
+
+		if row.RemovingAt != nil {
+			var removing int64
+			if err := tx.Model(&dao.TrackSource{}).
+				Where("source = ? AND removing_at > now() - make_interval(secs => ?)", source, removalTimeout.Seconds()).
+				Count(&removing).Error; err != nil {
+				return err
+			}
+
+			if removing > 0 {
+				return errSourceRemoving
+			}
+		}
+
+		stored = row.Stored && row.RemovingAt == nil
+
+		return tx.Model(&row).Updates(map[string]interface{}{
+			"refs":        row.Refs + 1,
+			"stored":      stored,
+			"removing_at": nil,
+		}).Error
+	})
+
+	return stored, err
+}
+
+// dropSourceReference drops the reference of a failed upload, nothing is
+// stored for it
+func (t trackRepository) dropSourceReference(source string) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		var row dao.TrackSource
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("source = ?", source).Take(&row).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if row.Refs > 1 {
+			return tx.Model(&row).Update("refs", gorm.Expr("refs - 1")).Error
+		}
+
+		return tx.Delete(&row).Error
+	})
+}
+
+// ReleaseSource drops the reference in a short transaction, the last one marks
+// the source as being removed and its object is removed after the commit
+func (t trackRepository) ReleaseSource(source string, remove func() error) error {
+	var removing *dao.TrackSource
+	var legacy bool
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var sources []*dao.TrackSource
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("source = ?", source).Find(&sources).Error; err != nil {
+			return err
+		}
+
+		if len(sources) == 0 {
+			legacy = true
+			return nil
+		}
+
+		switch {
+		case sources[0].Refs > 1:
+			return tx.Model(sources[0]).Update("refs", gorm.Expr("refs - 1")).Error
+		case sources[0].Refs == 0:
+			return nil
+		}
+
+		removing = &dao.TrackSource{}
+		return tx.Raw(`
+			UPDATE track_sources
+			SET refs = 0, stored = false, removing_at = now()
+			WHERE source = ?
+			RETURNING *`, source).Scan(removing).Error
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table track_sources)")
+	}
+
+	if !legacy && removing == nil {
+		return nil
+	}
+
+	removeErr := remove()
+	if legacy {
+		return removeErr
+	}
+
+	// the row is dropped even if the object is left, so new references don't
+	// wait for it, unless the removal was taken over meanwhile
+	err = t.db.Where("source = ? AND refs = 0 AND removing_at = ?", source, removing.RemovingAt).
+		Delete(&dao.TrackSource{}).Error
+	if removeErr != nil {
+		return removeErr
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table track_sources)")
+	}
+
+	return nil
+}
+
+func (t trackRepository) GetUnhashedTracks(afterId uint64, limit int) ([]*models.TrackMeta, error) {
+	var tracks []*dao.TrackMeta
+	tx := t.db.Where("id > ?", afterId).
+		Where("NOT EXISTS (SELECT 1 FROM track_sources s WHERE s.source = tracks.source)").
+		Order("id").
+		Limit(limit).
+		Find(&tracks)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "database error (table track)")
+	}
+
+	res := make([]*models.TrackMeta, 0, len(tracks))
+	for _, v := range tracks {
+		res = append(res, dao.ToModelTrack(v, &dao.Genre{}))
+	}
+
+	return res, nil
+}
+
+func (t trackRepository) ReplaceSource(id uint64, from string, to string) error {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&dao.TrackMeta{}).Where("id = ? AND source = ?", id, from).Update("source", to)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNotFound
+		}
+
+		var updated dao.TrackMeta
+		if err := tx.Where("id = ?", id).Take(&updated).Error; err != nil {
+			return err
+		}
+
+		payload := dao.ToTrackPayload(&updated)
+		payload.SourceChanged = true
+
+		outbox, err := dao.NewOutbox(events.TrackUpdated, updated.ID, payload)
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table track)")
+	}
+
+	return nil
+}
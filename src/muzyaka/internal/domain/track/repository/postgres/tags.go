@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"src/internal/models"
+	"src/internal/models/dao"
+	"strings"
+)
+
+// ResolveGenres finds genres by name ignoring case keeping the order of names,
+// models.ErrInvalidGenre is returned if any of them is unknown
+func ResolveGenres(db *gorm.DB, names []string) ([]*dao.Genre, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	lower := make([]string, 0, len(names))
+	for _, v := range names {
+		lower = append(lower, strings.ToLower(v))
+	}
+
+	var genres []*dao.Genre
+	if err := db.Where("lower(name) IN ?", lower).Find(&genres).Error; err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*dao.Genre, len(genres))
+	for _, v := range genres {
+		byName[strings.ToLower(v.Name)] = v
+	}
+
+	res := make([]*dao.Genre, 0, len(names))
+	for i, v := range names {
+		genre, ok := byName[lower[i]]
+		if !ok {
+			return nil, errors.Wrap(models.ErrInvalidGenre, v)
+		}
+		res = append(res, genre)
+	}
+
+	return res, nil
+}
+
+// PrimaryGenre is the genre to keep in tracks.genre
+func PrimaryGenre(genres []*dao.Genre) *uint64 {
+	if len(genres) == 0 {
+		return nil
+	}
+
+	return genres[0].ID
+}
+
+// SaveTrackTags replaces genres and moods of the track
+func SaveTrackTags(tx *gorm.DB, trackId uint64, genres []*dao.Genre, moods []string) error {
+	if err := tx.Where("track_id = ?", trackId).Delete(&dao.TrackGenre{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("track_id = ?", trackId).Delete(&dao.TrackMood{}).Error; err != nil {
+		return err
+	}
+
+	if len(genres) > 0 {
+		rows := make([]*dao.TrackGenre, 0, len(genres))
+		for _, v := range genres {
+			rows = append(rows, &dao.TrackGenre{TrackID: trackId, GenreID: *v.ID})
+		}
+
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(moods) > 0 {
+		rows := make([]*dao.TrackMood, 0, len(moods))
+		for _, v := range moods {
+			rows = append(rows, &dao.TrackMood{TrackID: trackId, Mood: v})
+		}
+
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveTrackCredits replaces featured artists and credits of the track
+func SaveTrackCredits(tx *gorm.DB, trackId uint64, featured []string, credits []*models.TrackCredit) error {
+	if err := tx.Where("track_id = ?", trackId).Delete(&dao.TrackCredit{}).Error; err != nil {
+		return err
+	}
+
+	rows := make([]*dao.TrackCredit, 0, len(featured)+len(credits))
+	for i, v := range featured {
+		rows = append(rows, &dao.TrackCredit{TrackID: trackId, Role: dao.TrackCreditFeatured, Name: v, Position: i})
+	}
+	for i, v := range credits {
+		rows = append(rows, &dao.TrackCredit{TrackID: trackId, Role: v.Role, Name: v.Name, Position: i})
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return tx.Create(&rows).Error
+}
+
+// LoadTracks converts tracks filling their genres, moods, credits, artists,
+// album genres and album loudness
+func LoadTracks(db *gorm.DB, tracks []*dao.TrackMeta) ([]*models.TrackMeta, error) {
+	if len(tracks) == 0 {
+		return nil, nil
+	}
+
+	trackIds := make([]uint64, 0, len(tracks))
+	albumIds := make([]uint64, 0, len(tracks))
+	for _, v := range tracks {
+		trackIds = append(trackIds, v.ID)
+		albumIds = append(albumIds, v.AlbumID)
+	}
+
+	var primary []*dao.GenreTag
+	tx := db.Raw(`
+		SELECT t.id AS owner_id, g.name
+		FROM tracks t
+		         JOIN genres g ON g.id = t.genre
+		WHERE t.id IN ?`, trackIds).Scan(&primary)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var genres []*dao.GenreTag
+	tx = db.Raw(`
+		SELECT tg.track_id AS owner_id, g.name
+		FROM track_genres tg
+		         JOIN genres g ON g.id = tg.genre_id
+		WHERE tg.track_id IN ?
+		ORDER BY g.name`, trackIds).Scan(&genres)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	albumGenres, err := LoadAlbumGenres(db, albumIds)
+	if err != nil {
+		return nil, err
+	}
+
+	albumLoudness, err := loadAlbumLoudness(db, albumIds)
+	if err != nil {
+		return nil, err
+	}
+
+	var moods []*dao.TrackMood
+	tx = db.Where("track_id IN ?", trackIds).Order("mood").Find(&moods)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var credits []*dao.TrackCredit
+	tx = db.Where("track_id IN ?", trackIds).Order("position").Find(&credits)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	artists, err := loadTrackArtists(db, trackIds)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryByTrack := make(map[uint64]string, len(primary))
+	for _, v := range primary {
+		primaryByTrack[v.OwnerID] = v.Name
+	}
+
+	genresByTrack := make(map[uint64][]string)
+	for _, v := range genres {
+		if v.Name != primaryByTrack[v.OwnerID] {
+			genresByTrack[v.OwnerID] = append(genresByTrack[v.OwnerID], v.Name)
+		}
+	}
+
+	moodsByTrack := make(map[uint64][]string)
+	for _, v := range moods {
+		moodsByTrack[v.TrackID] = append(moodsByTrack[v.TrackID], v.Mood)
+	}
+
+	featuredByTrack := make(map[uint64][]string)
+	creditsByTrack := make(map[uint64][]*models.TrackCredit)
+	for _, v := range credits {
+		if v.Role == dao.TrackCreditFeatured {
+			featuredByTrack[v.TrackID] = append(featuredByTrack[v.TrackID], v.Name)
+		} else {
+			creditsByTrack[v.TrackID] = append(creditsByTrack[v.TrackID], &models.TrackCredit{Role: v.Role, Name: v.Name})
+		}
+	}
+
+	res := make([]*models.TrackMeta, 0, len(tracks))
+	for _, v := range tracks {
+		track := dao.ToModelTrack(v, &dao.Genre{Name: primaryByTrack[v.ID]})
+		if track.Genre != "" {
+			track.Genres = append([]string{track.Genre}, genresByTrack[v.ID]...)
+		} else {
+			track.Genres = genresByTrack[v.ID]
+		}
+		track.Moods = moodsByTrack[v.ID]
+		track.AlbumGenres = albumGenres[v.AlbumID]
+		track.AlbumLoudness = albumLoudness[v.AlbumID]
+		track.FeaturedArtists = featuredByTrack[v.ID]
+		track.Credits = creditsByTrack[v.ID]
+		track.Artists = artists[v.ID]
+		res = append(res, track)
+	}
+
+	return res, nil
+}
+
+// LoadAlbumGenres returns genre names of every album ordered by name
+func LoadAlbumGenres(db *gorm.DB, albumIds []uint64) (map[uint64][]string, error) {
+	res := make(map[uint64][]string)
+	if len(albumIds) == 0 {
+		return res, nil
+	}
+
+	var genres []*dao.GenreTag
+	tx := db.Raw(`
+		SELECT ag.album_id AS owner_id, g.name
+		FROM album_genres ag
+		         JOIN genres g ON g.id = ag.genre_id
+		WHERE ag.album_id IN ?
+		ORDER BY g.name`, albumIds).Scan(&genres)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	for _, v := range genres {
+		res[v.OwnerID] = append(res[v.OwnerID], v.Name)
+	}
+
+	return res, nil
+}
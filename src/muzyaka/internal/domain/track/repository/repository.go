@@ -6,8 +6,52 @@ import "src/internal/models"
 
 type TrackRepository interface {
 	GetTrack(id uint64) (*models.TrackMeta, error)
+	// GetTracks skips unknown ids and doesn't keep their order
+	GetTracks(ids []uint64) ([]*models.TrackMeta, error)
+	// UpdateTrack clears loudness of the track and its album if the source
+	// changes, until the track is measured again
 	UpdateTrack(track *models.TrackMeta) error
+	// UpdateDuration sets the measured duration in seconds, it publishes no
+	// event as it follows processing of an uploaded source
+	UpdateDuration(id uint64, duration int) error
+	// SaveLoudness sets loudness of the track, and of its album once every
+	// track of it is measured. It publishes no event as UpdateDuration.
+	SaveLoudness(id uint64, loudness *models.Loudness) error
+
+	// AcquireSource adds a reference to the source object and calls upload
+	// unless the object is stored already. The reference is dropped if upload
+	// fails. It waits while the object is being removed by ReleaseSource.
+	// Neither upload nor remove are called inside a transaction.
+	AcquireSource(source string, upload func() error) error
+	// ReleaseSource drops a reference to the source object and calls remove
+	// once none are left. Sources of tracks uploaded before sources were
+	// hashed have no references and are removed at once.
+	ReleaseSource(source string, remove func() error) error
+	// GetUnhashedTracks returns tracks with sources that were not hashed yet,
+	// the ones after afterId in id order
+	GetUnhashedTracks(afterId uint64, limit int) ([]*models.TrackMeta, error)
+	// ReplaceSource changes the source of the track unless it is no longer
+	// from, the update event makes the new source processed
+	ReplaceSource(id uint64, from string, to string) error
+	// SaveFingerprint replaces the fingerprint of the track with its keys
+	SaveFingerprint(fingerprint *models.Fingerprint, keys []uint32) error
+	// FindFingerprints returns fingerprints of tracks of other musicians
+	// sharing most keys with the track, at most limit of them
+	FindFingerprints(trackId uint64, keys []uint32, limit int) ([]*models.Fingerprint, error)
 
 	GetTracksByPartName(name string, offset int, limit int) ([]*models.TrackMeta, error)
+	FindTracks(filter *models.TrackFilter, offset int, limit int) ([]*models.TrackMeta, error)
+	// FindTracksByNames looks for tracks matching any of lookups with names
+	// compared by models.FoldName, at most limit of them for every lookup
+	FindTracksByNames(lookups []*models.TrackLookup, limit int) ([]*models.TrackDetails, error)
+	// GetTracksDetails keeps the order of ids and skips unknown ones
+	GetTracksDetails(ids []uint64) ([]*models.TrackDetails, error)
 	GetGenres() ([]string, error)
+
+	// GetLyrics returns lyrics in the language, or in the first language
+	// by name if language is empty
+	GetLyrics(trackId uint64, language string) (*models.Lyrics, error)
+	// SaveLyrics replaces lyrics of the track in the same language
+	SaveLyrics(lyrics *models.Lyrics) error
+	DeleteLyrics(trackId uint64, language string) error
 }
@@ -7,5 +7,14 @@ import "src/internal/models"
 type TrackStorage interface {
 	UploadObject(track *models.TrackObject) error
 	LoadObject(track *models.TrackMeta) (*models.TrackObject, error)
+	// DeleteObject deletes the source with its preview and waveform
 	DeleteObject(track *models.TrackMeta) error
+
+	// SourceTag identifies the current version of the source
+	SourceTag(track *models.TrackMeta) (string, error)
+	// SavePreview stores a WAV clip next to the source
+	SavePreview(track *models.TrackMeta, preview []byte) error
+	LoadPreview(track *models.TrackMeta) ([]byte, error)
+	SaveWaveform(track *models.TrackMeta, waveform *models.Waveform) error
+	LoadWaveform(track *models.TrackMeta) (*models.Waveform, error)
 }
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"src/internal/domain/track/repository"
+	"src/internal/models"
+)
+
+// StoreSource names the source after the content hash and uploads it unless
+// a track already references an identical one. The reference is taken before
+// the track is saved, so it must be released if saving fails.
+func StoreSource(trackRep repository.TrackRepository, storage repository.TrackStorage, track *models.TrackObject) error {
+	track.AddressByContent()
+
+	return trackRep.AcquireSource(track.Source, func() error {
+		return storage.UploadObject(track)
+	})
+}
+
+// ReleaseSource drops the reference of a deleted track and deletes the source
+// once no other track references it
+func ReleaseSource(trackRep repository.TrackRepository, storage repository.TrackStorage, track *models.TrackMeta) error {
+	return trackRep.ReleaseSource(track.Source, func() error {
+		return storage.DeleteObject(track)
+	})
+}
@@ -2,19 +2,38 @@ package usecase
 
 import (
 	"github.com/pkg/errors"
+	"slices"
 	"src/internal/domain/track/repository"
+	"src/internal/lib/lrc"
 	"src/internal/models"
+	"strings"
 )
 
 const MinPageSize = 10
 const MaxPageSize = 100
 
 type TrackUseCase interface {
+	// UpdateTrack replaces the source only if the payload is not empty and
+	// differs, its loudness is reset until the new source is analyzed
 	UpdateTrack(track *models.TrackObject) error
 	GetTrack(id uint64) (*models.TrackObject, error)
 	GetTracksByPartName(name string, page int, pageSize int) ([]*models.TrackMeta, error)
+	// FindTracks needs at least one of name, genres, moods and lyrics in the filter
+	FindTracks(filter *models.TrackFilter, page int, pageSize int) ([]*models.TrackMeta, error)
 
 	GetGenres() ([]string, error)
+
+	// GetLyrics returns lyrics in the language, or in any language if it is empty
+	GetLyrics(trackId uint64, language string) (*models.Lyrics, error)
+	// SetLyrics parses plain or LRC lyrics and replaces the ones in the same language
+	SetLyrics(trackId uint64, language string, text string) error
+	DeleteLyrics(trackId uint64, language string) error
+
+	// GetWaveform returns peaks in the smallest resolution with at least
+	// points, or in the largest one. Any points are accepted if points is 0.
+	GetWaveform(trackId uint64, points int) (*models.Waveform, error)
+	// GetPreview returns a WAV clip of the track, ErrNotFound until it is made
+	GetPreview(trackId uint64) ([]byte, error)
 }
 
 type usecase struct {
@@ -56,6 +75,45 @@ func (u *usecase) GetTracksByPartName(name string, page int, pageSize int) ([]*m
 	return tracks, nil
 }
 
+func (u *usecase) FindTracks(filter *models.TrackFilter, page int, pageSize int) ([]*models.TrackMeta, error) {
+	filter.Name = strings.TrimSpace(filter.Name)
+	filter.Lyrics = strings.TrimSpace(filter.Lyrics)
+
+	var err error
+	filter.Genres, err = models.NormalizeGenres(filter.Genres)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid genres")
+	}
+
+	filter.Moods, err = models.NormalizeMoods(filter.Moods)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid moods")
+	}
+
+	if filter.Name == "" && len(filter.Genres) == 0 && len(filter.Moods) == 0 && filter.Lyrics == "" {
+		return nil, errors.Wrap(models.ErrInvalidParameter, "empty filter")
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	switch {
+	case pageSize > MaxPageSize:
+		pageSize = MaxPageSize
+	case pageSize < MinPageSize:
+		pageSize = MinPageSize
+	}
+
+	offset := (page - 1) * pageSize
+	tracks, err := u.trackRep.FindTracks(filter, offset, pageSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "track.usecase.FindTracks error while get")
+	}
+
+	return tracks, nil
+}
+
 func (u *usecase) GetTrack(id uint64) (*models.TrackObject, error) {
 	meta, err := u.trackRep.GetTrack(id)
 	if err != nil {
@@ -71,15 +129,142 @@ func (u *usecase) GetTrack(id uint64) (*models.TrackObject, error) {
 }
 
 func (u *usecase) UpdateTrack(track *models.TrackObject) error {
-	err := u.storageRep.UploadObject(track)
+	if err := track.Normalize(); err != nil {
+		return errors.Wrap(err, "track.usecase.UpdateTrack invalid track")
+	}
+
+	// sources are shared by identical tracks, so a changed payload is stored
+	// as a new source and the old one is released after the update. The
+	// update event makes the new source analyzed, loudness is cleared until then.
+	var replaced *models.TrackMeta
+	if len(track.Payload) > 0 {
+		stored, err := u.trackRep.GetTrack(track.Id)
+		if err != nil {
+			return errors.Wrap(err, "track.usecase.UpdateTrack error while get")
+		}
+
+		if err := StoreSource(u.trackRep, u.storageRep, track); err != nil {
+			return errors.Wrap(err, "track.usecase.UpdateTrack error while update")
+		}
+
+		// the track already holds a reference to an unchanged source
+		replaced = stored
+	}
+
+	err := u.trackRep.UpdateTrack(track.ExtractMeta())
 	if err != nil {
+		if replaced != nil {
+			_ = ReleaseSource(u.trackRep, u.storageRep, track.ExtractMeta())
+		}
 		return errors.Wrap(err, "track.usecase.UpdateTrack error while update")
 	}
 
-	err = u.trackRep.UpdateTrack(track.ExtractMeta())
+	if replaced != nil {
+		if err := ReleaseSource(u.trackRep, u.storageRep, replaced); err != nil {
+			return errors.Wrap(err, "track.usecase.UpdateTrack error while delete")
+		}
+	}
+
+	return nil
+}
+
+func (u *usecase) GetLyrics(trackId uint64, language string) (*models.Lyrics, error) {
+	if language != "" {
+		var err error
+		language, err = models.NormalizeLanguage(language)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid language")
+		}
+	}
+
+	lyrics, err := u.trackRep.GetLyrics(trackId, language)
 	if err != nil {
-		return errors.Wrap(err, "track.usecase.UpdateTrack error while update")
+		return nil, errors.Wrap(err, "track.usecase.GetLyrics error while get")
+	}
+
+	return lyrics, nil
+}
+
+func (u *usecase) SetLyrics(trackId uint64, language string, text string) error {
+	language, err := models.NormalizeLanguage(language)
+	if err != nil {
+		return errors.Wrap(err, "invalid language")
+	}
+
+	if len(text) > models.MaxLyricsLength {
+		return errors.Wrap(models.ErrInvalidParameter, "lyrics are too long")
+	}
+
+	lines, synced, err := lrc.Parse(text)
+	if err != nil {
+		return errors.Wrap(err, "track.usecase.SetLyrics invalid lyrics")
+	}
+
+	err = u.trackRep.SaveLyrics(&models.Lyrics{
+		TrackId:  trackId,
+		Language: language,
+		Synced:   synced,
+		Lines:    lines,
+	})
+	if err != nil {
+		return errors.Wrap(err, "track.usecase.SetLyrics error while save")
 	}
 
 	return nil
 }
+
+func (u *usecase) DeleteLyrics(trackId uint64, language string) error {
+	language, err := models.NormalizeLanguage(language)
+	if err != nil {
+		return errors.Wrap(err, "invalid language")
+	}
+
+	if err := u.trackRep.DeleteLyrics(trackId, language); err != nil {
+		return errors.Wrap(err, "track.usecase.DeleteLyrics error while delete")
+	}
+
+	return nil
+}
+
+func (u *usecase) GetWaveform(trackId uint64, points int) (*models.Waveform, error) {
+	if points < 0 {
+		return nil, models.ErrInvalidParameter
+	}
+
+	track, err := u.trackRep.GetTrack(trackId)
+	if err != nil {
+		return nil, errors.Wrap(err, "track.usecase.GetWaveform error while get")
+	}
+
+	waveform, err := u.storageRep.LoadWaveform(track)
+	if err != nil {
+		return nil, errors.Wrap(err, "track.usecase.GetWaveform error while load")
+	}
+
+	if len(waveform.Peaks) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	slices.SortFunc(waveform.Peaks, func(a, b []float32) int { return len(a) - len(b) })
+	i := slices.IndexFunc(waveform.Peaks, func(v []float32) bool { return len(v) >= points })
+	if i < 0 {
+		i = len(waveform.Peaks) - 1
+	}
+	waveform.Peaks = waveform.Peaks[i : i+1]
+
+	return waveform, nil
+}
+
+func (u *usecase) GetPreview(trackId uint64) ([]byte, error) {
+	track, err := u.trackRep.GetTrack(trackId)
+	if err != nil {
+		return nil, errors.Wrap(err, "track.usecase.GetPreview error while get")
+	}
+
+	preview, err := u.storageRep.LoadPreview(track)
+	if err != nil {
+		return nil, errors.Wrap(err, "track.usecase.GetPreview error while load")
+	}
+
+	return preview, nil
+}
@@ -9,85 +9,24 @@ import (
 	"testing"
 )
 
-func TestUsecase_DeleteTrack(t *testing.T) {
-	type mock func(r *mock_repository.MockTrackRepository, trackId uint64, track models.TrackMeta)
-	type storageMock func(r *mock_repository.MockTrackStorage, tracks models.TrackMeta)
-
-	testTable := []struct {
-		name        string
-		trackId     uint64
-		inputTrack  models.TrackMeta
-		mock        mock
-		storageMock storageMock
-		expectedErr error
-	}{
-		{
-			name:    "Usual test",
-			trackId: uint64(1),
-			inputTrack: models.TrackMeta{
-				Id:     10,
-				Source: "test_src",
-				Name:   "test_name",
-				Genre:  "test_genre",
-			},
-			mock: func(r *mock_repository.MockTrackRepository, trackId uint64, track models.TrackMeta) {
-				r.EXPECT().GetTrack(trackId).Return(&track, nil)
-				r.EXPECT().DeleteTrack(trackId).Return(nil)
-			},
-			storageMock: func(r *mock_repository.MockTrackStorage, track models.TrackMeta) {
-				r.EXPECT().DeleteObject(&track).Return(nil)
-			},
-			expectedErr: nil,
-		},
-		{
-			name:    "Repo fail test",
-			trackId: uint64(2),
-			inputTrack: models.TrackMeta{
-				Id:     10,
-				Source: "test_src",
-				Name:   "test_name",
-				Genre:  "test_genre",
-			},
-			mock: func(r *mock_repository.MockTrackRepository, trackId uint64, track models.TrackMeta) {
-				r.EXPECT().GetTrack(trackId).Return(&track, nil)
-				r.EXPECT().DeleteTrack(trackId).Return(errors.New("error in repo"))
-			},
-			storageMock: func(r *mock_repository.MockTrackStorage, track models.TrackMeta) {
-			},
-			expectedErr: errors.Wrap(
-				errors.New("error in repo"),
-				"track.usecase.DeleteTrack error while delete"),
-		},
-	}
-
-	for _, tc := range testTable {
-		t.Run(tc.name, func(t *testing.T) {
-			// Init Dependencies
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			repo := mock_repository.NewMockTrackRepository(ctrl)
-			tc.mock(repo, tc.trackId, tc.inputTrack)
-
-			storage := mock_repository.NewMockTrackStorage(ctrl)
-			tc.storageMock(storage, tc.inputTrack)
-
-			s := NewTrackUseCase(repo, storage)
-			err := s.DeleteTrack(tc.trackId)
+// takeSource calls back as AcquireSource and ReleaseSource do for a source
+// not shared with other tracks, keepSource as for a shared one
+func takeSource(source string, f func() error) error {
+	return f()
+}
 
-			if tc.expectedErr == nil {
-				assert.Nil(t, err)
-			} else {
-				assert.Equal(t, err.Error(), tc.expectedErr.Error())
-			}
-		})
-	}
+func keepSource(source string, f func() error) error {
+	return nil
 }
 
 func TestUsecase_UpdatedTrack(t *testing.T) {
 	type mock func(r *mock_repository.MockTrackRepository, track models.TrackObject)
 	type storageMock func(r *mock_repository.MockTrackStorage, track models.TrackObject)
 
+	// sha256 of the payload of test tracks
+	const source = "039058c6f2c0cb492c533b0a4d14ef77cc0f78abccced5287d84a1a2011cfb81"
+	stored := &models.TrackMeta{Id: 1, Source: "updated_source.mp3"}
+
 	testTable := []struct {
 		name        string
 		inputTrack  models.TrackObject
@@ -107,13 +46,35 @@ func TestUsecase_UpdatedTrack(t *testing.T) {
 				Payload: []byte{1, 2, 3},
 			},
 			mock: func(r *mock_repository.MockTrackRepository, track models.TrackObject) {
+				track.AddressByContent()
+				r.EXPECT().GetTrack(uint64(1)).Return(stored, nil)
+				r.EXPECT().AcquireSource(source, gomock.Any()).DoAndReturn(takeSource)
 				r.EXPECT().UpdateTrack(track.ExtractMeta()).Return(nil)
+				r.EXPECT().ReleaseSource("updated_source.mp3", gomock.Any()).DoAndReturn(takeSource)
 			},
 			storageMock: func(r *mock_repository.MockTrackStorage, track models.TrackObject) {
+				track.AddressByContent()
 				r.EXPECT().UploadObject(&track).Return(nil)
+				r.EXPECT().DeleteObject(stored).Return(nil)
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "Same payload test",
+			inputTrack: models.TrackObject{
+				TrackMeta: models.TrackMeta{Id: 1, Name: "Updated TrackMeta Name"},
+				Payload:   []byte{1, 2, 3},
+			},
+			mock: func(r *mock_repository.MockTrackRepository, track models.TrackObject) {
+				track.AddressByContent()
+				r.EXPECT().GetTrack(uint64(1)).Return(&models.TrackMeta{Id: 1, Source: source}, nil)
+				r.EXPECT().AcquireSource(source, gomock.Any()).DoAndReturn(keepSource)
+				r.EXPECT().UpdateTrack(track.ExtractMeta()).Return(nil)
+				r.EXPECT().ReleaseSource(source, gomock.Any()).DoAndReturn(keepSource)
+			},
+			storageMock: func(r *mock_repository.MockTrackStorage, track models.TrackObject) {},
+			expectedErr: nil,
+		},
 		{
 			name: "Repo fail test",
 			inputTrack: models.TrackObject{
@@ -126,13 +87,105 @@ func TestUsecase_UpdatedTrack(t *testing.T) {
 				Payload: []byte{1, 2, 3},
 			},
 			mock: func(r *mock_repository.MockTrackRepository, track models.TrackObject) {
+				track.AddressByContent()
+				r.EXPECT().GetTrack(uint64(1)).Return(stored, nil)
+				r.EXPECT().AcquireSource(source, gomock.Any()).DoAndReturn(takeSource)
 				r.EXPECT().UpdateTrack(track.ExtractMeta()).Return(errors.New("error in repo"))
+				r.EXPECT().ReleaseSource(source, gomock.Any()).DoAndReturn(takeSource)
 			},
 			storageMock: func(r *mock_repository.MockTrackStorage, track models.TrackObject) {
+				track.AddressByContent()
 				r.EXPECT().UploadObject(&track).Return(nil)
+				r.EXPECT().DeleteObject(track.ExtractMeta()).Return(nil)
 			},
 			expectedErr: errors.Wrap(errors.New("error in repo"), "track.usecase.UpdateTrack error while update"),
 		},
+		{
+			name: "Metadata is normalized",
+			inputTrack: models.TrackObject{
+				TrackMeta: models.TrackMeta{
+					Id:              1,
+					Name:            "Updated TrackMeta Name",
+					Isrc:            "us-rc1-76-07839",
+					Explicit:        true,
+					FeaturedArtists: []string{" Guest ", "Guest"},
+					Credits: []*models.TrackCredit{
+						{Role: "Composer", Name: " Someone "},
+						{Role: "composer", Name: "Someone"},
+						{Role: "producer", Name: "Someone"},
+					},
+				},
+				Payload: []byte{1, 2, 3},
+			},
+			mock: func(r *mock_repository.MockTrackRepository, track models.TrackObject) {
+				r.EXPECT().GetTrack(uint64(1)).Return(&models.TrackMeta{Id: 1, Source: "stored.mp3"}, nil)
+				r.EXPECT().AcquireSource(source, gomock.Any()).DoAndReturn(takeSource)
+				r.EXPECT().ReleaseSource("stored.mp3", gomock.Any()).DoAndReturn(keepSource)
+				r.EXPECT().UpdateTrack(&models.TrackMeta{
+					Id:              1,
+					Source:          source,
+					Name:            "Updated TrackMeta Name",
+					Isrc:            "USRC17607839",
+					Explicit:        true,
+					FeaturedArtists: []string{"Guest"},
+					Credits: []*models.TrackCredit{
+						{Role: models.CreditComposer, Name: "Someone"},
+						{Role: models.CreditProducer, Name: "Someone"},
+					},
+				}).Return(nil)
+			},
+			storageMock: func(r *mock_repository.MockTrackStorage, track models.TrackObject) {
+				r.EXPECT().UploadObject(gomock.Any()).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Metadata only test",
+			inputTrack: models.TrackObject{
+				TrackMeta: models.TrackMeta{Id: 1, Name: "Updated TrackMeta Name"},
+			},
+			mock: func(r *mock_repository.MockTrackRepository, track models.TrackObject) {
+				r.EXPECT().UpdateTrack(track.ExtractMeta()).Return(nil)
+			},
+			storageMock: func(r *mock_repository.MockTrackStorage, track models.TrackObject) {},
+			expectedErr: nil,
+		},
+		{
+			name: "Deleted track test",
+			inputTrack: models.TrackObject{
+				TrackMeta: models.TrackMeta{Id: 1, Name: "Updated TrackMeta Name"},
+				Payload:   []byte{1, 2, 3},
+			},
+			mock: func(r *mock_repository.MockTrackRepository, track models.TrackObject) {
+				r.EXPECT().GetTrack(uint64(1)).Return(nil, models.ErrNotFound)
+			},
+			storageMock: func(r *mock_repository.MockTrackStorage, track models.TrackObject) {},
+			expectedErr: errors.Wrap(models.ErrNotFound, "track.usecase.UpdateTrack error while get"),
+		},
+		{
+			name: "Invalid ISRC",
+			inputTrack: models.TrackObject{
+				TrackMeta: models.TrackMeta{Id: 1, Name: "Updated TrackMeta Name", Isrc: "US-RC1-76"},
+				Payload:   []byte{1, 2, 3},
+			},
+			mock:        func(r *mock_repository.MockTrackRepository, track models.TrackObject) {},
+			storageMock: func(r *mock_repository.MockTrackStorage, track models.TrackObject) {},
+			expectedErr: errors.Wrap(models.ErrInvalidParameter, "track.usecase.UpdateTrack invalid track"),
+		},
+		{
+			name: "Unknown credit role",
+			inputTrack: models.TrackObject{
+				TrackMeta: models.TrackMeta{
+					Id:      1,
+					Name:    "Updated TrackMeta Name",
+					Credits: []*models.TrackCredit{{Role: "drummer", Name: "Someone"}},
+				},
+				Payload: []byte{1, 2, 3},
+			},
+			mock:        func(r *mock_repository.MockTrackRepository, track models.TrackObject) {},
+			storageMock: func(r *mock_repository.MockTrackStorage, track models.TrackObject) {},
+			expectedErr: errors.Wrap(models.ErrInvalidParameter, "track.usecase.UpdateTrack invalid track"),
+		},
 	}
 
 	for _, tc := range testTable {
@@ -245,3 +298,223 @@ func TestUsecase_GetTrack(t *testing.T) {
 		})
 	}
 }
+
+func TestUsecase_FindTracks(t *testing.T) {
+	type mock func(r *mock_repository.MockTrackRepository)
+
+	testTable := []struct {
+		name        string
+		filter      *models.TrackFilter
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name:   "Genres and moods",
+			filter: &models.TrackFilter{Genres: []string{" Metal ", "Metal"}, Moods: []string{"Calm", "calm "}},
+			mock: func(r *mock_repository.MockTrackRepository) {
+				r.EXPECT().FindTracks(&models.TrackFilter{Genres: []string{"Metal"}, Moods: []string{"calm"}}, 10, 10).
+					Return([]*models.TrackMeta{}, nil)
+			},
+		},
+		{
+			name:   "Name only",
+			filter: &models.TrackFilter{Name: "song"},
+			mock: func(r *mock_repository.MockTrackRepository) {
+				r.EXPECT().FindTracks(&models.TrackFilter{Name: "song"}, 10, 10).Return(nil, errors.New("error in repo"))
+			},
+			expectedErr: errors.Wrap(errors.New("error in repo"), "track.usecase.FindTracks error while get"),
+		},
+		{
+			name:   "Lyrics only",
+			filter: &models.TrackFilter{Lyrics: " hello world "},
+			mock: func(r *mock_repository.MockTrackRepository) {
+				r.EXPECT().FindTracks(&models.TrackFilter{Lyrics: "hello world"}, 10, 10).Return(nil, nil)
+			},
+		},
+		{
+			name:        "Empty filter",
+			filter:      &models.TrackFilter{Name: " "},
+			mock:        func(r *mock_repository.MockTrackRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Empty mood",
+			filter:      &models.TrackFilter{Moods: []string{""}},
+			mock:        func(r *mock_repository.MockTrackRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockTrackRepository(ctrl)
+			tc.mock(repo)
+
+			storage := mock_repository.NewMockTrackStorage(ctrl)
+
+			u := NewTrackUseCase(repo, storage)
+			_, err := u.FindTracks(tc.filter, 2, 0)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedErr.Error())
+			}
+		})
+	}
+}
+
+func TestUsecase_SetLyrics(t *testing.T) {
+	type mock func(r *mock_repository.MockTrackRepository)
+
+	testTable := []struct {
+		name        string
+		language    string
+		text        string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name:     "Synced lyrics",
+			language: "pt-BR",
+			text:     "[ti:Title]\n[00:02.00]second\n[00:01.50]first\n",
+			mock: func(r *mock_repository.MockTrackRepository) {
+				r.EXPECT().SaveLyrics(&models.Lyrics{
+					TrackId:  1,
+					Language: "pt-br",
+					Synced:   true,
+					Lines: []*models.LyricsLine{
+						{OffsetMs: 1500, Text: "first"},
+						{OffsetMs: 2000, Text: "second"},
+					},
+				}).Return(nil)
+			},
+		},
+		{
+			name:     "Track not found",
+			language: "en",
+			text:     "plain\nlyrics",
+			mock: func(r *mock_repository.MockTrackRepository) {
+				r.EXPECT().SaveLyrics(gomock.Any()).Return(models.ErrNotFound)
+			},
+			expectedErr: models.ErrNotFound,
+		},
+		{
+			name:        "Invalid language",
+			language:    "english!",
+			text:        "plain",
+			mock:        func(r *mock_repository.MockTrackRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+		{
+			name:        "Partly synced lyrics",
+			language:    "en",
+			text:        "[00:01.00]first\nsecond",
+			mock:        func(r *mock_repository.MockTrackRepository) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockTrackRepository(ctrl)
+			tc.mock(repo)
+
+			storage := mock_repository.NewMockTrackStorage(ctrl)
+
+			u := NewTrackUseCase(repo, storage)
+			err := u.SetLyrics(1, tc.language, tc.text)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			}
+		})
+	}
+}
+
+func TestUsecase_GetWaveform(t *testing.T) {
+	type mock func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage)
+
+	track := &models.TrackMeta{Id: 1, Source: "source"}
+	small, large := []float32{0.5, 1}, []float32{0.1, 0.5, 1, 0.2}
+
+	testTable := []struct {
+		name          string
+		points        int
+		mock          mock
+		expectedPeaks []float32
+		expectedErr   error
+	}{
+		{
+			name:   "Smallest resolution by default",
+			points: 0,
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage) {
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().LoadWaveform(track).Return(&models.Waveform{Peaks: [][]float32{large, small}}, nil)
+			},
+			expectedPeaks: small,
+		},
+		{
+			name:   "Smallest resolution with enough points",
+			points: 3,
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage) {
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().LoadWaveform(track).Return(&models.Waveform{Peaks: [][]float32{small, large}}, nil)
+			},
+			expectedPeaks: large,
+		},
+		{
+			name:   "Largest resolution",
+			points: 10,
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage) {
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().LoadWaveform(track).Return(&models.Waveform{Peaks: [][]float32{small, large}}, nil)
+			},
+			expectedPeaks: large,
+		},
+		{
+			name:   "Not processed yet",
+			points: 0,
+			mock: func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage) {
+				r.EXPECT().GetTrack(uint64(1)).Return(track, nil)
+				s.EXPECT().LoadWaveform(track).Return(nil, models.ErrNotFound)
+			},
+			expectedErr: models.ErrNotFound,
+		},
+		{
+			name:        "Negative points",
+			points:      -1,
+			mock:        func(r *mock_repository.MockTrackRepository, s *mock_repository.MockTrackStorage) {},
+			expectedErr: models.ErrInvalidParameter,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockTrackRepository(ctrl)
+			storage := mock_repository.NewMockTrackStorage(ctrl)
+			tc.mock(repo, storage)
+
+			u := NewTrackUseCase(repo, storage)
+			waveform, err := u.GetWaveform(1, tc.points)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, [][]float32{tc.expectedPeaks}, waveform.Peaks)
+			} else {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			}
+		})
+	}
+}
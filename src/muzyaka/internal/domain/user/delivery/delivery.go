@@ -3,6 +3,7 @@ package delivery
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/pkg/errors"
 	"net/http"
 	"src/internal/domain/auth/middleware"
 	usecase3 "src/internal/domain/auth/usecase"
@@ -338,3 +339,93 @@ func IsLiked(useCase usecase.UserUseCase) http.HandlerFunc {
 		render.JSON(w, r, dto.IsLikedResponse{IsLiked: ans})
 	}
 }
+
+// @Summary FollowMusician
+// @Security ApiKeyAuth
+// @Tags user
+// @Description follow musician, releases of followed musicians are shown in the feed
+// @ID follow-musician
+// @Accept  json
+// @Produce  json
+// @Param musician_id path int true "musician ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/follow [post]
+func Follow(useCase usecase.UserUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianID := chi.URLParam(r, "musician_id")
+		musicianIDUint, err := strconv.ParseUint(musicianID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		userInfo, isOk := r.Context().Value(middleware.ValuesFromContext).(middleware.ContextValues)
+		if !isOk {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidContext.Error()))
+			return
+		}
+
+		err = useCase.FollowMusician(userInfo.Id, musicianIDUint)
+		if errors.Is(err, models.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// @Summary UnfollowMusician
+// @Security ApiKeyAuth
+// @Tags user
+// @Description unfollow musician
+// @ID unfollow-musician
+// @Accept  json
+// @Produce  json
+// @Param musician_id path int true "musician ID"
+// @Success 200 {object} response.Response
+// @Failure 400,404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure default {object} response.Response
+// @Router /api/musician/{musician_id}/follow [delete]
+func Unfollow(useCase usecase.UserUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		musicianID := chi.URLParam(r, "musician_id")
+		musicianIDUint, err := strconv.ParseUint(musicianID, 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		userInfo, isOk := r.Context().Value(middleware.ValuesFromContext).(middleware.ContextValues)
+		if !isOk {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(models.ErrInvalidContext.Error()))
+			return
+		}
+
+		err = useCase.UnfollowMusician(userInfo.Id, musicianIDUint)
+		if errors.Is(err, models.ErrNothingToDelete) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		} else if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
@@ -78,6 +78,49 @@ func (mr *MockUserRepositoryMockRecorder) DeleteUser(id interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserRepository)(nil).DeleteUser), id)
 }
 
+// DislikeTrack mocks base method.
+func (m *MockUserRepository) DislikeTrack(userId, trackId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DislikeTrack", userId, trackId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DislikeTrack indicates an expected call of DislikeTrack.
+func (mr *MockUserRepositoryMockRecorder) DislikeTrack(userId, trackId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DislikeTrack", reflect.TypeOf((*MockUserRepository)(nil).DislikeTrack), userId, trackId)
+}
+
+// FollowMusician mocks base method.
+func (m *MockUserRepository) FollowMusician(userId, musicianId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowMusician", userId, musicianId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowMusician indicates an expected call of FollowMusician.
+func (mr *MockUserRepositoryMockRecorder) FollowMusician(userId, musicianId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowMusician", reflect.TypeOf((*MockUserRepository)(nil).FollowMusician), userId, musicianId)
+}
+
+// GetAllLikedTracks mocks base method.
+func (m *MockUserRepository) GetAllLikedTracks(userId uint64) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllLikedTracks", userId)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllLikedTracks indicates an expected call of GetAllLikedTracks.
+func (mr *MockUserRepositoryMockRecorder) GetAllLikedTracks(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllLikedTracks", reflect.TypeOf((*MockUserRepository)(nil).GetAllLikedTracks), userId)
+}
+
 // GetUser mocks base method.
 func (m *MockUserRepository) GetUser(id uint64) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +151,49 @@ func (mr *MockUserRepositoryMockRecorder) GetUserByEmail(email interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepository)(nil).GetUserByEmail), email)
 }
 
+// IsTrackLiked mocks base method.
+func (m *MockUserRepository) IsTrackLiked(userId, trackId uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTrackLiked", userId, trackId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTrackLiked indicates an expected call of IsTrackLiked.
+func (mr *MockUserRepositoryMockRecorder) IsTrackLiked(userId, trackId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTrackLiked", reflect.TypeOf((*MockUserRepository)(nil).IsTrackLiked), userId, trackId)
+}
+
+// LikeTrack mocks base method.
+func (m *MockUserRepository) LikeTrack(userId, trackId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LikeTrack", userId, trackId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LikeTrack indicates an expected call of LikeTrack.
+func (mr *MockUserRepositoryMockRecorder) LikeTrack(userId, trackId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LikeTrack", reflect.TypeOf((*MockUserRepository)(nil).LikeTrack), userId, trackId)
+}
+
+// UnfollowMusician mocks base method.
+func (m *MockUserRepository) UnfollowMusician(userId, musicianId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowMusician", userId, musicianId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowMusician indicates an expected call of UnfollowMusician.
+func (mr *MockUserRepositoryMockRecorder) UnfollowMusician(userId, musicianId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowMusician", reflect.TypeOf((*MockUserRepository)(nil).UnfollowMusician), userId, musicianId)
+}
+
 // UpdateUser mocks base method.
 func (m *MockUserRepository) UpdateUser(user *models.User) error {
 	m.ctrl.T.Helper()
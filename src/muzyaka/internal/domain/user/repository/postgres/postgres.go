@@ -3,9 +3,11 @@ package postgres
 import (
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	repository2 "src/internal/domain/user/repository"
 	"src/internal/models"
 	"src/internal/models/dao"
+	"src/internal/models/events"
 )
 
 type userRepository struct {
@@ -57,27 +59,52 @@ func (u userRepository) GetUserByEmail(email string) (*models.User, error) {
 }
 
 func (u userRepository) LikeTrack(userId uint64, trackId uint64) error {
-	tx := u.db.Create(dao.UserTrack{
-		TrackId: trackId,
-		UserId:  userId,
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dao.UserTrack{
+			TrackId: trackId,
+			UserId:  userId,
+		}).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.TrackLiked, userId, events.LikePayload{UserId: userId, TrackId: trackId})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
 	})
 
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table users_track)")
+	if err != nil {
+		return errors.Wrap(err, "database error (table users_track)")
 	}
 
 	return nil
 }
 
 func (u userRepository) DislikeTrack(userId uint64, trackId uint64) error {
-	tx := u.db.Delete(dao.UserTrack{}, "user_id = ? AND track_id = ?", userId, trackId)
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(dao.UserTrack{}, "user_id = ? AND track_id = ?", userId, trackId)
+		if res.Error != nil {
+			return res.Error
+		}
 
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table users_track)")
-	}
+		if res.RowsAffected == 0 {
+			return models.ErrNothingToDelete
+		}
+
+		outbox, err := dao.NewOutbox(events.TrackDisliked, userId, events.LikePayload{UserId: userId, TrackId: trackId})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
 
-	if tx.RowsAffected == 0 {
+	if errors.Is(err, models.ErrNothingToDelete) {
 		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table users_track)")
 	}
 
 	return nil
@@ -125,7 +152,21 @@ func (u userRepository) AddUserWithMusician(musician *models.Musician, user *mod
 			return err
 		}
 
-		return nil
+		outbox, err := dao.NewOutbox(events.MusicianCreated, pgMusician.ID, dao.ToMusicianPayload(pgMusician))
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(outbox).Error; err != nil {
+			return err
+		}
+
+		outbox, err = dao.NewOutbox(events.UserCreated, pgUser.ID, dao.ToUserPayload(pgUser))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
 	})
 
 	if err != nil {
@@ -139,9 +180,26 @@ func (u userRepository) AddUserWithMusician(musician *models.Musician, user *mod
 func (u userRepository) UpdateUser(user *models.User) error {
 	pgUser := dao.ToPostgresUser(user)
 
-	tx := u.db.Omit("id").Updates(&pgUser)
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table user)")
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("id").Updates(&pgUser).Error; err != nil {
+			return err
+		}
+
+		var updated dao.User
+		if err := tx.Where("id = ?", pgUser.ID).Take(&updated).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.UserUpdated, updated.ID, dao.ToUserPayload(&updated))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "database error (table user)")
 	}
 
 	return nil
@@ -150,9 +208,21 @@ func (u userRepository) UpdateUser(user *models.User) error {
 func (u userRepository) AddUser(user *models.User) (uint64, error) {
 	pgUser := dao.ToPostgresUser(user)
 
-	tx := u.db.Create(&pgUser)
-	if tx.Error != nil {
-		return 0, errors.Wrap(tx.Error, "database error (table user)")
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&pgUser).Error; err != nil {
+			return err
+		}
+
+		outbox, err := dao.NewOutbox(events.UserCreated, pgUser.ID, dao.ToUserPayload(pgUser))
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if err != nil {
+		return 0, errors.Wrap(err, "database error (table user)")
 	}
 
 	user.Id = pgUser.ID
@@ -160,14 +230,97 @@ func (u userRepository) AddUser(user *models.User) (uint64, error) {
 }
 
 func (u userRepository) DeleteUser(id uint64) error {
-	tx := u.db.Delete(&dao.User{}, id)
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&dao.User{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
 
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "database error (table user)")
+		if res.RowsAffected == 0 {
+			return models.ErrNothingToDelete
+		}
+
+		outbox, err := dao.NewOutbox(events.UserDeleted, id, events.UserPayload{UserId: id})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNothingToDelete) {
+		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table user)")
+	}
+
+	return nil
+}
+
+func (u userRepository) FollowMusician(userId uint64, musicianId uint64) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", musicianId).Take(&dao.Musician{}).Error; err != nil {
+			return err
+		}
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&dao.MusicianFollower{
+			UserId:     userId,
+			MusicianId: musicianId,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		outbox, err := dao.NewOutbox(events.MusicianFollowed, userId, events.FollowPayload{
+			UserId:     userId,
+			MusicianId: musicianId,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ErrNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table musician_followers)")
 	}
 
-	if tx.RowsAffected == 0 {
+	return nil
+}
+
+func (u userRepository) UnfollowMusician(userId uint64, musicianId uint64) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&dao.MusicianFollower{}, "user_id = ? AND musician_id = ?", userId, musicianId)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.ErrNothingToDelete
+		}
+
+		outbox, err := dao.NewOutbox(events.MusicianUnfollowed, userId, events.FollowPayload{
+			UserId:     userId,
+			MusicianId: musicianId,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(outbox).Error
+	})
+
+	if errors.Is(err, models.ErrNothingToDelete) {
 		return models.ErrNothingToDelete
+	} else if err != nil {
+		return errors.Wrap(err, "database error (table musician_followers)")
 	}
 
 	return nil
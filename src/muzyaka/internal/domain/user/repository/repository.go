@@ -17,4 +17,8 @@ type UserRepository interface {
 	DislikeTrack(userId uint64, trackId uint64) error
 	GetAllLikedTracks(userId uint64) ([]uint64, error)
 	IsTrackLiked(userId uint64, trackId uint64) (bool, error)
+
+	// FollowMusician does nothing if the musician is already followed
+	FollowMusician(userId uint64, musicianId uint64) error
+	UnfollowMusician(userId uint64, musicianId uint64) error
 }
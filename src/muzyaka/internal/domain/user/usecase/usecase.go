@@ -18,6 +18,9 @@ type UserUseCase interface {
 	DislikeTrack(userId uint64, trackId uint64) error
 	GetAllLikedTracks(userId uint64) ([]*models.TrackMeta, error)
 	IsTrackLiked(userId uint64, trackId uint64) (bool, error)
+
+	FollowMusician(userId uint64, musicianId uint64) error
+	UnfollowMusician(userId uint64, musicianId uint64) error
 }
 
 type usecase struct {
@@ -131,3 +134,21 @@ func (u usecase) DeleteUser(id uint64) error {
 
 	return nil
 }
+
+func (u *usecase) FollowMusician(userId uint64, musicianId uint64) error {
+	err := u.userRep.FollowMusician(userId, musicianId)
+	if err != nil {
+		return errors.Wrap(err, "user.usecase.FollowMusician error while add")
+	}
+
+	return nil
+}
+
+func (u *usecase) UnfollowMusician(userId uint64, musicianId uint64) error {
+	err := u.userRep.UnfollowMusician(userId, musicianId)
+	if err != nil {
+		return errors.Wrap(err, "user.usecase.UnfollowMusician error while delete")
+	}
+
+	return nil
+}
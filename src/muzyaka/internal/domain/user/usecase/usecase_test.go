@@ -4,6 +4,8 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	mock_usecase "src/internal/domain/auth/usecase/mocks"
+	mock_repository2 "src/internal/domain/track/repository/mocks"
 	mock_repository "src/internal/domain/user/repository/mocks"
 	"src/internal/models"
 	"testing"
@@ -11,11 +13,13 @@ import (
 
 func TestUsecase_UpdateUser(t *testing.T) {
 	type mock func(r *mock_repository.MockUserRepository, user *models.User)
+	type mockEnc func(r *mock_usecase.MockEncryptor, password []byte)
 
 	testTable := []struct {
 		name        string
 		inputUser   *models.User
 		mock        mock
+		mockEnc     mockEnc
 		expectedErr error
 	}{
 		{
@@ -30,6 +34,9 @@ func TestUsecase_UpdateUser(t *testing.T) {
 			mock: func(r *mock_repository.MockUserRepository, user *models.User) {
 				r.EXPECT().UpdateUser(user).Return(nil)
 			},
+			mockEnc: func(r *mock_usecase.MockEncryptor, password []byte) {
+				r.EXPECT().EncodePassword(password).Return([]byte("hashed"), nil)
+			},
 			expectedErr: nil,
 		},
 		{
@@ -44,6 +51,9 @@ func TestUsecase_UpdateUser(t *testing.T) {
 			mock: func(r *mock_repository.MockUserRepository, user *models.User) {
 				r.EXPECT().UpdateUser(user).Return(errors.New("error in repo"))
 			},
+			mockEnc: func(r *mock_usecase.MockEncryptor, password []byte) {
+				r.EXPECT().EncodePassword(password).Return([]byte("hashed"), nil)
+			},
 			expectedErr: errors.Wrap(errors.New("error in repo"),
 				"user.usecase.UpdateUser error while update"),
 		},
@@ -57,7 +67,10 @@ func TestUsecase_UpdateUser(t *testing.T) {
 			repo := mock_repository.NewMockUserRepository(ctrl)
 			tc.mock(repo, tc.inputUser)
 
-			u := NewUserUseCase(repo)
+			enc := mock_usecase.NewMockEncryptor(ctrl)
+			tc.mockEnc(enc, []byte(tc.inputUser.Password))
+
+			u := NewUserUseCase(repo, mock_repository2.NewMockTrackRepository(ctrl), enc)
 			err := u.UpdateUser(tc.inputUser)
 
 			if tc.expectedErr == nil {
@@ -120,7 +133,7 @@ func TestUsecase_GetUser(t *testing.T) {
 			repo := mock_repository.NewMockUserRepository(ctrl)
 			tc.mock(repo, tc.id)
 
-			u := NewUserUseCase(repo)
+			u := NewUserUseCase(repo, mock_repository2.NewMockTrackRepository(ctrl), mock_usecase.NewMockEncryptor(ctrl))
 			user, err := u.GetUser(tc.id)
 
 			assert.Equal(t, tc.expectedUser, user)
@@ -183,7 +196,7 @@ func TestUsecase_AddUser(t *testing.T) {
 			repo := mock_repository.NewMockUserRepository(ctrl)
 			tc.mock(repo, tc.inputUser)
 
-			u := NewUserUseCase(repo)
+			u := NewUserUseCase(repo, mock_repository2.NewMockTrackRepository(ctrl), mock_usecase.NewMockEncryptor(ctrl))
 			id, err := u.AddUser(tc.inputUser)
 
 			assert.Equal(t, tc.expectedID, id)
@@ -232,7 +245,7 @@ func TestUsecase_DeleteUser(t *testing.T) {
 			repo := mock_repository.NewMockUserRepository(ctrl)
 			tc.mock(repo, tc.id)
 
-			u := NewUserUseCase(repo)
+			u := NewUserUseCase(repo, mock_repository2.NewMockTrackRepository(ctrl), mock_usecase.NewMockEncryptor(ctrl))
 			err := u.DeleteUser(tc.id)
 
 			if tc.expectedErr == nil {
@@ -243,3 +256,91 @@ func TestUsecase_DeleteUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUsecase_FollowMusician(t *testing.T) {
+	type mock func(r *mock_repository.MockUserRepository)
+
+	testTable := []struct {
+		name        string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockUserRepository) {
+				r.EXPECT().FollowMusician(uint64(1), uint64(2)).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Unknown musician test",
+			mock: func(r *mock_repository.MockUserRepository) {
+				r.EXPECT().FollowMusician(uint64(1), uint64(2)).Return(models.ErrNotFound)
+			},
+			expectedErr: errors.Wrap(models.ErrNotFound, "user.usecase.FollowMusician error while add"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockUserRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewUserUseCase(repo, mock_repository2.NewMockTrackRepository(ctrl), mock_usecase.NewMockEncryptor(ctrl))
+			err := u.FollowMusician(1, 2)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, err.Error(), tc.expectedErr.Error())
+			}
+		})
+	}
+}
+
+func TestUsecase_UnfollowMusician(t *testing.T) {
+	type mock func(r *mock_repository.MockUserRepository)
+
+	testTable := []struct {
+		name        string
+		mock        mock
+		expectedErr error
+	}{
+		{
+			name: "Usual test",
+			mock: func(r *mock_repository.MockUserRepository) {
+				r.EXPECT().UnfollowMusician(uint64(1), uint64(2)).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Not followed test",
+			mock: func(r *mock_repository.MockUserRepository) {
+				r.EXPECT().UnfollowMusician(uint64(1), uint64(2)).Return(models.ErrNothingToDelete)
+			},
+			expectedErr: errors.Wrap(models.ErrNothingToDelete, "user.usecase.UnfollowMusician error while delete"),
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_repository.NewMockUserRepository(ctrl)
+			tc.mock(repo)
+
+			u := NewUserUseCase(repo, mock_repository2.NewMockTrackRepository(ctrl), mock_usecase.NewMockEncryptor(ctrl))
+			err := u.UnfollowMusician(1, 2)
+
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, err.Error(), tc.expectedErr.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/hajimehoshi/go-mp3"
+	"github.com/mewkiz/flac"
+	"github.com/pkg/errors"
+	"io"
+	"math"
+	"src/internal/models"
+	"time"
+)
+
+const (
+	FormatMp3  = "mp3"
+	FormatFlac = "flac"
+	FormatWav  = "wav"
+
+	WavContentType = "audio/wav"
+
+	// MaxChannels is the most channels a decoded file can have
+	MaxChannels = 8
+)
+
+var errTooLong = errors.New("audio is too long")
+
+// PCM is decoded audio with samples of every channel, samples are from -1 to 1
+type PCM struct {
+	SampleRate int
+	Channels   [][]float32
+}
+
+// Len is the number of samples in a channel
+func (p *PCM) Len() int {
+	if len(p.Channels) == 0 {
+		return 0
+	}
+
+	return len(p.Channels[0])
+}
+
+func (p *PCM) Duration() time.Duration {
+	if p.SampleRate == 0 {
+		return 0
+	}
+
+	return time.Duration(p.Len()) * time.Second / time.Duration(p.SampleRate)
+}
+
+// Clip returns samples from offset of at most length, it shares samples with p
+func (p *PCM) Clip(offset time.Duration, length time.Duration) *PCM {
+	start := min(p.sampleIndex(offset), p.Len())
+	end := min(start+p.sampleIndex(length), p.Len())
+
+	res := &PCM{SampleRate: p.SampleRate, Channels: make([][]float32, 0, len(p.Channels))}
+	for _, v := range p.Channels {
+		res.Channels = append(res.Channels, v[start:end])
+	}
+
+	return res
+}
+
+func (p *PCM) sampleIndex(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+
+	return int(d * time.Duration(p.SampleRate) / time.Second)
+}
+
+// Detect returns the format of the file by its signature
+func Detect(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		return FormatFlac, nil
+	case len(data) >= 12 && bytes.HasPrefix(data, []byte("RIFF")) && string(data[8:12]) == "WAVE":
+		return FormatWav, nil
+	case bytes.HasPrefix(data, []byte("ID3")), len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0:
+		return FormatMp3, nil
+	}
+
+	return "", models.ErrInvalidFileFormat
+}
+
+// Decode decodes MP3, FLAC or WAV file. Files longer than maxDuration are
+// rejected before they are decoded when their length is declared and as soon
+// as it is exceeded otherwise.
+func Decode(data []byte, maxDuration time.Duration) (*PCM, error) {
+	format, err := Detect(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *PCM
+	switch format {
+	case FormatMp3:
+		res, err = decodeMp3(data, maxDuration)
+	case FormatFlac:
+		res, err = decodeFlac(data, maxDuration)
+	default:
+		res, err = decodeWav(data, maxDuration)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(models.ErrInvalidFileFormat, err.Error())
+	}
+
+	if res.SampleRate <= 0 || len(res.Channels) == 0 {
+		return nil, errors.Wrap(models.ErrInvalidFileFormat, "invalid format")
+	}
+
+	return res, nil
+}
+
+// maxSamples is the number of samples in a channel of maxDuration
+func maxSamples(sampleRate int, maxDuration time.Duration) int {
+	return int(maxDuration * time.Duration(sampleRate) / time.Second)
+}
+
+func decodeMp3(data []byte, maxDuration time.Duration) (*PCM, error) {
+	decoder, err := mp3.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// the decoder always returns 16 bit stereo, mono is decoded to equal channels
+	const frameSize = 4
+	limit := maxSamples(decoder.SampleRate(), maxDuration)
+	if decoder.Length()/frameSize > int64(limit) {
+		return nil, errTooLong
+	}
+
+	var left, right []float32
+	if decoder.Length() > 0 {
+		left = make([]float32, 0, decoder.Length()/frameSize)
+		right = make([]float32, 0, decoder.Length()/frameSize)
+	}
+	mono := true
+
+	buf := make([]byte, 4096*frameSize)
+	for {
+		n, err := io.ReadFull(decoder, buf)
+		for i := 0; i+frameSize <= n; i += frameSize {
+			l := int16(binary.LittleEndian.Uint16(buf[i:]))
+			r := int16(binary.LittleEndian.Uint16(buf[i+2:]))
+			mono = mono && l == r
+			left = append(left, float32(l)/(1<<15))
+			right = append(right, float32(r)/(1<<15))
+		}
+
+		if len(left) > limit {
+			return nil, errTooLong
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	res := &PCM{SampleRate: decoder.SampleRate(), Channels: [][]float32{left, right}}
+	if mono {
+		res.Channels = res.Channels[:1]
+	}
+
+	return res, nil
+}
+
+func decodeFlac(data []byte, maxDuration time.Duration) (*PCM, error) {
+	stream, err := flac.New(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if stream.Info.NChannels > MaxChannels {
+		return nil, errors.New("too many channels")
+	}
+
+	// NSamples is 0 if unknown
+	limit := maxSamples(int(stream.Info.SampleRate), maxDuration)
+	if stream.Info.NSamples > uint64(limit) {
+		return nil, errTooLong
+	}
+
+	res := &PCM{
+		SampleRate: int(stream.Info.SampleRate),
+		Channels:   make([][]float32, stream.Info.NChannels),
+	}
+	for i := range res.Channels {
+		res.Channels[i] = make([]float32, 0, stream.Info.NSamples)
+	}
+	scale := float32(int64(1) << (stream.Info.BitsPerSample - 1))
+
+	for {
+		frame, err := stream.ParseNext()
+		if err == io.EOF {
+			return res, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(frame.Subframes) != len(res.Channels) {
+			return nil, errors.New("number of channels is changed")
+		}
+
+		for ch, subframe := range frame.Subframes {
+			for _, v := range subframe.Samples[:frame.BlockSize] {
+				res.Channels[ch] = append(res.Channels[ch], float32(v)/scale)
+			}
+		}
+
+		if res.Len() > limit {
+			return nil, errTooLong
+		}
+	}
+}
+
+const (
+	wavFormatPcm        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+type wavFormat struct {
+	Format        uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+}
+
+func decodeWav(data []byte, maxDuration time.Duration) (*PCM, error) {
+	var format *wavFormat
+
+	// chunks follow "RIFF", size and "WAVE", each is padded to an even size
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		body := data[pos+8:]
+		// size of the data chunk is not known while a file is streamed
+		if size < 0 || size > len(body) {
+			size = len(body)
+		}
+		body = body[:size]
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, errors.New("fmt chunk is too short")
+			}
+
+			format = &wavFormat{}
+			_ = binary.Read(bytes.NewReader(body), binary.LittleEndian, format)
+			// the actual format is the first field of the subformat GUID
+			if format.Format == wavFormatExtensible && size >= 26 {
+				format.Format = binary.LittleEndian.Uint16(body[24:])
+			}
+		case "data":
+			if format == nil {
+				return nil, errors.New("data chunk before fmt chunk")
+			}
+
+			return decodeWavData(format, body, maxDuration)
+		}
+
+		pos += 8 + size + size%2
+	}
+
+	return nil, errors.New("no data chunk")
+}
+
+func decodeWavData(format *wavFormat, body []byte, maxDuration time.Duration) (*PCM, error) {
+	channels := int(format.Channels)
+	width := int(format.BitsPerSample+7) / 8
+	if channels == 0 || width == 0 {
+		return nil, errors.New("invalid format")
+	}
+
+	if channels > MaxChannels {
+		return nil, errors.New("too many channels")
+	}
+
+	var sample func(b []byte) float32
+	switch {
+	case format.Format == wavFormatPcm && width == 1:
+		sample = func(b []byte) float32 { return (float32(b[0]) - 128) / 128 }
+	case format.Format == wavFormatPcm && width == 2:
+		sample = func(b []byte) float32 { return float32(int16(binary.LittleEndian.Uint16(b))) / (1 << 15) }
+	case format.Format == wavFormatPcm && width == 3:
+		sample = func(b []byte) float32 {
+			return float32(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24)>>8) / (1 << 23)
+		}
+	case format.Format == wavFormatPcm && width == 4:
+		sample = func(b []byte) float32 { return float32(int32(binary.LittleEndian.Uint32(b))) / (1 << 31) }
+	case format.Format == wavFormatFloat && width == 4:
+		sample = func(b []byte) float32 { return math.Float32frombits(binary.LittleEndian.Uint32(b)) }
+	default:
+		return nil, errors.Errorf("unsupported format %d with %d bits", format.Format, format.BitsPerSample)
+	}
+
+	frameSize := channels * width
+	if len(body)/frameSize > maxSamples(int(format.SampleRate), maxDuration) {
+		return nil, errTooLong
+	}
+
+	res := &PCM{
+		SampleRate: int(format.SampleRate),
+		Channels:   make([][]float32, channels),
+	}
+	for ch := range res.Channels {
+		res.Channels[ch] = make([]float32, 0, len(body)/frameSize)
+	}
+
+	for i := 0; i+frameSize <= len(body); i += frameSize {
+		for ch := range res.Channels {
+			res.Channels[ch] = append(res.Channels[ch], sample(body[i+ch*width:]))
+		}
+	}
+
+	return res, nil
+}
+
+// EncodeWav encodes samples as 16 bit WAV
+func EncodeWav(p *PCM) []byte {
+	const headerSize = 44
+	channels := len(p.Channels)
+	size := p.Len() * channels * 2
+
+	buf := bytes.NewBuffer(make([]byte, 0, headerSize+size))
+	buf.WriteString("RIFF")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(headerSize-8+size))
+	buf.WriteString("WAVEfmt ")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(buf, binary.LittleEndian, wavFormat{
+		Format:        wavFormatPcm,
+		Channels:      uint16(channels),
+		SampleRate:    uint32(p.SampleRate),
+		ByteRate:      uint32(p.SampleRate * channels * 2),
+		BlockAlign:    uint16(channels * 2),
+		BitsPerSample: 16,
+	})
+	buf.WriteString("data")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(size))
+
+	samples := make([]byte, size)
+	for ch, channel := range p.Channels {
+		for i, v := range channel {
+			v = max(-1, min(1, v))
+			binary.LittleEndian.PutUint16(samples[2*(i*channels+ch):], uint16(int16(math.Round(float64(v)*math.MaxInt16))))
+		}
+	}
+	buf.Write(samples)
+
+	return buf.Bytes()
+}
+
+// Peaks splits samples into points parts and returns the peak amplitude of
+// each of them in any channel rounded to 3 digits
+func Peaks(p *PCM, points int) []float32 {
+	res := make([]float32, points)
+	if points <= 0 {
+		return res
+	}
+
+	for i := range res {
+		start := i * p.Len() / points
+		end := (i + 1) * p.Len() / points
+
+		var peak float32
+		for _, channel := range p.Channels {
+			for _, v := range channel[start:end] {
+				peak = max(peak, float32(math.Abs(float64(v))))
+			}
+		}
+		res[i] = float32(math.Round(float64(min(peak, 1))*1000) / 1000)
+	}
+
+	return res
+}
@@ -0,0 +1,199 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"src/internal/models"
+	"testing"
+	"time"
+)
+
+const testRate = 8000
+
+// testSine is a second of 440 Hz at half of the full scale
+func testSine() *PCM {
+	samples := make([]float32, testRate)
+	for i := range samples {
+		samples[i] = float32(0.5 * math.Sin(2*math.Pi*440*float64(i)/testRate))
+	}
+	return &PCM{SampleRate: testRate, Channels: [][]float32{samples}}
+}
+
+func TestDetect(t *testing.T) {
+	testTable := []struct {
+		name           string
+		data           []byte
+		expectedFormat string
+		expectedErr    error
+	}{
+		{name: "FLAC", data: []byte("fLaC\x00\x00"), expectedFormat: FormatFlac},
+		{name: "WAV", data: []byte("RIFF\x00\x00\x00\x00WAVEfmt "), expectedFormat: FormatWav},
+		{name: "MP3 with ID3", data: []byte("ID3\x04\x00"), expectedFormat: FormatMp3},
+		{name: "MP3 frame", data: []byte{0xFF, 0xFB, 0x90, 0x00}, expectedFormat: FormatMp3},
+		{name: "RIFF without WAVE", data: []byte("RIFF\x00\x00\x00\x00AVI "), expectedErr: models.ErrInvalidFileFormat},
+		{name: "Empty", data: nil, expectedErr: models.ErrInvalidFileFormat},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			format, err := Detect(tc.data)
+			assert.Equal(t, tc.expectedFormat, format)
+			assert.True(t, errors.Is(err, tc.expectedErr))
+		})
+	}
+}
+
+func TestDecode_Wav(t *testing.T) {
+	sine := testSine()
+
+	res, err := Decode(EncodeWav(sine), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, testRate, res.SampleRate)
+	assert.Equal(t, time.Second, res.Duration())
+	assert.Len(t, res.Channels, 1)
+	assert.InDeltaSlice(t, sine.Channels[0], res.Channels[0], 1e-4)
+}
+
+func TestDecode_WavStereo(t *testing.T) {
+	stereo := &PCM{SampleRate: testRate, Channels: [][]float32{{0.5, -0.5, 0}, {0.25, 0, -1}}}
+
+	res, err := Decode(EncodeWav(stereo), time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, res.Channels, 2)
+	assert.InDeltaSlice(t, stereo.Channels[0], res.Channels[0], 1e-4)
+	assert.InDeltaSlice(t, stereo.Channels[1], res.Channels[1], 1e-4)
+}
+
+func TestDecode_Wav24BitStereo(t *testing.T) {
+	var fmtChunk bytes.Buffer
+	_ = binary.Write(&fmtChunk, binary.LittleEndian, wavFormat{
+		Format:        wavFormatExtensible,
+		Channels:      2,
+		SampleRate:    testRate,
+		ByteRate:      testRate * 6,
+		BlockAlign:    6,
+		BitsPerSample: 24,
+	})
+	// extension size, valid bits, channel mask and subformat GUID
+	_ = binary.Write(&fmtChunk, binary.LittleEndian, []uint16{22, 24, 3, 0, wavFormatPcm})
+	fmtChunk.Write(make([]byte, 14))
+
+	// left is half of the full scale, right is a negative quarter
+	samples := []byte{0x00, 0x00, 0x40, 0x00, 0x00, 0xE0}
+
+	var data bytes.Buffer
+	data.WriteString("RIFF\x00\x00\x00\x00WAVE")
+	// unknown chunks with odd size are skipped with padding
+	data.WriteString("LIST\x03\x00\x00\x00abc\x00")
+	data.WriteString("fmt ")
+	_ = binary.Write(&data, binary.LittleEndian, uint32(fmtChunk.Len()))
+	data.Write(fmtChunk.Bytes())
+	data.WriteString("data")
+	_ = binary.Write(&data, binary.LittleEndian, uint32(len(samples)))
+	data.Write(samples)
+
+	res, err := Decode(data.Bytes(), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{0.5}, {-0.25}}, res.Channels)
+}
+
+func TestDecode_Flac(t *testing.T) {
+	sine := testSine()
+
+	const blockSize = 1000
+	var buf bytes.Buffer
+	enc, err := flac.NewEncoder(&buf, &meta.StreamInfo{
+		BlockSizeMin:  blockSize,
+		BlockSizeMax:  blockSize,
+		SampleRate:    testRate,
+		NChannels:     1,
+		BitsPerSample: 16,
+		NSamples:      uint64(sine.Len()),
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < sine.Len(); i += blockSize {
+		samples := make([]int32, blockSize)
+		for j := range samples {
+			samples[j] = int32(math.Round(float64(sine.Channels[0][i+j]) * (1 << 15)))
+		}
+
+		err = enc.WriteFrame(&frame.Frame{
+			Header: frame.Header{
+				HasFixedBlockSize: true,
+				BlockSize:         blockSize,
+				SampleRate:        testRate,
+				Channels:          frame.ChannelsMono,
+				BitsPerSample:     16,
+				Num:               uint64(i / blockSize),
+			},
+			Subframes: []*frame.Subframe{{
+				SubHeader: frame.SubHeader{Pred: frame.PredVerbatim},
+				Samples:   samples,
+				NSamples:  blockSize,
+			}},
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, enc.Close())
+
+	res, err := Decode(buf.Bytes(), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, testRate, res.SampleRate)
+	assert.InDeltaSlice(t, sine.Channels[0], res.Channels[0], 1e-4)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	testTable := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Unknown format", data: []byte("text")},
+		{name: "WAV without data", data: []byte("RIFF\x04\x00\x00\x00WAVE")},
+		{name: "Truncated FLAC", data: []byte("fLaC\x00\x00")},
+		{name: "Broken MP3", data: []byte{0xFF, 0xFB, 0x00, 0x00}},
+		{name: "Too long WAV", data: EncodeWav(&PCM{SampleRate: testRate, Channels: [][]float32{make([]float32, 61*testRate)}})},
+		{name: "Too many channels", data: EncodeWav(&PCM{SampleRate: testRate, Channels: make([][]float32, MaxChannels+1)})},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decode(tc.data, time.Minute)
+			assert.True(t, errors.Is(err, models.ErrInvalidFileFormat))
+		})
+	}
+}
+
+func TestPeaks(t *testing.T) {
+	pcm := &PCM{SampleRate: 4, Channels: [][]float32{
+		{0.1, -0.5, 0.25, 0.2, -1.5, 0, 0.12345, 0},
+		{0, 0, 0, -0.3, 0, 0, 0, 0},
+	}}
+
+	assert.Equal(t, []float32{0.5, 0.3, 1, 0.123}, Peaks(pcm, 4))
+	assert.Equal(t, []float32{0.5, 1}, Peaks(pcm, 2))
+
+	peaks := Peaks(testSine(), 100)
+	assert.Len(t, peaks, 100)
+	for _, v := range peaks {
+		assert.InDelta(t, 0.5, v, 0.01)
+	}
+}
+
+func TestPCM_Clip(t *testing.T) {
+	sine := testSine()
+
+	clip := sine.Clip(250*time.Millisecond, 500*time.Millisecond)
+	assert.Equal(t, 500*time.Millisecond, clip.Duration())
+	assert.Equal(t, [][]float32{sine.Channels[0][2000:6000]}, clip.Channels)
+
+	assert.Equal(t, 250*time.Millisecond, sine.Clip(750*time.Millisecond, time.Second).Duration())
+	assert.Zero(t, sine.Clip(2*time.Second, time.Second).Len())
+}
@@ -0,0 +1,231 @@
+package fingerprint
+
+import (
+	"math"
+	"math/bits"
+	"src/internal/lib/audio"
+)
+
+const (
+	sampleRate = 11025
+	frameSize  = 4096
+	// a value is computed every 124 ms
+	frameStep = frameSize / 3
+
+	minFrequency = 55
+	maxFrequency = 3520
+	// silenceEnergy is the mean square of a frame below -60 dBFS
+	silenceEnergy = 1e-6
+
+	// keyBits are the bits comparing notes within a frame, they are kept
+	// by lossy encoding better than the ones comparing frames
+	keyBits = 20
+)
+
+// Compute returns a chroma based fingerprint, a value for every frame. Each
+// bit of a value compares the energy of two pitch classes within the frame
+// or of a pitch class with the previous frames. Silent frames are 0, a value
+// of other frames is 0 only if it has no notes.
+func Compute(p *audio.PCM) []uint32 {
+	samples := resample(p)
+	if len(samples) < frameSize {
+		return nil
+	}
+
+	window := make([]float64, frameSize)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/frameSize)
+	}
+
+	cos, sin := twiddles(frameSize)
+
+	pitchClasses := make([]int, frameSize/2)
+	for k := range pitchClasses {
+		f := float64(k) * sampleRate / frameSize
+		if f < minFrequency || f > maxFrequency {
+			pitchClasses[k] = -1
+			continue
+		}
+		note := int(math.Round(12*math.Log2(f/440))) + 69
+		pitchClasses[k] = note % 12
+	}
+
+	var chroma [][12]float64
+	re := make([]float64, frameSize)
+	im := make([]float64, frameSize)
+	for start := 0; start+frameSize <= len(samples); start += frameStep {
+		var energy float64
+		for i := range re {
+			v := samples[start+i]
+			energy += v * v
+			re[i], im[i] = v*window[i], 0
+		}
+
+		var frame [12]float64
+		if energy/frameSize > silenceEnergy {
+			fft(re, im, cos, sin)
+			for k, class := range pitchClasses {
+				if class >= 0 {
+					frame[class] += re[k]*re[k] + im[k]*im[k]
+				}
+			}
+		}
+		chroma = append(chroma, frame)
+	}
+
+	res := make([]uint32, len(chroma))
+	for i := range chroma {
+		// averaging 3 frames reduces noise of short notes
+		var cur, prev [12]float64
+		for j := max(0, i-1); j <= min(len(chroma)-1, i+1); j++ {
+			for c := range cur {
+				cur[c] += chroma[j][c]
+			}
+		}
+		for j := max(0, i-2); j <= i; j++ {
+			for c := range prev {
+				prev[c] += chroma[j][c]
+			}
+		}
+
+		if chroma[i] == [12]float64{} {
+			continue
+		}
+
+		var v uint32
+		for c := 0; c < 12; c++ {
+			if cur[c] > cur[(c+1)%12] {
+				v |= 1 << c
+			}
+		}
+		for c := 0; c < 8; c++ {
+			if cur[c] > cur[(c+5)%12] {
+				v |= 1 << (12 + c)
+			}
+		}
+		for c := 0; c < 12; c++ {
+			if cur[c] > prev[c] {
+				v |= 1 << (keyBits + c)
+			}
+		}
+		res[i] = v
+	}
+
+	return res
+}
+
+// Keys returns distinct keys to look up fingerprints sharing some frames
+func Keys(values []uint32) []uint32 {
+	seen := make(map[uint32]bool, len(values))
+	res := make([]uint32, 0, len(values))
+	for _, v := range values {
+		key := v & (1<<keyBits - 1)
+		if v == 0 || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		res = append(res, key)
+	}
+
+	return res
+}
+
+// Similarity returns the share of equal bits of a and b at their best
+// alignment, from 0 for unrelated fingerprints near 0.5 to 1. The aligned
+// part has to cover at least half of the shorter one, silent frames are
+// skipped.
+func Similarity(a []uint32, b []uint32) float64 {
+	minOverlap := min(len(a), len(b)) / 2
+	if minOverlap == 0 {
+		return 0
+	}
+
+	var best float64
+	// b[i] is compared with a[i+offset]
+	for offset := minOverlap - len(b); offset <= len(a)-minOverlap; offset++ {
+		var frames, equal int
+		for i := max(0, -offset); i < len(b) && i+offset < len(a); i++ {
+			x, y := a[i+offset], b[i]
+			if x == 0 || y == 0 {
+				continue
+			}
+
+			frames++
+			equal += 32 - bits.OnesCount32(x^y)
+		}
+
+		if frames >= minOverlap {
+			best = max(best, float64(equal)/float64(32*frames))
+		}
+	}
+
+	return math.Round(best*1000) / 1000
+}
+
+// resample mixes channels down and converts them to sampleRate averaging
+// samples of every output sample
+func resample(p *audio.PCM) []float64 {
+	if p.SampleRate <= 0 || len(p.Channels) == 0 {
+		return nil
+	}
+
+	ratio := float64(p.SampleRate) / sampleRate
+	res := make([]float64, int(float64(p.Len())/ratio))
+	for i := range res {
+		start := int(float64(i) * ratio)
+		end := min(max(start+1, int(float64(i+1)*ratio)), p.Len())
+
+		var sum float64
+		for _, channel := range p.Channels {
+			for _, v := range channel[start:end] {
+				sum += float64(v)
+			}
+		}
+		res[i] = sum / float64((end-start)*len(p.Channels))
+	}
+
+	return res
+}
+
+// fft is an in place radix-2 transform, the length is a power of 2
+func fft(re []float64, im []float64, cos []float64, sin []float64) {
+	n := len(re)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+
+		if i < j {
+			re[i], re[j] = re[j], re[i]
+			im[i], im[j] = im[j], im[i]
+		}
+	}
+
+	for length := 2; length <= n; length <<= 1 {
+		stride := n / length
+		for start := 0; start < n; start += length {
+			for k := 0; k < length/2; k++ {
+				wr, wi := cos[k*stride], sin[k*stride]
+				i, j := start+k, start+k+length/2
+				xr := re[j]*wr - im[j]*wi
+				xi := re[j]*wi + im[j]*wr
+				re[j], im[j] = re[i]-xr, im[i]-xi
+				re[i], im[i] = re[i]+xr, im[i]+xi
+			}
+		}
+	}
+}
+
+// twiddles returns factors of the transform of length n
+func twiddles(n int) ([]float64, []float64) {
+	cos, sin := make([]float64, n/2), make([]float64, n/2)
+	for k := range cos {
+		cos[k] = math.Cos(-2 * math.Pi * float64(k) / float64(n))
+		sin[k] = math.Sin(-2 * math.Pi * float64(k) / float64(n))
+	}
+
+	return cos, sin
+}
@@ -0,0 +1,115 @@
+package fingerprint
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"src/internal/lib/audio"
+	"testing"
+)
+
+// testSong plays a random chord with harmonics every half a second
+func testSong(seed int64, rate int, seconds int) []float64 {
+	rnd := rand.New(rand.NewSource(seed))
+	res := make([]float64, rate*seconds)
+
+	for start := 0; start < len(res); start += rate / 2 {
+		root := 48 + rnd.Intn(24)
+		notes := []int{root, root + 3 + rnd.Intn(2), root + 7}
+		for i := start; i < min(start+rate/2, len(res)); i++ {
+			t := float64(i) / float64(rate)
+			for _, note := range notes {
+				f := 440 * math.Pow(2, float64(note-69)/12)
+				for h := 1; h <= 3; h++ {
+					res[i] += 0.1 / float64(h) * math.Sin(2*math.Pi*f*float64(h)*t)
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+func toPCM(samples []float64, rate int, gain float64, noise float64) *audio.PCM {
+	rnd := rand.New(rand.NewSource(1))
+	channel := make([]float32, len(samples))
+	for i, v := range samples {
+		channel[i] = float32(gain*v + noise*(rnd.Float64()*2-1))
+	}
+
+	return &audio.PCM{SampleRate: rate, Channels: [][]float32{channel, channel}}
+}
+
+func TestSimilarity(t *testing.T) {
+	song := testSong(1, 44100, 20)
+	original := Compute(toPCM(song, 44100, 1, 0))
+
+	testTable := []struct {
+		name  string
+		other *audio.PCM
+		min   float64
+		max   float64
+	}{
+		{
+			name:  "Same track",
+			other: toPCM(song, 44100, 1, 0),
+			min:   1,
+			max:   1,
+		},
+		{
+			name:  "Quieter with noise at another rate",
+			other: toPCM(testSong(1, 48000, 20), 48000, 0.5, 0.02),
+			min:   0.85,
+			max:   1,
+		},
+		{
+			name:  "Cut track",
+			other: toPCM(song[3*44100:15*44100], 44100, 1, 0),
+			min:   0.85,
+			max:   1,
+		},
+		{
+			name:  "Other track",
+			other: toPCM(testSong(2, 44100, 20), 44100, 1, 0),
+			min:   0,
+			max:   0.7,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			res := Similarity(original, Compute(tc.other))
+			assert.GreaterOrEqual(t, res, tc.min)
+			assert.LessOrEqual(t, res, tc.max)
+		})
+	}
+}
+
+func TestCompute_Silence(t *testing.T) {
+	silence := toPCM(make([]float64, 44100*5), 44100, 1, 0)
+
+	values := Compute(silence)
+	assert.NotEmpty(t, values)
+	for _, v := range values {
+		assert.Zero(t, v)
+	}
+
+	assert.Empty(t, Keys(values))
+	assert.Zero(t, Similarity(values, values))
+	assert.Empty(t, Compute(toPCM(make([]float64, 100), 44100, 1, 0)))
+}
+
+func TestKeys(t *testing.T) {
+	values := []uint32{0, 1<<keyBits | 5, 2<<keyBits | 5, 7}
+	assert.Equal(t, []uint32{5, 7}, Keys(values))
+}
+
+func TestFft(t *testing.T) {
+	re := []float64{1, 2, 3, 4}
+	im := make([]float64, 4)
+	cos, sin := twiddles(4)
+
+	fft(re, im, cos, sin)
+	assert.InDeltaSlice(t, []float64{10, -2, -2, -2}, re, 1e-9)
+	assert.InDeltaSlice(t, []float64{0, 2, 0, -2}, im, 1e-9)
+}
@@ -0,0 +1,73 @@
+package geo
+
+import "math"
+
+// EarthRadiusKm is the mean radius used by Distance
+const EarthRadiusKm = 6371.0
+
+// Distance returns the great-circle distance in kilometres between two points
+// given in degrees
+func Distance(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+	phi1, phi2 := radians(lat1), radians(lat2)
+	dPhi, dLambda := radians(lat2-lat1), radians(lon2-lon1)
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Box is a latitude and longitude range in degrees. MinLon is greater than
+// MaxLon when the box crosses the antimeridian.
+type Box struct {
+	MinLat, MaxLat float64
+	MinLon, MaxLon float64
+}
+
+// BoundingBox returns a box holding every point within radiusKm of the centre,
+// it is used to prefilter points before Distance is computed
+func BoundingBox(lat float64, lon float64, radiusKm float64) Box {
+	dLat := degrees(radiusKm / EarthRadiusKm)
+	box := Box{MinLat: lat - dLat, MaxLat: lat + dLat, MinLon: -180, MaxLon: 180}
+
+	// a pole is inside the circle, every longitude is
+	if box.MinLat <= -90 || box.MaxLat >= 90 {
+		box.MinLat = math.Max(box.MinLat, -90)
+		box.MaxLat = math.Min(box.MaxLat, 90)
+		return box
+	}
+
+	dLon := degrees(math.Asin(math.Sin(radiusKm/EarthRadiusKm) / math.Cos(radians(lat))))
+	if radiusKm/EarthRadiusKm >= math.Pi/2 || math.IsNaN(dLon) {
+		return box
+	}
+
+	box.MinLon = wrap(lon - dLon)
+	box.MaxLon = wrap(lon + dLon)
+
+	return box
+}
+
+// CrossesAntimeridian reports whether longitudes of the box are MinLon..180 and -180..MaxLon
+func (b Box) CrossesAntimeridian() bool {
+	return b.MinLon > b.MaxLon
+}
+
+func wrap(lon float64) float64 {
+	switch {
+	case lon < -180:
+		return lon + 360
+	case lon > 180:
+		return lon - 360
+	}
+
+	return lon
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,50 @@
+package geo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	// Berlin to Paris
+	assert.InDelta(t, 878, Distance(52.52, 13.405, 48.8566, 2.3522), 5)
+	assert.InDelta(t, 0, Distance(10, 20, 10, 20), 1e-9)
+	// across the antimeridian
+	assert.InDelta(t, 111.2, Distance(0, 179.5, 0, -179.5), 0.5)
+}
+
+func TestBoundingBox(t *testing.T) {
+	testTable := []struct {
+		name      string
+		lat, lon  float64
+		radiusKm  float64
+		crosses   bool
+		allLongs  bool
+		insideLat float64
+		insideLon float64
+	}{
+		{name: "Usual test", lat: 52.52, lon: 13.405, radiusKm: 50, insideLat: 52.8, insideLon: 13.9},
+		{name: "Antimeridian test", lat: 0, lon: 179.9, radiusKm: 100, crosses: true, insideLat: 0, insideLon: -179.8},
+		{name: "Pole test", lat: 89.9, lon: 0, radiusKm: 100, allLongs: true, insideLat: 89.95, insideLon: 120},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			box := BoundingBox(tc.lat, tc.lon, tc.radiusKm)
+
+			assert.Equal(t, tc.crosses, box.CrossesAntimeridian())
+			if tc.allLongs {
+				assert.Equal(t, -180.0, box.MinLon)
+				assert.Equal(t, 180.0, box.MaxLon)
+			}
+
+			assert.LessOrEqual(t, Distance(tc.lat, tc.lon, tc.insideLat, tc.insideLon), tc.radiusKm)
+			assert.True(t, tc.insideLat >= box.MinLat && tc.insideLat <= box.MaxLat)
+			if box.CrossesAntimeridian() {
+				assert.True(t, tc.insideLon >= box.MinLon || tc.insideLon <= box.MaxLon)
+			} else {
+				assert.True(t, tc.insideLon >= box.MinLon && tc.insideLon <= box.MaxLon)
+			}
+		})
+	}
+}
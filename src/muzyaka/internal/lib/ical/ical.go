@@ -0,0 +1,100 @@
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"src/internal/models"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+	prodId      = "-//muzyaka//concerts//EN"
+	// maxLineLength is in octets, longer lines are folded
+	maxLineLength = 75
+	timeFormat    = "20060102T150405Z"
+)
+
+// Encode writes concerts as an iCalendar (RFC 5545) feed. Start times are in
+// UTC, the time zone of a concert is kept in X-MUZYAKA-TZID. stamp is DTSTAMP
+// of every event.
+func Encode(name string, concerts []*models.Concert, stamp time.Time) []byte {
+	var buf bytes.Buffer
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+prodId)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escape(name))
+	}
+
+	for _, c := range concerts {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, fmt.Sprintf("UID:concert-%d@muzyaka", c.Id))
+		writeLine(&buf, "DTSTAMP:"+stamp.UTC().Format(timeFormat))
+		writeLine(&buf, "DTSTART:"+c.StartsAt.UTC().Format(timeFormat))
+		writeLine(&buf, "SUMMARY:"+escape(c.Title))
+		writeLine(&buf, "LOCATION:"+escape(c.Venue+", "+c.City))
+		writeLine(&buf, "GEO:"+formatFloat(c.Latitude)+";"+formatFloat(c.Longitude))
+
+		description := c.Description
+		if c.SoldOut {
+			description = strings.TrimSpace("Sold out. " + description)
+		}
+		if description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escape(description))
+		}
+		if c.TicketUrl != "" {
+			writeLine(&buf, "URL:"+c.TicketUrl)
+		}
+		if c.TimeZone != "" {
+			writeLine(&buf, "X-MUZYAKA-TZID:"+escape(c.TimeZone))
+		}
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+// escape escapes TEXT values
+func escape(s string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\r\n", "\\n",
+		"\n", "\\n",
+		"\r", "\\n",
+	).Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 6, 64)
+}
+
+// writeLine folds the line at maxLineLength octets without splitting runes
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation counts
+		limit = maxLineLength - 1
+	}
+
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,55 @@
+package ical
+
+import (
+	"github.com/stretchr/testify/assert"
+	"src/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*60*60)
+	concerts := []*models.Concert{
+		{
+			Id:          7,
+			Title:       "Band, live",
+			Description: "Doors at 19:00; support act\nTBA",
+			Venue:       "Columbiahalle",
+			City:        "Berlin",
+			Latitude:    52.4839,
+			Longitude:   13.3897,
+			StartsAt:    time.Date(2026, 10, 19, 20, 0, 0, 0, berlin),
+			TimeZone:    "Europe/Berlin",
+			TicketUrl:   "https://tickets.example.com/7",
+			SoldOut:     true,
+		},
+	}
+
+	data := string(Encode("Band tour", concerts, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.True(t, strings.HasPrefix(data, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(data, "END:VCALENDAR\r\n"))
+	assert.Contains(t, data, "UID:concert-7@muzyaka\r\n")
+	assert.Contains(t, data, "DTSTAMP:20261001T000000Z\r\n")
+	assert.Contains(t, data, "DTSTART:20261019T180000Z\r\n")
+	assert.Contains(t, data, "SUMMARY:Band\\, live\r\n")
+	assert.Contains(t, data, "LOCATION:Columbiahalle\\, Berlin\r\n")
+	assert.Contains(t, data, "GEO:52.483900;13.389700\r\n")
+	assert.Contains(t, data, "DESCRIPTION:Sold out. Doors at 19:00\\; support act\\nTBA\r\n")
+	assert.Contains(t, data, "URL:https://tickets.example.com/7\r\n")
+}
+
+func TestWriteLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("ä", 60)
+
+	data := string(Encode("", []*models.Concert{{Description: strings.Repeat("ä", 60)}}, time.Now()))
+	assert.Contains(t, data, "DESCRIPTION:")
+
+	for _, l := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(l), maxLineLength)
+	}
+
+	unfolded := strings.ReplaceAll(data, "\r\n ", "")
+	assert.Contains(t, unfolded, line+"\r\n")
+}
@@ -0,0 +1,25 @@
+package inbox
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"src/internal/models"
+	"src/internal/models/dao"
+)
+
+// MarkProcessed records that the consumer handled the event within tx.
+// Returns models.ErrAlreadyProcessed if it did before. Each consumer keeps
+// its own records, so consumers of the same event don't skip each other.
+func MarkProcessed(tx *gorm.DB, consumer string, eventId string) error {
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&dao.ProcessedEvent{Consumer: consumer, EventId: eventId})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return models.ErrAlreadyProcessed
+	}
+
+	return nil
+}
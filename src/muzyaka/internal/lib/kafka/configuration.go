@@ -11,12 +11,23 @@ const (
 	AddTopic     = "sync-add-events"
 	DeleteTopic  = "sync-delete-events"
 	UpdateTopic  = "sync-update-events"
+
+	RecSysSimilarTopic  = "recsys-similar-tracks"
+	RecSysTrendingTopic = "recsys-trending-tracks"
+
+	RecSysGroup = "muzyaka-recsys"
+	FeedGroup   = "muzyaka-feed"
+	MediaGroup  = "muzyaka-media"
 )
 
+//go:generate mockgen -destination=mocks/mock.go github.com/IBM/sarama ConsumerGroup,ConsumerGroupSession,ConsumerGroupClaim
+
 func NewProducer(addr string) (sarama.SyncProducer, error) {
 	cfg := sarama.NewConfig()
 	cfg.Producer.RequiredAcks = sarama.WaitForAll
 	cfg.Producer.Return.Successes = true
+	// events of one aggregate must keep their order, so they are keyed by its id
+	cfg.Producer.Partitioner = sarama.NewHashPartitioner
 	producer, err := sarama.NewSyncProducer([]string{fmt.Sprintf(addr)}, cfg)
 	if err != nil {
 		return nil, errors.Wrap(err, "kafka.NewProducer producer creation error")
@@ -24,3 +35,17 @@ func NewProducer(addr string) (sarama.SyncProducer, error) {
 
 	return producer, nil
 }
+
+func NewConsumerGroup(addr string, group string) (sarama.ConsumerGroup, error) {
+	cfg := sarama.NewConfig()
+	cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	cfg.Consumer.Return.Errors = true
+	// sticky assignment keeps most partitions on their consumers during rebalance
+	cfg.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategySticky()}
+	consumerGroup, err := sarama.NewConsumerGroup([]string{addr}, group, cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka.NewConsumerGroup consumer group creation error")
+	}
+
+	return consumerGroup, nil
+}
@@ -0,0 +1,346 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/IBM/sarama (interfaces: ConsumerGroup,ConsumerGroupSession,ConsumerGroupClaim)
+
+// Package mock_sarama is a generated GoMock package.
+package mock_sarama
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sarama "github.com/IBM/sarama"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockConsumerGroup is a mock of ConsumerGroup interface.
+type MockConsumerGroup struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumerGroupMockRecorder
+}
+
+// MockConsumerGroupMockRecorder is the mock recorder for MockConsumerGroup.
+type MockConsumerGroupMockRecorder struct {
+	mock *MockConsumerGroup
+}
+
+// NewMockConsumerGroup creates a new mock instance.
+func NewMockConsumerGroup(ctrl *gomock.Controller) *MockConsumerGroup {
+	mock := &MockConsumerGroup{ctrl: ctrl}
+	mock.recorder = &MockConsumerGroupMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsumerGroup) EXPECT() *MockConsumerGroupMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockConsumerGroup) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockConsumerGroupMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockConsumerGroup)(nil).Close))
+}
+
+// Consume mocks base method.
+func (m *MockConsumerGroup) Consume(arg0 context.Context, arg1 []string, arg2 sarama.ConsumerGroupHandler) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockConsumerGroupMockRecorder) Consume(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockConsumerGroup)(nil).Consume), arg0, arg1, arg2)
+}
+
+// Errors mocks base method.
+func (m *MockConsumerGroup) Errors() <-chan error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Errors")
+	ret0, _ := ret[0].(<-chan error)
+	return ret0
+}
+
+// Errors indicates an expected call of Errors.
+func (mr *MockConsumerGroupMockRecorder) Errors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Errors", reflect.TypeOf((*MockConsumerGroup)(nil).Errors))
+}
+
+// Pause mocks base method.
+func (m *MockConsumerGroup) Pause(arg0 map[string][]int32) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Pause", arg0)
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockConsumerGroupMockRecorder) Pause(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockConsumerGroup)(nil).Pause), arg0)
+}
+
+// PauseAll mocks base method.
+func (m *MockConsumerGroup) PauseAll() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PauseAll")
+}
+
+// PauseAll indicates an expected call of PauseAll.
+func (mr *MockConsumerGroupMockRecorder) PauseAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseAll", reflect.TypeOf((*MockConsumerGroup)(nil).PauseAll))
+}
+
+// Resume mocks base method.
+func (m *MockConsumerGroup) Resume(arg0 map[string][]int32) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Resume", arg0)
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockConsumerGroupMockRecorder) Resume(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockConsumerGroup)(nil).Resume), arg0)
+}
+
+// ResumeAll mocks base method.
+func (m *MockConsumerGroup) ResumeAll() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResumeAll")
+}
+
+// ResumeAll indicates an expected call of ResumeAll.
+func (mr *MockConsumerGroupMockRecorder) ResumeAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeAll", reflect.TypeOf((*MockConsumerGroup)(nil).ResumeAll))
+}
+
+// MockConsumerGroupSession is a mock of ConsumerGroupSession interface.
+type MockConsumerGroupSession struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumerGroupSessionMockRecorder
+}
+
+// MockConsumerGroupSessionMockRecorder is the mock recorder for MockConsumerGroupSession.
+type MockConsumerGroupSessionMockRecorder struct {
+	mock *MockConsumerGroupSession
+}
+
+// NewMockConsumerGroupSession creates a new mock instance.
+func NewMockConsumerGroupSession(ctrl *gomock.Controller) *MockConsumerGroupSession {
+	mock := &MockConsumerGroupSession{ctrl: ctrl}
+	mock.recorder = &MockConsumerGroupSessionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsumerGroupSession) EXPECT() *MockConsumerGroupSessionMockRecorder {
+	return m.recorder
+}
+
+// Claims mocks base method.
+func (m *MockConsumerGroupSession) Claims() map[string][]int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claims")
+	ret0, _ := ret[0].(map[string][]int32)
+	return ret0
+}
+
+// Claims indicates an expected call of Claims.
+func (mr *MockConsumerGroupSessionMockRecorder) Claims() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claims", reflect.TypeOf((*MockConsumerGroupSession)(nil).Claims))
+}
+
+// Commit mocks base method.
+func (m *MockConsumerGroupSession) Commit() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Commit")
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockConsumerGroupSessionMockRecorder) Commit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockConsumerGroupSession)(nil).Commit))
+}
+
+// Context mocks base method.
+func (m *MockConsumerGroupSession) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockConsumerGroupSessionMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockConsumerGroupSession)(nil).Context))
+}
+
+// GenerationID mocks base method.
+func (m *MockConsumerGroupSession) GenerationID() int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerationID")
+	ret0, _ := ret[0].(int32)
+	return ret0
+}
+
+// GenerationID indicates an expected call of GenerationID.
+func (mr *MockConsumerGroupSessionMockRecorder) GenerationID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerationID", reflect.TypeOf((*MockConsumerGroupSession)(nil).GenerationID))
+}
+
+// MarkMessage mocks base method.
+func (m *MockConsumerGroupSession) MarkMessage(arg0 *sarama.ConsumerMessage, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "MarkMessage", arg0, arg1)
+}
+
+// MarkMessage indicates an expected call of MarkMessage.
+func (mr *MockConsumerGroupSessionMockRecorder) MarkMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMessage", reflect.TypeOf((*MockConsumerGroupSession)(nil).MarkMessage), arg0, arg1)
+}
+
+// MarkOffset mocks base method.
+func (m *MockConsumerGroupSession) MarkOffset(arg0 string, arg1 int32, arg2 int64, arg3 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "MarkOffset", arg0, arg1, arg2, arg3)
+}
+
+// MarkOffset indicates an expected call of MarkOffset.
+func (mr *MockConsumerGroupSessionMockRecorder) MarkOffset(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOffset", reflect.TypeOf((*MockConsumerGroupSession)(nil).MarkOffset), arg0, arg1, arg2, arg3)
+}
+
+// MemberID mocks base method.
+func (m *MockConsumerGroupSession) MemberID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MemberID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// MemberID indicates an expected call of MemberID.
+func (mr *MockConsumerGroupSessionMockRecorder) MemberID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MemberID", reflect.TypeOf((*MockConsumerGroupSession)(nil).MemberID))
+}
+
+// ResetOffset mocks base method.
+func (m *MockConsumerGroupSession) ResetOffset(arg0 string, arg1 int32, arg2 int64, arg3 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResetOffset", arg0, arg1, arg2, arg3)
+}
+
+// ResetOffset indicates an expected call of ResetOffset.
+func (mr *MockConsumerGroupSessionMockRecorder) ResetOffset(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetOffset", reflect.TypeOf((*MockConsumerGroupSession)(nil).ResetOffset), arg0, arg1, arg2, arg3)
+}
+
+// MockConsumerGroupClaim is a mock of ConsumerGroupClaim interface.
+type MockConsumerGroupClaim struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumerGroupClaimMockRecorder
+}
+
+// MockConsumerGroupClaimMockRecorder is the mock recorder for MockConsumerGroupClaim.
+type MockConsumerGroupClaimMockRecorder struct {
+	mock *MockConsumerGroupClaim
+}
+
+// NewMockConsumerGroupClaim creates a new mock instance.
+func NewMockConsumerGroupClaim(ctrl *gomock.Controller) *MockConsumerGroupClaim {
+	mock := &MockConsumerGroupClaim{ctrl: ctrl}
+	mock.recorder = &MockConsumerGroupClaimMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsumerGroupClaim) EXPECT() *MockConsumerGroupClaimMockRecorder {
+	return m.recorder
+}
+
+// HighWaterMarkOffset mocks base method.
+func (m *MockConsumerGroupClaim) HighWaterMarkOffset() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HighWaterMarkOffset")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// HighWaterMarkOffset indicates an expected call of HighWaterMarkOffset.
+func (mr *MockConsumerGroupClaimMockRecorder) HighWaterMarkOffset() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HighWaterMarkOffset", reflect.TypeOf((*MockConsumerGroupClaim)(nil).HighWaterMarkOffset))
+}
+
+// InitialOffset mocks base method.
+func (m *MockConsumerGroupClaim) InitialOffset() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitialOffset")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// InitialOffset indicates an expected call of InitialOffset.
+func (mr *MockConsumerGroupClaimMockRecorder) InitialOffset() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitialOffset", reflect.TypeOf((*MockConsumerGroupClaim)(nil).InitialOffset))
+}
+
+// Messages mocks base method.
+func (m *MockConsumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Messages")
+	ret0, _ := ret[0].(<-chan *sarama.ConsumerMessage)
+	return ret0
+}
+
+// Messages indicates an expected call of Messages.
+func (mr *MockConsumerGroupClaimMockRecorder) Messages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Messages", reflect.TypeOf((*MockConsumerGroupClaim)(nil).Messages))
+}
+
+// Partition mocks base method.
+func (m *MockConsumerGroupClaim) Partition() int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Partition")
+	ret0, _ := ret[0].(int32)
+	return ret0
+}
+
+// Partition indicates an expected call of Partition.
+func (mr *MockConsumerGroupClaimMockRecorder) Partition() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Partition", reflect.TypeOf((*MockConsumerGroupClaim)(nil).Partition))
+}
+
+// Topic mocks base method.
+func (m *MockConsumerGroupClaim) Topic() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Topic")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Topic indicates an expected call of Topic.
+func (mr *MockConsumerGroupClaimMockRecorder) Topic() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Topic", reflect.TypeOf((*MockConsumerGroupClaim)(nil).Topic))
+}
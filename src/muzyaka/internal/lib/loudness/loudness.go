@@ -0,0 +1,217 @@
+package loudness
+
+import (
+	"math"
+	"src/internal/lib/audio"
+	"src/internal/models"
+)
+
+const (
+	blockLength  = 0.4
+	blockStep    = 0.1
+	absoluteGate = -70
+	relativeGate = -10
+)
+
+// Measure returns integrated loudness and true peak following EBU R128 and
+// ITU-R BS.1770-4. Every channel has the weight of a front channel. The
+// loudness of a track too short or too quiet to measure is MinLoudness.
+func Measure(p *audio.PCM) *models.Loudness {
+	res := &models.Loudness{Integrated: models.MinLoudness, TruePeak: models.MinLoudness}
+	if p.Len() == 0 || p.SampleRate <= 0 {
+		return res
+	}
+
+	if integrated, ok := integrated(p); ok {
+		res.Integrated = round(max(integrated, models.MinLoudness))
+	}
+
+	if peak := truePeak(p); peak > 0 {
+		res.TruePeak = round(max(20*math.Log10(peak), models.MinLoudness))
+	}
+
+	return res
+}
+
+// Combine returns loudness of tracks played one after another, integrated
+// loudness is the power mean of tracks weighted by their duration. It
+// approximates the album gain of ReplayGain 2.0, which gates blocks of all
+// tracks together, so quiet passages gated out of one track may count for
+// the album. The results differ little unless loudness of tracks differs a lot.
+func Combine(tracks []*models.Loudness, durations []float64) *models.Loudness {
+	res := &models.Loudness{Integrated: models.MinLoudness, TruePeak: models.MinLoudness}
+
+	var power, total float64
+	for i, v := range tracks {
+		power += durations[i] * math.Pow(10, v.Integrated/10)
+		total += durations[i]
+		res.TruePeak = max(res.TruePeak, v.TruePeak)
+	}
+
+	if total > 0 {
+		res.Integrated = round(max(10*math.Log10(power/total), models.MinLoudness))
+	}
+
+	return res
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// blockLoudness converts mean square of a block summed over channels
+func blockLoudness(power float64) float64 {
+	return -0.691 + 10*math.Log10(power)
+}
+
+func integrated(p *audio.PCM) (float64, bool) {
+	length := int(blockLength * float64(p.SampleRate))
+	step := int(blockStep * float64(p.SampleRate))
+	if length == 0 || p.Len() < length {
+		return 0, false
+	}
+
+	// power of every step, so overlapping blocks are summed from 4 steps
+	steps := make([]float64, p.Len()/step)
+	filter := newKWeighting(float64(p.SampleRate))
+	for _, channel := range p.Channels {
+		filter.reset()
+		for i, v := range channel[:len(steps)*step] {
+			y := filter.process(float64(v))
+			steps[i/step] += y * y
+		}
+	}
+
+	stepsPerBlock := length / step
+	blocks := make([]float64, 0, len(steps))
+	for i := 0; i+stepsPerBlock <= len(steps); i++ {
+		var power float64
+		for _, v := range steps[i : i+stepsPerBlock] {
+			power += v
+		}
+		blocks = append(blocks, power/float64(stepsPerBlock*step))
+	}
+
+	gated := func(gate float64) (float64, bool) {
+		var sum float64
+		var n int
+		for _, v := range blocks {
+			if v > 0 && blockLoudness(v) > gate {
+				sum += v
+				n++
+			}
+		}
+
+		if n == 0 {
+			return 0, false
+		}
+		return sum / float64(n), true
+	}
+
+	power, ok := gated(absoluteGate)
+	if !ok {
+		return 0, false
+	}
+
+	power, ok = gated(blockLoudness(power) + relativeGate)
+	if !ok {
+		return 0, false
+	}
+
+	return blockLoudness(power), true
+}
+
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	z1, z2             float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.z1
+	f.z1 = f.b1*x - f.a1*y + f.z2
+	f.z2 = f.b2*x - f.a2*y
+	return y
+}
+
+type kWeighting struct {
+	shelf, highPass biquad
+}
+
+// newKWeighting makes the pre-filter and the RLB filter of BS.1770 for any
+// sample rate, at 48 kHz they match the coefficients of the standard
+func newKWeighting(rate float64) *kWeighting {
+	res := &kWeighting{}
+
+	f0, gain, q := 1681.974450955533, 3.999843853973347, 0.7071752369554196
+	k := math.Tan(math.Pi * f0 / rate)
+	vh := math.Pow(10, gain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	res.shelf = biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	f0, q = 38.13547087602444, 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / rate)
+	a0 = 1 + k/q + k*k
+	res.highPass = biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	return res
+}
+
+func (f *kWeighting) reset() {
+	f.shelf.z1, f.shelf.z2 = 0, 0
+	f.highPass.z1, f.highPass.z2 = 0, 0
+}
+
+func (f *kWeighting) process(x float64) float64 {
+	return f.highPass.process(f.shelf.process(x))
+}
+
+// interpolation is the 4 phase FIR filter of BS.1770-4 Annex 2
+var interpolation = [4][12]float64{
+	{0.0017089843750, 0.0109863281250, -0.0196533203125, 0.0332031250000, -0.0594482421875, 0.1373291015625,
+		0.9721679687500, -0.1022949218750, 0.0476074218750, -0.0266113281250, 0.0148925781250, -0.0083007812500},
+	{-0.0291748046875, 0.0292968750000, -0.0517578125000, 0.0891113281250, -0.1665039062500, 0.4650878906250,
+		0.7797851562500, -0.2003173828125, 0.1015625000000, -0.0582275390625, 0.0330810546875, -0.0189208984375},
+	{-0.0189208984375, 0.0330810546875, -0.0582275390625, 0.1015625000000, -0.2003173828125, 0.7797851562500,
+		0.4650878906250, -0.1665039062500, 0.0891113281250, -0.0517578125000, 0.0292968750000, -0.0291748046875},
+	{-0.0083007812500, 0.0148925781250, -0.0266113281250, 0.0476074218750, -0.1022949218750, 0.9721679687500,
+		0.1373291015625, -0.0594482421875, 0.0332031250000, -0.0196533203125, 0.0109863281250, 0.0017089843750},
+}
+
+// truePeak oversamples signals below 96 kHz 4 times and returns the peak
+// linear amplitude
+func truePeak(p *audio.PCM) float64 {
+	var peak float64
+	for _, channel := range p.Channels {
+		for i, v := range channel {
+			peak = max(peak, math.Abs(float64(v)))
+			if p.SampleRate >= 96000 {
+				continue
+			}
+
+			for _, phase := range interpolation {
+				var y float64
+				for j, h := range phase {
+					if i >= j {
+						y += h * float64(channel[i-j])
+					}
+				}
+				peak = max(peak, math.Abs(y))
+			}
+		}
+	}
+
+	return peak
+}
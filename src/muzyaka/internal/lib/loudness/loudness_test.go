@@ -0,0 +1,89 @@
+package loudness
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"src/internal/lib/audio"
+	"src/internal/models"
+	"testing"
+)
+
+const testRate = 48000
+
+// sine appends seconds of 1 kHz sine with the peak level in dBFS
+func sine(samples []float32, level float64, seconds float64) []float32 {
+	amplitude := math.Pow(10, level/20)
+	for i := 0; i < int(seconds*testRate); i++ {
+		samples = append(samples, float32(amplitude*math.Sin(2*math.Pi*1000*float64(i)/testRate)))
+	}
+	return samples
+}
+
+func stereo(samples []float32) *audio.PCM {
+	return &audio.PCM{SampleRate: testRate, Channels: [][]float32{samples, samples}}
+}
+
+func TestMeasure(t *testing.T) {
+	testTable := []struct {
+		name               string
+		pcm                *audio.PCM
+		expectedIntegrated float64
+		expectedTruePeak   float64
+	}{
+		{
+			name:               "Stereo sine at -23 dBFS",
+			pcm:                stereo(sine(nil, -23, 20)),
+			expectedIntegrated: -23,
+			expectedTruePeak:   -23,
+		},
+		{
+			name:               "Mono sine at -20 dBFS",
+			pcm:                &audio.PCM{SampleRate: testRate, Channels: [][]float32{sine(nil, -20, 20)}},
+			expectedIntegrated: -23,
+			expectedTruePeak:   -20,
+		},
+		{
+			name:               "Quiet parts are gated",
+			pcm:                stereo(sine(sine(sine(nil, -36, 10), -23, 60), -36, 10)),
+			expectedIntegrated: -23,
+			expectedTruePeak:   -23,
+		},
+		{
+			name:               "Silence",
+			pcm:                stereo(make([]float32, testRate)),
+			expectedIntegrated: models.MinLoudness,
+			expectedTruePeak:   models.MinLoudness,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			res := Measure(tc.pcm)
+			assert.InDelta(t, tc.expectedIntegrated, res.Integrated, 0.1)
+			assert.InDelta(t, tc.expectedTruePeak, res.TruePeak, 0.1)
+		})
+	}
+}
+
+func TestMeasure_TruePeak(t *testing.T) {
+	// samples of a quarter of the sample rate are at 45 degrees, the signal
+	// peaks between them
+	samples := make([]float32, testRate)
+	for i := range samples {
+		samples[i] = float32(math.Sin(math.Pi/2*float64(i) + math.Pi/4))
+	}
+
+	res := Measure(&audio.PCM{SampleRate: testRate, Channels: [][]float32{samples}})
+	assert.Greater(t, res.TruePeak, -1.0)
+}
+
+func TestCombine(t *testing.T) {
+	tracks := []*models.Loudness{
+		{Integrated: -10, TruePeak: -1},
+		{Integrated: -20, TruePeak: -3},
+	}
+
+	assert.Equal(t, &models.Loudness{Integrated: -12.6, TruePeak: -1}, Combine(tracks, []float64{1, 1}))
+	assert.Equal(t, &models.Loudness{Integrated: -10.37, TruePeak: -1}, Combine(tracks, []float64{10, 1}))
+	assert.Equal(t, &models.Loudness{Integrated: models.MinLoudness, TruePeak: models.MinLoudness}, Combine(nil, nil))
+}
@@ -0,0 +1,162 @@
+package lrc
+
+import (
+	"bufio"
+	"github.com/pkg/errors"
+	"regexp"
+	"sort"
+	"src/internal/models"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// timeTag is [mm:ss], [mm:ss.xx] or [mm:ss.xxx], some editors write [mm:ss:xx]
+var timeTag = regexp.MustCompile(`^\[(\d{1,3}):(\d{2})(?:[.:](\d{1,3}))?]`)
+
+// idTag is a metadata tag such as [ar:Artist] or [offset:+250]
+var idTag = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)]$`)
+
+// wordTag is a word timestamp of the enhanced format, <mm:ss.xx>
+var wordTag = regexp.MustCompile(`<\d{1,3}:\d{2}(?:[.:]\d{1,3})?>`)
+
+// Parse reads lyrics in LRC format, or plain lyrics if there are no
+// timestamps at all. Synced lines are sorted by offset, a line with several
+// timestamps is repeated for each of them. Malformed lyrics return
+// models.ErrInvalidParameter.
+func Parse(text string) ([]*models.LyricsLine, bool, error) {
+	lines, synced, err := parse(text)
+	if err != nil {
+		return nil, false, errors.Wrap(models.ErrInvalidParameter, err.Error())
+	}
+
+	return lines, synced, nil
+}
+
+func parse(text string) ([]*models.LyricsLine, bool, error) {
+	if !utf8.ValidString(text) {
+		return nil, false, errors.New("lyrics are not valid UTF-8")
+	}
+
+	var synced, plain []*models.LyricsLine
+	var offset int64
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 4096), models.MaxLyricsLength)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+
+		var offsets []int64
+		for {
+			m := timeTag.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+
+			ms, err := toMilliseconds(m[1], m[2], m[3])
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "line %d", n)
+			}
+
+			offsets = append(offsets, ms)
+			line = line[len(m[0]):]
+		}
+
+		if len(offsets) == 0 {
+			if m := idTag.FindStringSubmatch(line); m != nil {
+				if strings.ToLower(m[1]) == "offset" {
+					v, err := strconv.ParseInt(strings.TrimSpace(m[2]), 10, 64)
+					if err != nil {
+						return nil, false, errors.Errorf("line %d: invalid offset", n)
+					}
+					offset = v
+				}
+				continue
+			}
+		}
+
+		line = strings.TrimSpace(wordTag.ReplaceAllString(line, ""))
+		if utf8.RuneCountInString(line) > models.MaxLyricsLineLength {
+			return nil, false, errors.Errorf("line %d is too long", n)
+		}
+
+		if len(offsets) == 0 {
+			plain = append(plain, &models.LyricsLine{Text: line})
+			continue
+		}
+
+		for _, v := range offsets {
+			synced = append(synced, &models.LyricsLine{OffsetMs: v, Text: line})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if len(synced) == 0 {
+		plain = trimEmpty(plain)
+		if len(plain) == 0 {
+			return nil, false, errors.New("lyrics are empty")
+		}
+		return plain, false, nil
+	}
+
+	for _, v := range plain {
+		if v.Text != "" {
+			return nil, false, errors.Errorf("line %q has no timestamp", v.Text)
+		}
+	}
+
+	// a positive offset shows lines earlier
+	for _, v := range synced {
+		v.OffsetMs -= offset
+		if v.OffsetMs < 0 {
+			v.OffsetMs = 0
+		}
+	}
+
+	sort.SliceStable(synced, func(i, j int) bool {
+		return synced[i].OffsetMs < synced[j].OffsetMs
+	})
+
+	return synced, true, nil
+}
+
+func toMilliseconds(minutes string, seconds string, fraction string) (int64, error) {
+	m, _ := strconv.ParseInt(minutes, 10, 64)
+	s, _ := strconv.ParseInt(seconds, 10, 64)
+	if s >= 60 {
+		return 0, errors.New("invalid timestamp")
+	}
+
+	var ms int64
+	if fraction != "" {
+		// .5 is 500ms, .05 is 50ms and .005 is 5ms
+		f, _ := strconv.ParseInt(fraction, 10, 64)
+		for i := len(fraction); i < 3; i++ {
+			f *= 10
+		}
+		ms = f
+	}
+
+	return (m*60+s)*1000 + ms, nil
+}
+
+// trimEmpty drops empty lines around the lyrics and keeps one empty line
+// between verses
+func trimEmpty(lines []*models.LyricsLine) []*models.LyricsLine {
+	var res []*models.LyricsLine
+	for _, v := range lines {
+		if v.Text == "" && (len(res) == 0 || res[len(res)-1].Text == "") {
+			continue
+		}
+		res = append(res, v)
+	}
+
+	if len(res) > 0 && res[len(res)-1].Text == "" {
+		res = res[:len(res)-1]
+	}
+
+	return res
+}
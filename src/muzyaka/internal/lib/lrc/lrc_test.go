@@ -0,0 +1,59 @@
+package lrc
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"src/internal/models"
+	"testing"
+)
+
+func TestParseSynced(t *testing.T) {
+	text := "\ufeff[ar:Artist]\n[ti:Title]\n[offset:+250]\n\n" +
+		"[00:12.00]First <00:12.50>line\n" +
+		"[00:05.5][01:02.345]Chorus\n" +
+		"[00:20:07]\n"
+
+	lines, synced, err := Parse(text)
+	require.NoError(t, err)
+	assert.True(t, synced)
+	assert.Equal(t, []*models.LyricsLine{
+		{OffsetMs: 5250, Text: "Chorus"},
+		{OffsetMs: 11750, Text: "First line"},
+		{OffsetMs: 19820, Text: ""},
+		{OffsetMs: 62095, Text: "Chorus"},
+	}, lines)
+}
+
+func TestParsePlain(t *testing.T) {
+	lines, synced, err := Parse("\n\nFirst verse\nstill first\n\n\n[Chorus]\nla la\n\n")
+	require.NoError(t, err)
+	assert.False(t, synced)
+	assert.Equal(t, []*models.LyricsLine{
+		{Text: "First verse"},
+		{Text: "still first"},
+		{Text: ""},
+		{Text: "[Chorus]"},
+		{Text: "la la"},
+	}, lines)
+}
+
+func TestParseInvalid(t *testing.T) {
+	testTable := []struct {
+		name string
+		text string
+	}{
+		{name: "Empty", text: "\n[ar:Artist]\n"},
+		{name: "Line without timestamp", text: "[00:01.00]one\ntwo"},
+		{name: "Invalid seconds", text: "[00:61.00]one"},
+		{name: "Invalid offset", text: "[offset:soon]\n[00:01.00]one"},
+		{name: "Invalid UTF-8", text: "[00:01.00]\xff"},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := Parse(tc.text)
+			assert.True(t, errors.Is(err, models.ErrInvalidParameter))
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package playlistfmt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/pkg/errors"
+	"path"
+	"src/internal/models"
+	"strconv"
+	"strings"
+)
+
+const xspfNamespace = "http://xspf.org/ns/0/"
+
+var contentTypes = map[string]string{
+	models.PlaylistFormatM3U8: "audio/x-mpegurl; charset=utf-8",
+	models.PlaylistFormatXSPF: "application/xspf+xml",
+	models.PlaylistFormatJSPF: "application/json",
+}
+
+func IsSupported(format string) bool {
+	_, ok := contentTypes[format]
+	return ok
+}
+
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+func Encode(format string, doc *models.PlaylistDocument) ([]byte, error) {
+	switch format {
+	case models.PlaylistFormatM3U8:
+		return encodeM3U8(doc), nil
+	case models.PlaylistFormatXSPF:
+		return encodeXSPF(doc)
+	case models.PlaylistFormatJSPF:
+		return encodeJSPF(doc)
+	}
+
+	return nil, models.ErrInvalidParameter
+}
+
+// Decode parses a playlist file, malformed files return models.ErrInvalidParameter
+func Decode(format string, data []byte) (*models.PlaylistDocument, error) {
+	var doc *models.PlaylistDocument
+	var err error
+
+	switch format {
+	case models.PlaylistFormatM3U8:
+		doc, err = decodeM3U8(data)
+	case models.PlaylistFormatXSPF:
+		doc, err = decodeXSPF(data)
+	case models.PlaylistFormatJSPF:
+		doc, err = decodeJSPF(data)
+	default:
+		return nil, models.ErrInvalidParameter
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(models.ErrInvalidParameter, err.Error())
+	}
+
+	return doc, nil
+}
+
+func encodeM3U8(doc *models.PlaylistDocument) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("#EXTM3U\n")
+	if doc.Name != "" {
+		fmt.Fprintf(&buf, "#PLAYLIST:%s\n", oneLine(doc.Name))
+	}
+
+	for _, e := range doc.Entries {
+		duration := e.Duration
+		if duration <= 0 {
+			duration = -1
+		}
+
+		title := oneLine(e.Title)
+		if e.Musician != "" {
+			title = oneLine(e.Musician) + " - " + title
+		}
+
+		fmt.Fprintf(&buf, "#EXTINF:%d,%s\n%s\n", duration, title, oneLine(e.Location))
+	}
+
+	return buf.Bytes()
+}
+
+func decodeM3U8(data []byte) (*models.PlaylistDocument, error) {
+	doc := &models.PlaylistDocument{}
+	var pending *models.PlaylistEntry
+
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || line == "#EXTM3U":
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			doc.Name = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			entry, err := parseExtInf(strings.TrimPrefix(line, "#EXTINF:"))
+			if err != nil {
+				return nil, err
+			}
+			pending = entry
+		case strings.HasPrefix(line, "#"):
+		default:
+			if pending == nil {
+				name := path.Base(line)
+				pending = &models.PlaylistEntry{Title: strings.TrimSuffix(name, path.Ext(name))}
+			}
+			pending.Location = line
+			doc.Entries = append(doc.Entries, pending)
+			pending = nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// parseExtInf parses "duration,Musician - Title", attributes before the comma are ignored
+func parseExtInf(s string) (*models.PlaylistEntry, error) {
+	info, title, found := strings.Cut(s, ",")
+	if !found {
+		return nil, errors.New("malformed #EXTINF line")
+	}
+
+	fields := strings.Fields(info)
+	if len(fields) == 0 {
+		return nil, errors.New("malformed #EXTINF line")
+	}
+
+	duration, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed #EXTINF duration")
+	}
+
+	entry := &models.PlaylistEntry{Title: strings.TrimSpace(title)}
+	if duration > 0 {
+		entry.Duration = int(duration + 0.5)
+	}
+
+	if musician, name, found := strings.Cut(entry.Title, " - "); found {
+		entry.Musician = strings.TrimSpace(musician)
+		entry.Title = strings.TrimSpace(name)
+	}
+
+	return entry, nil
+}
+
+type xspfPlaylist struct {
+	XMLName    xml.Name    `xml:"playlist"`
+	Version    string      `xml:"version,attr"`
+	Namespace  string      `xml:"xmlns,attr,omitempty"`
+	Title      string      `xml:"title,omitempty"`
+	Annotation string      `xml:"annotation,omitempty"`
+	Tracks     []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location []string `xml:"location,omitempty"`
+	Title    string   `xml:"title,omitempty"`
+	Creator  string   `xml:"creator,omitempty"`
+	// Duration is in milliseconds
+	Duration int `xml:"duration,omitempty"`
+}
+
+func encodeXSPF(doc *models.PlaylistDocument) ([]byte, error) {
+	playlist := xspfPlaylist{
+		Version:    "1",
+		Namespace:  xspfNamespace,
+		Title:      doc.Name,
+		Annotation: doc.Description,
+		Tracks:     make([]xspfTrack, 0, len(doc.Entries)),
+	}
+
+	for _, e := range doc.Entries {
+		track := xspfTrack{Title: e.Title, Creator: e.Musician, Duration: e.Duration * 1000}
+		if e.Location != "" {
+			track.Location = []string{e.Location}
+		}
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+
+	data, err := xml.MarshalIndent(playlist, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+func decodeXSPF(data []byte) (*models.PlaylistDocument, error) {
+	var playlist xspfPlaylist
+	if err := xml.Unmarshal(data, &playlist); err != nil {
+		return nil, err
+	}
+
+	doc := &models.PlaylistDocument{
+		Name:        strings.TrimSpace(playlist.Title),
+		Description: strings.TrimSpace(playlist.Annotation),
+	}
+
+	for _, t := range playlist.Tracks {
+		doc.Entries = append(doc.Entries, toEntry(t.Title, t.Creator, t.Duration, t.Location))
+	}
+
+	return doc, nil
+}
+
+type jspfDocument struct {
+	Playlist jspfPlaylist `json:"playlist"`
+}
+
+type jspfPlaylist struct {
+	Title      string      `json:"title,omitempty"`
+	Annotation string      `json:"annotation,omitempty"`
+	Tracks     []jspfTrack `json:"track"`
+}
+
+type jspfTrack struct {
+	Location []string `json:"location,omitempty"`
+	Title    string   `json:"title,omitempty"`
+	Creator  string   `json:"creator,omitempty"`
+	// Duration is in milliseconds
+	Duration int `json:"duration,omitempty"`
+}
+
+func encodeJSPF(doc *models.PlaylistDocument) ([]byte, error) {
+	res := jspfDocument{Playlist: jspfPlaylist{
+		Title:      doc.Name,
+		Annotation: doc.Description,
+		Tracks:     make([]jspfTrack, 0, len(doc.Entries)),
+	}}
+
+	for _, e := range doc.Entries {
+		track := jspfTrack{Title: e.Title, Creator: e.Musician, Duration: e.Duration * 1000}
+		if e.Location != "" {
+			track.Location = []string{e.Location}
+		}
+		res.Playlist.Tracks = append(res.Playlist.Tracks, track)
+	}
+
+	return json.MarshalIndent(res, "", "  ")
+}
+
+func decodeJSPF(data []byte) (*models.PlaylistDocument, error) {
+	var res jspfDocument
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	doc := &models.PlaylistDocument{
+		Name:        strings.TrimSpace(res.Playlist.Title),
+		Description: strings.TrimSpace(res.Playlist.Annotation),
+	}
+
+	for _, t := range res.Playlist.Tracks {
+		doc.Entries = append(doc.Entries, toEntry(t.Title, t.Creator, t.Duration, t.Location))
+	}
+
+	return doc, nil
+}
+
+func toEntry(title string, creator string, durationMs int, locations []string) *models.PlaylistEntry {
+	entry := &models.PlaylistEntry{
+		Title:    strings.TrimSpace(title),
+		Musician: strings.TrimSpace(creator),
+	}
+
+	if durationMs > 0 {
+		entry.Duration = (durationMs + 500) / 1000
+	}
+
+	if len(locations) > 0 {
+		entry.Location = strings.TrimSpace(locations[0])
+	}
+
+	return entry
+}
+
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
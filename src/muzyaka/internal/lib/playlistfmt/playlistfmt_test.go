@@ -0,0 +1,64 @@
+package playlistfmt
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"src/internal/models"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	doc := &models.PlaylistDocument{
+		Name: "Road trip",
+		Entries: []*models.PlaylistEntry{
+			{Title: "Song", Musician: "Band", Duration: 215, Location: "/api/track/1"},
+			{Title: "Other - Part 2", Musician: "Artist", Location: "/api/track/2"},
+		},
+	}
+
+	for _, format := range []string{models.PlaylistFormatM3U8, models.PlaylistFormatXSPF, models.PlaylistFormatJSPF} {
+		t.Run(format, func(t *testing.T) {
+			data, err := Encode(format, doc)
+			require.NoError(t, err)
+
+			decoded, err := Decode(format, data)
+			require.NoError(t, err)
+			assert.Equal(t, doc, decoded)
+		})
+	}
+}
+
+func TestDecodeM3U8(t *testing.T) {
+	data := []byte("\ufeff#EXTM3U\r\n" +
+		"#EXTINF:183.6 tvg-id=\"x\",Band - Song\r\n" +
+		"http://example.com/song.mp3\r\n" +
+		"music/Untitled.flac\r\n")
+
+	doc, err := Decode(models.PlaylistFormatM3U8, data)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.PlaylistEntry{
+		{Title: "Song", Musician: "Band", Duration: 184, Location: "http://example.com/song.mp3"},
+		{Title: "Untitled", Location: "music/Untitled.flac"},
+	}, doc.Entries)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	testTable := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{name: "Unknown format", format: "pls", data: "[playlist]"},
+		{name: "Bad EXTINF", format: models.PlaylistFormatM3U8, data: "#EXTM3U\n#EXTINF:abc,Song\nsong.mp3\n"},
+		{name: "Bad XML", format: models.PlaylistFormatXSPF, data: "<playlist><trackList>"},
+		{name: "Bad JSON", format: models.PlaylistFormatJSPF, data: "{\"playlist\":"},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decode(tc.format, []byte(tc.data))
+			assert.True(t, errors.Is(err, models.ErrInvalidParameter))
+		})
+	}
+}
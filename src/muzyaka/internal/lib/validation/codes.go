@@ -0,0 +1,53 @@
+package validation
+
+import "strings"
+
+// ValidateUpc checks a 12 digit UPC-A or a 13 digit EAN-13 code and its check digit
+func ValidateUpc(code string) bool {
+	if len(code) != 12 && len(code) != 13 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(code); i++ {
+		c := code[len(code)-1-i]
+		if c < '0' || c > '9' {
+			return false
+		}
+
+		// the check digit has weight 1, then weights alternate 3 and 1
+		digit := int(c - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return sum%10 == 0
+}
+
+// NormalizeIsrc uppercases the code and drops hyphens. ISRC has no check digit,
+// so only the CC-XXX-YY-NNNNN format is checked.
+func NormalizeIsrc(code string) (string, bool) {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if len(code) != 12 {
+		return "", false
+	}
+
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		isLetter := c >= 'A' && c <= 'Z'
+		isDigit := c >= '0' && c <= '9'
+
+		switch {
+		case i < 2 && !isLetter:
+			return "", false
+		case i >= 2 && i < 5 && !isLetter && !isDigit:
+			return "", false
+		case i >= 5 && !isDigit:
+			return "", false
+		}
+	}
+
+	return code, true
+}
@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateUpc(t *testing.T) {
+	assert.True(t, ValidateUpc("036000291452"))
+	assert.True(t, ValidateUpc("4006381333931"))
+
+	assert.False(t, ValidateUpc("036000291453"))
+	assert.False(t, ValidateUpc("4006381333932"))
+	assert.False(t, ValidateUpc("03600029145"))
+	assert.False(t, ValidateUpc("03600029145a"))
+	assert.False(t, ValidateUpc(""))
+}
+
+func TestNormalizeIsrc(t *testing.T) {
+	code, ok := NormalizeIsrc("us-rc1-76-07839")
+	assert.True(t, ok)
+	assert.Equal(t, "USRC17607839", code)
+
+	code, ok = NormalizeIsrc("GBAYE0601498")
+	assert.True(t, ok)
+	assert.Equal(t, "GBAYE0601498", code)
+
+	for _, v := range []string{"", "US-RC1-76-0783", "1SRC17607839", "USR_17607839", "USRC1760783X"} {
+		_, ok = NormalizeIsrc(v)
+		assert.False(t, ok, v)
+	}
+}
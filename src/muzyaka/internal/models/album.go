@@ -1,8 +1,77 @@
 package models
 
+import (
+	"src/internal/lib/validation"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	AlbumSingle      = "single"
+	AlbumEP          = "EP"
+	AlbumAlbum       = "album"
+	AlbumCompilation = "compilation"
+)
+
+// AlbumTypes maps lowercase type names to album types, LP is saved as album
+var AlbumTypes = map[string]string{
+	"single":      AlbumSingle,
+	"ep":          AlbumEP,
+	"album":       AlbumAlbum,
+	"lp":          AlbumAlbum,
+	"compilation": AlbumCompilation,
+}
+
+const MaxLabelLength = 254
+
 type Album struct {
 	Id        uint64
 	Name      string
 	CoverFile []byte
 	Type      string
+	// Genres are inherited by the tracks of the album
+	Genres []string
+	// ReleaseDate is zero if unknown
+	ReleaseDate time.Time
+	Label       string
+	// Upc is a UPC-A or EAN-13 code
+	Upc string
+	// Loudness is nil while any track is not measured, it is read only
+	Loudness *Loudness
+	// Artists starts with the primary artist on read
+	Artists []*Artist
+}
+
+// Normalize checks the type, label, UPC, genres and artists of the album
+func (a *Album) Normalize() error {
+	albumType, ok := AlbumTypes[strings.ToLower(strings.TrimSpace(a.Type))]
+	if !ok {
+		return ErrInvalidParameter
+	}
+	a.Type = albumType
+
+	a.Label = strings.TrimSpace(a.Label)
+	if utf8.RuneCountInString(a.Label) > MaxLabelLength {
+		return ErrInvalidParameter
+	}
+
+	a.Upc = strings.TrimSpace(a.Upc)
+	if a.Upc != "" && !validation.ValidateUpc(a.Upc) {
+		return ErrInvalidParameter
+	}
+
+	genres, err := NormalizeGenres(a.Genres)
+	if err != nil {
+		return err
+	}
+	a.Genres = genres
+
+	artists, err := NormalizeArtists(a.Artists)
+	if err != nil {
+		return err
+	}
+	a.Artists = artists
+
+	return nil
 }
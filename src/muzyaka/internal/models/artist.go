@@ -0,0 +1,64 @@
+package models
+
+const (
+	ArtistPrimary  = "primary"
+	ArtistFeatured = "featured"
+	ArtistRemixer  = "remixer"
+	ArtistProducer = "producer"
+)
+
+var ArtistRoles = map[string]bool{
+	ArtistPrimary:  true,
+	ArtistFeatured: true,
+	ArtistRemixer:  true,
+	ArtistProducer: true,
+}
+
+const MaxArtists = 20
+
+// Artist links a musician to an album or a track. The musician the album
+// belongs to is its primary artist and the only one who can edit it, other
+// artists are listed on their pages once they approve the credit.
+type Artist struct {
+	MusicianId uint64
+	// Name is filled on read
+	Name     string
+	Role     string
+	Approved bool
+}
+
+// ArtistCredit is a credit of a musician on a release of another musician
+type ArtistCredit struct {
+	Id        uint64
+	AlbumId   uint64
+	AlbumName string
+	// TrackId is 0 for album credits
+	TrackId   uint64
+	TrackName string
+	Role      string
+	Approved  bool
+}
+
+// NormalizeArtists checks roles and keeps the first link of every musician.
+// The primary role belongs to the album's own musician and can't be given.
+func NormalizeArtists(artists []*Artist) ([]*Artist, error) {
+	var res []*Artist
+	seen := make(map[uint64]bool, len(artists))
+	for _, v := range artists {
+		if v.MusicianId == 0 || v.Role == ArtistPrimary || !ArtistRoles[v.Role] {
+			return nil, ErrInvalidParameter
+		}
+
+		if seen[v.MusicianId] {
+			continue
+		}
+		seen[v.MusicianId] = true
+		res = append(res, &Artist{MusicianId: v.MusicianId, Role: v.Role})
+	}
+
+	if len(res) > MaxArtists {
+		return nil, ErrInvalidParameter
+	}
+
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+const (
+	ChartPeriodDay   = "day"
+	ChartPeriodWeek  = "week"
+	ChartPeriodMonth = "month"
+)
+
+const (
+	ChartKindTrack    = "track"
+	ChartKindAlbum    = "album"
+	ChartKindMusician = "musician"
+)
+
+const (
+	ChartMovementUp   = "up"
+	ChartMovementDown = "down"
+	ChartMovementSame = "same"
+	ChartMovementNew  = "new"
+)
+
+var ChartPeriods = map[string]time.Duration{
+	ChartPeriodDay:   24 * time.Hour,
+	ChartPeriodWeek:  7 * 24 * time.Hour,
+	ChartPeriodMonth: 30 * 24 * time.Hour,
+}
+
+var ChartKinds = []string{ChartKindTrack, ChartKindAlbum, ChartKindMusician}
+
+// TrackSignals counts what happened to a track during a chart period,
+// GenreIds are the genres whose charts the track counts in
+type TrackSignals struct {
+	TrackId      uint64
+	GenreIds     []uint64
+	AlbumId      uint64
+	MusicianId   uint64
+	Plays        int64
+	Skips        int64
+	Likes        int64
+	PlaylistAdds int64
+}
+
+// Chart is a snapshot of a top, GenreId is nil for global charts
+type Chart struct {
+	Id         uint64
+	Kind       string
+	Period     string
+	GenreId    *uint64
+	ComputedAt time.Time
+	Entries    []*ChartEntry
+}
+
+type ChartEntry struct {
+	Position int
+	ItemId   uint64
+	Name     string
+	Score    float64
+	// PreviousPosition is nil if the item wasn't in the previous snapshot
+	PreviousPosition *int
+}
+
+func (e *ChartEntry) Movement() string {
+	switch {
+	case e.PreviousPosition == nil:
+		return ChartMovementNew
+	case *e.PreviousPosition > e.Position:
+		return ChartMovementUp
+	case *e.PreviousPosition < e.Position:
+		return ChartMovementDown
+	default:
+		return ChartMovementSame
+	}
+}
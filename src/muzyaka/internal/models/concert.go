@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type Concert struct {
+	Id          uint64
+	MusicianId  uint64
+	Title       string
+	Description string
+	Venue       string
+	City        string
+	Latitude    float64
+	Longitude   float64
+	// StartsAt is in the location of TimeZone
+	StartsAt time.Time
+	// TimeZone is an IANA name, e.g. "Europe/Berlin"
+	TimeZone  string
+	TicketUrl string
+	SoldOut   bool
+}
+
+type NearbyConcert struct {
+	Concert    *Concert
+	DistanceKm float64
+}
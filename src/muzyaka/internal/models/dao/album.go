@@ -1,6 +1,10 @@
 package dao
 
-import "src/internal/models"
+import (
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
+)
 
 type Album struct {
 	ID         uint64 `gorm:"column:id"`
@@ -8,6 +12,14 @@ type Album struct {
 	Cover      []byte `gorm:"column:cover_file"`
 	Type       string `gorm:"column:type"`
 	MusicianID uint64 `gorm:"column:musician_id"`
+
+	ReleaseDate *time.Time `gorm:"column:release_date;type:date"`
+	Label       *string    `gorm:"column:label"`
+	Upc         *string    `gorm:"column:upc"`
+
+	// set by media processing only
+	Loudness *float64 `gorm:"column:loudness"`
+	TruePeak *float64 `gorm:"column:true_peak"`
 }
 
 func (Album) TableName() string {
@@ -15,20 +27,59 @@ func (Album) TableName() string {
 }
 
 func ToPostgresAlbum(e *models.Album, musicianId uint64) *Album {
-	return &Album{
+	res := &Album{
 		ID:         e.Id,
 		Name:       e.Name,
 		Cover:      e.CoverFile,
 		Type:       e.Type,
 		MusicianID: musicianId,
 	}
+
+	if !e.ReleaseDate.IsZero() {
+		res.ReleaseDate = &e.ReleaseDate
+	}
+
+	if e.Label != "" {
+		res.Label = &e.Label
+	}
+
+	if e.Upc != "" {
+		res.Upc = &e.Upc
+	}
+
+	return res
 }
 
 func ToModelAlbum(e *Album) *models.Album {
-	return &models.Album{
+	res := &models.Album{
 		Id:        e.ID,
 		Name:      e.Name,
 		CoverFile: e.Cover,
 		Type:      e.Type,
 	}
+
+	if e.ReleaseDate != nil {
+		res.ReleaseDate = *e.ReleaseDate
+	}
+
+	if e.Label != nil {
+		res.Label = *e.Label
+	}
+
+	if e.Upc != nil {
+		res.Upc = *e.Upc
+	}
+
+	res.Loudness = ToModelLoudness(e.Loudness, e.TruePeak)
+
+	return res
+}
+
+func ToAlbumPayload(e *Album) events.AlbumPayload {
+	return events.AlbumPayload{
+		AlbumId:    e.ID,
+		MusicianId: e.MusicianID,
+		Name:       e.Name,
+		Type:       e.Type,
+	}
 }
@@ -0,0 +1,67 @@
+package dao
+
+import "src/internal/models"
+
+type ReleaseArtist struct {
+	ID      uint64 `gorm:"column:id"`
+	AlbumID uint64 `gorm:"column:album_id"`
+	// TrackID is nil for album artists
+	TrackID    *uint64 `gorm:"column:track_id"`
+	MusicianID uint64  `gorm:"column:musician_id"`
+	Role       string  `gorm:"column:role"`
+	Approved   bool    `gorm:"column:approved"`
+	Declined   bool    `gorm:"column:declined"`
+	Position   int     `gorm:"column:position"`
+}
+
+func (ReleaseArtist) TableName() string {
+	return "release_artists"
+}
+
+// ArtistRow is a release artist joined with the musician name
+type ArtistRow struct {
+	OwnerID    uint64 `gorm:"column:owner_id"`
+	MusicianID uint64 `gorm:"column:musician_id"`
+	Name       string `gorm:"column:name"`
+	Role       string `gorm:"column:role"`
+	Approved   bool   `gorm:"column:approved"`
+}
+
+// ArtistCredit is a release artist joined with album and track names
+type ArtistCredit struct {
+	ID        uint64  `gorm:"column:id"`
+	AlbumID   uint64  `gorm:"column:album_id"`
+	AlbumName string  `gorm:"column:album_name"`
+	TrackID   *uint64 `gorm:"column:track_id"`
+	TrackName *string `gorm:"column:track_name"`
+	Role      string  `gorm:"column:role"`
+	Approved  bool    `gorm:"column:approved"`
+}
+
+func ToModelArtist(e *ArtistRow) *models.Artist {
+	return &models.Artist{
+		MusicianId: e.MusicianID,
+		Name:       e.Name,
+		Role:       e.Role,
+		Approved:   e.Approved,
+	}
+}
+
+func ToModelArtistCredit(e *ArtistCredit) *models.ArtistCredit {
+	res := &models.ArtistCredit{
+		Id:        e.ID,
+		AlbumId:   e.AlbumID,
+		AlbumName: e.AlbumName,
+		Role:      e.Role,
+		Approved:  e.Approved,
+	}
+
+	if e.TrackID != nil {
+		res.TrackId = *e.TrackID
+	}
+	if e.TrackName != nil {
+		res.TrackName = *e.TrackName
+	}
+
+	return res
+}
@@ -0,0 +1,50 @@
+package dao
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type ChartSnapshot struct {
+	ID         uint64    `gorm:"column:id"`
+	Kind       string    `gorm:"column:kind"`
+	Period     string    `gorm:"column:period"`
+	GenreId    *uint64   `gorm:"column:genre_id"`
+	ComputedAt time.Time `gorm:"column:computed_at"`
+}
+
+func (ChartSnapshot) TableName() string {
+	return "chart_snapshots"
+}
+
+type ChartEntry struct {
+	SnapshotId       uint64  `gorm:"column:snapshot_id;primaryKey"`
+	Position         int     `gorm:"column:position;primaryKey"`
+	ItemId           uint64  `gorm:"column:item_id"`
+	Score            float64 `gorm:"column:score"`
+	PreviousPosition *int    `gorm:"column:previous_position"`
+}
+
+func (ChartEntry) TableName() string {
+	return "chart_entries"
+}
+
+func ToPostgresChartSnapshot(chart *models.Chart) *ChartSnapshot {
+	return &ChartSnapshot{
+		ID:         chart.Id,
+		Kind:       chart.Kind,
+		Period:     chart.Period,
+		GenreId:    chart.GenreId,
+		ComputedAt: chart.ComputedAt,
+	}
+}
+
+func ToModelChart(snapshot *ChartSnapshot) *models.Chart {
+	return &models.Chart{
+		Id:         snapshot.ID,
+		Kind:       snapshot.Kind,
+		Period:     snapshot.Period,
+		GenreId:    snapshot.GenreId,
+		ComputedAt: snapshot.ComputedAt,
+	}
+}
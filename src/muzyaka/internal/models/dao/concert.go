@@ -0,0 +1,95 @@
+package dao
+
+import (
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
+)
+
+type Concert struct {
+	ID          uint64    `gorm:"column:id"`
+	MusicianID  uint64    `gorm:"column:musician_id"`
+	Title       string    `gorm:"column:title"`
+	Description string    `gorm:"column:description"`
+	Venue       string    `gorm:"column:venue"`
+	City        string    `gorm:"column:city"`
+	Latitude    float64   `gorm:"column:latitude"`
+	Longitude   float64   `gorm:"column:longitude"`
+	StartsAt    time.Time `gorm:"column:starts_at"`
+	TimeZone    string    `gorm:"column:time_zone"`
+	TicketUrl   *string   `gorm:"column:ticket_url"`
+	SoldOut     bool      `gorm:"column:sold_out"`
+}
+
+func (Concert) TableName() string {
+	return "concerts"
+}
+
+// NearbyConcert is a concert with its distance from the search centre
+type NearbyConcert struct {
+	Concert
+	DistanceKm float64 `gorm:"column:distance_km"`
+}
+
+func ToPostgresConcert(e *models.Concert, musicianId uint64) *Concert {
+	var ticketUrl *string
+	if e.TicketUrl != "" {
+		ticketUrl = &e.TicketUrl
+	}
+
+	return &Concert{
+		ID:          e.Id,
+		MusicianID:  musicianId,
+		Title:       e.Title,
+		Description: e.Description,
+		Venue:       e.Venue,
+		City:        e.City,
+		Latitude:    e.Latitude,
+		Longitude:   e.Longitude,
+		StartsAt:    e.StartsAt,
+		TimeZone:    e.TimeZone,
+		TicketUrl:   ticketUrl,
+		SoldOut:     e.SoldOut,
+	}
+}
+
+// ToModelConcert shows StartsAt in the time zone of the concert
+func ToModelConcert(e *Concert) *models.Concert {
+	var ticketUrl string
+	if e.TicketUrl != nil {
+		ticketUrl = *e.TicketUrl
+	}
+
+	startsAt := e.StartsAt
+	if loc, err := time.LoadLocation(e.TimeZone); err == nil {
+		startsAt = startsAt.In(loc)
+	}
+
+	return &models.Concert{
+		Id:          e.ID,
+		MusicianId:  e.MusicianID,
+		Title:       e.Title,
+		Description: e.Description,
+		Venue:       e.Venue,
+		City:        e.City,
+		Latitude:    e.Latitude,
+		Longitude:   e.Longitude,
+		StartsAt:    startsAt,
+		TimeZone:    e.TimeZone,
+		TicketUrl:   ticketUrl,
+		SoldOut:     e.SoldOut,
+	}
+}
+
+func ToConcertPayload(e *Concert) events.ConcertPayload {
+	startsAt := e.StartsAt.UTC()
+
+	return events.ConcertPayload{
+		ConcertId:  e.ID,
+		MusicianId: e.MusicianID,
+		Title:      e.Title,
+		City:       e.City,
+		StartsAt:   &startsAt,
+		SoldOut:    e.SoldOut,
+	}
+}
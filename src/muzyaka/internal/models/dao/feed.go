@@ -0,0 +1,34 @@
+package dao
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type FeedItem struct {
+	ID          uint64    `gorm:"column:id"`
+	Kind        string    `gorm:"column:kind"`
+	ItemId      uint64    `gorm:"column:item_id"`
+	MusicianId  uint64    `gorm:"column:musician_id"`
+	AlbumId     *uint64   `gorm:"column:album_id"`
+	PublishedAt time.Time `gorm:"column:published_at"`
+}
+
+func (FeedItem) TableName() string {
+	return "feed_items"
+}
+
+func ToPostgresFeedItem(e *models.FeedItem) *FeedItem {
+	var albumId *uint64
+	if e.AlbumId != 0 {
+		albumId = &e.AlbumId
+	}
+
+	return &FeedItem{
+		Kind:        e.Kind,
+		ItemId:      e.ItemId,
+		MusicianId:  e.MusicianId,
+		AlbumId:     albumId,
+		PublishedAt: e.PublishedAt,
+	}
+}
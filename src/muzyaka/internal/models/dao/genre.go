@@ -0,0 +1,78 @@
+package dao
+
+import "src/internal/models"
+
+type Genre struct {
+	ID       *uint64 `gorm:"column:id"`
+	Name     string  `gorm:"column:name"`
+	ParentID *uint64 `gorm:"column:parent_id"`
+}
+
+func (Genre) TableName() string {
+	return "genres"
+}
+
+type TrackGenre struct {
+	TrackID uint64 `gorm:"column:track_id;primaryKey"`
+	GenreID uint64 `gorm:"column:genre_id;primaryKey"`
+}
+
+func (TrackGenre) TableName() string {
+	return "track_genres"
+}
+
+type TrackMood struct {
+	TrackID uint64 `gorm:"column:track_id;primaryKey"`
+	Mood    string `gorm:"column:mood;primaryKey"`
+}
+
+func (TrackMood) TableName() string {
+	return "track_moods"
+}
+
+type AlbumGenre struct {
+	AlbumID uint64 `gorm:"column:album_id;primaryKey"`
+	GenreID uint64 `gorm:"column:genre_id;primaryKey"`
+}
+
+func (AlbumGenre) TableName() string {
+	return "album_genres"
+}
+
+// GenreTag is a genre name of a track or an album
+type GenreTag struct {
+	OwnerID uint64 `gorm:"column:owner_id"`
+	Name    string `gorm:"column:name"`
+}
+
+func ToPostgresGenre(e *models.Genre) *Genre {
+	var parent *uint64
+	if e.ParentId != 0 {
+		parent = &e.ParentId
+	}
+
+	var id *uint64
+	if e.Id != 0 {
+		id = &e.Id
+	}
+
+	return &Genre{
+		ID:       id,
+		Name:     e.Name,
+		ParentID: parent,
+	}
+}
+
+func ToModelGenre(e *Genre) *models.Genre {
+	res := &models.Genre{Name: e.Name}
+
+	if e.ID != nil {
+		res.Id = *e.ID
+	}
+
+	if e.ParentID != nil {
+		res.ParentId = *e.ParentID
+	}
+
+	return res
+}
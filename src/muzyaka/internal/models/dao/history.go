@@ -0,0 +1,52 @@
+package dao
+
+import (
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
+)
+
+type Play struct {
+	ID         uint64    `gorm:"column:id"`
+	UserId     uint64    `gorm:"column:user_id"`
+	TrackId    uint64    `gorm:"column:track_id"`
+	PlayedAt   time.Time `gorm:"column:played_at"`
+	DurationMs int64     `gorm:"column:duration_ms"`
+	Completed  bool      `gorm:"column:completed"`
+}
+
+func (Play) TableName() string {
+	return "plays"
+}
+
+func ToPostgresPlay(play *models.Play) *Play {
+	return &Play{
+		ID:         play.Id,
+		UserId:     play.UserId,
+		TrackId:    play.TrackId,
+		PlayedAt:   play.PlayedAt,
+		DurationMs: play.Duration.Milliseconds(),
+		Completed:  play.Completed,
+	}
+}
+
+func ToModelPlay(play *Play) *models.Play {
+	return &models.Play{
+		Id:        play.ID,
+		UserId:    play.UserId,
+		TrackId:   play.TrackId,
+		PlayedAt:  play.PlayedAt,
+		Duration:  time.Duration(play.DurationMs) * time.Millisecond,
+		Completed: play.Completed,
+	}
+}
+
+func ToPlayPayload(play *Play) events.PlayPayload {
+	return events.PlayPayload{
+		UserId:     play.UserId,
+		TrackId:    play.TrackId,
+		PlayedAt:   play.PlayedAt,
+		DurationMs: play.DurationMs,
+		Completed:  play.Completed,
+	}
+}
@@ -0,0 +1,72 @@
+package dao
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"src/internal/models"
+)
+
+type TrackLyrics struct {
+	TrackID  uint64      `gorm:"column:track_id;primaryKey"`
+	Language string      `gorm:"column:language;primaryKey"`
+	Synced   bool        `gorm:"column:synced"`
+	Lines    LyricsLines `gorm:"column:lines;type:jsonb"`
+	Text     string      `gorm:"column:text"`
+}
+
+func (TrackLyrics) TableName() string {
+	return "track_lyrics"
+}
+
+// LyricsLines is stored as jsonb
+type LyricsLines []LyricsLine
+
+type LyricsLine struct {
+	OffsetMs int64  `json:"offset_ms"`
+	Text     string `json:"text"`
+}
+
+func (l LyricsLines) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+func (l *LyricsLines) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	}
+
+	return errors.Errorf("unsupported lines type %T", src)
+}
+
+func ToPostgresLyrics(e *models.Lyrics) *TrackLyrics {
+	lines := make(LyricsLines, 0, len(e.Lines))
+	for _, v := range e.Lines {
+		lines = append(lines, LyricsLine{OffsetMs: v.OffsetMs, Text: v.Text})
+	}
+
+	return &TrackLyrics{
+		TrackID:  e.TrackId,
+		Language: e.Language,
+		Synced:   e.Synced,
+		Lines:    lines,
+		Text:     e.PlainText(),
+	}
+}
+
+func ToModelLyrics(e *TrackLyrics) *models.Lyrics {
+	lines := make([]*models.LyricsLine, 0, len(e.Lines))
+	for _, v := range e.Lines {
+		lines = append(lines, &models.LyricsLine{OffsetMs: v.OffsetMs, Text: v.Text})
+	}
+
+	return &models.Lyrics{
+		TrackId:  e.TrackID,
+		Language: e.Language,
+		Synced:   e.Synced,
+		Lines:    lines,
+	}
+}
@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"encoding/binary"
+	"src/internal/models"
+	"time"
+)
+
+// Waveform is stored as json next to the track source
+type Waveform struct {
+	SourceTag  string      `json:"source_tag"`
+	DurationMs int64       `json:"duration_ms"`
+	Peaks      [][]float32 `json:"peaks"`
+}
+
+func ToStorageWaveform(e *models.Waveform) *Waveform {
+	return &Waveform{
+		SourceTag:  e.SourceTag,
+		DurationMs: e.Duration.Milliseconds(),
+		Peaks:      e.Peaks,
+	}
+}
+
+func ToModelWaveform(e *Waveform) *models.Waveform {
+	return &models.Waveform{
+		SourceTag: e.SourceTag,
+		Duration:  time.Duration(e.DurationMs) * time.Millisecond,
+		Peaks:     e.Peaks,
+	}
+}
+
+type TrackSource struct {
+	Source     string     `gorm:"column:source;primaryKey"`
+	Refs       int        `gorm:"column:refs"`
+	Stored     bool       `gorm:"column:stored"`
+	RemovingAt *time.Time `gorm:"column:removing_at"`
+}
+
+func (TrackSource) TableName() string {
+	return "track_sources"
+}
+
+type Fingerprint struct {
+	TrackID     uint64 `gorm:"column:track_id;primaryKey"`
+	Fingerprint []byte `gorm:"column:fingerprint"`
+}
+
+func (Fingerprint) TableName() string {
+	return "track_fingerprints"
+}
+
+type FingerprintKey struct {
+	Key     uint32 `gorm:"column:key;primaryKey"`
+	TrackID uint64 `gorm:"column:track_id;primaryKey"`
+}
+
+func (FingerprintKey) TableName() string {
+	return "track_fingerprint_keys"
+}
+
+func ToPostgresFingerprint(e *models.Fingerprint) *Fingerprint {
+	data := make([]byte, 4*len(e.Values))
+	for i, v := range e.Values {
+		binary.LittleEndian.PutUint32(data[4*i:], v)
+	}
+
+	return &Fingerprint{TrackID: e.TrackId, Fingerprint: data}
+}
+
+func ToModelFingerprint(e *Fingerprint) *models.Fingerprint {
+	values := make([]uint32, len(e.Fingerprint)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(e.Fingerprint[4*i:])
+	}
+
+	return &models.Fingerprint{TrackId: e.TrackID, Values: values}
+}
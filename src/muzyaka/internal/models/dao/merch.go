@@ -1,19 +1,33 @@
 package dao
 
-import "src/internal/models"
+import (
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
+)
 
 type Merch struct {
-	ID         uint64 `gorm:"column:id"`
-	Name       string `gorm:"column:name"`
-	Desc       string `gorm:"column:description"`
-	Link       string `gorm:"column:link"`
-	MusicianID uint64 `gorm:"column:musician_id"`
+	ID         uint64  `gorm:"column:id"`
+	Name       string  `gorm:"column:name"`
+	Desc       string  `gorm:"column:description"`
+	Link       *string `gorm:"column:link"`
+	MusicianID uint64  `gorm:"column:musician_id"`
 }
 
 func (Merch) TableName() string {
 	return "merch"
 }
 
+type MerchClick struct {
+	ID        uint64    `gorm:"column:id"`
+	MerchId   uint64    `gorm:"column:merch_id"`
+	ClickedAt time.Time `gorm:"column:clicked_at;default:now()"`
+}
+
+func (MerchClick) TableName() string {
+	return "merch_clicks"
+}
+
 type MerchPhotos struct {
 	ID        uint64 `gorm:"column:id"`
 	MerchId   uint64 `gorm:"column:merch_id"`
@@ -24,16 +38,71 @@ func (MerchPhotos) TableName() string {
 	return "merch_photos"
 }
 
+type MerchVariant struct {
+	ID                uint64 `gorm:"column:id"`
+	MerchId           uint64 `gorm:"column:merch_id"`
+	Sku               string `gorm:"column:sku"`
+	Size              string `gorm:"column:size"`
+	Colour            string `gorm:"column:colour"`
+	Price             int64  `gorm:"column:price"`
+	Currency          string `gorm:"column:currency"`
+	Stock             int    `gorm:"column:stock"`
+	Reserved          int    `gorm:"column:reserved"`
+	LowStockThreshold int    `gorm:"column:low_stock_threshold"`
+}
+
+func (MerchVariant) TableName() string {
+	return "merch_variants"
+}
+
 func ToPostgresMerch(e *models.Merch, musicianId uint64) *Merch {
+	var link *string
+	if e.OrderUrl != "" {
+		link = &e.OrderUrl
+	}
+
 	return &Merch{
 		ID:         e.Id,
 		Name:       e.Name,
 		Desc:       e.Description,
-		Link:       e.OrderUrl,
+		Link:       link,
 		MusicianID: musicianId,
 	}
 }
 
+func ToPostgresMerchVariants(e *models.Merch) []*MerchVariant {
+	var variants []*MerchVariant
+
+	for _, v := range e.Variants {
+		variants = append(variants, &MerchVariant{
+			ID:                v.Id,
+			MerchId:           e.Id,
+			Sku:               v.Sku,
+			Size:              v.Size,
+			Colour:            v.Colour,
+			Price:             v.Price.Amount,
+			Currency:          v.Price.Currency,
+			Stock:             v.Stock,
+			LowStockThreshold: v.LowStockThreshold,
+		})
+	}
+
+	return variants
+}
+
+func ToModelMerchVariant(e *MerchVariant) *models.MerchVariant {
+	return &models.MerchVariant{
+		Id:                e.ID,
+		Sku:               e.Sku,
+		Size:              e.Size,
+		Colour:            e.Colour,
+		Price:             models.Price{Amount: e.Price, Currency: e.Currency},
+		Stock:             e.Stock,
+		Reserved:          e.Reserved,
+		LowStockThreshold: e.LowStockThreshold,
+	}
+}
+
 func ToPostgresMerchPhotos(e *models.Merch) []*MerchPhotos {
 	var merchPhotos []*MerchPhotos
 
@@ -50,18 +119,45 @@ func ToPostgresMerchPhotos(e *models.Merch) []*MerchPhotos {
 	return merchPhotos
 }
 
-func ToModelMerch(e *Merch, mp []*MerchPhotos) *models.Merch {
+func ToModelMerch(e *Merch, mp []*MerchPhotos, mv []*MerchVariant) *models.Merch {
 	var photos [][]byte
 
 	for _, v := range mp {
 		photos = append(photos, v.PhotoFile)
 	}
 
+	var variants []*models.MerchVariant
+
+	for _, v := range mv {
+		variants = append(variants, ToModelMerchVariant(v))
+	}
+
+	var link string
+	if e.Link != nil {
+		link = *e.Link
+	}
+
 	return &models.Merch{
 		Id:          e.ID,
 		Name:        e.Name,
 		PhotoFiles:  photos,
 		Description: e.Desc,
-		OrderUrl:    e.Link,
+		OrderUrl:    link,
+		Variants:    variants,
+	}
+}
+
+func ToMerchPayload(e *Merch) events.MerchPayload {
+	var link string
+	if e.Link != nil {
+		link = *e.Link
+	}
+
+	return events.MerchPayload{
+		MerchId:     e.ID,
+		MusicianId:  e.MusicianID,
+		Name:        e.Name,
+		Description: e.Desc,
+		OrderUrl:    link,
 	}
 }
@@ -0,0 +1,41 @@
+package dao
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type DuplicateReport struct {
+	ID              uint64    `gorm:"column:id"`
+	TrackID         uint64    `gorm:"column:track_id"`
+	OriginalTrackID uint64    `gorm:"column:original_track_id"`
+	Similarity      float64   `gorm:"column:similarity"`
+	Status          string    `gorm:"column:status"`
+	CreatedAt       time.Time `gorm:"column:created_at"`
+}
+
+func (DuplicateReport) TableName() string {
+	return "duplicate_reports"
+}
+
+func ToPostgresDuplicateReport(e *models.DuplicateReport) *DuplicateReport {
+	return &DuplicateReport{
+		ID:              e.Id,
+		TrackID:         e.TrackId,
+		OriginalTrackID: e.OriginalTrackId,
+		Similarity:      e.Similarity,
+		Status:          e.Status,
+		CreatedAt:       e.CreatedAt,
+	}
+}
+
+func ToModelDuplicateReport(e *DuplicateReport) *models.DuplicateReport {
+	return &models.DuplicateReport{
+		Id:              e.ID,
+		TrackId:         e.TrackID,
+		OriginalTrackId: e.OriginalTrackID,
+		Similarity:      e.Similarity,
+		Status:          e.Status,
+		CreatedAt:       e.CreatedAt,
+	}
+}
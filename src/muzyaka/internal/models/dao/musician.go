@@ -1,6 +1,10 @@
 package dao
 
-import "src/internal/models"
+import (
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
+)
 
 type Musician struct {
 	ID          uint64 `gorm:"column:id"`
@@ -55,3 +59,21 @@ func ToModelMusician(musician *Musician, photos []*MusicianPhotos) *models.Music
 		Description: musician.Description,
 	}
 }
+
+func ToMusicianPayload(e *Musician) events.MusicianPayload {
+	return events.MusicianPayload{
+		MusicianId:  e.ID,
+		Name:        e.Name,
+		Description: e.Description,
+	}
+}
+
+type MusicianFollower struct {
+	UserId     uint64    `gorm:"column:user_id"`
+	MusicianId uint64    `gorm:"column:musician_id"`
+	FollowedAt time.Time `gorm:"column:followed_at;default:now()"`
+}
+
+func (MusicianFollower) TableName() string {
+	return "musician_followers"
+}
@@ -0,0 +1,156 @@
+package dao
+
+import (
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
+)
+
+type CartItem struct {
+	UserId    uint64    `gorm:"column:user_id"`
+	VariantId uint64    `gorm:"column:variant_id"`
+	Quantity  int       `gorm:"column:quantity"`
+	AddedAt   time.Time `gorm:"column:added_at;default:now()"`
+}
+
+func (CartItem) TableName() string {
+	return "cart_items"
+}
+
+// CartLine is a cart item joined with its variant and merch
+type CartLine struct {
+	MerchVariant
+	MerchName  string `gorm:"column:merch_name"`
+	MusicianId uint64 `gorm:"column:musician_id"`
+	Quantity   int    `gorm:"column:quantity"`
+}
+
+type Order struct {
+	ID         uint64    `gorm:"column:id"`
+	UserId     *uint64   `gorm:"column:user_id"`
+	MusicianId uint64    `gorm:"column:musician_id"`
+	Status     string    `gorm:"column:status"`
+	Total      int64     `gorm:"column:total"`
+	Currency   string    `gorm:"column:currency"`
+	PaymentId  *string   `gorm:"column:payment_id"`
+	PaymentUrl *string   `gorm:"column:payment_url"`
+	CreatedAt  time.Time `gorm:"column:created_at;default:now()"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;default:now()"`
+}
+
+func (Order) TableName() string {
+	return "orders"
+}
+
+type OrderItem struct {
+	ID        uint64  `gorm:"column:id"`
+	OrderId   uint64  `gorm:"column:order_id"`
+	VariantId *uint64 `gorm:"column:variant_id"`
+	MerchId   *uint64 `gorm:"column:merch_id"`
+	Name      string  `gorm:"column:name"`
+	Sku       string  `gorm:"column:sku"`
+	Size      string  `gorm:"column:size"`
+	Colour    string  `gorm:"column:colour"`
+	UnitPrice int64   `gorm:"column:unit_price"`
+	Quantity  int     `gorm:"column:quantity"`
+}
+
+func (OrderItem) TableName() string {
+	return "order_items"
+}
+
+type PaymentEvent struct {
+	EventId       string    `gorm:"column:event_id"`
+	PaymentId     string    `gorm:"column:payment_id"`
+	Status        string    `gorm:"column:status"`
+	RefundPending bool      `gorm:"column:refund_pending"`
+	ReceivedAt    time.Time `gorm:"column:received_at;default:now()"`
+}
+
+func (PaymentEvent) TableName() string {
+	return "payment_events"
+}
+
+func ToModelCartItem(e *CartLine) *models.CartItem {
+	return &models.CartItem{
+		MerchId:    e.MerchId,
+		MerchName:  e.MerchName,
+		MusicianId: e.MusicianId,
+		Variant:    ToModelMerchVariant(&e.MerchVariant),
+		Quantity:   e.Quantity,
+	}
+}
+
+func ToPostgresOrderItem(e *CartLine, orderId uint64) *OrderItem {
+	variantId, merchId := e.ID, e.MerchId
+
+	return &OrderItem{
+		OrderId:   orderId,
+		VariantId: &variantId,
+		MerchId:   &merchId,
+		Name:      e.MerchName,
+		Sku:       e.Sku,
+		Size:      e.Size,
+		Colour:    e.Colour,
+		UnitPrice: e.Price,
+		Quantity:  e.Quantity,
+	}
+}
+
+func ToModelOrder(e *Order, items []*OrderItem) *models.Order {
+	order := &models.Order{
+		Id:         e.ID,
+		MusicianId: e.MusicianId,
+		Status:     e.Status,
+		Total:      models.Price{Amount: e.Total, Currency: e.Currency},
+		CreatedAt:  e.CreatedAt,
+		UpdatedAt:  e.UpdatedAt,
+	}
+
+	if e.UserId != nil {
+		order.UserId = *e.UserId
+	}
+	if e.PaymentId != nil {
+		order.PaymentId = *e.PaymentId
+	}
+	if e.PaymentUrl != nil {
+		order.PaymentUrl = *e.PaymentUrl
+	}
+
+	for _, v := range items {
+		item := &models.OrderItem{
+			Name:      v.Name,
+			Sku:       v.Sku,
+			Size:      v.Size,
+			Colour:    v.Colour,
+			UnitPrice: models.Price{Amount: v.UnitPrice, Currency: e.Currency},
+			Quantity:  v.Quantity,
+		}
+		if v.VariantId != nil {
+			item.VariantId = *v.VariantId
+		}
+		if v.MerchId != nil {
+			item.MerchId = *v.MerchId
+		}
+
+		order.Items = append(order.Items, item)
+	}
+
+	return order
+}
+
+func ToOrderPayload(e *Order) events.OrderPayload {
+	var userId uint64
+	if e.UserId != nil {
+		userId = *e.UserId
+	}
+
+	return events.OrderPayload{
+		OrderId:    e.ID,
+		UserId:     userId,
+		MusicianId: e.MusicianId,
+		Status:     e.Status,
+		Total:      e.Total,
+		Currency:   e.Currency,
+	}
+}
@@ -1,22 +1,74 @@
 package dao
 
-var (
-	TypeDelete = "delete"
-	TypeAdd    = "add"
-	TypeUpdate = "update"
+import (
+	"encoding/json"
+	"github.com/hashicorp/go-uuid"
+	"github.com/pkg/errors"
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
 )
 
 type Outbox struct {
-	ID         uint64 `gorm:"column:id"`
-	EventId    string `gorm:"column:event_id"`
-	TrackId    uint64 `gorm:"column:track_id"`
-	Source     string `gorm:"column:source"`
-	Name       string `gorm:"column:name"`
-	GenreRefer uint64 `gorm:"column:genre"`
-	Type       string `gorm:"column:type"`
-	Sent       bool   `gorm:"column:sent"`
+	ID            uint64     `gorm:"column:id"`
+	EventId       string     `gorm:"column:event_id"`
+	AggregateType string     `gorm:"column:aggregate_type"`
+	AggregateId   uint64     `gorm:"column:aggregate_id"`
+	Type          string     `gorm:"column:type"`
+	Payload       string     `gorm:"column:payload"`
+	OccurredAt    time.Time  `gorm:"column:occurred_at"`
+	Sent          bool       `gorm:"column:sent"`
+	SentAt        *time.Time `gorm:"column:sent_at"`
+	Attempts      int        `gorm:"column:attempts"`
+	LastError     string     `gorm:"column:last_error"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at"`
+	DeadLettered  bool       `gorm:"column:dead_lettered"`
 }
 
 func (Outbox) TableName() string {
 	return "outbox"
 }
+
+// NewOutbox prepares an outbox row for one of the events from events.Catalogue.
+// It has to be created in the same transaction as the change it describes.
+func NewOutbox(eventType string, aggregateId uint64, payload interface{}) (*Outbox, error) {
+	aggregateType, ok := events.Catalogue[eventType]
+	if !ok {
+		return nil, errors.Wrap(models.ErrUnknownEvent, eventType)
+	}
+
+	eventId, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	return &Outbox{
+		EventId:       eventId,
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		Type:          eventType,
+		Payload:       string(data),
+		OccurredAt:    now,
+		Sent:          false,
+		NextAttemptAt: now,
+	}, nil
+}
+
+func ToEvent(e *Outbox) *events.Event {
+	return &events.Event{
+		Version:       events.Version,
+		EventId:       e.EventId,
+		AggregateType: e.AggregateType,
+		AggregateId:   e.AggregateId,
+		Type:          e.Type,
+		OccurredAt:    e.OccurredAt,
+		Payload:       json.RawMessage(e.Payload),
+	}
+}
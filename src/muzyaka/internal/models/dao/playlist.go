@@ -1,18 +1,76 @@
 package dao
 
-import "src/internal/models"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"src/internal/models"
+	"src/internal/models/events"
+	"time"
+)
 
 type Playlist struct {
-	ID          uint64 `gorm:"column:id"`
-	Name        string `gorm:"column:name"`
-	CoverFile   []byte `gorm:"column:cover_file"`
-	Description string `gorm:"column:description"`
-	UserID      uint64 `gorm:"column:user_id"`
+	ID            uint64      `gorm:"column:id"`
+	Name          string      `gorm:"column:name"`
+	CoverFile     []byte      `gorm:"column:cover_file"`
+	Description   string      `gorm:"column:description"`
+	UserID        uint64      `gorm:"column:user_id"`
+	Kind          string      `gorm:"column:kind"`
+	Visibility    string      `gorm:"column:visibility"`
+	Collaborative bool        `gorm:"column:collaborative"`
+	Rules         *SmartRules `gorm:"column:rules;type:jsonb"`
+}
+
+// SmartRules is stored as jsonb
+type SmartRules struct {
+	Match      string           `json:"match"`
+	Conditions []SmartCondition `json:"conditions"`
+	Sort       string           `json:"sort"`
+	Limit      int              `json:"limit"`
+}
+
+type SmartCondition struct {
+	Field  string   `json:"field"`
+	Op     string   `json:"op"`
+	Value  string   `json:"value,omitempty"`
+	Values []string `json:"values,omitempty"`
+	Days   int      `json:"days,omitempty"`
+}
+
+func (r SmartRules) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+func (r *SmartRules) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, r)
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	}
+
+	return errors.Errorf("unsupported rules type %T", src)
 }
 
 type PlaylistTrack struct {
-	TrackId    uint64 `gorm:"column:track_id"`
-	PlaylistId uint64 `gorm:"column:playlist_id"`
+	TrackId    uint64    `gorm:"column:track_id"`
+	PlaylistId uint64    `gorm:"column:playlist_id"`
+	AddedAt    time.Time `gorm:"column:added_at;default:now()"`
+	AddedBy    *uint64   `gorm:"column:added_by"`
+}
+
+type PlaylistCollaborator struct {
+	PlaylistId uint64    `gorm:"column:playlist_id"`
+	UserId     uint64    `gorm:"column:user_id"`
+	JoinedAt   time.Time `gorm:"column:joined_at;default:now()"`
+}
+
+type PlaylistInvite struct {
+	Token      string    `gorm:"column:token"`
+	PlaylistId uint64    `gorm:"column:playlist_id"`
+	CreatedBy  uint64    `gorm:"column:created_by"`
+	CreatedAt  time.Time `gorm:"column:created_at;default:now()"`
+	ExpiresAt  time.Time `gorm:"column:expires_at"`
 }
 
 func (Playlist) TableName() string {
@@ -23,21 +81,106 @@ func (PlaylistTrack) TableName() string {
 	return "track_playlist"
 }
 
+func (PlaylistCollaborator) TableName() string {
+	return "playlist_collaborators"
+}
+
+func (PlaylistInvite) TableName() string {
+	return "playlist_invites"
+}
+
 func ToModelPlaylist(playlist *Playlist) *models.Playlist {
 	return &models.Playlist{
-		Id:          playlist.ID,
-		Name:        playlist.Name,
-		CoverFile:   playlist.CoverFile,
-		Description: playlist.Description,
+		Id:            playlist.ID,
+		Name:          playlist.Name,
+		CoverFile:     playlist.CoverFile,
+		Description:   playlist.Description,
+		Kind:          playlist.Kind,
+		Visibility:    playlist.Visibility,
+		Collaborative: playlist.Collaborative,
+		Rules:         ToModelSmartRules(playlist.Rules),
 	}
 }
 
+func ToModelSmartRules(e *SmartRules) *models.SmartRules {
+	if e == nil {
+		return nil
+	}
+
+	res := &models.SmartRules{Match: e.Match, Sort: e.Sort, Limit: e.Limit}
+	for _, c := range e.Conditions {
+		res.Conditions = append(res.Conditions, models.SmartCondition(c))
+	}
+
+	return res
+}
+
+func ToPostgresSmartRules(e *models.SmartRules) *SmartRules {
+	if e == nil {
+		return nil
+	}
+
+	res := &SmartRules{Match: e.Match, Sort: e.Sort, Limit: e.Limit}
+	for _, c := range e.Conditions {
+		res.Conditions = append(res.Conditions, SmartCondition(c))
+	}
+
+	return res
+}
+
 func ToPostgresPlaylist(playlist *models.Playlist, userId uint64) *Playlist {
+	kind := playlist.Kind
+	if kind == "" {
+		kind = models.PlaylistKindUser
+	}
+
+	visibility := playlist.Visibility
+	if visibility == "" {
+		visibility = models.PlaylistPublic
+	}
+
 	return &Playlist{
-		ID:          playlist.Id,
-		Name:        playlist.Name,
-		CoverFile:   playlist.CoverFile,
-		Description: playlist.Description,
-		UserID:      userId,
+		ID:            playlist.Id,
+		Name:          playlist.Name,
+		CoverFile:     playlist.CoverFile,
+		Description:   playlist.Description,
+		UserID:        userId,
+		Kind:          kind,
+		Visibility:    visibility,
+		Collaborative: playlist.Collaborative,
+		Rules:         ToPostgresSmartRules(playlist.Rules),
+	}
+}
+
+func ToModelPlaylistTrack(e *PlaylistTrack) *models.PlaylistTrack {
+	var addedBy uint64
+	if e.AddedBy != nil {
+		addedBy = *e.AddedBy
+	}
+
+	return &models.PlaylistTrack{
+		TrackId: e.TrackId,
+		AddedBy: addedBy,
+		AddedAt: e.AddedAt,
+	}
+}
+
+func ToPostgresPlaylistInvite(e *models.PlaylistInvite) *PlaylistInvite {
+	return &PlaylistInvite{
+		Token:      e.Token,
+		PlaylistId: e.PlaylistId,
+		CreatedBy:  e.CreatedBy,
+		ExpiresAt:  e.ExpiresAt,
+	}
+}
+
+func ToPlaylistPayload(e *Playlist) events.PlaylistPayload {
+	return events.PlaylistPayload{
+		PlaylistId:    e.ID,
+		UserId:        e.UserID,
+		Name:          e.Name,
+		Description:   e.Description,
+		Visibility:    e.Visibility,
+		Collaborative: e.Collaborative,
 	}
 }
@@ -0,0 +1,47 @@
+package dao
+
+import "time"
+
+type ProcessedEvent struct {
+	Consumer    string    `gorm:"column:consumer;primaryKey"`
+	EventId     string    `gorm:"column:event_id;primaryKey"`
+	ProcessedAt time.Time `gorm:"column:processed_at;default:now()"`
+}
+
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
+
+type SimilarTrack struct {
+	TrackId        uint64    `gorm:"column:track_id;primaryKey"`
+	SimilarTrackId uint64    `gorm:"column:similar_track_id;primaryKey"`
+	Position       int       `gorm:"column:position"`
+	Score          float64   `gorm:"column:score"`
+	ComputedAt     time.Time `gorm:"column:computed_at"`
+}
+
+func (SimilarTrack) TableName() string {
+	return "similar_tracks"
+}
+
+type TrendingTrack struct {
+	TrackId    uint64    `gorm:"column:track_id;primaryKey"`
+	Position   int       `gorm:"column:position"`
+	Score      float64   `gorm:"column:score"`
+	ComputedAt time.Time `gorm:"column:computed_at"`
+}
+
+func (TrendingTrack) TableName() string {
+	return "trending_tracks"
+}
+
+type TrackNeighbour struct {
+	TrackId     uint64  `gorm:"column:track_id;primaryKey"`
+	NeighbourId uint64  `gorm:"column:neighbour_id;primaryKey"`
+	Position    int     `gorm:"column:position"`
+	Score       float64 `gorm:"column:score"`
+}
+
+func (TrackNeighbour) TableName() string {
+	return "track_neighbours"
+}
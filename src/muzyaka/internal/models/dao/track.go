@@ -1,15 +1,9 @@
 package dao
 
-import "src/internal/models"
-
-type Genre struct {
-	ID   *uint64 `gorm:"column:id"`
-	Name string  `gorm:"column:name"`
-}
-
-func (Genre) TableName() string {
-	return "genres"
-}
+import (
+	"src/internal/models"
+	"src/internal/models/events"
+)
 
 type TrackMeta struct {
 	ID         uint64  `gorm:"column:id"`
@@ -17,12 +11,33 @@ type TrackMeta struct {
 	Name       string  `gorm:"column:name"`
 	GenreRefer *uint64 `gorm:"column:genre"`
 	AlbumID    uint64  `gorm:"column:album_id"`
+	Isrc       *string `gorm:"column:isrc"`
+	Explicit   bool    `gorm:"column:explicit"`
+
+	// set by media processing only
+	Duration *int     `gorm:"column:duration"`
+	Loudness *float64 `gorm:"column:loudness"`
+	TruePeak *float64 `gorm:"column:true_peak"`
 }
 
 func (TrackMeta) TableName() string {
 	return "tracks"
 }
 
+// TrackCreditFeatured is the role of featured artists in track_credits
+const TrackCreditFeatured = "featured"
+
+type TrackCredit struct {
+	TrackID  uint64 `gorm:"column:track_id;primaryKey"`
+	Role     string `gorm:"column:role;primaryKey"`
+	Name     string `gorm:"column:name;primaryKey"`
+	Position int    `gorm:"column:position"`
+}
+
+func (TrackCredit) TableName() string {
+	return "track_credits"
+}
+
 func ToPostgresTrack(e *models.TrackMeta, genreRefer *uint64, albumId uint64) *TrackMeta {
 	var refer *uint64
 	if genreRefer == nil {
@@ -31,20 +46,91 @@ func ToPostgresTrack(e *models.TrackMeta, genreRefer *uint64, albumId uint64) *T
 		refer = genreRefer
 	}
 
+	var isrc *string
+	if e.Isrc != "" {
+		isrc = &e.Isrc
+	}
+
 	return &TrackMeta{
 		ID:         e.Id,
 		Source:     e.Source,
 		Name:       e.Name,
 		GenreRefer: refer,
 		AlbumID:    albumId,
+		Isrc:       isrc,
+		Explicit:   e.Explicit,
 	}
 }
 
 func ToModelTrack(track *TrackMeta, genre *Genre) *models.TrackMeta {
-	return &models.TrackMeta{
-		Id:     track.ID,
-		Source: track.Source,
-		Name:   track.Name,
-		Genre:  genre.Name,
+	res := &models.TrackMeta{
+		Id:       track.ID,
+		Source:   track.Source,
+		Name:     track.Name,
+		Genre:    genre.Name,
+		Explicit: track.Explicit,
+	}
+
+	if track.Isrc != nil {
+		res.Isrc = *track.Isrc
 	}
+
+	res.Loudness = ToModelLoudness(track.Loudness, track.TruePeak)
+
+	return res
+}
+
+// ToModelLoudness returns nil until loudness is measured
+func ToModelLoudness(integrated *float64, truePeak *float64) *models.Loudness {
+	if integrated == nil || truePeak == nil {
+		return nil
+	}
+
+	return &models.Loudness{Integrated: *integrated, TruePeak: *truePeak}
+}
+
+func ToTrackPayload(e *TrackMeta) events.TrackPayload {
+	var genre uint64
+	if e.GenreRefer != nil {
+		genre = *e.GenreRefer
+	}
+
+	return events.TrackPayload{
+		TrackId: e.ID,
+		AlbumId: e.AlbumID,
+		Source:  e.Source,
+		Name:    e.Name,
+		GenreId: genre,
+	}
+}
+
+// TrackDetails is a row of tracks joined with the genre and musician names
+type TrackDetails struct {
+	ID       uint64  `gorm:"column:id"`
+	Source   string  `gorm:"column:source"`
+	Name     string  `gorm:"column:name"`
+	Genre    *string `gorm:"column:genre"`
+	Musician string  `gorm:"column:musician"`
+	Duration *int    `gorm:"column:duration"`
+}
+
+func ToModelTrackDetails(e *TrackDetails) *models.TrackDetails {
+	res := &models.TrackDetails{
+		TrackMeta: models.TrackMeta{
+			Id:     e.ID,
+			Source: e.Source,
+			Name:   e.Name,
+		},
+		Musician: e.Musician,
+	}
+
+	if e.Genre != nil {
+		res.Genre = *e.Genre
+	}
+
+	if e.Duration != nil {
+		res.Duration = *e.Duration
+	}
+
+	return res
 }
@@ -1,6 +1,9 @@
 package dao
 
-import "src/internal/models"
+import (
+	"src/internal/models"
+	"src/internal/models/events"
+)
 
 type User struct {
 	ID       uint64 `gorm:"column:id"`
@@ -51,3 +54,11 @@ func ToPostgresUser(user *models.User) *User {
 		Role:     user.Role,
 	}
 }
+
+func ToUserPayload(e *User) events.UserPayload {
+	return events.UserPayload{
+		UserId: e.ID,
+		Name:   e.Name,
+		Role:   e.Role,
+	}
+}
@@ -9,12 +9,30 @@ type Album struct {
 	Name      string `json:"name"`
 	CoverFile []byte `json:"cover_file"`
 	Type      string `json:"type"`
+	// Genres are inherited by the tracks of the album
+	Genres      []string `json:"genres,omitempty"`
+	ReleaseDate *Date    `json:"release_date,omitempty" swaggertype:"string" example:"2024-05-17"`
+	Label       string   `json:"label,omitempty"`
+	// Upc is a UPC-A or EAN-13 code
+	Upc string `json:"upc,omitempty"`
+	// Artists starts with the primary artist, the owner of the album
+	Artists []*Artist `json:"artists,omitempty"`
+	// Loudness is missing until every track is analyzed. It approximates the
+	// album gain of ReplayGain 2.0 from loudness of the tracks.
+	Loudness *Loudness `json:"loudness,omitempty"`
 }
 
 type AlbumWithoutId struct {
 	Name      string `json:"name"`
 	CoverFile []byte `json:"cover_file"`
-	Type      string `json:"type"`
+	// Type is single, EP, album or compilation
+	Type        string   `json:"type"`
+	Genres      []string `json:"genres,omitempty"`
+	ReleaseDate *Date    `json:"release_date,omitempty" swaggertype:"string" example:"2024-05-17"`
+	Label       string   `json:"label,omitempty"`
+	Upc         string   `json:"upc,omitempty"`
+	// Artists are other musicians on the album, each of them approves the credit
+	Artists []*ArtistLink `json:"artists,omitempty"`
 }
 
 type AlbumsCollection struct {
@@ -40,6 +58,13 @@ func ToDtoAlbum(a *models.Album) *Album {
 		Name:      a.Name,
 		CoverFile: a.CoverFile,
 		Type:      a.Type,
+		Genres:    a.Genres,
+
+		ReleaseDate: toDtoDate(a.ReleaseDate),
+		Label:       a.Label,
+		Upc:         a.Upc,
+		Artists:     toDtoArtists(a.Artists),
+		Loudness:    toDtoLoudness(a.Loudness),
 	}
 }
 
@@ -49,6 +74,11 @@ func ToModelAlbum(a *Album) *models.Album {
 		Name:      a.Name,
 		CoverFile: a.CoverFile,
 		Type:      a.Type,
+		Genres:    a.Genres,
+
+		ReleaseDate: toModelDate(a.ReleaseDate),
+		Label:       a.Label,
+		Upc:         a.Upc,
 	}
 }
 
@@ -58,5 +88,11 @@ func ToModelAlbumWithId(id uint64, a *AlbumWithoutId) *models.Album {
 		Name:      a.Name,
 		CoverFile: a.CoverFile,
 		Type:      a.Type,
+		Genres:    a.Genres,
+
+		ReleaseDate: toModelDate(a.ReleaseDate),
+		Label:       a.Label,
+		Upc:         a.Upc,
+		Artists:     toModelArtists(a.Artists),
 	}
 }
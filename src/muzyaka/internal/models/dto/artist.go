@@ -0,0 +1,70 @@
+package dto
+
+import "src/internal/models"
+
+type Artist struct {
+	MusicianId uint64 `json:"musician_id"`
+	Name       string `json:"name"`
+	// Role is primary, featured, remixer or producer
+	Role string `json:"role"`
+	// Approved is false until the musician approves the credit
+	Approved bool `json:"approved"`
+}
+
+type ArtistLink struct {
+	MusicianId uint64 `json:"musician_id"`
+	// Role is featured, remixer or producer, a musician is linked once
+	Role string `json:"role"`
+}
+
+type ArtistCredit struct {
+	Id        uint64 `json:"id"`
+	AlbumId   uint64 `json:"album_id"`
+	AlbumName string `json:"album_name"`
+	// TrackId is omitted for album credits
+	TrackId   uint64 `json:"track_id,omitempty"`
+	TrackName string `json:"track_name,omitempty"`
+	Role      string `json:"role"`
+	Approved  bool   `json:"approved"`
+}
+
+type ArtistCreditsCollection struct {
+	Credits []*ArtistCredit `json:"credits"`
+}
+
+func toDtoArtists(artists []*models.Artist) []*Artist {
+	res := make([]*Artist, 0, len(artists))
+	for _, v := range artists {
+		res = append(res, &Artist{
+			MusicianId: v.MusicianId,
+			Name:       v.Name,
+			Role:       v.Role,
+			Approved:   v.Approved,
+		})
+	}
+	return res
+}
+
+func toModelArtists(links []*ArtistLink) []*models.Artist {
+	res := make([]*models.Artist, 0, len(links))
+	for _, v := range links {
+		res = append(res, &models.Artist{MusicianId: v.MusicianId, Role: v.Role})
+	}
+	return res
+}
+
+func ToDtoArtistCredits(credits []*models.ArtistCredit) *ArtistCreditsCollection {
+	res := &ArtistCreditsCollection{Credits: make([]*ArtistCredit, 0, len(credits))}
+	for _, v := range credits {
+		res.Credits = append(res.Credits, &ArtistCredit{
+			Id:        v.Id,
+			AlbumId:   v.AlbumId,
+			AlbumName: v.AlbumName,
+			TrackId:   v.TrackId,
+			TrackName: v.TrackName,
+			Role:      v.Role,
+			Approved:  v.Approved,
+		})
+	}
+	return res
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type Chart struct {
+	Kind       string        `json:"type"`
+	Period     string        `json:"period"`
+	Genre      string        `json:"genre,omitempty"`
+	ComputedAt time.Time     `json:"computed_at"`
+	Entries    []*ChartEntry `json:"entries"`
+}
+
+type ChartEntry struct {
+	Position         int     `json:"position"`
+	PreviousPosition *int    `json:"previous_position"`
+	Movement         string  `json:"movement"`
+	Id               uint64  `json:"id"`
+	Name             string  `json:"name"`
+	Score            float64 `json:"score"`
+}
+
+func ToDtoChart(chart *models.Chart, genre string) *Chart {
+	res := &Chart{
+		Kind:       chart.Kind,
+		Period:     chart.Period,
+		Genre:      genre,
+		ComputedAt: chart.ComputedAt,
+		Entries:    make([]*ChartEntry, 0, len(chart.Entries)),
+	}
+
+	for _, v := range chart.Entries {
+		res.Entries = append(res.Entries, &ChartEntry{
+			Position:         v.Position,
+			PreviousPosition: v.PreviousPosition,
+			Movement:         v.Movement(),
+			Id:               v.ItemId,
+			Name:             v.Name,
+			Score:            v.Score,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type Concert struct {
+	Id          uint64  `json:"id"`
+	MusicianId  uint64  `json:"musician_id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Venue       string  `json:"venue"`
+	City        string  `json:"city"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	// StartsAt has the offset of TimeZone
+	StartsAt  time.Time `json:"starts_at"`
+	TimeZone  string    `json:"time_zone"`
+	TicketUrl string    `json:"ticket_url,omitempty"`
+	SoldOut   bool      `json:"sold_out"`
+}
+
+type ConcertWithoutId struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Venue       string  `json:"venue"`
+	City        string  `json:"city"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	// StartsAt is RFC 3339 with any offset, it is moved to TimeZone
+	StartsAt time.Time `json:"starts_at"`
+	// TimeZone is an IANA name, e.g. "Europe/Berlin"
+	TimeZone  string `json:"time_zone"`
+	TicketUrl string `json:"ticket_url"`
+	SoldOut   bool   `json:"sold_out"`
+}
+
+type CreateConcertResponse struct {
+	Id uint64 `json:"id"`
+}
+
+type ConcertsCollection struct {
+	Items []*Concert `json:"items"`
+}
+
+type NearbyConcert struct {
+	Concert
+	DistanceKm float64 `json:"distance_km"`
+}
+
+type NearbyConcertsCollection struct {
+	Items []*NearbyConcert `json:"items"`
+}
+
+func ToModelConcertWithoutId(c *ConcertWithoutId, id uint64) *models.Concert {
+	return &models.Concert{
+		Id:          id,
+		Title:       c.Title,
+		Description: c.Description,
+		Venue:       c.Venue,
+		City:        c.City,
+		Latitude:    c.Latitude,
+		Longitude:   c.Longitude,
+		StartsAt:    c.StartsAt,
+		TimeZone:    c.TimeZone,
+		TicketUrl:   c.TicketUrl,
+		SoldOut:     c.SoldOut,
+	}
+}
+
+func ToDtoConcert(c *models.Concert) *Concert {
+	return &Concert{
+		Id:          c.Id,
+		MusicianId:  c.MusicianId,
+		Title:       c.Title,
+		Description: c.Description,
+		Venue:       c.Venue,
+		City:        c.City,
+		Latitude:    c.Latitude,
+		Longitude:   c.Longitude,
+		StartsAt:    c.StartsAt,
+		TimeZone:    c.TimeZone,
+		TicketUrl:   c.TicketUrl,
+		SoldOut:     c.SoldOut,
+	}
+}
+
+func ToDtoConcerts(concerts []*models.Concert) *ConcertsCollection {
+	res := &ConcertsCollection{Items: make([]*Concert, 0, len(concerts))}
+
+	for _, v := range concerts {
+		res.Items = append(res.Items, ToDtoConcert(v))
+	}
+
+	return res
+}
+
+func ToDtoNearbyConcerts(concerts []*models.NearbyConcert) *NearbyConcertsCollection {
+	res := &NearbyConcertsCollection{Items: make([]*NearbyConcert, 0, len(concerts))}
+
+	for _, v := range concerts {
+		res.Items = append(res.Items, &NearbyConcert{
+			Concert:    *ToDtoConcert(v.Concert),
+			DistanceKm: v.DistanceKm,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date encoded as YYYY-MM-DD
+type Date struct {
+	time.Time
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(dateLayout))
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return err
+	}
+	d.Time = t
+
+	return nil
+}
+
+func toDtoDate(t time.Time) *Date {
+	if t.IsZero() {
+		return nil
+	}
+	return &Date{Time: t}
+}
+
+func toModelDate(d *Date) time.Time {
+	if d == nil {
+		return time.Time{}
+	}
+	return d.Time
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type FeedItem struct {
+	Kind         string    `json:"kind"`
+	Id           uint64    `json:"id"`
+	Name         string    `json:"name"`
+	MusicianId   uint64    `json:"musician_id"`
+	MusicianName string    `json:"musician_name"`
+	AlbumId      uint64    `json:"album_id,omitempty"`
+	PublishedAt  time.Time `json:"published_at"`
+}
+
+type Feed struct {
+	Items []*FeedItem `json:"items"`
+}
+
+func ToDtoFeed(items []*models.FeedItem) *Feed {
+	res := &Feed{Items: make([]*FeedItem, 0, len(items))}
+	for _, v := range items {
+		res.Items = append(res.Items, &FeedItem{
+			Kind:         v.Kind,
+			Id:           v.ItemId,
+			Name:         v.Name,
+			MusicianId:   v.MusicianId,
+			MusicianName: v.MusicianName,
+			AlbumId:      v.AlbumId,
+			PublishedAt:  v.PublishedAt,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,60 @@
+package dto
+
+import "src/internal/models"
+
+type Genre struct {
+	Id   uint64 `json:"id"`
+	Name string `json:"name"`
+	// ParentId is omitted for top level genres
+	ParentId  uint64   `json:"parent_id,omitempty"`
+	Subgenres []*Genre `json:"subgenres,omitempty"`
+}
+
+type GenreWithoutId struct {
+	Name     string `json:"name"`
+	ParentId uint64 `json:"parent_id,omitempty"`
+}
+
+type GenresTree struct {
+	Genres []*Genre `json:"genres"`
+}
+
+type CreateGenreResponse struct {
+	Id uint64 `json:"id"`
+}
+
+func ToDtoGenre(g *models.Genre) *Genre {
+	return &Genre{
+		Id:       g.Id,
+		Name:     g.Name,
+		ParentId: g.ParentId,
+	}
+}
+
+func ToModelGenreWithoutId(g *GenreWithoutId, id uint64) *models.Genre {
+	return &models.Genre{
+		Id:       id,
+		Name:     g.Name,
+		ParentId: g.ParentId,
+	}
+}
+
+// ToDtoGenresTree nests subgenres into their parents keeping the order of genres
+func ToDtoGenresTree(genres []*models.Genre) *GenresTree {
+	byId := make(map[uint64]*Genre, len(genres))
+	for _, v := range genres {
+		byId[v.Id] = ToDtoGenre(v)
+	}
+
+	res := &GenresTree{Genres: make([]*Genre, 0)}
+	for _, v := range genres {
+		genre := byId[v.Id]
+		if parent, ok := byId[v.ParentId]; ok {
+			parent.Subgenres = append(parent.Subgenres, genre)
+		} else {
+			res.Genres = append(res.Genres, genre)
+		}
+	}
+
+	return res
+}
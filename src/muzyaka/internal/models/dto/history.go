@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type Play struct {
+	// PlayedAt is the time the play started, now if omitted
+	PlayedAt   *time.Time `json:"played_at,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+	Completed  bool       `json:"completed"`
+}
+
+type HistoryEntry struct {
+	Track      *TrackMeta `json:"track"`
+	PlayedAt   time.Time  `json:"played_at"`
+	DurationMs int64      `json:"duration_ms"`
+	Completed  bool       `json:"completed"`
+}
+
+type History struct {
+	Plays []*HistoryEntry `json:"plays"`
+}
+
+func ToModelPlay(play *Play, userId uint64, trackId uint64) *models.Play {
+	res := &models.Play{
+		UserId:    userId,
+		TrackId:   trackId,
+		Duration:  time.Duration(play.DurationMs) * time.Millisecond,
+		Completed: play.Completed,
+	}
+
+	if play.PlayedAt != nil {
+		res.PlayedAt = *play.PlayedAt
+	}
+
+	return res
+}
+
+func ToDtoHistory(entries []*models.HistoryEntry) *History {
+	res := &History{Plays: make([]*HistoryEntry, 0, len(entries))}
+	for _, v := range entries {
+		res.Plays = append(res.Plays, &HistoryEntry{
+			Track:      ToDtoTrackMeta(v.Track),
+			PlayedAt:   v.PlayedAt,
+			DurationMs: v.Duration.Milliseconds(),
+			Completed:  v.Completed,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type LibraryAdd struct {
+	Id uint64 `json:"id"`
+}
+
+type LibraryItem struct {
+	Kind    string    `json:"kind"`
+	Id      uint64    `json:"id"`
+	Name    string    `json:"name"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+type Library struct {
+	Items []*LibraryItem `json:"items"`
+}
+
+// LibraryContains is in the same order as the requested ids
+type LibraryContains struct {
+	Contains []bool `json:"contains"`
+}
+
+func ToDtoLibrary(items []*models.LibraryItem) *Library {
+	res := &Library{Items: make([]*LibraryItem, 0, len(items))}
+	for _, v := range items {
+		res.Items = append(res.Items, &LibraryItem{
+			Kind:    v.Kind,
+			Id:      v.ItemId,
+			Name:    v.Name,
+			AddedAt: v.AddedAt,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "src/internal/models"
+
+type SetLyricsRequest struct {
+	// Language is a BCP 47 tag, e.g. en or pt-BR
+	Language string `json:"language"`
+	// Lyrics are plain text or LRC with [mm:ss.xx] timestamps
+	Lyrics string `json:"lyrics"`
+}
+
+type LyricsLine struct {
+	// OffsetMs is 0 for plain lyrics
+	OffsetMs int64  `json:"offset_ms"`
+	Text     string `json:"text"`
+}
+
+type Lyrics struct {
+	TrackId  uint64        `json:"track_id"`
+	Language string        `json:"language"`
+	Synced   bool          `json:"synced"`
+	Lines    []*LyricsLine `json:"lines"`
+	// Languages are all languages the track has lyrics in
+	Languages []string `json:"languages"`
+}
+
+func ToDtoLyrics(l *models.Lyrics) *Lyrics {
+	res := &Lyrics{
+		TrackId:   l.TrackId,
+		Language:  l.Language,
+		Synced:    l.Synced,
+		Lines:     make([]*LyricsLine, 0, len(l.Lines)),
+		Languages: l.Languages,
+	}
+
+	for _, v := range l.Lines {
+		res.Lines = append(res.Lines, &LyricsLine{OffsetMs: v.OffsetMs, Text: v.Text})
+	}
+
+	return res
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "src/internal/models"
+
+type Waveform struct {
+	DurationMs int64 `json:"duration_ms"`
+	// Peaks are peak amplitudes from 0 to 1 evenly spread over the track
+	Peaks []float32 `json:"peaks"`
+}
+
+func ToDtoWaveform(w *models.Waveform) *Waveform {
+	res := &Waveform{DurationMs: w.Duration.Milliseconds()}
+	if len(w.Peaks) > 0 {
+		res.Peaks = w.Peaks[0]
+	}
+
+	return res
+}
+
+// Loudness is ReplayGain 2.0 data, players apply GainDb and keep the peak
+// below 0 dBTP
+type Loudness struct {
+	IntegratedLufs float64 `json:"integrated_lufs"`
+	TruePeakDbtp   float64 `json:"true_peak_dbtp"`
+	GainDb         float64 `json:"gain_db"`
+}
+
+func toDtoLoudness(l *models.Loudness) *Loudness {
+	if l == nil {
+		return nil
+	}
+
+	return &Loudness{
+		IntegratedLufs: l.Integrated,
+		TruePeakDbtp:   l.TruePeak,
+		GainDb:         l.Gain(),
+	}
+}
@@ -3,18 +3,47 @@ package dto
 import "src/internal/models"
 
 type Merch struct {
-	Id          uint64   `json:"id"`
-	Name        string   `json:"name"`
-	PhotoFiles  [][]byte `json:"photo_files"`
-	Description string   `json:"description"`
-	OrderUrl    string   `json:"order_url"`
+	Id          uint64          `json:"id"`
+	Name        string          `json:"name"`
+	PhotoFiles  [][]byte        `json:"photo_files"`
+	Description string          `json:"description"`
+	OrderUrl    string          `json:"order_url"`
+	Variants    []*MerchVariant `json:"variants"`
+	// Availability is in_stock, low_stock, out_of_stock or external
+	Availability string `json:"availability"`
 }
 
 type MerchWithoutId struct {
 	Name        string   `json:"name"`
 	PhotoFiles  [][]byte `json:"photo_files"`
 	Description string   `json:"description"`
-	OrderUrl    string   `json:"order_url"`
+	// OrderUrl may be empty for merch with variants
+	OrderUrl string          `json:"order_url"`
+	Variants []*MerchVariant `json:"variants"`
+}
+
+type Price struct {
+	// Amount is in minor units, e.g. cents
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// Formatted is set in responses only
+	Formatted string `json:"formatted,omitempty"`
+}
+
+type MerchVariant struct {
+	// Id is set in responses only, variants are matched by sku on update
+	Id     uint64 `json:"id,omitempty"`
+	Sku    string `json:"sku"`
+	Size   string `json:"size,omitempty"`
+	Colour string `json:"colour,omitempty"`
+	Price  Price  `json:"price"`
+	// Stock is the items on hand including the ones in orders not shipped
+	// yet, which are Reserved. Reserved is set in responses only.
+	Stock    int `json:"stock"`
+	Reserved int `json:"reserved,omitempty"`
+	// LowStockThreshold is 5 if 0
+	LowStockThreshold int    `json:"low_stock_threshold,omitempty"`
+	Availability      string `json:"availability,omitempty"`
 }
 
 type MerchWithMusician struct {
@@ -37,6 +66,7 @@ func ToModelMerchWithoutId(m *MerchWithoutId, id uint64) *models.Merch {
 		PhotoFiles:  m.PhotoFiles,
 		Description: m.Description,
 		OrderUrl:    m.OrderUrl,
+		Variants:    ToModelMerchVariants(m.Variants),
 	}
 }
 
@@ -47,28 +77,70 @@ func ToModelMerch(m *Merch) *models.Merch {
 		PhotoFiles:  m.PhotoFiles,
 		Description: m.Description,
 		OrderUrl:    m.OrderUrl,
+		Variants:    ToModelMerchVariants(m.Variants),
 	}
 }
 
+func ToModelMerchVariants(variants []*MerchVariant) []*models.MerchVariant {
+	var res []*models.MerchVariant
+
+	for _, v := range variants {
+		res = append(res, &models.MerchVariant{
+			Sku:               v.Sku,
+			Size:              v.Size,
+			Colour:            v.Colour,
+			Price:             models.Price{Amount: v.Price.Amount, Currency: v.Price.Currency},
+			Stock:             v.Stock,
+			LowStockThreshold: v.LowStockThreshold,
+		})
+	}
+
+	return res
+}
+
+func ToDtoPrice(p models.Price) Price {
+	return Price{
+		Amount:    p.Amount,
+		Currency:  p.Currency,
+		Formatted: p.String(),
+	}
+}
+
+func ToDtoMerchVariants(variants []*models.MerchVariant) []*MerchVariant {
+	res := make([]*MerchVariant, 0, len(variants))
+
+	for _, v := range variants {
+		res = append(res, &MerchVariant{
+			Id:                v.Id,
+			Sku:               v.Sku,
+			Size:              v.Size,
+			Colour:            v.Colour,
+			Price:             ToDtoPrice(v.Price),
+			Stock:             v.Stock,
+			Reserved:          v.Reserved,
+			LowStockThreshold: v.LowStockThreshold,
+			Availability:      v.Availability(),
+		})
+	}
+
+	return res
+}
+
 func ToDtoMerchWithMusician(m *models.Merch, musicianId uint64) *MerchWithMusician {
 	return &MerchWithMusician{
-		Merch: Merch{
-			Id:          m.Id,
-			Name:        m.Name,
-			PhotoFiles:  m.PhotoFiles,
-			Description: m.Description,
-			OrderUrl:    m.OrderUrl,
-		},
+		Merch:      *ToDtoMerch(m),
 		MusicianId: musicianId,
 	}
 }
 
 func ToDtoMerch(m *models.Merch) *Merch {
 	return &Merch{
-		Id:          m.Id,
-		Name:        m.Name,
-		PhotoFiles:  m.PhotoFiles,
-		Description: m.Description,
-		OrderUrl:    m.OrderUrl,
+		Id:           m.Id,
+		Name:         m.Name,
+		PhotoFiles:   m.PhotoFiles,
+		Description:  m.Description,
+		OrderUrl:     m.OrderUrl,
+		Variants:     ToDtoMerchVariants(m.Variants),
+		Availability: m.Availability(),
 	}
 }
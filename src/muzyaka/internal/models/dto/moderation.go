@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type DuplicateReport struct {
+	Id uint64 `json:"id"`
+	// TrackId sounds like OriginalTrackId of another musician uploaded before
+	TrackId         uint64    `json:"track_id"`
+	OriginalTrackId uint64    `json:"original_track_id"`
+	Similarity      float64   `json:"similarity"`
+	Status          string    `json:"status"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type DuplicateReportsCollection struct {
+	Reports []*DuplicateReport `json:"reports"`
+}
+
+type ResolveReport struct {
+	// Status is confirmed or dismissed
+	Status string `json:"status"`
+}
+
+func ToDtoDuplicateReport(e *models.DuplicateReport) *DuplicateReport {
+	return &DuplicateReport{
+		Id:              e.Id,
+		TrackId:         e.TrackId,
+		OriginalTrackId: e.OriginalTrackId,
+		Similarity:      e.Similarity,
+		Status:          e.Status,
+		CreatedAt:       e.CreatedAt,
+	}
+}
+
+func ToDtoDuplicateReports(reports []*models.DuplicateReport) *DuplicateReportsCollection {
+	res := make([]*DuplicateReport, 0, len(reports))
+	for _, v := range reports {
+		res = append(res, ToDtoDuplicateReport(v))
+	}
+
+	return &DuplicateReportsCollection{Reports: res}
+}
@@ -9,6 +9,8 @@ type Musician struct {
 	Name        string   `json:"musician_name"`
 	PhotoFiles  [][]byte `json:"photo_files"`
 	Description string   `json:"description"`
+	// Followers is ignored on update
+	Followers int64 `json:"followers"`
 }
 
 type MusicianWithoutId struct {
@@ -45,5 +47,6 @@ func ToDtoMusician(musician *models.Musician) *Musician {
 		Name:        musician.Name,
 		PhotoFiles:  musician.PhotoFiles,
 		Description: musician.Description,
+		Followers:   musician.Followers,
 	}
 }
@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type SetCartItemRequest struct {
+	// Quantity 0 removes the variant from the cart
+	Quantity int `json:"quantity"`
+}
+
+type CartItem struct {
+	MerchId    uint64        `json:"merch_id"`
+	MerchName  string        `json:"merch_name"`
+	MusicianId uint64        `json:"musician_id"`
+	Variant    *MerchVariant `json:"variant"`
+	Quantity   int           `json:"quantity"`
+}
+
+type Cart struct {
+	Items []*CartItem `json:"items"`
+	// Totals has one price per currency in the cart
+	Totals []Price `json:"totals"`
+}
+
+type OrderItem struct {
+	// VariantId is omitted once the variant is deleted
+	VariantId uint64 `json:"variant_id,omitempty"`
+	MerchId   uint64 `json:"merch_id,omitempty"`
+	Name      string `json:"name"`
+	Sku       string `json:"sku"`
+	Size      string `json:"size,omitempty"`
+	Colour    string `json:"colour,omitempty"`
+	UnitPrice Price  `json:"unit_price"`
+	Quantity  int    `json:"quantity"`
+}
+
+type Order struct {
+	Id         uint64 `json:"id"`
+	UserId     uint64 `json:"user_id,omitempty"`
+	MusicianId uint64 `json:"musician_id"`
+	// Status is pending, paid, shipped, cancelled or refunded
+	Status string `json:"status"`
+	Total  Price  `json:"total"`
+	// PaymentUrl is where a pending order is paid
+	PaymentUrl string       `json:"payment_url,omitempty"`
+	Items      []*OrderItem `json:"items"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+type OrdersCollection struct {
+	Items []*Order `json:"items"`
+}
+
+type UpdateOrderStatusRequest struct {
+	// Status is shipped, cancelled or refunded
+	Status string `json:"status"`
+}
+
+func ToDtoCart(c *models.Cart) *Cart {
+	res := &Cart{
+		Items:  make([]*CartItem, 0, len(c.Items)),
+		Totals: make([]Price, 0, len(c.Totals)),
+	}
+
+	for _, v := range c.Items {
+		res.Items = append(res.Items, &CartItem{
+			MerchId:    v.MerchId,
+			MerchName:  v.MerchName,
+			MusicianId: v.MusicianId,
+			Variant:    ToDtoMerchVariants([]*models.MerchVariant{v.Variant})[0],
+			Quantity:   v.Quantity,
+		})
+	}
+
+	for _, v := range c.Totals {
+		res.Totals = append(res.Totals, ToDtoPrice(v))
+	}
+
+	return res
+}
+
+func ToDtoOrder(o *models.Order) *Order {
+	res := &Order{
+		Id:         o.Id,
+		UserId:     o.UserId,
+		MusicianId: o.MusicianId,
+		Status:     o.Status,
+		Total:      ToDtoPrice(o.Total),
+		Items:      make([]*OrderItem, 0, len(o.Items)),
+		CreatedAt:  o.CreatedAt,
+		UpdatedAt:  o.UpdatedAt,
+	}
+
+	if o.Status == models.OrderPending {
+		res.PaymentUrl = o.PaymentUrl
+	}
+
+	for _, v := range o.Items {
+		res.Items = append(res.Items, &OrderItem{
+			VariantId: v.VariantId,
+			MerchId:   v.MerchId,
+			Name:      v.Name,
+			Sku:       v.Sku,
+			Size:      v.Size,
+			Colour:    v.Colour,
+			UnitPrice: ToDtoPrice(v.UnitPrice),
+			Quantity:  v.Quantity,
+		})
+	}
+
+	return res
+}
+
+func ToDtoOrders(orders []*models.Order) *OrdersCollection {
+	res := &OrdersCollection{Items: make([]*Order, 0, len(orders))}
+
+	for _, v := range orders {
+		res.Items = append(res.Items, ToDtoOrder(v))
+	}
+
+	return res
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+type OutboxStats struct {
+	Pending           int64      `json:"pending"`
+	DeadLettered      int64      `json:"dead_lettered"`
+	OldestPending     *time.Time `json:"oldest_pending,omitempty"`
+	LagSeconds        float64    `json:"lag_seconds"`
+	SentTotal         uint64     `json:"sent_total"`
+	FailedTotal       uint64     `json:"failed_total"`
+	DeadLetteredTotal uint64     `json:"dead_lettered_total"`
+	PurgedTotal       uint64     `json:"purged_total"`
+}
+
+type OutboxReplayRequest struct {
+	EventIds []string   `json:"event_ids"`
+	DeadOnly bool       `json:"dead_only"`
+	Since    *time.Time `json:"since"`
+}
+
+type OutboxReplayResponse struct {
+	Replayed int64 `json:"replayed"`
+}
+
+func ToDtoOutboxStats(m *models.OutboxMetrics) *OutboxStats {
+	return &OutboxStats{
+		Pending:           m.Pending,
+		DeadLettered:      m.DeadLettered,
+		OldestPending:     m.OldestPending,
+		LagSeconds:        m.Lag.Seconds(),
+		SentTotal:         m.SentTotal,
+		FailedTotal:       m.FailedTotal,
+		DeadLetteredTotal: m.DeadLetteredTotal,
+		PurgedTotal:       m.PurgedTotal,
+	}
+}
+
+func ToModelOutboxReplayFilter(r *OutboxReplayRequest) *models.OutboxReplayFilter {
+	return &models.OutboxReplayFilter{
+		EventIds: r.EventIds,
+		DeadOnly: r.DeadOnly,
+		Since:    r.Since,
+	}
+}
@@ -1,12 +1,19 @@
 package dto
 
-import "src/internal/models"
+import (
+	"src/internal/models"
+	"time"
+)
 
 type Playlist struct {
-	Id          uint64 `json:"id"`
-	Name        string `json:"name"`
-	CoverFile   []byte `json:"cover_file"`
-	Description string `json:"description"`
+	Id            uint64      `json:"id"`
+	Name          string      `json:"name"`
+	CoverFile     []byte      `json:"cover_file"`
+	Description   string      `json:"description"`
+	Kind          string      `json:"kind,omitempty"`
+	Visibility    string      `json:"visibility,omitempty"`
+	Collaborative bool        `json:"collaborative"`
+	Rules         *SmartRules `json:"rules,omitempty"`
 }
 
 type PlaylistWithUser struct {
@@ -18,6 +25,72 @@ type PlaylistWithoutId struct {
 	Name        string `json:"name"`
 	CoverFile   []byte `json:"cover_file"`
 	Description string `json:"description"`
+	// Visibility and Collaborative are used on creation only, public by default
+	// and private for smart playlists
+	Visibility    string `json:"visibility,omitempty"`
+	Collaborative bool   `json:"collaborative,omitempty"`
+	// Rules make the playlist smart on creation and replace the rules of
+	// a smart playlist on update
+	Rules *SmartRules `json:"rules,omitempty"`
+}
+
+// SmartRules are matched against all tracks, likes, plays and follows are
+// those of the playlist owner
+type SmartRules struct {
+	// Match is "all" (default) or "any" of the conditions
+	Match      string           `json:"match,omitempty"`
+	Conditions []SmartCondition `json:"conditions"`
+	// Sort is newest (default), name, most_played or random
+	Sort string `json:"sort,omitempty"`
+	// Limit is 50 by default and at most 100
+	Limit int `json:"limit,omitempty"`
+}
+
+// SmartCondition is one of: genre eq/neq (value), genre in (values),
+// musician eq (value), musician followed, name contains (value),
+// liked or played any, liked or played within_days (days)
+type SmartCondition struct {
+	Field  string   `json:"field"`
+	Op     string   `json:"op"`
+	Value  string   `json:"value,omitempty"`
+	Values []string `json:"values,omitempty"`
+	Days   int      `json:"days,omitempty"`
+}
+
+type PlaylistSharing struct {
+	Visibility    string `json:"visibility"`
+	Collaborative bool   `json:"collaborative"`
+}
+
+type CreateInviteRequest struct {
+	// TtlHours is 168 (a week) by default and at most 720
+	TtlHours int `json:"ttl_hours,omitempty"`
+}
+
+type PlaylistInvite struct {
+	Token      string    `json:"token"`
+	PlaylistId uint64    `json:"playlist_id"`
+	Url        string    `json:"url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type AcceptInviteResponse struct {
+	PlaylistId uint64 `json:"playlist_id"`
+}
+
+type Collaborators struct {
+	UserIds []uint64 `json:"user_ids"`
+}
+
+type PlaylistTrack struct {
+	TrackMeta
+	// AddedBy is omitted for tracks added by the service or by deleted users
+	AddedBy uint64    `json:"added_by,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+type PlaylistTracksCollection struct {
+	Tracks []*PlaylistTrack `json:"tracks"`
 }
 
 type CreatePlaylistResponse struct {
@@ -47,20 +120,27 @@ func ToModelPlaylist(playlist *Playlist) *models.Playlist {
 
 func ToModelPlaylistWithoutId(playlist *PlaylistWithoutId, id uint64) *models.Playlist {
 	return &models.Playlist{
-		Id:          id,
-		Name:        playlist.Name,
-		CoverFile:   playlist.CoverFile,
-		Description: playlist.Description,
+		Id:            id,
+		Name:          playlist.Name,
+		CoverFile:     playlist.CoverFile,
+		Description:   playlist.Description,
+		Visibility:    playlist.Visibility,
+		Collaborative: playlist.Collaborative,
+		Rules:         ToModelSmartRules(playlist.Rules),
 	}
 }
 
 func ToDtoPlaylistWithUser(p *models.Playlist, userId uint64) *PlaylistWithUser {
 	return &PlaylistWithUser{
 		Playlist: Playlist{
-			Id:          p.Id,
-			Name:        p.Name,
-			CoverFile:   p.CoverFile,
-			Description: p.Description,
+			Id:            p.Id,
+			Name:          p.Name,
+			CoverFile:     p.CoverFile,
+			Description:   p.Description,
+			Kind:          p.Kind,
+			Visibility:    p.Visibility,
+			Collaborative: p.Collaborative,
+			Rules:         ToDtoSmartRules(p.Rules),
 		},
 		UserId: userId,
 	}
@@ -68,9 +148,93 @@ func ToDtoPlaylistWithUser(p *models.Playlist, userId uint64) *PlaylistWithUser
 
 func ToDtoPlaylist(playlist *models.Playlist) *Playlist {
 	return &Playlist{
-		Name:        playlist.Name,
-		CoverFile:   playlist.CoverFile,
-		Description: playlist.Description,
-		Id:          playlist.Id,
+		Name:          playlist.Name,
+		CoverFile:     playlist.CoverFile,
+		Description:   playlist.Description,
+		Id:            playlist.Id,
+		Kind:          playlist.Kind,
+		Visibility:    playlist.Visibility,
+		Collaborative: playlist.Collaborative,
+		Rules:         ToDtoSmartRules(playlist.Rules),
 	}
 }
+
+func ToDtoPlaylistInvite(invite *models.PlaylistInvite) *PlaylistInvite {
+	return &PlaylistInvite{
+		Token:      invite.Token,
+		PlaylistId: invite.PlaylistId,
+		Url:        "/api/playlist/invites/" + invite.Token,
+		ExpiresAt:  invite.ExpiresAt,
+	}
+}
+
+func ToDtoPlaylistTracks(tracks []*models.PlaylistTrack) *PlaylistTracksCollection {
+	res := &PlaylistTracksCollection{Tracks: make([]*PlaylistTrack, 0, len(tracks))}
+	for _, v := range tracks {
+		res.Tracks = append(res.Tracks, &PlaylistTrack{
+			TrackMeta: *ToDtoTrackMeta(v.Track),
+			AddedBy:   v.AddedBy,
+			AddedAt:   v.AddedAt,
+		})
+	}
+
+	return res
+}
+
+type PlaylistEntry struct {
+	Title    string `json:"title"`
+	Musician string `json:"musician,omitempty"`
+	// Duration is in seconds, omitted if unknown
+	Duration int    `json:"duration,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+type PlaylistImportResponse struct {
+	Id        uint64           `json:"id"`
+	Matched   int              `json:"matched"`
+	Unmatched []*PlaylistEntry `json:"unmatched"`
+}
+
+func ToDtoPlaylistImportResult(res *models.PlaylistImportResult) *PlaylistImportResponse {
+	unmatched := make([]*PlaylistEntry, 0, len(res.Unmatched))
+	for _, v := range res.Unmatched {
+		unmatched = append(unmatched, &PlaylistEntry{
+			Title:    v.Title,
+			Musician: v.Musician,
+			Duration: v.Duration,
+			Location: v.Location,
+		})
+	}
+
+	return &PlaylistImportResponse{
+		Id:        res.PlaylistId,
+		Matched:   res.Matched,
+		Unmatched: unmatched,
+	}
+}
+
+func ToModelSmartRules(rules *SmartRules) *models.SmartRules {
+	if rules == nil {
+		return nil
+	}
+
+	res := &models.SmartRules{Match: rules.Match, Sort: rules.Sort, Limit: rules.Limit}
+	for _, c := range rules.Conditions {
+		res.Conditions = append(res.Conditions, models.SmartCondition(c))
+	}
+
+	return res
+}
+
+func ToDtoSmartRules(rules *models.SmartRules) *SmartRules {
+	if rules == nil {
+		return nil
+	}
+
+	res := &SmartRules{Match: rules.Match, Sort: rules.Sort, Limit: rules.Limit}
+	for _, c := range rules.Conditions {
+		res.Conditions = append(res.Conditions, SmartCondition(c))
+	}
+
+	return res
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "src/internal/models"
+
+type RecommendedTracks struct {
+	Tracks []*TrackMeta `json:"tracks"`
+	Source string       `json:"source"`
+}
+
+func ToDtoRecommendedTracks(m *models.Recommendations) *RecommendedTracks {
+	var tracks []*TrackMeta
+	for _, v := range m.Tracks {
+		tracks = append(tracks, ToDtoTrackMeta(v))
+	}
+
+	return &RecommendedTracks{
+		Tracks: tracks,
+		Source: m.Source,
+	}
+}
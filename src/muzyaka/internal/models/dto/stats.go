@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"src/internal/models"
+	"time"
+)
+
+const DateLayout = time.DateOnly
+
+type StatsPoint struct {
+	Day          string `json:"day"`
+	Plays        int64  `json:"plays"`
+	Skips        int64  `json:"skips"`
+	Likes        int64  `json:"likes"`
+	PlaylistAdds int64  `json:"playlist_adds"`
+	Listeners    int64  `json:"listeners"`
+}
+
+type MerchStatsPoint struct {
+	Day    string `json:"day"`
+	Clicks int64  `json:"clicks"`
+}
+
+type ItemStats struct {
+	Id     uint64        `json:"id"`
+	Name   string        `json:"name"`
+	Series []*StatsPoint `json:"series"`
+}
+
+type MerchStats struct {
+	Id     uint64             `json:"id"`
+	Name   string             `json:"name"`
+	Series []*MerchStatsPoint `json:"series"`
+}
+
+type PlaylistReach struct {
+	Id     uint64 `json:"id"`
+	Name   string `json:"name"`
+	Tracks int64  `json:"tracks"`
+}
+
+type MusicianStats struct {
+	MusicianId   uint64           `json:"musician_id"`
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	Tracks       []*ItemStats     `json:"tracks"`
+	Albums       []*ItemStats     `json:"albums"`
+	Merch        []*MerchStats    `json:"merch"`
+	TopPlaylists []*PlaylistReach `json:"top_playlists"`
+}
+
+func toDtoItemStats(items []*models.ItemStats) []*ItemStats {
+	res := make([]*ItemStats, 0, len(items))
+	for _, v := range items {
+		series := make([]*StatsPoint, 0, len(v.Series))
+		for _, p := range v.Series {
+			series = append(series, &StatsPoint{
+				Day:          p.Day.Format(DateLayout),
+				Plays:        p.Plays,
+				Skips:        p.Skips,
+				Likes:        p.Likes,
+				PlaylistAdds: p.PlaylistAdds,
+				Listeners:    p.Listeners,
+			})
+		}
+		res = append(res, &ItemStats{Id: v.Id, Name: v.Name, Series: series})
+	}
+
+	return res
+}
+
+func ToDtoMusicianStats(stats *models.MusicianStats) *MusicianStats {
+	merch := make([]*MerchStats, 0, len(stats.Merch))
+	for _, v := range stats.Merch {
+		series := make([]*MerchStatsPoint, 0, len(v.Series))
+		for _, p := range v.Series {
+			series = append(series, &MerchStatsPoint{Day: p.Day.Format(DateLayout), Clicks: p.Clicks})
+		}
+		merch = append(merch, &MerchStats{Id: v.Id, Name: v.Name, Series: series})
+	}
+
+	playlists := make([]*PlaylistReach, 0, len(stats.TopPlaylists))
+	for _, v := range stats.TopPlaylists {
+		playlists = append(playlists, &PlaylistReach{Id: v.PlaylistId, Name: v.Name, Tracks: v.Tracks})
+	}
+
+	return &MusicianStats{
+		MusicianId:   stats.MusicianId,
+		From:         stats.From.Format(DateLayout),
+		To:           stats.To.Format(DateLayout),
+		Tracks:       toDtoItemStats(stats.Tracks),
+		Albums:       toDtoItemStats(stats.Albums),
+		Merch:        merch,
+		TopPlaylists: playlists,
+	}
+}
@@ -7,14 +7,45 @@ type Genres struct {
 }
 
 type TrackMeta struct {
-	Id    uint64  `json:"id"`
-	Name  string  `json:"name"`
-	Genre *string `json:"genre"`
+	Id   uint64 `json:"id"`
+	Name string `json:"name"`
+	// Genre is the primary genre, the first of Genres
+	Genre  *string  `json:"genre"`
+	Genres []string `json:"genres,omitempty"`
+	Moods  []string `json:"moods,omitempty"`
+	// AlbumGenres are inherited from the album
+	AlbumGenres     []string       `json:"album_genres,omitempty"`
+	Isrc            string         `json:"isrc,omitempty"`
+	Explicit        bool           `json:"explicit"`
+	FeaturedArtists []string       `json:"featured_artists,omitempty"`
+	Credits         []*TrackCredit `json:"credits,omitempty"`
+	// Artists are musicians linked to the track
+	Artists []*Artist `json:"artists,omitempty"`
+	// Loudness is missing until the track is analyzed, AlbumLoudness until
+	// every track of the album is
+	Loudness      *Loudness `json:"loudness,omitempty"`
+	AlbumLoudness *Loudness `json:"album_loudness,omitempty"`
 }
 
 type TrackMetaWithoutId struct {
-	Name  string  `json:"name"`
-	Genre *string `json:"genre"`
+	Name string `json:"name"`
+	// Genre is added to Genres as the primary genre
+	Genre  *string  `json:"genre"`
+	Genres []string `json:"genres,omitempty"`
+	Moods  []string `json:"moods,omitempty"`
+	// Isrc may contain hyphens
+	Isrc            string         `json:"isrc,omitempty"`
+	Explicit        bool           `json:"explicit"`
+	FeaturedArtists []string       `json:"featured_artists,omitempty"`
+	Credits         []*TrackCredit `json:"credits,omitempty"`
+	// Artists are musicians linked to the track, each of them approves the credit
+	Artists []*ArtistLink `json:"artists,omitempty"`
+}
+
+type TrackCredit struct {
+	// Role is composer or producer
+	Role string `json:"role"`
+	Name string `json:"name"`
 }
 
 type TrackObjectWithoutId struct {
@@ -46,9 +77,20 @@ func ToDtoTrackMeta(m *models.TrackMeta) *TrackMeta {
 	}
 
 	return &TrackMeta{
-		Id:    m.Id,
-		Name:  m.Name,
-		Genre: genre,
+		Id:          m.Id,
+		Name:        m.Name,
+		Genre:       genre,
+		Genres:      m.Genres,
+		Moods:       m.Moods,
+		AlbumGenres: m.AlbumGenres,
+
+		Isrc:            m.Isrc,
+		Explicit:        m.Explicit,
+		FeaturedArtists: m.FeaturedArtists,
+		Credits:         toDtoTrackCredits(m.Credits),
+		Artists:         toDtoArtists(m.Artists),
+		Loudness:        toDtoLoudness(m.Loudness),
+		AlbumLoudness:   toDtoLoudness(m.AlbumLoudness),
 	}
 }
 
@@ -65,6 +107,14 @@ func ToModelTrackObjectWithoutId(t *TrackObjectWithoutId, id uint64, source stri
 			Source: source,
 			Name:   t.Name,
 			Genre:  genre,
+			Genres: t.Genres,
+			Moods:  t.Moods,
+
+			Isrc:            t.Isrc,
+			Explicit:        t.Explicit,
+			FeaturedArtists: t.FeaturedArtists,
+			Credits:         toModelTrackCredits(t.Credits),
+			Artists:         toModelArtists(t.Artists),
 		},
 		Payload: t.Payload,
 	}
@@ -79,11 +129,38 @@ func ToDtoTrackObjectWithSource(t *models.TrackObject) *TrackObjectWithSource {
 	}
 	return &TrackObjectWithSource{
 		TrackMeta: TrackMeta{
-			Id:    t.Id,
-			Name:  t.Name,
-			Genre: genre,
+			Id:          t.Id,
+			Name:        t.Name,
+			Genre:       genre,
+			Genres:      t.Genres,
+			Moods:       t.Moods,
+			AlbumGenres: t.AlbumGenres,
+
+			Isrc:            t.Isrc,
+			Explicit:        t.Explicit,
+			FeaturedArtists: t.FeaturedArtists,
+			Credits:         toDtoTrackCredits(t.Credits),
+			Artists:         toDtoArtists(t.Artists),
+			Loudness:        toDtoLoudness(t.Loudness),
+			AlbumLoudness:   toDtoLoudness(t.AlbumLoudness),
 		},
 		Source:  t.Source,
 		Payload: t.Payload,
 	}
 }
+
+func toDtoTrackCredits(credits []*models.TrackCredit) []*TrackCredit {
+	res := make([]*TrackCredit, 0, len(credits))
+	for _, v := range credits {
+		res = append(res, &TrackCredit{Role: v.Role, Name: v.Name})
+	}
+	return res
+}
+
+func toModelTrackCredits(credits []*TrackCredit) []*models.TrackCredit {
+	res := make([]*models.TrackCredit, 0, len(credits))
+	for _, v := range credits {
+		res = append(res, &models.TrackCredit{Role: v.Role, Name: v.Name})
+	}
+	return res
+}
@@ -8,6 +8,8 @@ var (
 	ErrInvalidPassword = errors.New("invalid password")
 	ErrInvalidLogin    = errors.New("invalid login")
 	ErrInvalidGenre    = errors.New("invalid genre")
+	ErrGenreExists     = errors.New("genre already exists")
+	ErrInvalidMusician = errors.New("invalid musician")
 	ErrInvalidToken    = errors.New("invalid token")
 	ErrEmptyAlbum      = errors.New("album cannot be empty")
 
@@ -19,4 +21,13 @@ var (
 
 	ErrInvalidPayload    = errors.New("error, invalid payload")
 	ErrInvalidFileFormat = errors.New("error, invalid file format")
+
+	ErrUnknownEvent     = errors.New("unknown event type")
+	ErrAlreadyProcessed = errors.New("event is already processed")
+
+	ErrOverloaded = errors.New("service is overloaded, try again later")
+	ErrExpired    = errors.New("link is expired")
+
+	ErrOutOfStock        = errors.New("not enough items in stock")
+	ErrInvalidTransition = errors.New("order status can't be changed")
 )
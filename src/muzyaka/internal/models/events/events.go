@@ -0,0 +1,138 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Version is bumped on every incompatible change of Event or of any payload
+const Version = 2
+
+const (
+	HeaderEventId = "event_id"
+	HeaderVersion = "version"
+	HeaderType    = "type"
+)
+
+const (
+	AggregateTrack    = "track"
+	AggregateAlbum    = "album"
+	AggregateMusician = "musician"
+	AggregateMerch    = "merch"
+	AggregatePlaylist = "playlist"
+	AggregateUser     = "user"
+	AggregateOrder    = "order"
+	AggregateConcert  = "concert"
+)
+
+const (
+	TrackAdded   = "track.added"
+	TrackUpdated = "track.updated"
+	TrackDeleted = "track.deleted"
+
+	AlbumCreated = "album.created"
+	AlbumUpdated = "album.updated"
+	AlbumDeleted = "album.deleted"
+
+	MusicianCreated = "musician.created"
+	MusicianUpdated = "musician.updated"
+	MusicianDeleted = "musician.deleted"
+
+	MerchCreated = "merch.created"
+	MerchUpdated = "merch.updated"
+	MerchDeleted = "merch.deleted"
+	MerchClicked = "merch.clicked"
+
+	ConcertCreated = "concert.created"
+	ConcertUpdated = "concert.updated"
+	ConcertDeleted = "concert.deleted"
+
+	PlaylistCreated      = "playlist.created"
+	PlaylistUpdated      = "playlist.updated"
+	PlaylistDeleted      = "playlist.deleted"
+	PlaylistTrackAdded   = "playlist.track_added"
+	PlaylistTrackRemoved = "playlist.track_removed"
+
+	PlaylistCollaboratorAdded   = "playlist.collaborator_added"
+	PlaylistCollaboratorRemoved = "playlist.collaborator_removed"
+
+	UserCreated   = "user.created"
+	UserUpdated   = "user.updated"
+	UserDeleted   = "user.deleted"
+	TrackLiked    = "user.track_liked"
+	TrackDisliked = "user.track_disliked"
+	TrackPlayed   = "user.track_played"
+
+	MusicianFollowed   = "user.musician_followed"
+	MusicianUnfollowed = "user.musician_unfollowed"
+	AlbumSaved         = "user.album_saved"
+	AlbumUnsaved       = "user.album_unsaved"
+	PlaylistSaved      = "user.playlist_saved"
+	PlaylistUnsaved    = "user.playlist_unsaved"
+
+	OrderCreated       = "order.created"
+	OrderStatusChanged = "order.status_changed"
+)
+
+// Catalogue maps every known event type to the aggregate it belongs to.
+// Deleting an aggregate does not produce events for rows removed by cascade,
+// except for tracks and albums of a deleted musician.
+var Catalogue = map[string]string{
+	TrackAdded:   AggregateTrack,
+	TrackUpdated: AggregateTrack,
+	TrackDeleted: AggregateTrack,
+
+	AlbumCreated: AggregateAlbum,
+	AlbumUpdated: AggregateAlbum,
+	AlbumDeleted: AggregateAlbum,
+
+	MusicianCreated: AggregateMusician,
+	MusicianUpdated: AggregateMusician,
+	MusicianDeleted: AggregateMusician,
+
+	MerchCreated: AggregateMerch,
+	MerchUpdated: AggregateMerch,
+	MerchDeleted: AggregateMerch,
+	MerchClicked: AggregateMerch,
+
+	ConcertCreated: AggregateConcert,
+	ConcertUpdated: AggregateConcert,
+	ConcertDeleted: AggregateConcert,
+
+	PlaylistCreated:      AggregatePlaylist,
+	PlaylistUpdated:      AggregatePlaylist,
+	PlaylistDeleted:      AggregatePlaylist,
+	PlaylistTrackAdded:   AggregatePlaylist,
+	PlaylistTrackRemoved: AggregatePlaylist,
+
+	PlaylistCollaboratorAdded:   AggregatePlaylist,
+	PlaylistCollaboratorRemoved: AggregatePlaylist,
+
+	UserCreated:   AggregateUser,
+	UserUpdated:   AggregateUser,
+	UserDeleted:   AggregateUser,
+	TrackLiked:    AggregateUser,
+	TrackDisliked: AggregateUser,
+	TrackPlayed:   AggregateUser,
+
+	MusicianFollowed:   AggregateUser,
+	MusicianUnfollowed: AggregateUser,
+	AlbumSaved:         AggregateUser,
+	AlbumUnsaved:       AggregateUser,
+	PlaylistSaved:      AggregateUser,
+	PlaylistUnsaved:    AggregateUser,
+
+	OrderCreated:       AggregateOrder,
+	OrderStatusChanged: AggregateOrder,
+}
+
+// Event is the envelope every message is published in
+type Event struct {
+	Version       int             `json:"version"`
+	EventId       string          `json:"event_id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateId   uint64          `json:"aggregate_id"`
+	Type          string          `json:"type"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
@@ -0,0 +1,120 @@
+package events
+
+import "time"
+
+// TrackPayload is used by track.* events. Only TrackId is set for track.deleted.
+type TrackPayload struct {
+	TrackId uint64 `json:"track_id"`
+	AlbumId uint64 `json:"album_id,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Name    string `json:"name,omitempty"`
+	GenreId uint64 `json:"genre_id,omitempty"`
+	// SourceChanged is set by track.updated if the source is replaced
+	SourceChanged bool `json:"source_changed,omitempty"`
+}
+
+// AlbumPayload is used by album.* events. Only AlbumId is set for album.deleted.
+type AlbumPayload struct {
+	AlbumId    uint64 `json:"album_id"`
+	MusicianId uint64 `json:"musician_id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Type       string `json:"type,omitempty"`
+}
+
+// MusicianPayload is used by musician.* events. Only MusicianId is set for musician.deleted.
+type MusicianPayload struct {
+	MusicianId  uint64 `json:"musician_id"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// MerchPayload is used by merch.* events. Only MerchId and MusicianId are set
+// for merch.deleted and merch.clicked.
+type MerchPayload struct {
+	MerchId     uint64 `json:"merch_id"`
+	MusicianId  uint64 `json:"musician_id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	OrderUrl    string `json:"order_url,omitempty"`
+}
+
+// ConcertPayload is used by concert.* events. Only ConcertId and MusicianId
+// are set for concert.deleted.
+type ConcertPayload struct {
+	ConcertId  uint64     `json:"concert_id"`
+	MusicianId uint64     `json:"musician_id,omitempty"`
+	Title      string     `json:"title,omitempty"`
+	City       string     `json:"city,omitempty"`
+	StartsAt   *time.Time `json:"starts_at,omitempty"`
+	SoldOut    bool       `json:"sold_out,omitempty"`
+}
+
+// PlaylistPayload is used by playlist.created, playlist.updated and playlist.deleted
+type PlaylistPayload struct {
+	PlaylistId    uint64 `json:"playlist_id"`
+	UserId        uint64 `json:"user_id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Visibility    string `json:"visibility,omitempty"`
+	Collaborative bool   `json:"collaborative,omitempty"`
+}
+
+// PlaylistTrackPayload is used by playlist.track_added and playlist.track_removed
+type PlaylistTrackPayload struct {
+	PlaylistId uint64 `json:"playlist_id"`
+	TrackId    uint64 `json:"track_id"`
+	AddedBy    uint64 `json:"added_by,omitempty"`
+}
+
+// CollaboratorPayload is used by playlist.collaborator_added and playlist.collaborator_removed
+type CollaboratorPayload struct {
+	PlaylistId uint64 `json:"playlist_id"`
+	UserId     uint64 `json:"user_id"`
+}
+
+// OrderPayload is used by order.created and order.status_changed.
+// UserId is 0 for orders of deleted users.
+type OrderPayload struct {
+	OrderId    uint64 `json:"order_id"`
+	UserId     uint64 `json:"user_id,omitempty"`
+	MusicianId uint64 `json:"musician_id"`
+	Status     string `json:"status"`
+	Total      int64  `json:"total"`
+	Currency   string `json:"currency"`
+}
+
+// UserPayload is used by user.created, user.updated and user.deleted.
+// Credentials and email are never published.
+type UserPayload struct {
+	UserId uint64 `json:"user_id"`
+	Name   string `json:"name,omitempty"`
+	Role   string `json:"role,omitempty"`
+}
+
+// LikePayload is used by user.track_liked and user.track_disliked
+type LikePayload struct {
+	UserId  uint64 `json:"user_id"`
+	TrackId uint64 `json:"track_id"`
+}
+
+// PlayPayload is used by user.track_played
+type PlayPayload struct {
+	UserId     uint64    `json:"user_id"`
+	TrackId    uint64    `json:"track_id"`
+	PlayedAt   time.Time `json:"played_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Completed  bool      `json:"completed"`
+}
+
+// FollowPayload is used by user.musician_followed and user.musician_unfollowed
+type FollowPayload struct {
+	UserId     uint64 `json:"user_id"`
+	MusicianId uint64 `json:"musician_id"`
+}
+
+// SavedPayload is used by user.album_saved, user.album_unsaved,
+// user.playlist_saved and user.playlist_unsaved
+type SavedPayload struct {
+	UserId uint64 `json:"user_id"`
+	ItemId uint64 `json:"item_id"`
+}
@@ -0,0 +1,28 @@
+package events
+
+import "time"
+
+// Events produced by the recommendation service and consumed by us.
+// They use the same Event envelope as outgoing events.
+const (
+	RecSysSimilarTracks = "recsys.similar_tracks"
+	RecSysTrending      = "recsys.trending"
+)
+
+type ScoredTrack struct {
+	TrackId uint64  `json:"track_id"`
+	Score   float64 `json:"score"`
+}
+
+// SimilarTracksPayload replaces the whole list of similar tracks for TrackId.
+type SimilarTracksPayload struct {
+	TrackId    uint64        `json:"track_id"`
+	Similar    []ScoredTrack `json:"similar"`
+	ComputedAt time.Time     `json:"computed_at"`
+}
+
+// TrendingPayload replaces the whole trending list.
+type TrendingPayload struct {
+	Tracks     []ScoredTrack `json:"tracks"`
+	ComputedAt time.Time     `json:"computed_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	FeedItemAlbum = "album"
+	FeedItemTrack = "track"
+	FeedItemMerch = "merch"
+)
+
+// FeedItem is a release of a musician. AlbumId is set for tracks only,
+// names are filled on read.
+type FeedItem struct {
+	Kind         string
+	ItemId       uint64
+	MusicianId   uint64
+	MusicianName string
+	AlbumId      uint64
+	Name         string
+	PublishedAt  time.Time
+}
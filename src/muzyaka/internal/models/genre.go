@@ -0,0 +1,71 @@
+package models
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxGenreNameLength = 100
+	MaxMoodLength      = 50
+	// MaxGenres limits genres of a track or an album
+	MaxGenres = 5
+	MaxMoods  = 10
+)
+
+// Genre is a node of the genre tree, ParentId is 0 for top level genres
+type Genre struct {
+	Id       uint64
+	Name     string
+	ParentId uint64
+}
+
+// TrackFilter matches tracks by part of the name, by any of Genres including
+// their subgenres and album genres, and by any of Moods. Empty fields match all.
+type TrackFilter struct {
+	Name   string
+	Genres []string
+	Moods  []string
+	// Lyrics are words to find in lyrics of any language
+	Lyrics string
+}
+
+// NormalizeGenres trims names and drops duplicates ignoring case, keeping the order
+func NormalizeGenres(genres []string) ([]string, error) {
+	return normalizeTags(genres, MaxGenres, MaxGenreNameLength, false)
+}
+
+// NormalizeMoods lowercases moods and drops duplicates, keeping the order
+func NormalizeMoods(moods []string) ([]string, error) {
+	return normalizeTags(moods, MaxMoods, MaxMoodLength, true)
+}
+
+func normalizeTags(tags []string, maxCount int, maxLength int, lower bool) ([]string, error) {
+	var res []string
+	seen := make(map[string]bool, len(tags))
+
+	for _, v := range tags {
+		v = strings.TrimSpace(v)
+		if lower {
+			v = strings.ToLower(v)
+		}
+
+		if v == "" || utf8.RuneCountInString(v) > maxLength {
+			return nil, ErrInvalidParameter
+		}
+
+		// genres are matched ignoring case as well
+		key := strings.ToLower(v)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		res = append(res, v)
+	}
+
+	if len(res) > maxCount {
+		return nil, ErrInvalidParameter
+	}
+
+	return res, nil
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Play is a single listening of a track by a user
+type Play struct {
+	Id        uint64
+	UserId    uint64
+	TrackId   uint64
+	PlayedAt  time.Time
+	Duration  time.Duration
+	Completed bool
+}
+
+type HistoryEntry struct {
+	Play
+	Track *TrackMeta
+}
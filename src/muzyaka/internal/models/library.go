@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	LibraryTrack    = "track"
+	LibraryAlbum    = "album"
+	LibraryPlaylist = "playlist"
+	LibraryMusician = "musician"
+)
+
+var LibraryKinds = map[string]bool{
+	LibraryTrack:    true,
+	LibraryAlbum:    true,
+	LibraryPlaylist: true,
+	LibraryMusician: true,
+}
+
+const (
+	// LibrarySortAdded lists recently added items first
+	LibrarySortAdded = "added_at"
+	// LibrarySortName lists items alphabetically
+	LibrarySortName = "name"
+)
+
+// LibraryItem is a liked track, saved album or playlist or followed musician
+type LibraryItem struct {
+	Kind    string
+	ItemId  uint64
+	Name    string
+	AddedAt time.Time
+}
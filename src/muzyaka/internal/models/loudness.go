@@ -0,0 +1,23 @@
+package models
+
+import "math"
+
+const (
+	// ReferenceLoudness is the target level of ReplayGain 2.0 in LUFS
+	ReferenceLoudness = -18
+	// MinLoudness is the loudness of silence and its true peak
+	MinLoudness = -70
+)
+
+// Loudness is measured following EBU R128
+type Loudness struct {
+	// Integrated is in LUFS
+	Integrated float64
+	// TruePeak is in dBTP
+	TruePeak float64
+}
+
+// Gain is in dB, it brings the loudness to ReferenceLoudness
+func (l *Loudness) Gain() float64 {
+	return math.Round((ReferenceLoudness-l.Integrated)*100) / 100
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxLyricsLength is in bytes of the uploaded text
+	MaxLyricsLength     = 64 * 1024
+	MaxLyricsLineLength = 500
+)
+
+type LyricsLine struct {
+	// OffsetMs is from the start of the track, 0 for plain lyrics
+	OffsetMs int64
+	Text     string
+}
+
+type Lyrics struct {
+	TrackId  uint64
+	Language string
+	// Synced lyrics have an offset for every line
+	Synced bool
+	Lines  []*LyricsLine
+	// Languages are all languages of the track lyrics, filled on read
+	Languages []string
+}
+
+// PlainText joins the lines without offsets
+func (l *Lyrics) PlainText() string {
+	lines := make([]string, 0, len(l.Lines))
+	for _, v := range l.Lines {
+		lines = append(lines, v.Text)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+var languageRe = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLanguage lowercases a BCP 47 language tag such as en or pt-BR
+func NormalizeLanguage(language string) (string, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if len(language) > 35 || !languageRe.MatchString(language) {
+		return "", ErrInvalidParameter
+	}
+
+	return language, nil
+}
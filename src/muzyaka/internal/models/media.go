@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Waveform is made from the source of a track after it is uploaded
+type Waveform struct {
+	// SourceTag identifies the version of the source it is made from
+	SourceTag string
+	Duration  time.Duration
+	// Peaks are peak amplitudes from 0 to 1, one list per resolution
+	Peaks [][]float32
+}
+
+// Fingerprint is the acoustic fingerprint of a track, see lib/fingerprint
+type Fingerprint struct {
+	TrackId uint64
+	Values  []uint32
+}
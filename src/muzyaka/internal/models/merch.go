@@ -1,9 +1,128 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	MerchInStock    = "in_stock"
+	MerchLowStock   = "low_stock"
+	MerchOutOfStock = "out_of_stock"
+	// MerchExternal merch has no variants and is sold through OrderUrl only
+	MerchExternal = "external"
+)
+
+// DefaultLowStockThreshold is used for variants with LowStockThreshold 0
+const DefaultLowStockThreshold = 5
+
+// Currencies maps supported ISO 4217 codes to the number of minor units
+var Currencies = map[string]int{
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"RUB": 2,
+	"JPY": 0,
+}
+
 type Merch struct {
 	Id          uint64
 	Name        string
 	PhotoFiles  [][]byte
 	Description string
-	OrderUrl    string
+	// OrderUrl is optional for merch sold through the store
+	OrderUrl string
+	Variants []*MerchVariant
+}
+
+// Price is an amount in minor units of Currency, e.g. cents
+type Price struct {
+	Amount   int64
+	Currency string
+}
+
+// String formats the price in major units, e.g. "19.99 USD"
+func (p Price) String() string {
+	exponent := Currencies[p.Currency]
+	if exponent == 0 {
+		return fmt.Sprintf("%d %s", p.Amount, p.Currency)
+	}
+
+	divisor := int64(1)
+	for i := 0; i < exponent; i++ {
+		divisor *= 10
+	}
+
+	sign := ""
+	amount := p.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	minor := fmt.Sprintf("%d", amount%divisor)
+	minor = strings.Repeat("0", exponent-len(minor)) + minor
+
+	return fmt.Sprintf("%s%d.%s %s", sign, amount/divisor, minor, p.Currency)
+}
+
+// MerchVariant has Stock items on hand, Reserved of them are in orders not
+// shipped yet. Reserved is kept on update.
+type MerchVariant struct {
+	Id                uint64
+	Sku               string
+	Size              string
+	Colour            string
+	Price             Price
+	Stock             int
+	Reserved          int
+	LowStockThreshold int
+}
+
+// Available is the number of items that can be ordered
+func (v *MerchVariant) Available() int {
+	return max(v.Stock-v.Reserved, 0)
+}
+
+type MerchFilter struct {
+	// Currency is required with MinPrice or MaxPrice
+	Currency string
+	MinPrice *int64
+	MaxPrice *int64
+	// Available keeps merch with at least one variant in stock
+	Available bool
+}
+
+func (v *MerchVariant) Availability() string {
+	threshold := v.LowStockThreshold
+	if threshold == 0 {
+		threshold = DefaultLowStockThreshold
+	}
+
+	switch {
+	case v.Available() <= 0:
+		return MerchOutOfStock
+	case v.Available() <= threshold:
+		return MerchLowStock
+	}
+
+	return MerchInStock
+}
+
+// Availability is the best availability among the variants
+func (m *Merch) Availability() string {
+	if len(m.Variants) == 0 {
+		return MerchExternal
+	}
+
+	res := MerchOutOfStock
+	for _, v := range m.Variants {
+		switch v.Availability() {
+		case MerchInStock:
+			return MerchInStock
+		case MerchLowStock:
+			res = MerchLowStock
+		}
+	}
+
+	return res
 }
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	ReportPending   = "pending"
+	ReportConfirmed = "confirmed"
+	ReportDismissed = "dismissed"
+)
+
+var ReportStatuses = map[string]bool{
+	ReportPending:   true,
+	ReportConfirmed: true,
+	ReportDismissed: true,
+}
+
+// DuplicateReport flags a track that sounds like a track of another
+// musician uploaded before it
+type DuplicateReport struct {
+	Id              uint64
+	TrackId         uint64
+	OriginalTrackId uint64
+	// Similarity is from 0.5 for unrelated tracks to 1 for the same recording
+	Similarity float64
+	Status     string
+	CreatedAt  time.Time
+}
@@ -5,4 +5,5 @@ type Musician struct {
 	Name        string
 	PhotoFiles  [][]byte
 	Description string
+	Followers   int64
 }
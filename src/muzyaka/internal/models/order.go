@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+const (
+	OrderPending   = "pending"
+	OrderPaid      = "paid"
+	OrderShipped   = "shipped"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+)
+
+var OrderStatuses = map[string]bool{
+	OrderPending:   true,
+	OrderPaid:      true,
+	OrderShipped:   true,
+	OrderCancelled: true,
+	OrderRefunded:  true,
+}
+
+// OrderTransitions lists the statuses each status can be changed to
+var OrderTransitions = map[string]map[string]bool{
+	OrderPending: {OrderPaid: true, OrderCancelled: true},
+	OrderPaid:    {OrderShipped: true, OrderRefunded: true},
+	OrderShipped: {OrderRefunded: true},
+}
+
+// CanTransition reports whether an order in status from can be moved to status to
+func CanTransition(from string, to string) bool {
+	return OrderTransitions[from][to]
+}
+
+// Restocks reports whether moving an order from status from to status to
+// returns its items to stock. Shipped items are not returned.
+func Restocks(from string, to string) bool {
+	return (from == OrderPending || from == OrderPaid) && (to == OrderCancelled || to == OrderRefunded)
+}
+
+// MaxCartQuantity is the most items of one variant a cart can hold
+const MaxCartQuantity = 99
+
+type CartItem struct {
+	MerchId    uint64
+	MerchName  string
+	MusicianId uint64
+	Variant    *MerchVariant
+	Quantity   int
+}
+
+type Cart struct {
+	Items []*CartItem
+	// Totals has one price per currency in the cart
+	Totals []Price
+}
+
+// Order holds the items of one musician in one currency, a checkout creates
+// an order for each of them
+type Order struct {
+	Id         uint64
+	UserId     uint64
+	MusicianId uint64
+	Status     string
+	Total      Price
+	PaymentId  string
+	PaymentUrl string
+	Items      []*OrderItem
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// OrderItem keeps a copy of the variant as it was sold, VariantId is 0 once
+// the variant is deleted
+type OrderItem struct {
+	VariantId uint64
+	MerchId   uint64
+	Name      string
+	Sku       string
+	Size      string
+	Colour    string
+	UnitPrice Price
+	Quantity  int
+}
+
+const (
+	PaymentSucceeded = "succeeded"
+	PaymentFailed    = "failed"
+)
+
+type Payment struct {
+	Id string
+	// Url is where the user completes the payment
+	Url string
+}
+
+// PaymentEvent is a payment provider notification, Id is unique per notification
+type PaymentEvent struct {
+	Id        string
+	PaymentId string
+	Status    string
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type OutboxStats struct {
+	Pending       int64
+	DeadLettered  int64
+	OldestPending *time.Time
+}
+
+type OutboxMetrics struct {
+	OutboxStats
+	Lag               time.Duration
+	SentTotal         uint64
+	FailedTotal       uint64
+	DeadLetteredTotal uint64
+	PurgedTotal       uint64
+}
+
+// OutboxReplayFilter selects already processed events to send again.
+// At least one of the fields has to be set.
+type OutboxReplayFilter struct {
+	EventIds []string
+	DeadOnly bool
+	Since    *time.Time
+}
@@ -1,8 +1,94 @@
 package models
 
+import "time"
+
+const (
+	PlaylistKindUser = "user"
+	// PlaylistKindDailyMix playlists are generated by the service and can't be edited
+	PlaylistKindDailyMix = "daily_mix"
+)
+
+const (
+	// PlaylistPrivate playlists are readable by the owner and collaborators only
+	PlaylistPrivate = "private"
+	// PlaylistUnlisted playlists are readable by anyone who knows the id
+	PlaylistUnlisted = "unlisted"
+	PlaylistPublic   = "public"
+)
+
+var PlaylistVisibilities = map[string]bool{
+	PlaylistPrivate:  true,
+	PlaylistUnlisted: true,
+	PlaylistPublic:   true,
+}
+
 type Playlist struct {
-	Id          uint64
+	Id            uint64
+	Name          string
+	CoverFile     []byte
+	Description   string
+	Kind          string
+	Visibility    string
+	Collaborative bool
+	// Rules are set for smart playlists only
+	Rules *SmartRules
+}
+
+type DailyMix struct {
+	Playlist
+	TrackIds []uint64
+}
+
+// PlaylistAccess is what read and edit checks need to know about a playlist and a user
+type PlaylistAccess struct {
+	OwnerId        uint64
+	Kind           string
+	Visibility     string
+	Collaborative  bool
+	IsCollaborator bool
+}
+
+// PlaylistTrack is a track of a playlist, AddedBy is 0 for tracks added by
+// the service or by deleted users
+type PlaylistTrack struct {
+	TrackId uint64
+	AddedBy uint64
+	AddedAt time.Time
+	Track   *TrackMeta
+}
+
+// PlaylistInvite lets anyone who has the token join a collaborative playlist until ExpiresAt
+type PlaylistInvite struct {
+	Token      string
+	PlaylistId uint64
+	CreatedBy  uint64
+	ExpiresAt  time.Time
+}
+
+const (
+	PlaylistFormatM3U8 = "m3u8"
+	PlaylistFormatXSPF = "xspf"
+	PlaylistFormatJSPF = "jspf"
+)
+
+// PlaylistEntry is a track of an exported or imported playlist file,
+// Duration is in seconds and 0 if unknown
+type PlaylistEntry struct {
+	Title    string
+	Musician string
+	Duration int
+	Location string
+}
+
+// PlaylistDocument is the format independent content of a playlist file
+type PlaylistDocument struct {
 	Name        string
-	CoverFile   []byte
 	Description string
+	Entries     []*PlaylistEntry
+}
+
+type PlaylistImportResult struct {
+	PlaylistId uint64
+	Matched    int
+	Unmatched  []*PlaylistEntry
 }
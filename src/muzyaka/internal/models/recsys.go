@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Sources of recommendations
+const (
+	RecSourcePrecomputed = "precomputed"
+	RecSourceRemote      = "remote"
+	RecSourceLocal       = "local"
+	RecSourceFallback    = "fallback"
+	RecSourcePersonal    = "personal"
+	RecSourceTrending    = "trending"
+)
+
+type Recommendations struct {
+	Tracks []*TrackMeta
+	Source string
+}
+
+type ScoredTrack struct {
+	TrackId uint64
+	Score   float64
+}
+
+type SimilarTracks struct {
+	TrackId    uint64
+	Tracks     []*ScoredTrack
+	ComputedAt time.Time
+}
+
+type TrendingTracks struct {
+	Tracks     []*ScoredTrack
+	ComputedAt time.Time
+}
+
+// TrackInfo is what recommendations are diversified by
+type TrackInfo struct {
+	Id         uint64
+	GenreId    uint64
+	MusicianId uint64
+}
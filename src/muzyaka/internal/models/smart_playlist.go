@@ -0,0 +1,74 @@
+package models
+
+// PlaylistKindSmart playlists have no tracks of their own, they are resolved
+// from SmartRules every time they are read
+const PlaylistKindSmart = "smart"
+
+const (
+	SmartMatchAll = "all"
+	SmartMatchAny = "any"
+)
+
+const (
+	// SmartFieldGenre supports eq, neq and in
+	SmartFieldGenre = "genre"
+	// SmartFieldMusician supports eq and followed
+	SmartFieldMusician = "musician"
+	// SmartFieldName supports contains
+	SmartFieldName = "name"
+	// SmartFieldLiked supports any and within_days
+	SmartFieldLiked = "liked"
+	// SmartFieldPlayed supports any and within_days
+	SmartFieldPlayed = "played"
+)
+
+const (
+	SmartOpEq         = "eq"
+	SmartOpNeq        = "neq"
+	SmartOpIn         = "in"
+	SmartOpContains   = "contains"
+	SmartOpFollowed   = "followed"
+	SmartOpAny        = "any"
+	SmartOpWithinDays = "within_days"
+)
+
+const (
+	SmartSortNewest     = "newest"
+	SmartSortName       = "name"
+	SmartSortMostPlayed = "most_played"
+	SmartSortRandom     = "random"
+)
+
+// SmartOps lists the operators allowed for each field
+var SmartOps = map[string]map[string]bool{
+	SmartFieldGenre:    {SmartOpEq: true, SmartOpNeq: true, SmartOpIn: true},
+	SmartFieldMusician: {SmartOpEq: true, SmartOpFollowed: true},
+	SmartFieldName:     {SmartOpContains: true},
+	SmartFieldLiked:    {SmartOpAny: true, SmartOpWithinDays: true},
+	SmartFieldPlayed:   {SmartOpAny: true, SmartOpWithinDays: true},
+}
+
+var SmartSorts = map[string]bool{
+	SmartSortNewest:     true,
+	SmartSortName:       true,
+	SmartSortMostPlayed: true,
+	SmartSortRandom:     true,
+}
+
+// SmartRules select tracks for a smart playlist. Likes, plays and follows
+// are those of the playlist owner.
+type SmartRules struct {
+	Match      string
+	Conditions []SmartCondition
+	Sort       string
+	Limit      int
+}
+
+// SmartCondition uses Value, Values or Days depending on Op
+type SmartCondition struct {
+	Field  string
+	Op     string
+	Value  string
+	Values []string
+	Days   int
+}
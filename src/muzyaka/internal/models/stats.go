@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// StatsPoint holds the numbers of a single day, Clicks are set for merch only
+type StatsPoint struct {
+	Day          time.Time
+	Plays        int64
+	Skips        int64
+	Likes        int64
+	PlaylistAdds int64
+	Listeners    int64
+	Clicks       int64
+}
+
+// ItemStats is a daily series of a track, an album or a merch item.
+// Days without activity are omitted.
+type ItemStats struct {
+	Id     uint64
+	Name   string
+	Series []*StatsPoint
+}
+
+// PlaylistReach is a user playlist featuring tracks of a musician
+type PlaylistReach struct {
+	PlaylistId uint64
+	Name       string
+	Tracks     int64
+}
+
+type MusicianStats struct {
+	MusicianId   uint64
+	From         time.Time
+	To           time.Time
+	Tracks       []*ItemStats
+	Albums       []*ItemStats
+	Merch        []*ItemStats
+	TopPlaylists []*PlaylistReach
+}
@@ -1,10 +1,145 @@
 package models
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"src/internal/lib/validation"
+	"strings"
+	"unicode/utf8"
+)
+
 type TrackMeta struct {
 	Id     uint64
 	Source string
 	Name   string
+	// Genre is the primary genre, the first of Genres. Genres may be left
+	// empty when a track with at most one genre is saved.
 	Genre  string
+	Genres []string
+	Moods  []string
+	// AlbumGenres are inherited from the album and are not saved with the track
+	AlbumGenres []string
+
+	Isrc     string
+	Explicit bool
+	// Artists are musicians with a profile on the track, FeaturedArtists
+	// and Credits are names of people without one
+	Artists         []*Artist
+	FeaturedArtists []string
+	// Credits are composers and producers
+	Credits []*TrackCredit
+
+	// Loudness is nil until the source is measured, AlbumLoudness is nil
+	// while any track of the album is not measured. Both are read only.
+	Loudness      *Loudness
+	AlbumLoudness *Loudness
+}
+
+const (
+	CreditComposer = "composer"
+	CreditProducer = "producer"
+)
+
+var CreditRoles = map[string]bool{
+	CreditComposer: true,
+	CreditProducer: true,
+}
+
+const (
+	MaxCredits          = 20
+	MaxCreditNameLength = 254
+)
+
+type TrackCredit struct {
+	Role string
+	Name string
+}
+
+// Normalize checks and normalizes tags, ISRC and credits of the track
+func (t *TrackMeta) Normalize() error {
+	if err := t.NormalizeTags(); err != nil {
+		return err
+	}
+
+	if t.Isrc != "" {
+		isrc, ok := validation.NormalizeIsrc(t.Isrc)
+		if !ok {
+			return ErrInvalidParameter
+		}
+		t.Isrc = isrc
+	}
+
+	featured, err := normalizeTags(t.FeaturedArtists, MaxCredits, MaxCreditNameLength, false)
+	if err != nil {
+		return err
+	}
+	t.FeaturedArtists = featured
+
+	var credits []*TrackCredit
+	seen := make(map[TrackCredit]bool, len(t.Credits))
+	for _, v := range t.Credits {
+		credit := TrackCredit{Role: strings.ToLower(strings.TrimSpace(v.Role)), Name: strings.TrimSpace(v.Name)}
+		if !CreditRoles[credit.Role] || credit.Name == "" || utf8.RuneCountInString(credit.Name) > MaxCreditNameLength {
+			return ErrInvalidParameter
+		}
+
+		if seen[credit] {
+			continue
+		}
+		seen[credit] = true
+		credits = append(credits, &credit)
+	}
+
+	if len(credits) > MaxCredits {
+		return ErrInvalidParameter
+	}
+	t.Credits = credits
+
+	artists, err := NormalizeArtists(t.Artists)
+	if err != nil {
+		return err
+	}
+	t.Artists = artists
+
+	return nil
+}
+
+// NormalizeTags makes Genre the first of Genres and normalizes genres and moods
+func (t *TrackMeta) NormalizeTags() error {
+	genres := t.Genres
+	if t.Genre != "" {
+		genres = append([]string{t.Genre}, genres...)
+	}
+
+	genres, err := NormalizeGenres(genres)
+	if err != nil {
+		return err
+	}
+
+	moods, err := NormalizeMoods(t.Moods)
+	if err != nil {
+		return err
+	}
+
+	t.Genre = ""
+	if len(genres) > 0 {
+		t.Genre = genres[0]
+	}
+	if len(t.Genres) > 0 {
+		t.Genres = genres
+	}
+	t.Moods = moods
+
+	return nil
+}
+
+// AllGenres is Genres or Genre alone if Genres is empty
+func (t *TrackMeta) AllGenres() []string {
+	if len(t.Genres) == 0 && t.Genre != "" {
+		return []string{t.Genre}
+	}
+
+	return t.Genres
 }
 
 type TrackObject struct {
@@ -15,3 +150,31 @@ type TrackObject struct {
 func (t *TrackObject) ExtractMeta() *TrackMeta {
 	return &t.TrackMeta
 }
+
+// AddressByContent names the source after the hash of the payload, so tracks
+// with identical payloads share one object
+func (t *TrackObject) AddressByContent() {
+	sum := sha256.Sum256(t.Payload)
+	t.Source = hex.EncodeToString(sum[:])
+}
+
+// TrackDetails is a track with what is needed to tell it apart from tracks
+// with the same name, Duration is in seconds and 0 if unknown
+type TrackDetails struct {
+	TrackMeta
+	Musician string
+	Duration int
+}
+
+// TrackLookup asks for tracks named Name and, unless Musician is empty,
+// released by Musician
+type TrackLookup struct {
+	Name     string
+	Musician string
+}
+
+// FoldName is how names are compared on lookups, the same as
+// lower(btrim(name)) in the database
+func FoldName(name string) string {
+	return strings.ToLower(strings.Trim(name, " "))
+}
@@ -125,7 +125,7 @@ func (m *Menu) SignUpAsUser(opt wmenu.Opt) error {
 
 	jwt, err := utils.SignUpAsUser(client.Client,
 		dto.SignUp{
-			dto.UserInfo{
+			UserInfo: dto.UserInfo{
 				Name:     name,
 				Password: password,
 				Email:    login,
@@ -27,10 +27,10 @@ func GetMe(client *http.Client, jwt string) (*dto.GetMeResponse, error) {
 	}
 	req.Header.Set("Authorization", "Bearer "+jwt)
 	respGot, err := client.Do(req)
-	defer respGot.Body.Close()
 	if err != nil {
 		return nil, err
 	}
+	defer respGot.Body.Close()
 
 	var resp dto.GetMeResponse
 	err = render.DecodeJSON(respGot.Body, &resp)
@@ -10,6 +10,12 @@ from flask import Flask, jsonify, request
 from threading import Thread
 
 
+OPERATIONS = {
+    "track.added": "add",
+    "track.updated": "update",
+    "track.deleted": "delete",
+}
+
 MIN_PAGE_SIZE = 10
 MAX_PAGE_SIZE = 100
 
@@ -39,20 +45,26 @@ def create_consumer(config):
                     # действия с полученным сообщением
                     print(msg.value().decode('utf-8'))
                     message = json.loads(msg.value().decode('utf-8'))
-                    id = int(message['track_id'])
+                    if message['aggregate_type'] != "track":
+                        consumer.commit()
+                        continue
+
+                    payload = message['payload']
+                    id = int(payload['track_id'])
                     event_id = message['event_id']
-                    src = message['source']
+                    src = payload.get('source', "")
+                    operation = OPERATIONS.get(message['type'])
 
                     if not db.is_event_happened(event_id):
-                        if message['operation'] == "update":
+                        if operation == "update":
                             embs = emb_creator.create_embeddings(src, bucket)
                             db.update(id, embs, event_id)
                             model.update_recs()
-                        elif message['operation'] == "add":
+                        elif operation == "add":
                             embs = emb_creator.create_embeddings(src, bucket)
                             db.insert(id, embs, event_id)
                             model.update_recs()
-                        elif message['operation'] == "delete":
+                        elif operation == "delete":
                             db.delete(id, event_id)
                             model.update_recs()
 
@@ -94,7 +106,7 @@ def main():
     }
 
     consumer_loop = create_consumer(kafka_config)
-    thread = Thread(target=consumer_loop, args=(['sync-default-events'],))
+    thread = Thread(target=consumer_loop, args=(['sync-add-events', 'sync-update-events', 'sync-delete-events'],))
     thread.daemon = True
     thread.start()
 